@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
@@ -13,27 +14,35 @@ import (
 	"github.com/yourusername/connected-systems-go/internal/config"
 	"github.com/yourusername/connected-systems-go/internal/repository"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// shutdownTimeout bounds how long in-flight requests may drain during graceful shutdown.
+const shutdownTimeout = 30 * time.Second
+
 func main() {
-	// Initialize logger
-	logger, err := zap.NewProduction()
+	migrate := flag.Bool("migrate", false, "run database schema migrations before starting")
+	flag.Parse()
+
+	// Load configuration; the logger depends on it, so failures go to stderr
+	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to create logger: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
-	defer logger.Sync()
 
-	// Load configuration
-	cfg, err := config.Load()
+	// Initialize logger
+	logger, err := newLogger(cfg.Log)
 	if err != nil {
-		logger.Fatal("Failed to load configuration", zap.Error(err))
+		fmt.Fprintf(os.Stderr, "Failed to create logger: %v\n", err)
+		os.Exit(1)
 	}
+	defer logger.Sync()
 
 	// Initialize database
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable TimeZone=UTC",
 		cfg.Database.Host,
 		cfg.Database.Port,
 		cfg.Database.User,
@@ -45,10 +54,32 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to connect to database", zap.Error(err))
 	}
+	if err := repository.RegisterQueryTimeout(db, cfg.Database.QueryTimeout); err != nil {
+		logger.Fatal("Failed to configure query timeout", zap.Error(err))
+	}
+	if err := repository.RegisterErrorClassification(db); err != nil {
+		logger.Fatal("Failed to configure error classification", zap.Error(err))
+	}
+	if err := repository.RegisterOutputReprojection(db); err != nil {
+		logger.Fatal("Failed to configure geometry reprojection", zap.Error(err))
+	}
+	if err := repository.RegisterUTCTimestamps(db); err != nil {
+		logger.Fatal("Failed to configure UTC timestamps", zap.Error(err))
+	}
+	if cfg.API.Tenancy.Enabled {
+		if err := repository.RegisterTenantScoping(db); err != nil {
+			logger.Fatal("Failed to configure tenant scoping", zap.Error(err))
+		}
+	}
 
-	// Auto-migrate models
-	if err := repository.AutoMigrate(db); err != nil {
-		logger.Fatal("Failed to migrate database", zap.Error(err))
+	// Only migrate when explicitly asked; otherwise refuse to run against a schema we don't expect
+	if *migrate || cfg.Database.AutoMigrate {
+		if err := repository.AutoMigrate(db); err != nil {
+			logger.Fatal("Failed to migrate database", zap.Error(err))
+		}
+		logger.Info("Database migrated", zap.Int("schemaVersion", repository.SchemaVersion))
+	} else if err := repository.CheckSchemaVersion(db); err != nil {
+		logger.Fatal("Database schema is not at the expected version; restart with --migrate (or database.auto_migrate: true) to migrate it", zap.Error(err))
 	}
 
 	// Initialize repositories
@@ -57,10 +88,13 @@ func main() {
 	// Initialize API router
 	router := api.NewRouter(cfg, logger, repos)
 
+	// Reject new requests with 503 + Retry-After once shutdown begins
+	shutdownGate := api.NewShutdownGate(shutdownTimeout)
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
-		Handler:      router,
+		Handler:      shutdownGate.Middleware(router),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -84,8 +118,11 @@ func main() {
 
 	logger.Info("Shutting down server...")
 
+	// Tell new requests to back off while existing ones drain
+	shutdownGate.BeginShutdown()
+
 	// Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
@@ -94,3 +131,26 @@ func main() {
 
 	logger.Info("Server exited")
 }
+
+// newLogger builds the zap logger described by cfg: the production
+// configuration at the configured level, with the development encoder
+// settings (colored levels, readable timestamps) when the encoding is console.
+func newLogger(cfg config.LogConfig) (*zap.Logger, error) {
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log.level %q: must be one of debug, info, warn, error, dpanic, panic or fatal", cfg.Level)
+	}
+
+	zapCfg := zap.NewProductionConfig()
+	zapCfg.Level = zap.NewAtomicLevelAt(level)
+	switch cfg.Encoding {
+	case "", "json":
+	case "console":
+		zapCfg.Encoding = "console"
+		zapCfg.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+		zapCfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	default:
+		return nil, fmt.Errorf("invalid log.encoding %q: must be json or console", cfg.Encoding)
+	}
+	return zapCfg.Build()
+}
@@ -0,0 +1,90 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/api"
+	"github.com/yourusername/connected-systems-go/internal/model/formaters"
+	"go.uber.org/zap"
+)
+
+func TestBasePath_ServesUnderPrefix(t *testing.T) {
+	cleanupDB(t)
+
+	// The router is built once the server URL, which its links use, is known
+	var router http.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	cfg := *testConfig
+	cfg.API.BaseURL = server.URL
+	cfg.API.BasePath = "/csapi/"
+	router = api.NewRouter(&cfg, zap.NewNop(), testRepos)
+	// NewRouter sets the association link base for the whole process
+	defer formaters.SetAssociationLinksBaseURL(testConfig.API.BaseURL)
+
+	prefix := server.URL + "/csapi"
+
+	body, err := json.Marshal(baseSystemPayload("Gateway Sensor"))
+	require.NoError(t, err)
+	resp, err := http.Post(prefix+"/systems", "application/geo+json", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	location := resp.Header.Get("Location")
+	require.True(t, strings.HasPrefix(location, prefix+"/systems/"), "Location %q must include the prefix", location)
+	systemID := parseID(location, "/systems/")
+
+	linkHrefs := func(t *testing.T, url string) map[string]string {
+		t.Helper()
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var doc struct {
+			Links []struct {
+				Href string `json:"href"`
+				Rel  string `json:"rel"`
+			} `json:"links"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+		hrefs := make(map[string]string, len(doc.Links))
+		for _, link := range doc.Links {
+			hrefs[link.Rel] = link.Href
+		}
+		return hrefs
+	}
+
+	t.Run("collection self link includes the prefix", func(t *testing.T) {
+		assert.Equal(t, prefix+"/systems", linkHrefs(t, prefix+"/systems")["self"])
+	})
+
+	t.Run("item links include the prefix", func(t *testing.T) {
+		hrefs := linkHrefs(t, prefix+"/systems/"+systemID)
+		require.NotEmpty(t, hrefs)
+		for rel, href := range hrefs {
+			assert.True(t, strings.HasPrefix(href, prefix+"/"), "%s link %q must include the prefix", rel, href)
+		}
+	})
+
+	t.Run("landing page links include the prefix", func(t *testing.T) {
+		hrefs := linkHrefs(t, prefix+"/")
+		assert.Equal(t, prefix+"/conformance", hrefs["conformance"])
+		assert.Equal(t, prefix+"/", hrefs["self"])
+	})
+
+	t.Run("routes are not served at the root", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/systems")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
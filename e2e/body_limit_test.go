@@ -0,0 +1,81 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBodyLimit(t *testing.T) {
+	cleanupDB(t)
+
+	// The test server limits bodies to 1MB, and batch observation creates to 4MB
+	oversized := baseSystemPayload("Oversized System")
+	oversized["properties"].(map[string]interface{})["description"] = strings.Repeat("x", 2<<20)
+	body, err := json.Marshal(oversized)
+	require.NoError(t, err)
+
+	assertTooLarge := func(t *testing.T, resp *http.Response) {
+		t.Helper()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+		var problem map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+		assert.NotEmpty(t, problem["error"])
+	}
+
+	t.Run("declared Content-Length over the limit", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assertTooLarge(t, resp)
+	})
+
+	t.Run("chunked body over the limit", func(t *testing.T) {
+		// MultiReader hides the length, so the body is sent chunked
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems", io.MultiReader(bytes.NewReader(body)))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+		require.EqualValues(t, -1, req.ContentLength)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assertTooLarge(t, resp)
+	})
+
+	t.Run("batch route allows a larger body", func(t *testing.T) {
+		datastream := seedDatastreamForObservationTests(t)
+
+		start := time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)
+		records := make([]interface{}, 0, 20000)
+		for i := 0; i < cap(records); i++ {
+			records = append(records, map[string]interface{}{
+				"resultTime": start.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+				"result": map[string]interface{}{
+					"temperature": 20.0,
+					"humidity":    50.0,
+				},
+			})
+		}
+		batch, err := json.Marshal(records)
+		require.NoError(t, err)
+		require.Greater(t, len(batch), 1<<20, "batch must exceed the default limit")
+		require.Less(t, len(batch), 4<<20, "batch must fit the batch limit")
+
+		resp := postObservationsRaw(t, datastream.ID, records)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode, fmt.Sprintf("batch of %d bytes", len(batch)))
+	})
+}
@@ -0,0 +1,85 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The e2e config caches properties items for one minute and leaves every
+// other resource at the defaults.
+func TestCacheControl_PerResource(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Cache System"))
+
+	property, err := json.Marshal(map[string]interface{}{
+		"label":        "Cache Property",
+		"uniqueId":     "urn:test:property:cache:" + uuid.NewString(),
+		"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+	})
+	require.NoError(t, err)
+	resp, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(property))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Expires"), "writes are not cached")
+	propertyID := parseID(resp.Header.Get("Location"), "/properties/")
+	require.NotEmpty(t, propertyID)
+
+	get := func(t *testing.T, path, ifNoneMatch string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+		require.NoError(t, err)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	t.Run("configured item gets max-age and Expires", func(t *testing.T) {
+		resp := get(t, "/properties/"+propertyID, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "max-age=60", resp.Header.Get("Cache-Control"))
+
+		expires, err := http.ParseTime(resp.Header.Get("Expires"))
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), expires, 5*time.Second)
+
+		notModified := get(t, "/properties/"+propertyID, resp.Header.Get("ETag"))
+		require.Equal(t, http.StatusNotModified, notModified.StatusCode)
+		assert.Equal(t, "max-age=60", notModified.Header.Get("Cache-Control"))
+	})
+
+	t.Run("unconfigured item is revalidated", func(t *testing.T) {
+		resp := get(t, "/systems/"+systemID, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+		assert.Empty(t, resp.Header.Get("Expires"))
+	})
+
+	t.Run("unconfigured collection is not stored", func(t *testing.T) {
+		for _, path := range []string{"/properties", "/systems"} {
+			resp := get(t, path, "")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"), path)
+			assert.Empty(t, resp.Header.Get("Expires"), path)
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		resp := get(t, "/properties/"+uuid.NewString(), "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.NotContains(t, resp.Header.Get("Cache-Control"), "max-age")
+		assert.Empty(t, resp.Header.Get("Expires"))
+	})
+}
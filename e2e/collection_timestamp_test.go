@@ -0,0 +1,46 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionResponses_CarryTimeStamp(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("TimeStamp System"))
+	createDatastreamViaAPI(t, "/systems/"+systemID+"/datastreams", baseDatastreamPayload())
+
+	paths := []string{
+		"/systems",
+		"/datastreams",
+		"/systems/" + systemID + "/datastreams",
+		"/controlstreams",
+		"/commands",
+		"/systemEvents",
+		"/observations",
+		"/collections",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			before := time.Now().UTC().Truncate(time.Second)
+			resp, err := http.Get(testServer.URL + path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var body struct {
+				TimeStamp string `json:"timeStamp"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			stamp, err := time.Parse(time.RFC3339, body.TimeStamp)
+			require.NoError(t, err, "timeStamp %q", body.TimeStamp)
+			assert.False(t, stamp.Before(before), "timeStamp %v is older than the request", stamp)
+		})
+	}
+}
@@ -270,6 +270,50 @@ func TestControlStream_SystemSubCollection(t *testing.T) {
 	assert.True(t, found, "control stream must appear in /systems/{id}/controlstreams")
 }
 
+func TestControlStream_SystemSubCollection_ScopedToSystem(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemForControlStreamTest(t)
+	emptySystemID := createSystemForControlStreamTest(t)
+	csID := createControlStreamViaAPI(t, systemID, baseControlStreamPayload())
+
+	t.Run("camelCase path with enriched system link", func(t *testing.T) {
+		resp := doGet(t, "/systems/"+systemID+"/controlStreams")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var collection struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+		require.Len(t, collection.Items, 1)
+		assert.Equal(t, csID, collection.Items[0]["id"])
+
+		systemLink, ok := collection.Items[0]["system@link"].(map[string]interface{})
+		require.True(t, ok, "control stream must carry a system@link")
+		assert.NotEmpty(t, systemLink["title"], "system@link must be enriched with the system name")
+		assert.NotEmpty(t, systemLink["uid"], "system@link must be enriched with the system uid")
+	})
+
+	t.Run("system without streams returns empty collection", func(t *testing.T) {
+		resp := doGet(t, "/systems/"+emptySystemID+"/controlStreams")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var collection map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+		items, ok := collection["items"].([]interface{})
+		require.True(t, ok, "items must be an array")
+		assert.Empty(t, items)
+	})
+
+	t.Run("unknown system returns 404", func(t *testing.T) {
+		resp := doGet(t, "/systems/does-not-exist/controlStreams")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
 // =============================================================================
 // Conformance Class: /conf/json
 // Requirement: /req/json/control-stream-schema
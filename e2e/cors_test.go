@@ -0,0 +1,54 @@
+package e2e
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	send := func(t *testing.T, method, origin string, headers map[string]string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, testServer.URL+"/systems", nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("preflight from allowed origin", func(t *testing.T) {
+		resp := send(t, http.MethodOptions, "http://allowed.example", map[string]string{
+			"Access-Control-Request-Method":  http.MethodPost,
+			"Access-Control-Request-Headers": "Content-Type",
+		})
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "http://allowed.example", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, http.MethodPost, resp.Header.Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "600", resp.Header.Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("actual request from allowed origin", func(t *testing.T) {
+		resp := send(t, http.MethodGet, "http://allowed.example", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "http://allowed.example", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "Location", resp.Header.Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("other origins get no CORS headers", func(t *testing.T) {
+		resp := send(t, http.MethodOptions, "http://denied.example", map[string]string{
+			"Access-Control-Request-Method": http.MethodGet,
+		})
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+		resp = send(t, http.MethodGet, "http://denied.example", nil)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+	})
+}
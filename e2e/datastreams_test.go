@@ -267,9 +267,18 @@ func TestDatastream_CreateUnderSystem_AndSchemaRoundTrip(t *testing.T) {
 // =============================================================================
 // Conformance Class: /conf/create-replace-delete/datastream
 // Requirement: /req/create-replace-delete/datastream
-// DELETE /datastreams/{id}?cascade=true must delete the datastream and its observations.
+// DELETE /datastreams/{id} must delete the datastream and its observations,
+// with or without cascade=true.
 // =============================================================================
 func TestDatastream_DeleteCascade_RemovesObservations(t *testing.T) {
+	for name, query := range map[string]string{"cascade": "?cascade=true", "no cascade": ""} {
+		t.Run(name, func(t *testing.T) {
+			testDatastreamDeleteRemovesObservations(t, query)
+		})
+	}
+}
+
+func testDatastreamDeleteRemovesObservations(t *testing.T, query string) {
 	cleanupDB(t)
 
 	systemID := uuid.NewString()
@@ -284,7 +293,7 @@ func TestDatastream_DeleteCascade_RemovesObservations(t *testing.T) {
 	}
 	obsID := createObservationViaAPI(t, datastreamID, obsPayload)
 
-	delReq, err := http.NewRequest(http.MethodDelete, testServer.URL+"/datastreams/"+datastreamID+"?cascade=true", nil)
+	delReq, err := http.NewRequest(http.MethodDelete, testServer.URL+"/datastreams/"+datastreamID+query, nil)
 	require.NoError(t, err)
 	delResp, err := http.DefaultClient.Do(delReq)
 	require.NoError(t, err)
@@ -305,3 +314,47 @@ func TestDatastream_DeleteCascade_RemovesObservations(t *testing.T) {
 	defer getObsResp.Body.Close()
 	assert.Equal(t, http.StatusNotFound, getObsResp.StatusCode)
 }
+
+func TestDatastream_SystemSubCollection_ScopedToSystem(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Datastream Parent"))
+	emptySystemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Datastream Empty Parent"))
+	datastreamID := createDatastreamViaAPI(t, "/systems/"+systemID+"/datastreams", baseDatastreamPayload())
+
+	t.Run("lists only the system's datastreams with enriched system link", func(t *testing.T) {
+		resp := doGet(t, "/systems/"+systemID+"/datastreams")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var collection struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+		require.Len(t, collection.Items, 1)
+		assert.Equal(t, datastreamID, collection.Items[0]["id"])
+
+		systemLink, ok := collection.Items[0]["system@link"].(map[string]interface{})
+		require.True(t, ok, "datastream must carry a system@link")
+		assert.Equal(t, "Datastream Parent", systemLink["title"])
+		assert.NotEmpty(t, systemLink["uid"])
+	})
+
+	t.Run("system without datastreams returns empty collection", func(t *testing.T) {
+		resp := doGet(t, "/systems/"+emptySystemID+"/datastreams")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var collection map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+		items, ok := collection["items"].([]interface{})
+		require.True(t, ok, "items must be an array")
+		assert.Empty(t, items)
+	})
+
+	t.Run("unknown system returns 404", func(t *testing.T) {
+		resp := doGet(t, "/systems/does-not-exist/datastreams")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -199,6 +199,106 @@ func TestDeployment_AssociationLinks_DeployedSystems(t *testing.T) {
 	assert.Contains(t, href, "/systems/"+systemID, "deployedSystems@link href must reference the deployed system")
 }
 
+// Deployments link to their deployed systems and, when one is set, their
+// platform through links as well as the *@link properties.
+func TestDeployment_AssociationLinks_SystemsAndPlatform(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Deployment Links System"))
+	platformID := createSystemViaAPI(t, "/systems", baseSystemPayload("Deployment Links Platform"))
+	payload := baseDeploymentPayload("Deployment Links", systemID)
+	payload["properties"].(map[string]interface{})["platform@link"] = map[string]interface{}{
+		"href": testServer.URL + "/systems/" + platformID,
+	}
+	depID := createDeploymentViaAPI(t, "/deployments", payload)
+
+	for _, accept := range []string{"application/geo+json", "application/sml+json"} {
+		t.Run(accept, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, testServer.URL+"/deployments/"+depID, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", accept)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var doc struct {
+				Links []struct {
+					Rel  string `json:"rel"`
+					Href string `json:"href"`
+				} `json:"links"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+
+			hrefs := map[string]string{}
+			for _, link := range doc.Links {
+				hrefs[link.Rel] = link.Href
+			}
+			assert.Contains(t, hrefs["ogc-rel:deployedSystems"], "/systems?id="+systemID)
+			assert.Contains(t, hrefs["ogc-rel:platform"], "/systems/"+platformID)
+		})
+	}
+}
+
+// =============================================================================
+// Conformance Class: /conf/deployment
+// Requirement: datetime and bbox filters on the deployments collection
+// =============================================================================
+func TestDeploymentConformance_DatetimeAndBboxFilters(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Deployment Filter System"))
+	inside := createDeploymentViaAPI(t, "/deployments", baseDeploymentPayload("Deployment Filter Inside", systemID))
+
+	elsewhere := baseDeploymentPayload("Deployment Filter Elsewhere", systemID)
+	elsewhere["properties"].(map[string]interface{})["validTime"] = []string{"2020-01-01T00:00:00Z", "2020-12-31T23:59:59Z"}
+	elsewhere["geometry"] = map[string]interface{}{"type": "Point", "coordinates": []float64{2.35, 48.85}}
+	outside := createDeploymentViaAPI(t, "/deployments", elsewhere)
+
+	list := func(t *testing.T, query string) (int, []string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/deployments?"+query, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/geo+json")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		return resp.StatusCode, getDeploymentCollectionIDs(t, body)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"datetime interval", "dateTime=2026-06-01T00:00:00Z/2026-07-01T00:00:00Z", []string{inside}},
+		{"datetime instant", "dateTime=2020-06-01T00:00:00Z", []string{outside}},
+		{"bbox", "bbox=-118,32,-117,33", []string{inside}},
+		{"bbox and datetime", "bbox=-118,32,-117,33&dateTime=2020-06-01T00:00:00Z", []string{}},
+		{"no filter", "", []string{inside, outside}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status, ids := list(t, tc.query)
+			require.Equal(t, http.StatusOK, status)
+			assert.ElementsMatch(t, tc.want, ids)
+		})
+	}
+
+	t.Run("malformed bbox", func(t *testing.T) {
+		status, _ := list(t, "bbox=1,2,3")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
 // =============================================================================
 // Conformance Class: /conf/geojson and /conf/sensorml
 // Requirements: /req/geojson/deployment-schema and /req/sensorml/deployment-schema
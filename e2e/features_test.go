@@ -3,6 +3,7 @@ package e2e
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"testing"
 
@@ -71,3 +72,166 @@ func TestFeaturesAPI_E2E(t *testing.T) {
 		assert.Equal(t, "FeatureCollection", result["type"])
 	})
 }
+
+func TestFeatures_SortByAndDateTime(t *testing.T) {
+	cleanupDB(t)
+
+	collection, err := json.Marshal(map[string]interface{}{"id": "sorted-features", "title": "Sorted Features"})
+	require.NoError(t, err)
+	resp, err := http.Post(testServer.URL+"/collections", "application/json", bytes.NewReader(collection))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	create := func(t *testing.T, properties map[string]interface{}) string {
+		t.Helper()
+		body, err := json.Marshal(map[string]interface{}{"type": "Feature", "properties": properties, "geometry": map[string]interface{}{"type": "Point", "coordinates": []float64{1, 2}}})
+		require.NoError(t, err)
+		resp, err := http.Post(testServer.URL+"/collections/sorted-features/items", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		id, _ := created["id"].(string)
+		require.NotEmpty(t, id)
+		return id
+	}
+
+	charlie := create(t, map[string]interface{}{"name": "Charlie", "dateTime": "2024-03-15T12:00:00Z"})
+	alpha := create(t, map[string]interface{}{"name": "Alpha", "validTime": []string{"2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z"}})
+	bravo := create(t, map[string]interface{}{"name": "Bravo"})
+
+	list := func(t *testing.T, query string) (int, []string) {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/collections/sorted-features/items?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, getFeatureCollectionIDs(t, body)
+	}
+
+	tests := map[string]struct {
+		query      string
+		wantStatus int
+		want       []string
+	}{
+		"sortby name":       {query: "sortby=name", wantStatus: http.StatusOK, want: []string{alpha, bravo, charlie}},
+		"sortby -name":      {query: "sortby=-name", wantStatus: http.StatusOK, want: []string{charlie, bravo, alpha}},
+		"sortby created":    {query: "sortby=created", wantStatus: http.StatusOK, want: []string{charlie, alpha, bravo}},
+		"datetime interval": {query: "datetime=2024-01-15T00:00:00Z/2024-03-31T00:00:00Z&sortby=name", wantStatus: http.StatusOK, want: []string{alpha, charlie}},
+		"datetime instant":  {query: "datetime=2024-03-15T12:00:00Z", wantStatus: http.StatusOK, want: []string{charlie}},
+		"unknown sortby":    {query: "sortby=distance", wantStatus: http.StatusBadRequest},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, ids := list(t, tc.query)
+			require.Equal(t, tc.wantStatus, status)
+			if tc.wantStatus == http.StatusOK {
+				assert.Equal(t, tc.want, ids)
+			}
+		})
+	}
+}
+
+func TestFeatures_BboxExcludesNullGeometry(t *testing.T) {
+	cleanupDB(t)
+
+	collection, err := json.Marshal(map[string]interface{}{"id": "bbox-features", "title": "Bbox Features"})
+	require.NoError(t, err)
+	resp, err := http.Post(testServer.URL+"/collections", "application/json", bytes.NewReader(collection))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	create := func(t *testing.T, name string, geometry interface{}) string {
+		t.Helper()
+		body, err := json.Marshal(map[string]interface{}{"type": "Feature", "properties": map[string]interface{}{"name": name}, "geometry": geometry})
+		require.NoError(t, err)
+		resp, err := http.Post(testServer.URL+"/collections/bbox-features/items", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		id, _ := created["id"].(string)
+		require.NotEmpty(t, id)
+		return id
+	}
+
+	inside := create(t, "Inside", map[string]interface{}{"type": "Point", "coordinates": []float64{1, 2}})
+	outside := create(t, "Outside", map[string]interface{}{"type": "Point", "coordinates": []float64{50, 50}})
+	create(t, "No Geometry", nil)
+
+	for query, want := range map[string][]string{
+		"bbox=0,0,5,5":         {inside},
+		"bbox=-180,-90,180,90": {inside, outside},
+		"bbox=0,0,0,5,5,100":   {inside},
+		"bbox=100,-10,110,-5":  {},
+	} {
+		t.Run(query, func(t *testing.T) {
+			resp, err := http.Get(testServer.URL + "/collections/bbox-features/items?" + query)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+			assert.ElementsMatch(t, want, getFeatureCollectionIDs(t, body))
+		})
+	}
+}
+
+// TestFeatures_GetItem checks the OGC API Features item path: a feature is
+// served as GeoJSON from its own collection and is not found in any other.
+func TestFeatures_GetItem(t *testing.T) {
+	cleanupDB(t)
+
+	for _, id := range []string{"item-home", "item-other"} {
+		collection, err := json.Marshal(map[string]interface{}{"id": id, "title": id})
+		require.NoError(t, err)
+		resp, err := http.Post(testServer.URL+"/collections", "application/json", bytes.NewReader(collection))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"type":       "Feature",
+		"properties": map[string]interface{}{"name": "Pier"},
+		"geometry":   map[string]interface{}{"type": "Point", "coordinates": []float64{-117.17, 32.71}},
+	})
+	require.NoError(t, err)
+	resp, err := http.Post(testServer.URL+"/collections/item-home/items", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	featureID, _ := created["id"].(string)
+	require.NotEmpty(t, featureID)
+
+	got := doGet(t, "/collections/item-home/items/"+featureID)
+	defer got.Body.Close()
+	require.Equal(t, http.StatusOK, got.StatusCode)
+	assert.Contains(t, got.Header.Get("Content-Type"), "application/geo+json")
+	var feature map[string]interface{}
+	require.NoError(t, json.NewDecoder(got.Body).Decode(&feature))
+	assert.Equal(t, "Feature", feature["type"])
+	assert.Equal(t, featureID, feature["id"])
+	assert.Equal(t, "Pier", feature["properties"].(map[string]interface{})["name"])
+
+	for _, path := range []string{
+		"/collections/item-other/items/" + featureID,
+		"/collections/item-missing/items/" + featureID,
+		"/collections/item-home/items/does-not-exist",
+	} {
+		resp := doGet(t, path)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+}
@@ -0,0 +1,86 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+)
+
+// getBodyTwice fetches path twice and asserts both responses are byte-identical.
+func getBodyTwice(t *testing.T, path string) []byte {
+	t.Helper()
+
+	var bodies [][]byte
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get(testServer.URL + path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		bodies = append(bodies, body)
+	}
+	require.Equal(t, string(bodies[0]), string(bodies[1]), "responses must be byte-stable")
+	return bodies[0]
+}
+
+func propertiesKeyOrder(t *testing.T, body []byte) []string {
+	t.Helper()
+	var feature struct {
+		Properties common_shared.OrderedMap `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(body, &feature))
+	return feature.Properties.Keys()
+}
+
+func TestJSONMemberOrder_SamplingFeature(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Member Order System"))
+	sfID := createSamplingFeatureViaAPI(t, systemID, baseSamplingFeaturePayload("Member Order SF"))
+
+	body := getBodyTwice(t, "/samplingFeatures/"+sfID)
+	keys := propertiesKeyOrder(t, body)
+	require.GreaterOrEqual(t, len(keys), 4)
+	assert.Equal(t, []string{"uid", "name", "description", "featureType"}, keys[:4])
+}
+
+func TestJSONMemberOrder_Feature(t *testing.T) {
+	cleanupDB(t)
+
+	collection, err := json.Marshal(map[string]interface{}{"id": "member-order", "title": "Member Order"})
+	require.NoError(t, err)
+	resp, err := http.Post(testServer.URL+"/collections", "application/json", bytes.NewReader(collection))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	feature, err := json.Marshal(map[string]interface{}{
+		"type": "Feature",
+		"properties": map[string]interface{}{
+			"zeta":        1,
+			"name":        "Ordered Feature",
+			"alpha":       2,
+			"description": "Extra properties follow the known ones",
+		},
+		"geometry": map[string]interface{}{"type": "Point", "coordinates": []float64{1, 2}},
+	})
+	require.NoError(t, err)
+	resp, err = http.Post(testServer.URL+"/collections/member-order/items", "application/json", bytes.NewReader(feature))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	featureID, _ := created["id"].(string)
+	require.NotEmpty(t, featureID)
+
+	body := getBodyTwice(t, "/collections/member-order/items/"+featureID)
+	assert.Equal(t, []string{"uid", "name", "description", "collectionId", "alpha", "zeta"}, propertiesKeyOrder(t, body))
+}
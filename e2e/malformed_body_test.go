@@ -0,0 +1,98 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postRaw sends body as is to path and decodes the problem document returned.
+func postRaw(t *testing.T, method, path, contentType, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(method, testServer.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var problem map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem), "the response must be a JSON document")
+	return resp.StatusCode, problem
+}
+
+// firstViolation returns the path and message of the first entry in the
+// errors array of a problem document.
+func firstViolation(t *testing.T, problem map[string]interface{}) (string, string) {
+	t.Helper()
+	errs, ok := problem["errors"].([]interface{})
+	require.True(t, ok, "problem must list errors: %v", problem)
+	require.NotEmpty(t, errs)
+	violation := errs[0].(map[string]interface{})
+	path, _ := violation["path"].(string)
+	message, _ := violation["message"].(string)
+	return path, message
+}
+
+func TestMalformedRequestBodies(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Malformed Body Parent"))
+	datastreamID := createDatastreamViaAPI(t, "/systems/"+systemID+"/datastreams", baseDatastreamPayload())
+
+	truncated := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+	}{
+		{"system geojson", http.MethodPost, "/systems", "application/geo+json"},
+		{"system sensorml", http.MethodPost, "/systems", "application/sml+json"},
+		{"system replace", http.MethodPut, "/systems/" + systemID, "application/geo+json"},
+		{"sampling feature", http.MethodPost, "/systems/" + systemID + "/samplingFeatures", "application/geo+json"},
+		{"deployment", http.MethodPost, "/deployments", "application/geo+json"},
+		{"procedure", http.MethodPost, "/procedures", "application/geo+json"},
+		{"property", http.MethodPost, "/properties", "application/sml+json"},
+		{"datastream", http.MethodPost, "/systems/" + systemID + "/datastreams", "application/json"},
+		{"observation", http.MethodPost, "/datastreams/" + datastreamID + "/observations", "application/json"},
+		{"control stream", http.MethodPost, "/systems/" + systemID + "/controlstreams", "application/json"},
+		{"system event", http.MethodPost, "/systems/" + systemID + "/events", "application/json"},
+	}
+	for _, tc := range truncated {
+		t.Run("truncated "+tc.name, func(t *testing.T) {
+			status, problem := postRaw(t, tc.method, tc.path, tc.contentType, `{"type":`)
+			require.Equal(t, http.StatusBadRequest, status, "%v", problem)
+			assert.Equal(t, "Malformed request body", problem["title"])
+			_, message := firstViolation(t, problem)
+			assert.Contains(t, message, "malformed JSON")
+		})
+	}
+
+	t.Run("syntax error reports the offset", func(t *testing.T) {
+		status, problem := postRaw(t, http.MethodPost, "/systems", "application/sml+json", `{"type": "PhysicalSystem",}`)
+		require.Equal(t, http.StatusBadRequest, status, "%v", problem)
+		_, message := firstViolation(t, problem)
+		assert.Contains(t, message, "byte offset")
+		assert.Contains(t, message, "invalid character '}'")
+	})
+
+	t.Run("type mismatch names the member", func(t *testing.T) {
+		status, problem := postRaw(t, http.MethodPost, "/systems", "application/geo+json", `{"type": "Feature", "properties": {"name": 5}}`)
+		require.Equal(t, http.StatusBadRequest, status, "%v", problem)
+		path, message := firstViolation(t, problem)
+		assert.Equal(t, "properties.name", path)
+		assert.Contains(t, message, "must be a string, got JSON number")
+	})
+
+	t.Run("validate only reports the same violation", func(t *testing.T) {
+		status, report := postRaw(t, http.MethodPost, "/systems?validate=true", "application/geo+json", `{"type":`)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, false, report["valid"])
+		_, message := firstViolation(t, report)
+		assert.Contains(t, message, "malformed JSON")
+	})
+}
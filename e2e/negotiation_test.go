@@ -0,0 +1,153 @@
+package e2e
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// varyTokens returns the lower-cased header names listed across all Vary headers.
+func varyTokens(resp *http.Response) []string {
+	var tokens []string
+	for _, value := range resp.Header.Values("Vary") {
+		for _, token := range strings.Split(value, ",") {
+			tokens = append(tokens, strings.ToLower(strings.TrimSpace(token)))
+		}
+	}
+	return tokens
+}
+
+func TestVaryAccept_NegotiatedResponses(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Vary System"))
+
+	property, err := json.Marshal(map[string]interface{}{
+		"label":        "Vary Property",
+		"uniqueId":     "urn:test:property:vary:" + uuid.NewString(),
+		"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+	})
+	require.NoError(t, err)
+	resp, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(property))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	propertyID := parseID(resp.Header.Get("Location"), "/properties/")
+	require.NotEmpty(t, propertyID)
+
+	get := func(t *testing.T, path, accept, ifNoneMatch string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+		require.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	for _, path := range []string{"/systems", "/systems/" + systemID, "/properties", "/properties/" + propertyID} {
+		t.Run(path, func(t *testing.T) {
+			for _, accept := range []string{"", "application/geo+json", "application/sml+json"} {
+				resp := get(t, path, accept, "")
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, 1, countToken(varyTokens(resp), "accept"), "Accept %q", accept)
+			}
+		})
+	}
+
+	t.Run("not modified keeps Vary", func(t *testing.T) {
+		first := get(t, "/systems/"+systemID, "", "")
+		require.NotEmpty(t, first.Header.Get("ETag"))
+
+		resp := get(t, "/systems/"+systemID, "", first.Header.Get("ETag"))
+		require.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Contains(t, varyTokens(resp), "accept")
+	})
+}
+
+func countToken(tokens []string, want string) int {
+	n := 0
+	for _, token := range tokens {
+		if token == want {
+			n++
+		}
+	}
+	return n
+}
+
+// TestCompression_CollectionIsDecodable checks that a gzipped collection is
+// read correctly both by the standard client, which decompresses it
+// transparently, and by a client decoding the gzip stream itself.
+func TestCompression_CollectionIsDecodable(t *testing.T) {
+	cleanupDB(t)
+
+	for i := 0; i < 20; i++ {
+		createSystemViaAPI(t, "/systems", baseSystemPayload("Compressed System"))
+	}
+
+	t.Run("standard client", func(t *testing.T) {
+		resp := doGet(t, "/systems?limit=20")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, resp.Uncompressed, "the response must have been gzipped")
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Len(t, getFeatureCollectionIDs(t, body), 20)
+	})
+
+	t.Run("explicit gzip", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems?limit=20", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+		assert.Contains(t, varyTokens(resp), "accept-encoding")
+
+		compressed, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if length := resp.Header.Get("Content-Length"); length != "" {
+			assert.Equal(t, strconv.Itoa(len(compressed)), length, "a Content-Length must match the compressed body")
+		}
+		gz, err := gzip.NewReader(bytes.NewReader(compressed))
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Len(t, getFeatureCollectionIDs(t, body), 20)
+	})
+
+	t.Run("streamed ndjson", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems?limit=20", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/x-ndjson")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, resp.Uncompressed)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		require.Len(t, lines, 20)
+		for _, line := range lines {
+			assert.True(t, json.Valid([]byte(line)), line)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -109,6 +110,46 @@ func TestObservationConformance_ResourcesEndpoint(t *testing.T) {
 	assert.True(t, found, "created observation must be discoverable via /observations")
 }
 
+// TestObservation_ListTimesInUTC checks that listed observations, which are
+// streamed from the database cursor, carry UTC times whatever zone the driver
+// reads them in.
+func TestObservation_ListTimesInUTC(t *testing.T) {
+	cleanupDB(t)
+	local := time.Local
+	time.Local = time.FixedZone("+02:00", 2*60*60)
+	defer func() { time.Local = local }()
+
+	datastream := seedDatastreamForObservationTests(t)
+	createObservationViaAPI(t, datastream.ID, map[string]interface{}{
+		"phenomenonTime": "2026-03-13T09:00:00-01:00",
+		"resultTime":     "2026-03-13T12:00:00+02:00",
+		"result":         map[string]interface{}{"temperature": 20.5, "humidity": 50.1},
+	})
+
+	for _, path := range []string{"/observations", "/datastreams/" + datastream.ID + "/observations"} {
+		t.Run(path, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var collection struct {
+				Items []struct {
+					PhenomenonTime string `json:"phenomenonTime"`
+					ResultTime     string `json:"resultTime"`
+				} `json:"items"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+			require.Len(t, collection.Items, 1)
+			assert.Equal(t, "2026-03-13T10:00:00Z", collection.Items[0].PhenomenonTime)
+			assert.Equal(t, "2026-03-13T10:00:00Z", collection.Items[0].ResultTime)
+		})
+	}
+}
+
 // =============================================================================
 // Conformance Class: /conf/observation
 // Requirement: /req/observation/canonical-url
@@ -221,4 +262,165 @@ func TestObservation_Create_ValidatesParentDatastreamSchema(t *testing.T) {
 	require.NoError(t, err)
 	defer resp.Body.Close()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Every violation is reported, not just the first one.
+	var problem struct {
+		Status int `json:"status"`
+		Errors []struct {
+			Path    string `json:"path"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+
+	paths := make([]string, 0, len(problem.Errors))
+	for _, violation := range problem.Errors {
+		assert.NotEmpty(t, violation.Message)
+		paths = append(paths, violation.Path)
+	}
+	assert.ElementsMatch(t, []string{"result.temperature", "result.humidity"}, paths)
+
+	// Members the schema does not declare are rejected rather than stored
+	undeclared := postObservationsRaw(t, datastream.ID, map[string]interface{}{
+		"resultTime": "2026-03-13T13:10:00Z",
+		"result": map[string]interface{}{
+			"temperature": 20.2,
+			"humidity":    58.8,
+			"pressure":    1013.2,
+		},
+	})
+	defer undeclared.Body.Close()
+	require.Equal(t, http.StatusBadRequest, undeclared.StatusCode)
+	problem.Errors = nil
+	require.NoError(t, json.NewDecoder(undeclared.Body).Decode(&problem))
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "result.pressure", problem.Errors[0].Path)
+}
+
+func postObservationsRaw(t *testing.T, datastreamID string, payload interface{}) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, testServer.URL+"/datastreams/"+datastreamID+"/observations", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestObservation_Create_Batch(t *testing.T) {
+	cleanupDB(t)
+
+	datastream := seedDatastreamForObservationTests(t)
+
+	validRecord := func(resultTime string) map[string]interface{} {
+		return map[string]interface{}{
+			"resultTime": resultTime,
+			"result": map[string]interface{}{
+				"temperature": 20.0,
+				"humidity":    50.0,
+			},
+		}
+	}
+
+	t.Run("array returns summary", func(t *testing.T) {
+		resp := postObservationsRaw(t, datastream.ID, []interface{}{
+			validRecord("2026-03-13T10:00:00Z"),
+			validRecord("2026-03-13T10:01:00Z"),
+		})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var summary struct {
+			Count int      `json:"count"`
+			IDs   []string `json:"ids"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+		assert.Equal(t, 2, summary.Count)
+		require.Len(t, summary.IDs, 2)
+
+		for _, id := range summary.IDs {
+			getResp := doGet(t, "/observations/"+id)
+			getResp.Body.Close()
+			assert.Equal(t, http.StatusOK, getResp.StatusCode)
+		}
+	})
+
+	t.Run("invalid record rejects whole batch", func(t *testing.T) {
+		invalid := validRecord("2026-03-13T10:03:00Z")
+		invalid["result"] = map[string]interface{}{"temperature": "hot", "humidity": 50.0}
+
+		resp := postObservationsRaw(t, datastream.ID, []interface{}{
+			validRecord("2026-03-13T10:02:00Z"),
+			invalid,
+		})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var problem struct {
+			Errors []struct {
+				Path string `json:"path"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "[1].result.temperature", problem.Errors[0].Path)
+
+		var count int64
+		require.NoError(t, testDB.Model(&domains.Observation{}).
+			Where("datastream_id = ? AND result_time = ?", datastream.ID, "2026-03-13T10:02:00Z").
+			Count(&count).Error)
+		assert.Zero(t, count, "no observation from a rejected batch may be stored")
+	})
+
+	t.Run("unknown datastream", func(t *testing.T) {
+		resp := postObservationsRaw(t, "does-not-exist", []interface{}{validRecord("2026-03-13T10:04:00Z")})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestObservation_Create_EnforcesDatastreamValidTime(t *testing.T) {
+	cleanupDB(t)
+
+	testConfig.API.EnforceObservationValidTime = true
+	defer func() { testConfig.API.EnforceObservationValidTime = false }()
+
+	datastream := seedDatastreamForObservationTests(t)
+	require.NotNil(t, datastream.ValidTime)
+	require.NotNil(t, datastream.ValidTime.Start)
+
+	record := func(phenomenonTime time.Time) map[string]interface{} {
+		return map[string]interface{}{
+			"resultTime":     time.Now().UTC().Format(time.RFC3339),
+			"phenomenonTime": phenomenonTime.UTC().Format(time.RFC3339),
+			"result": map[string]interface{}{
+				"temperature": 20.0,
+				"humidity":    50.0,
+			},
+		}
+	}
+
+	inside := postObservationsRaw(t, datastream.ID, record(datastream.ValidTime.Start.Add(time.Hour)))
+	inside.Body.Close()
+	assert.Equal(t, http.StatusCreated, inside.StatusCode)
+
+	outside := postObservationsRaw(t, datastream.ID, record(datastream.ValidTime.Start.Add(-time.Hour)))
+	defer outside.Body.Close()
+	require.Equal(t, http.StatusBadRequest, outside.StatusCode)
+
+	var problem struct {
+		Title  string `json:"title"`
+		Errors []struct {
+			Path string `json:"path"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(outside.Body).Decode(&problem))
+	assert.Equal(t, "Observation is outside the datastream validTime", problem.Title)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "phenomenonTime", problem.Errors[0].Path)
 }
@@ -0,0 +1,63 @@
+package e2e
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_AllowHeader(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Options System"))
+
+	send := func(t *testing.T, method, path string, headers map[string]string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, testServer.URL+path, nil)
+		require.NoError(t, err)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	for path, allow := range map[string]string{
+		"/systems":                   "GET, POST, OPTIONS",
+		"/systems/" + systemID:       "GET, PUT, DELETE, OPTIONS",
+		"/systems/" + systemID + "/": "GET, PUT, DELETE, OPTIONS",
+		"/conformance":               "GET, OPTIONS",
+	} {
+		t.Run("OPTIONS "+path, func(t *testing.T) {
+			resp := send(t, http.MethodOptions, path, nil)
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+			assert.Equal(t, allow, resp.Header.Get("Allow"))
+		})
+	}
+
+	t.Run("unknown path", func(t *testing.T) {
+		resp := send(t, http.MethodOptions, "/no-such-resource", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Allow"))
+	})
+
+	t.Run("405 lists the same methods", func(t *testing.T) {
+		resp := send(t, http.MethodPost, "/systems/"+systemID, nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		assert.Equal(t, "GET, PUT, DELETE, OPTIONS", resp.Header.Get("Allow"))
+	})
+
+	t.Run("CORS preflight", func(t *testing.T) {
+		resp := send(t, http.MethodOptions, "/systems/"+systemID, map[string]string{
+			"Origin":                        "http://allowed.example",
+			"Access-Control-Request-Method": http.MethodPut,
+		})
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "http://allowed.example", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, http.MethodPut, resp.Header.Get("Access-Control-Allow-Methods"))
+	})
+}
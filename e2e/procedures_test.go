@@ -154,14 +154,8 @@ func TestProcedureConformance(t *testing.T) {
 
 				assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-				// Verify content type header contains expected media type
-				contentType := resp.Header.Get("Content-Type")
-				assert.NotEmpty(t, contentType, "Content-Type header must be set")
-				assert.True(t,
-					contentType == "application/sml+json" ||
-						contentType == "application/json" ||
-						contentType == "application/json; charset=utf-8",
-					"Content-Type must be a valid JSON media type, got: %s", contentType)
+				// The response carries exactly the negotiated media type
+				assert.Equal(t, "application/sml+json; charset=utf-8", resp.Header.Get("Content-Type"))
 			},
 		},
 		"/conf/procedure/canonical-url": {
@@ -573,3 +567,61 @@ func TestProcedureCRUD_Delete(t *testing.T) {
 	defer resp.Body.Close()
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
+
+func TestProcedures_FilterByType(t *testing.T) {
+	cleanupDB(t)
+
+	create := func(t *testing.T, processType, uid string) string {
+		t.Helper()
+		body, err := json.Marshal(map[string]interface{}{
+			"type":       processType,
+			"uniqueId":   uid,
+			"label":      processType + " Procedure",
+			"definition": "http://www.w3.org/ns/sosa/Procedure",
+		})
+		require.NoError(t, err)
+		resp, err := http.Post(testServer.URL+"/procedures", "application/sml+json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		return parseID(resp.Header.Get("Location"), "/procedures/")
+	}
+	simpleID := create(t, "SimpleProcess", "urn:test:procedure:type:simple")
+	physicalID := create(t, "PhysicalSystem", "urn:test:procedure:type:physical")
+	aggregateID := create(t, "AggregateProcess", "urn:test:procedure:type:aggregate")
+
+	list := func(t *testing.T, query string) (int, []string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/procedures?"+query, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/geo+json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		return resp.StatusCode, getFeatureCollectionIDs(t, body)
+	}
+
+	tests := map[string]struct {
+		query      string
+		wantStatus int
+		wantIDs    []string
+	}{
+		"single class":           {query: "type=PhysicalSystem", wantStatus: http.StatusOK, wantIDs: []string{physicalID}},
+		"OR-combined":            {query: "type=SimpleProcess,AggregateProcess", wantStatus: http.StatusOK, wantIDs: []string{simpleID, aggregateID}},
+		"repeated":               {query: "type=SimpleProcess&type=PhysicalSystem", wantStatus: http.StatusOK, wantIDs: []string{simpleID, physicalID}},
+		"no match":               {query: "type=PhysicalComponent", wantStatus: http.StatusOK},
+		"unknown class rejected": {query: "type=Sensor", wantStatus: http.StatusBadRequest},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, ids := list(t, tc.query)
+			require.Equal(t, tc.wantStatus, status)
+			assert.ElementsMatch(t, tc.wantIDs, ids)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -174,17 +175,8 @@ func TestPropertyConformance(t *testing.T) {
 
 				assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-				// Verify content type header contains expected media type
-				// Note: render.JSON may override Content-Type, but the key conformance
-				// requirement is that the endpoint exists and returns valid data
-				contentType := resp.Header.Get("Content-Type")
-				assert.NotEmpty(t, contentType, "Content-Type header must be set")
-				// Check that it's either the requested format or at least a valid JSON type
-				assert.True(t,
-					contentType == "application/sml+json" ||
-						contentType == "application/json" ||
-						contentType == "application/json; charset=utf-8",
-					"Content-Type must be a valid JSON media type, got: %s", contentType)
+				// The response carries exactly the negotiated media type
+				assert.Equal(t, "application/sml+json; charset=utf-8", resp.Header.Get("Content-Type"))
 			},
 		},
 		"/conf/property/canonical-url": {
@@ -467,6 +459,239 @@ func TestPropertyCRUD_Create(t *testing.T) {
 	}
 }
 
+func TestPropertyCRUD_QualifierUOM(t *testing.T) {
+	withUOM := func(qualifierType string, uom interface{}) []byte {
+		qualifier := map[string]interface{}{
+			"type":       qualifierType,
+			"label":      "Height",
+			"definition": "http://sensorml.com/ont/swe/property/Height",
+			"value":      5.0,
+		}
+		if qualifierType == "QuantityRange" {
+			qualifier["value"] = []float64{1, 2}
+		}
+		if uom != nil {
+			qualifier["uom"] = uom
+		}
+		body, _ := json.Marshal(map[string]interface{}{
+			"label":        "UOM Property",
+			"uniqueId":     "urn:test:property:uom:" + uuid.NewString(),
+			"baseProperty": "https://qudt.org/vocab/quantitykind/Length",
+			"qualifiers":   []map[string]interface{}{qualifier},
+		})
+		return body
+	}
+
+	tests := map[string]struct {
+		qualifierType string
+		uom           interface{}
+		want          int
+	}{
+		"simple code":             {qualifierType: "Quantity", uom: map[string]string{"code": "m"}, want: http.StatusCreated},
+		"compound code":           {qualifierType: "Quantity", uom: map[string]string{"code": "kg.m2/s2"}, want: http.StatusCreated},
+		"bracketed code":          {qualifierType: "Quantity", uom: map[string]string{"code": "[degF]"}, want: http.StatusCreated},
+		"unknown but well-formed": {qualifierType: "Quantity", uom: map[string]string{"code": "furlong/[wk]"}, want: http.StatusCreated},
+		"href only":               {qualifierType: "Quantity", uom: map[string]string{"href": "http://qudt.org/vocab/unit/M"}, want: http.StatusCreated},
+		"range with code":         {qualifierType: "QuantityRange", uom: map[string]string{"code": "m"}, want: http.StatusCreated},
+		"missing uom":             {qualifierType: "Quantity", want: http.StatusBadRequest},
+		"empty uom":               {qualifierType: "Quantity", uom: map[string]string{}, want: http.StatusBadRequest},
+		"empty code":              {qualifierType: "Quantity", uom: map[string]string{"code": ""}, want: http.StatusBadRequest},
+		"code with a space":       {qualifierType: "Quantity", uom: map[string]string{"code": "m s"}, want: http.StatusBadRequest},
+		"unbalanced parenthesis":  {qualifierType: "QuantityRange", uom: map[string]string{"code": "mol/(kg.s"}, want: http.StatusBadRequest},
+		"uom not an object":       {qualifierType: "Quantity", uom: "m", want: http.StatusBadRequest},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(withUOM(tt.qualifierType, tt.uom)))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.want, resp.StatusCode)
+
+			if tt.want == http.StatusBadRequest {
+				var problem struct {
+					Errors []struct {
+						Path string `json:"path"`
+					} `json:"errors"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+				require.NotEmpty(t, problem.Errors)
+				assert.Contains(t, problem.Errors[0].Path, "qualifiers[0].uom")
+			}
+		})
+	}
+}
+
+func TestPropertyCRUD_DuplicateUniqueID(t *testing.T) {
+	uid := "urn:test:property:duplicate:" + uuid.NewString()
+	payload := func(label string) []byte {
+		body, _ := json.Marshal(map[string]interface{}{
+			"label":        label,
+			"uniqueId":     uid,
+			"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+		})
+		return body
+	}
+
+	first, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(payload("Original")))
+	require.NoError(t, err)
+	first.Body.Close()
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+
+	second, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(payload("Duplicate")))
+	require.NoError(t, err)
+	defer second.Body.Close()
+	require.Equal(t, http.StatusConflict, second.StatusCode)
+
+	var problem map[string]interface{}
+	require.NoError(t, json.NewDecoder(second.Body).Decode(&problem))
+	assert.Equal(t, float64(http.StatusConflict), problem["status"])
+	assert.Contains(t, problem["detail"], uid)
+
+	// The original is untouched.
+	fetched, err := FollowLocation(first, "application/sml+json")
+	require.NoError(t, err)
+	assert.Equal(t, "Original", (*fetched)["label"])
+}
+
+// Properties only have a SensorML encoding, so asking for GeoJSON is refused
+// with 406 rather than answered in SensorML.
+func TestProperty_NotAcceptable(t *testing.T) {
+	cleanupDB(t)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"label":        "Not Acceptable Property",
+		"uniqueId":     "urn:test:property:406:" + uuid.NewString(),
+		"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+	})
+	require.NoError(t, err)
+	created, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(body))
+	require.NoError(t, err)
+	created.Body.Close()
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+	id := parseID(created.Header.Get("Location"), "/properties/")
+	require.NotEmpty(t, id)
+
+	get := func(t *testing.T, path, accept string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+		require.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	for _, path := range []string{"/properties/" + id, "/properties"} {
+		t.Run(path+" geo+json", func(t *testing.T) {
+			resp := get(t, path, "application/geo+json")
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+
+			var problem struct {
+				Status              int      `json:"status"`
+				Title               string   `json:"title"`
+				SupportedMediaTypes []string `json:"supportedMediaTypes"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+			assert.Equal(t, http.StatusNotAcceptable, problem.Status)
+			assert.Equal(t, "Not Acceptable", problem.Title)
+			assert.Equal(t, []string{"application/sml+json"}, problem.SupportedMediaTypes)
+		})
+	}
+
+	for _, accept := range []string{"", "application/sml+json", "application/json", "*/*", "application/geo+json;q=0.9, application/sml+json;q=0.5"} {
+		t.Run("item "+accept, func(t *testing.T) {
+			resp := get(t, "/properties/"+id, accept)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/sml+json; charset=utf-8", resp.Header.Get("Content-Type"))
+		})
+	}
+}
+
+// /properties/{id}/datastreams lists the datastreams whose observedProperties
+// reference the property's uniqueId, paged like any datastream collection.
+func TestProperty_ListDatastreams(t *testing.T) {
+	cleanupDB(t)
+
+	uid := "urn:test:property:observed:" + uuid.NewString()
+	body, err := json.Marshal(map[string]interface{}{
+		"label":        "Air Temperature",
+		"uniqueId":     uid,
+		"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+	})
+	require.NoError(t, err)
+	created, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(body))
+	require.NoError(t, err)
+	created.Body.Close()
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+	id := parseID(created.Header.Get("Location"), "/properties/")
+	require.NotEmpty(t, id)
+
+	endpoint := "/systems/" + uuid.NewString() + "/datastreams"
+	observing := map[string]bool{}
+	for _, label := range []string{"Air Temperature", "Temperature"} {
+		payload := baseDatastreamPayload()
+		payload["observedProperties"] = []map[string]interface{}{
+			{"definition": "https://qudt.org/vocab/quantitykind/RelativeHumidity", "label": "Humidity"},
+			{"definition": uid, "label": label},
+		}
+		observing[createDatastreamViaAPI(t, endpoint, payload)] = true
+	}
+	// A definition that merely contains the uid is not a match
+	other := baseDatastreamPayload()
+	other["observedProperties"] = []map[string]interface{}{
+		{"definition": uid + ":other", "label": "Other"},
+	}
+	createDatastreamViaAPI(t, endpoint, other)
+
+	list := func(t *testing.T, query string) map[string]interface{} {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/properties/" + id + "/datastreams" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var collection map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+		return collection
+	}
+
+	t.Run("lists observing datastreams", func(t *testing.T) {
+		items, ok := list(t, "")["items"].([]interface{})
+		require.True(t, ok, "response must contain 'items' array")
+		require.Len(t, items, 2)
+		for _, item := range items {
+			assert.True(t, observing[item.(map[string]interface{})["id"].(string)])
+		}
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		collection := list(t, "?limit=1")
+		items, ok := collection["items"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, items, 1)
+
+		hasNext := false
+		links, _ := collection["links"].([]interface{})
+		for _, l := range links {
+			if link, ok := l.(map[string]interface{}); ok && link["rel"] == "next" {
+				hasNext = true
+			}
+		}
+		assert.True(t, hasNext, "expected a next link with one of two datastreams returned")
+	})
+
+	t.Run("unknown property", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/properties/" + uuid.NewString() + "/datastreams")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
 // =============================================================================
 // Conformance Class: /conf/create-replace-delete/property
 // Requirement: /req/create-replace-delete/property
@@ -567,6 +792,105 @@ func TestPropertyCRUD_Replace(t *testing.T) {
 	}
 }
 
+func TestPropertyCRUD_QualifierConstraints(t *testing.T) {
+	qualified := func(uid string, qualifier map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"label":        "Constrained Property",
+			"uniqueId":     uid,
+			"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+			"qualifiers":   []map[string]interface{}{qualifier},
+		}
+	}
+	height := func(value interface{}, constraint map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"type":       "Quantity",
+			"label":      "Height",
+			"definition": "http://sensorml.com/ont/swe/property/Height",
+			"uom":        map[string]interface{}{"code": "m"},
+			"constraint": constraint,
+			"value":      value,
+		}
+	}
+	heightRange := func(value []interface{}, constraint map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"type":       "QuantityRange",
+			"label":      "Height Range",
+			"definition": "http://sensorml.com/ont/swe/property/Height",
+			"uom":        map[string]interface{}{"code": "m"},
+			"constraint": constraint,
+			"value":      value,
+		}
+	}
+	intervals := map[string]interface{}{
+		"type":      "AllowedValues",
+		"intervals": [][]interface{}{{0, 10}, {20, "+INF"}},
+	}
+	enumerated := map[string]interface{}{
+		"type":   "AllowedValues",
+		"values": []float64{2, 5, 10},
+	}
+
+	tests := map[string]struct {
+		qualifier map[string]interface{}
+		want      int
+	}{
+		"quantity within interval":        {qualifier: height(5.0, intervals), want: http.StatusCreated},
+		"quantity on interval bound":      {qualifier: height(10.0, intervals), want: http.StatusCreated},
+		"quantity in unbounded interval":  {qualifier: height(1000.0, intervals), want: http.StatusCreated},
+		"quantity between intervals":      {qualifier: height(15.0, intervals), want: http.StatusBadRequest},
+		"quantity below intervals":        {qualifier: height(-1.0, intervals), want: http.StatusBadRequest},
+		"quantity in enumerated values":   {qualifier: height(5.0, enumerated), want: http.StatusCreated},
+		"quantity not enumerated":         {qualifier: height(6.0, enumerated), want: http.StatusBadRequest},
+		"quantity range within interval":  {qualifier: heightRange([]interface{}{1.0, 9.0}, intervals), want: http.StatusCreated},
+		"quantity range crossing a bound": {qualifier: heightRange([]interface{}{5.0, 15.0}, intervals), want: http.StatusBadRequest},
+		"quantity range not a pair":       {qualifier: heightRange([]interface{}{5.0}, intervals), want: http.StatusBadRequest},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			body, _ := json.Marshal(qualified("urn:test:property:constraint:"+uuid.NewString(), tt.qualifier))
+			resp, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.want, resp.StatusCode)
+
+			if tt.want == http.StatusBadRequest {
+				var problem map[string]interface{}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+				assert.NotEmpty(t, problem["errors"])
+			}
+		})
+	}
+
+	t.Run("replace with a value outside the constraint", func(t *testing.T) {
+		uid := "urn:test:property:constraint:" + uuid.NewString()
+		body, _ := json.Marshal(qualified(uid, height(5.0, intervals)))
+		createResp, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(body))
+		require.NoError(t, err)
+		createResp.Body.Close()
+		require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+		created, err := FollowLocation(createResp, "application/sml+json")
+		require.NoError(t, err)
+		propID := (*created)["id"].(string)
+
+		updateBody, _ := json.Marshal(qualified(uid, height(15.0, intervals)))
+		req, _ := http.NewRequest(http.MethodPut, testServer.URL+"/properties/"+propID, bytes.NewReader(updateBody))
+		req.Header.Set("Content-Type", "application/sml+json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		fetched, err := FollowLocation(createResp, "application/sml+json")
+		require.NoError(t, err)
+		qualifiers, ok := (*fetched)["qualifiers"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, qualifiers, 1)
+		assert.Equal(t, 5.0, qualifiers[0].(map[string]interface{})["value"])
+	})
+}
+
 // =============================================================================
 // Conformance Class: /conf/create-replace-delete/property
 // Requirement: /req/create-replace-delete/property
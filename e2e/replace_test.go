@@ -0,0 +1,116 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplace_ReturnsNoContentAcrossResources asserts that a successful PUT
+// answers 204 No Content with an empty body for every replaceable resource.
+func TestReplace_ReturnsNoContentAcrossResources(t *testing.T) {
+	cleanupDB(t)
+
+	systemPayload := baseSystemPayload("Replace System")
+	systemID := createSystemViaAPI(t, "/systems", systemPayload)
+
+	procedurePayload := map[string]interface{}{
+		"type": "Feature",
+		"properties": map[string]interface{}{
+			"uid":         "urn:uuid:" + uuid.NewString(),
+			"name":        "Replace Procedure",
+			"featureType": "http://www.w3.org/ns/sosa/Procedure",
+		},
+	}
+	procedureID := createProcedureViaAPI(t, procedurePayload)
+
+	samplingFeaturePayload := baseSamplingFeaturePayload("Replace Sampling Feature")
+	samplingFeatureID := createSamplingFeatureViaAPI(t, systemID, samplingFeaturePayload)
+
+	deploymentPayload := baseDeploymentPayload("Replace Deployment", systemID)
+	deploymentID := createDeploymentViaAPI(t, "/deployments", deploymentPayload)
+
+	datastreamPayload := baseDatastreamPayload()
+	datastreamID := createDatastreamViaAPI(t, "/systems/"+systemID+"/datastreams", datastreamPayload)
+
+	controlStreamPayload := baseControlStreamPayload()
+	controlStreamID := createControlStreamViaAPI(t, systemID, controlStreamPayload)
+
+	propertyPayload := map[string]interface{}{
+		"label":        "Replace Property",
+		"uniqueId":     "urn:test:property:" + uuid.NewString(),
+		"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+	}
+	propertyBody, err := json.Marshal(propertyPayload)
+	require.NoError(t, err)
+	propertyResp, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(propertyBody))
+	require.NoError(t, err)
+	propertyResp.Body.Close()
+	require.Equal(t, http.StatusCreated, propertyResp.StatusCode)
+	propertyID := parseID(propertyResp.Header.Get("Location"), "/properties/")
+	require.NotEmpty(t, propertyID)
+
+	collectionBody, err := json.Marshal(map[string]interface{}{"id": "replace-collection", "title": "Replace Collection"})
+	require.NoError(t, err)
+	collectionResp, err := http.Post(testServer.URL+"/collections", "application/json", bytes.NewReader(collectionBody))
+	require.NoError(t, err)
+	collectionResp.Body.Close()
+	require.Equal(t, http.StatusCreated, collectionResp.StatusCode)
+
+	featurePayload := map[string]interface{}{
+		"type":       "Feature",
+		"properties": map[string]interface{}{"name": "Replace Feature"},
+		"geometry":   map[string]interface{}{"type": "Point", "coordinates": []float64{-117.1625, 32.715}},
+	}
+	featureBody, err := json.Marshal(featurePayload)
+	require.NoError(t, err)
+	featureResp, err := http.Post(testServer.URL+"/collections/replace-collection/items", "application/json", bytes.NewReader(featureBody))
+	require.NoError(t, err)
+	var createdFeature map[string]interface{}
+	require.NoError(t, json.NewDecoder(featureResp.Body).Decode(&createdFeature))
+	featureResp.Body.Close()
+	require.Equal(t, http.StatusCreated, featureResp.StatusCode)
+	featureID, _ := createdFeature["id"].(string)
+	require.NotEmpty(t, featureID)
+
+	tests := map[string]struct {
+		path        string
+		contentType string
+		payload     map[string]interface{}
+	}{
+		"systems":          {"/systems/" + systemID, "application/geo+json", systemPayload},
+		"procedures":       {"/procedures/" + procedureID, "application/geo+json", procedurePayload},
+		"samplingFeatures": {"/samplingFeatures/" + samplingFeatureID, "application/geo+json", samplingFeaturePayload},
+		"deployments":      {"/deployments/" + deploymentID, "application/geo+json", deploymentPayload},
+		"datastreams":      {"/datastreams/" + datastreamID, "application/json", datastreamPayload},
+		"controlstreams":   {"/controlstreams/" + controlStreamID, "application/json", controlStreamPayload},
+		"properties":       {"/properties/" + propertyID, "application/sml+json", propertyPayload},
+		"features":         {"/collections/replace-collection/items/" + featureID, "application/json", featurePayload},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			body, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPut, testServer.URL+tc.path, bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", tc.contentType)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+			assert.Empty(t, respBody, "replace responses must not carry a body")
+		})
+	}
+}
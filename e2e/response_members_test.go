@@ -0,0 +1,143 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/require"
+)
+
+// nullableMembers are the members a response may legitimately set to null,
+// such as the geometry of a GeoJSON feature without a location.
+var nullableMembers = map[string]bool{"geometry": true}
+
+// assertNoLeakedMembers fails when obj, or the properties object of a GeoJSON
+// feature, has a member whose name starts with a capital letter (a Go field
+// serialized without a json tag) or whose value is null.
+func assertNoLeakedMembers(t *testing.T, context string, obj map[string]json.RawMessage) {
+	t.Helper()
+	for name, value := range obj {
+		if name != "" && unicode.IsUpper([]rune(name)[0]) {
+			t.Errorf("%s: unexpected member %q (untagged Go field?)", context, name)
+		}
+		if bytes.Equal(bytes.TrimSpace(value), []byte("null")) && !nullableMembers[name] {
+			t.Errorf("%s: member %q is null", context, name)
+		}
+	}
+
+	if raw, ok := obj["properties"]; ok {
+		var properties map[string]json.RawMessage
+		if json.Unmarshal(raw, &properties) == nil {
+			assertNoLeakedMembers(t, context+" properties", properties)
+		}
+	}
+}
+
+// checkResponseMembers GETs path with the given Accept header and checks the
+// response body, and each member of its features or items array, with
+// assertNoLeakedMembers.
+func checkResponseMembers(t *testing.T, path, accept string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+	require.NoError(t, err)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "GET %s (%s)", path, accept)
+
+	var body map[string]json.RawMessage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	context := "GET " + path + " (" + accept + ")"
+	assertNoLeakedMembers(t, context, body)
+
+	for _, member := range []string{"features", "items"} {
+		raw, ok := body[member]
+		if !ok {
+			continue
+		}
+		var entries []map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(raw, &entries), "%s: %s must be an array of objects", context, member)
+		require.NotEmpty(t, entries, "%s: %s is empty", context, member)
+		for _, entry := range entries {
+			assertNoLeakedMembers(t, context+" "+member+"[]", entry)
+		}
+	}
+}
+
+func TestResponses_NoLeakedOrNullMembers(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Golden System"))
+	deploymentID := createDeploymentViaAPI(t, "/deployments", baseDeploymentPayload("Golden Deployment", systemID))
+	procedureID := createProcedureViaAPI(t, map[string]interface{}{
+		"type": "Feature",
+		"properties": map[string]interface{}{
+			"uid":         "urn:test:golden:procedure",
+			"name":        "Golden Procedure",
+			"featureType": "http://www.w3.org/ns/sosa/Procedure",
+		},
+	})
+	samplingFeatureID := createSamplingFeatureViaAPI(t, systemID, baseSamplingFeaturePayload("Golden Sampling Feature"))
+	datastreamID := createDatastreamViaAPI(t, "/systems/"+systemID+"/datastreams", baseDatastreamPayload())
+	observationID := createObservationViaAPI(t, datastreamID, map[string]interface{}{
+		"resultTime": "2026-03-13T10:00:00Z",
+		"result":     map[string]interface{}{"temperature": 21.4, "humidity": 57.9},
+	})
+	controlStreamID := createControlStreamViaAPI(t, systemID, baseControlStreamPayload())
+	eventID := createSystemEventViaAPI(t, systemID, baseSystemEventPayload("Golden Event"))
+
+	commandBody, err := json.Marshal(baseCommandPayload())
+	require.NoError(t, err)
+	commandResp, err := http.Post(testServer.URL+"/controlstreams/"+controlStreamID+"/commands", "application/json", bytes.NewReader(commandBody))
+	require.NoError(t, err)
+	commandResp.Body.Close()
+	require.Equal(t, http.StatusCreated, commandResp.StatusCode)
+	commandID := parseID(commandResp.Header.Get("Location"), "/commands/")
+	require.NotEmpty(t, commandID)
+
+	propertyBody, err := json.Marshal(map[string]interface{}{
+		"label":        "Golden Property",
+		"uniqueId":     "urn:test:golden:property",
+		"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+	})
+	require.NoError(t, err)
+	propertyResp, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(propertyBody))
+	require.NoError(t, err)
+	propertyResp.Body.Close()
+	require.Equal(t, http.StatusCreated, propertyResp.StatusCode)
+	propertyID := parseID(propertyResp.Header.Get("Location"), "/properties/")
+	require.NotEmpty(t, propertyID)
+
+	resources := []struct {
+		collection string
+		id         string
+		accepts    []string
+	}{
+		{"/systems", systemID, []string{"application/geo+json", "application/sml+json"}},
+		{"/deployments", deploymentID, []string{"application/geo+json", "application/sml+json"}},
+		{"/procedures", procedureID, []string{"application/geo+json", "application/sml+json"}},
+		{"/samplingFeatures", samplingFeatureID, []string{"application/geo+json", "application/sml+json"}},
+		{"/properties", propertyID, []string{"application/sml+json"}},
+		{"/datastreams", datastreamID, []string{"application/json"}},
+		{"/observations", observationID, []string{"application/json"}},
+		{"/controlstreams", controlStreamID, []string{"application/json"}},
+		{"/commands", commandID, []string{"application/json"}},
+		{"/systems/" + systemID + "/events", eventID, []string{"application/json"}},
+	}
+
+	for _, resource := range resources {
+		for _, accept := range resource.accepts {
+			t.Run(resource.collection+" "+accept, func(t *testing.T) {
+				checkResponseMembers(t, resource.collection, accept)
+				checkResponseMembers(t, resource.collection+"/"+resource.id, accept)
+			})
+		}
+	}
+}
@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
 )
 
 // Schema path constant for sampling feature validation
@@ -229,15 +233,8 @@ func TestSamplingFeatureConformance(t *testing.T) {
 
 				assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-				// Verify content type header contains expected media type
-				contentType := resp.Header.Get("Content-Type")
-				assert.NotEmpty(t, contentType, "Content-Type header must be set")
-				// Check that it's either the requested format or at least a valid JSON type
-				assert.True(t,
-					contentType == "application/geo+json" ||
-						contentType == "application/json" ||
-						contentType == "application/json; charset=utf-8",
-					"Content-Type must be a valid GeoJSON media type, got: %s", contentType)
+				// The response carries exactly the negotiated media type
+				assert.Equal(t, "application/geo+json; charset=utf-8", resp.Header.Get("Content-Type"))
 			},
 		},
 		"/conf/sf/canonical-url": {
@@ -814,6 +811,176 @@ func TestSamplingFeatureSystemLinks(t *testing.T) {
 	}
 }
 
+func TestSamplingFeatureTopLevelCreate(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Top-level SF Parent"))
+
+	basePayload := func(uid string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "Feature",
+			"properties": map[string]interface{}{
+				"uid":         uid,
+				"name":        "Top-level Sampling Point",
+				"featureType": "http://www.opengis.net/def/samplingFeatureType/OGC-OM/2.0/SF_SamplingPoint",
+			},
+			"geometry": map[string]interface{}{
+				"type":        "Point",
+				"coordinates": []float64{-117.0, 33.0},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		mutate         func(payload map[string]interface{})
+		expectedStatus int
+		expectedPath   string
+	}{
+		"parentSystem link": {
+			mutate: func(payload map[string]interface{}) {
+				payload["links"] = []map[string]interface{}{
+					{"rel": "ogc-rel:parentSystem", "href": "/systems/" + systemID},
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		"system@link property": {
+			mutate: func(payload map[string]interface{}) {
+				payload["properties"].(map[string]interface{})["system@link"] = map[string]interface{}{
+					"href": testServer.URL + "/systems/" + systemID,
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		"missing parent system": {
+			mutate:         func(payload map[string]interface{}) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"unknown parent system": {
+			mutate: func(payload map[string]interface{}) {
+				payload["links"] = []map[string]interface{}{
+					{"rel": "ogc-rel:parentSystem", "href": "/systems/does-not-exist"},
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedPath:   "parentSystem@link",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			payload := basePayload("urn:uuid:" + uuid.NewString())
+			tc.mutate(payload)
+
+			body, err := json.Marshal(payload)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, testServer.URL+"/samplingFeatures", bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/geo+json")
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, resp.StatusCode)
+			if tc.expectedPath != "" {
+				var problem struct {
+					Errors []struct {
+						Path string `json:"path"`
+					} `json:"errors"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+				require.NotEmpty(t, problem.Errors)
+				assert.Equal(t, tc.expectedPath, problem.Errors[0].Path)
+			}
+			if tc.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			location := resp.Header.Get("Location")
+			require.NotEmpty(t, location, "201 response must include a Location header")
+			sfID := location[strings.LastIndex(location, "/")+1:]
+
+			listResp, err := http.Get(testServer.URL + "/systems/" + systemID + "/samplingFeatures")
+			require.NoError(t, err)
+			defer listResp.Body.Close()
+			require.Equal(t, http.StatusOK, listResp.StatusCode)
+
+			var collection map[string]interface{}
+			require.NoError(t, json.NewDecoder(listResp.Body).Decode(&collection))
+			features, ok := collection["features"].([]interface{})
+			require.True(t, ok, "collection must contain features")
+
+			var found bool
+			for _, f := range features {
+				if feature, ok := f.(map[string]interface{}); ok && feature["id"] == sfID {
+					found = true
+				}
+			}
+			assert.True(t, found, "sampling feature created at top level must be listed under its parent system")
+		})
+	}
+}
+
+func TestSamplingFeatureUnknownParentSystem(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Unknown Parent SF Parent"))
+	payload := func(parentHref string) []byte {
+		feature := map[string]interface{}{
+			"type": "Feature",
+			"properties": map[string]interface{}{
+				"uid":         "urn:uuid:" + uuid.NewString(),
+				"name":        "Orphan Sampling Point",
+				"featureType": "http://www.opengis.net/def/samplingFeatureType/OGC-OM/2.0/SF_SamplingPoint",
+			},
+			"geometry": map[string]interface{}{"type": "Point", "coordinates": []float64{-117.0, 33.0}},
+		}
+		if parentHref != "" {
+			feature["links"] = []map[string]interface{}{{"rel": "ogc-rel:parentSystem", "href": parentHref}}
+		}
+		body, err := json.Marshal(feature)
+		require.NoError(t, err)
+		return body
+	}
+	send := func(method, path string, body []byte) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, testServer.URL+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("creating under an unknown system is a 404", func(t *testing.T) {
+		resp := send(http.MethodPost, "/systems/does-not-exist/samplingFeatures", payload(""))
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("updating to an unknown parent system is a 400", func(t *testing.T) {
+		resp := send(http.MethodPost, "/systems/"+systemID+"/samplingFeatures", payload(""))
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		location := resp.Header.Get("Location")
+		sfID := location[strings.LastIndex(location, "/")+1:]
+
+		resp = send(http.MethodPut, "/samplingFeatures/"+sfID, payload("/systems/does-not-exist"))
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var problem struct {
+			Errors []struct {
+				Path string `json:"path"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+		require.NotEmpty(t, problem.Errors)
+		assert.Equal(t, "parentSystem@link", problem.Errors[0].Path)
+	})
+}
+
 // =============================================================================
 // Conformance Class: SampleOf Relationships
 // Tests for sampleOf associations between sampling features
@@ -934,3 +1101,660 @@ func TestSamplingFeatureSampleOfRelationships(t *testing.T) {
 		})
 	}
 }
+
+func TestSamplingFeature_SampleOfValidation(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("SampleOf Validation System"))
+	parentID := createSamplingFeatureViaAPI(t, systemID, baseSamplingFeaturePayload("SampleOf Parent"))
+
+	withSampleOf := func(name string, hrefs ...string) map[string]interface{} {
+		payload := baseSamplingFeaturePayload(name)
+		links := make([]map[string]interface{}, 0, len(hrefs))
+		for _, href := range hrefs {
+			links = append(links, map[string]interface{}{"href": href, "rel": "ogc-rel:sampleOf"})
+		}
+		payload["links"] = links
+		return payload
+	}
+
+	send := func(t *testing.T, method, path string, payload map[string]interface{}) (*http.Response, []byte) {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		req, err := http.NewRequest(method, testServer.URL+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, respBody
+	}
+
+	relations := func(t *testing.T, id string) []string {
+		t.Helper()
+		var ids []string
+		require.NoError(t, testDB.Model(&domains.SamplingFeatureSampleOf{}).
+			Where("sampling_feature_id = ?", id).Order("sample_of_id").Pluck("sample_of_id", &ids).Error)
+		return ids
+	}
+
+	t.Run("unknown local feature is rejected", func(t *testing.T) {
+		resp, body := send(t, http.MethodPost, "/systems/"+systemID+"/samplingFeatures",
+			withSampleOf("Dangling Sample", "samplingFeatures/does-not-exist"))
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+
+		var problem struct {
+			Errors []struct {
+				Path    string `json:"path"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(body, &problem))
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "sampleOf", problem.Errors[0].Path)
+		assert.Contains(t, problem.Errors[0].Message, "does-not-exist")
+
+		listResp := doGet(t, "/samplingFeatures?q=Dangling")
+		defer listResp.Body.Close()
+		listBody, err := io.ReadAll(listResp.Body)
+		require.NoError(t, err)
+		assert.Empty(t, getFeatureCollectionIDs(t, listBody), "nothing must be created")
+	})
+
+	t.Run("validate-only reports an unknown local feature", func(t *testing.T) {
+		body, err := json.Marshal(withSampleOf("Validated Sample", "samplingFeatures/does-not-exist"))
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems/"+systemID+"/samplingFeatures", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+		req.Header.Set("Prefer", "handling=validate-only")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var report struct {
+			Valid  bool `json:"valid"`
+			Errors []struct {
+				Path string `json:"path"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		assert.False(t, report.Valid)
+		require.Len(t, report.Errors, 1)
+		assert.Equal(t, "sampleOf", report.Errors[0].Path)
+	})
+
+	t.Run("local and external references are accepted", func(t *testing.T) {
+		const external = "https://features.example.org/samplingFeatures/river-1"
+		resp, body := send(t, http.MethodPost, "/systems/"+systemID+"/samplingFeatures",
+			withSampleOf("Child Sample", "/samplingFeatures/"+parentID, external))
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+		childID := parseID(resp.Header.Get("Location"), "/samplingFeatures/")
+		require.NotEmpty(t, childID)
+
+		assert.Equal(t, []string{parentID}, relations(t, childID), "only the local reference is a relation")
+
+		getResp := doGet(t, "/samplingFeatures/"+childID)
+		defer getResp.Body.Close()
+		var feature struct {
+			Links []struct {
+				Href string `json:"href"`
+				Rel  string `json:"rel"`
+			} `json:"links"`
+		}
+		require.NoError(t, json.NewDecoder(getResp.Body).Decode(&feature))
+		var sampleOf []string
+		for _, link := range feature.Links {
+			if strings.HasSuffix(link.Rel, "sampleOf") {
+				sampleOf = append(sampleOf, link.Href)
+			}
+		}
+		assert.Contains(t, sampleOf, external, "external references are kept as opaque links")
+	})
+
+	t.Run("replace with an unknown local feature is rejected", func(t *testing.T) {
+		childID := createSamplingFeatureViaAPI(t, systemID, withSampleOf("Replaced Sample", "/samplingFeatures/"+parentID))
+
+		resp, body := send(t, http.MethodPut, "/samplingFeatures/"+childID,
+			withSampleOf("Replaced Sample", "/samplingFeatures/does-not-exist"))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+		assert.Equal(t, []string{parentID}, relations(t, childID), "the previous relation must be kept")
+	})
+}
+
+func TestSamplingFeature_FilterBySystemQueryParam(t *testing.T) {
+	cleanupDB(t)
+
+	systemA := createSystemViaAPI(t, "/systems", baseSystemPayload("SF Filter System A"))
+	systemB := createSystemViaAPI(t, "/systems", baseSystemPayload("SF Filter System B"))
+	systemC := createSystemViaAPI(t, "/systems", baseSystemPayload("SF Filter System C"))
+
+	sfA := createSamplingFeatureViaAPI(t, systemA, baseSamplingFeaturePayload("SF of A"))
+	sfB := createSamplingFeatureViaAPI(t, systemB, baseSamplingFeaturePayload("SF of B"))
+	_ = createSamplingFeatureViaAPI(t, systemC, baseSamplingFeaturePayload("SF of C"))
+
+	tests := map[string]struct {
+		query          string
+		expectedStatus int
+		expectedIDs    []string
+	}{
+		"single system": {
+			query:          "?system=" + systemA,
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{sfA},
+		},
+		"repeated systems": {
+			query:          "?system=" + systemA + "&system=" + systemB,
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{sfA, sfB},
+		},
+		"comma separated systems": {
+			query:          "?system=" + systemA + "," + systemB,
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{sfA, sfB},
+		},
+		"no matches": {
+			query:          "?system=" + uuid.NewString(),
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []string{},
+		},
+		"malformed id": {
+			query:          "?system=" + url.QueryEscape("not an id"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"empty id": {
+			query:          "?system=",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := http.Get(testServer.URL + "/samplingFeatures" + tc.query)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, resp.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedIDs, getFeatureCollectionIDs(t, body))
+		})
+	}
+}
+
+func TestSamplingFeature_Create_IdempotencyKey(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Idempotency Parent"))
+
+	post := func(t *testing.T, key string, payload map[string]interface{}) *http.Response {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems/"+systemID+"/samplingFeatures", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+		req.Header.Set("Idempotency-Key", key)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	listIDs := func(t *testing.T) []string {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems/" + systemID + "/samplingFeatures")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return getFeatureCollectionIDs(t, body)
+	}
+
+	key := uuid.NewString()
+	payload := baseSamplingFeaturePayload("Idempotent SF")
+
+	first := post(t, key, payload)
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+	location := first.Header.Get("Location")
+	require.NotEmpty(t, location)
+
+	t.Run("retry replays the original response", func(t *testing.T) {
+		retry := post(t, key, payload)
+		require.Equal(t, http.StatusCreated, retry.StatusCode)
+		assert.Equal(t, location, retry.Header.Get("Location"))
+		assert.Equal(t, "true", retry.Header.Get("Idempotent-Replayed"))
+		assert.Len(t, listIDs(t), 1, "retry must not create a second sampling feature")
+	})
+
+	t.Run("same key with a different body", func(t *testing.T) {
+		resp := post(t, key, baseSamplingFeaturePayload("Different SF"))
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("keys are scoped per resource type", func(t *testing.T) {
+		body, err := json.Marshal(baseSystemPayload("Idempotency Other Scope"))
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+		req.Header.Set("Idempotency-Key", key)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	})
+}
+
+func TestSamplingFeature_SystemSubCollection_Recursive(t *testing.T) {
+	cleanupDB(t)
+
+	platformID := createSystemViaAPI(t, "/systems", baseSystemPayload("Recursive SF Platform"))
+	subsystemID := createSystemViaAPI(t, "/systems/"+platformID+"/subsystems", baseSystemPayload("Recursive SF Subsystem"))
+	nestedID := createSystemViaAPI(t, "/systems/"+subsystemID+"/subsystems", baseSystemPayload("Recursive SF Nested"))
+	otherID := createSystemViaAPI(t, "/systems", baseSystemPayload("Recursive SF Unrelated"))
+
+	platformSF := createSamplingFeatureViaAPI(t, platformID, baseSamplingFeaturePayload("Platform SF"))
+	subsystemSF := createSamplingFeatureViaAPI(t, subsystemID, baseSamplingFeaturePayload("Subsystem SF"))
+	nestedSF := createSamplingFeatureViaAPI(t, nestedID, baseSamplingFeaturePayload("Nested SF"))
+	_ = createSamplingFeatureViaAPI(t, otherID, baseSamplingFeaturePayload("Unrelated SF"))
+
+	list := func(t *testing.T, query string) []string {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems/" + platformID + "/samplingFeatures" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return getFeatureCollectionIDs(t, body)
+	}
+
+	t.Run("default is not recursive", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{platformSF}, list(t, ""))
+	})
+
+	t.Run("recursive includes descendant subsystems", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{platformSF, subsystemSF, nestedSF}, list(t, "?recursive=true"))
+	})
+
+	t.Run("recursive results are paginated", func(t *testing.T) {
+		first := list(t, "?recursive=true&limit=2")
+		second := list(t, "?recursive=true&limit=2&offset=2")
+		assert.Len(t, first, 2)
+		assert.Len(t, second, 1)
+		assert.ElementsMatch(t, []string{platformSF, subsystemSF, nestedSF}, append(first, second...))
+	})
+}
+
+func TestSamplingFeature_SystemSubCollection_EmptyVsMissing(t *testing.T) {
+	cleanupDB(t)
+
+	emptyID := createSystemViaAPI(t, "/systems", baseSystemPayload("SF Empty System"))
+
+	t.Run("existing system without sampling features returns empty 200", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems/" + emptyID + "/samplingFeatures")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Empty(t, getFeatureCollectionIDs(t, body))
+	})
+
+	t.Run("unknown system returns 404", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems/does-not-exist/samplingFeatures")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("unknown system returns 404 when recursive", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems/does-not-exist/samplingFeatures?recursive=true")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestSamplingFeature_ParentSystemLinkEnrichment(t *testing.T) {
+	cleanupDB(t)
+
+	systemPayload := baseSystemPayload("Enrichment Parent")
+	parentUID := systemPayload["properties"].(map[string]interface{})["uid"].(string)
+	parentID := createSystemViaAPI(t, "/systems", systemPayload)
+
+	for i := 1; i <= 4; i++ {
+		createSamplingFeatureViaAPI(t, parentID, baseSamplingFeaturePayload(fmt.Sprintf("Enrichment SF %d", i)))
+	}
+
+	resp, err := http.Get(testServer.URL + "/systems/" + parentID + "/samplingFeatures")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var collection struct {
+		Features []struct {
+			Links []map[string]interface{} `json:"links"`
+		} `json:"features"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+	require.Len(t, collection.Features, 4)
+
+	for i, feature := range collection.Features {
+		var parentLink map[string]interface{}
+		for _, link := range feature.Links {
+			if rel, _ := link["rel"].(string); strings.HasSuffix(rel, "parentSystem") {
+				parentLink = link
+			}
+		}
+		require.NotNil(t, parentLink, "feature %d has no parentSystem link", i)
+		assert.True(t, strings.HasSuffix(parentLink["href"].(string), "/systems/"+parentID))
+		assert.Equal(t, "application/geo+json", parentLink["type"])
+		assert.Equal(t, "Enrichment Parent", parentLink["title"])
+		assert.Equal(t, parentUID, parentLink["uid"])
+	}
+}
+
+func TestSamplingFeature_NestedUnderSystem(t *testing.T) {
+	cleanupDB(t)
+
+	systemA := createSystemViaAPI(t, "/systems", baseSystemPayload("Nested SF System A"))
+	systemB := createSystemViaAPI(t, "/systems", baseSystemPayload("Nested SF System B"))
+	sfID := createSamplingFeatureViaAPI(t, systemA, baseSamplingFeaturePayload("Nested SF"))
+
+	get := func(t *testing.T, path string) (int, []byte) {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, body
+	}
+
+	linkHrefs := func(t *testing.T, body []byte) map[string]string {
+		t.Helper()
+		var feature struct {
+			Links []struct {
+				Rel  string `json:"rel"`
+				Href string `json:"href"`
+			} `json:"links"`
+		}
+		require.NoError(t, json.Unmarshal(body, &feature))
+		hrefs := map[string]string{}
+		for _, link := range feature.Links {
+			hrefs[link.Rel] = link.Href
+		}
+		return hrefs
+	}
+	withoutLinks := func(t *testing.T, body []byte) map[string]interface{} {
+		t.Helper()
+		var feature map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &feature))
+		delete(feature, "links")
+		return feature
+	}
+
+	t.Run("matches the canonical representation", func(t *testing.T) {
+		status, nested := get(t, "/systems/"+systemA+"/samplingFeatures/"+sfID)
+		require.Equal(t, http.StatusOK, status)
+
+		status, canonical := get(t, "/samplingFeatures/"+sfID)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, withoutLinks(t, canonical), withoutLinks(t, nested))
+	})
+
+	t.Run("self link follows the requested route", func(t *testing.T) {
+		status, nested := get(t, "/systems/"+systemA+"/samplingFeatures/"+sfID)
+		require.Equal(t, http.StatusOK, status)
+		links := linkHrefs(t, nested)
+		assert.True(t, strings.HasSuffix(links["self"], "/systems/"+systemA+"/samplingFeatures/"+sfID+"?f=geojson"), "self: %s", links["self"])
+		assert.True(t, strings.HasSuffix(links["alternate"], "/systems/"+systemA+"/samplingFeatures/"+sfID+"?f=sml"), "alternate: %s", links["alternate"])
+		assert.True(t, strings.HasSuffix(links["canonical"], "/samplingFeatures/"+sfID), "canonical: %s", links["canonical"])
+		assert.NotContains(t, links["canonical"], "/systems/")
+
+		status, canonical := get(t, "/samplingFeatures/"+sfID)
+		require.Equal(t, http.StatusOK, status)
+		links = linkHrefs(t, canonical)
+		assert.NotContains(t, links["self"], "/systems/")
+		assert.True(t, strings.HasSuffix(links["self"], "/samplingFeatures/"+sfID+"?f=geojson"), "self: %s", links["self"])
+		assert.True(t, strings.HasSuffix(links["canonical"], "/samplingFeatures/"+sfID), "canonical: %s", links["canonical"])
+	})
+
+	t.Run("other system returns 404", func(t *testing.T) {
+		status, _ := get(t, "/systems/"+systemB+"/samplingFeatures/"+sfID)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("unknown sampling feature returns 404", func(t *testing.T) {
+		status, _ := get(t, "/systems/"+systemA+"/samplingFeatures/does-not-exist")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
+
+func TestSamplingFeature_FilterByFeatureType(t *testing.T) {
+	cleanupDB(t)
+
+	_, createdIDs := setupSamplingFeatureConformanceData(t)
+	// Fixtures in creation order: point, curve, surface, point
+	pointIDs := []string{createdIDs[0], createdIDs[3]}
+	curveID, surfaceID := createdIDs[1], createdIDs[2]
+
+	const typeBase = "http://www.opengis.net/def/samplingFeatureType/OGC-OM/2.0/"
+
+	list := func(t *testing.T, query string) (int, []string) {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/samplingFeatures?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		return resp.StatusCode, getFeatureCollectionIDs(t, body)
+	}
+
+	t.Run("points only", func(t *testing.T) {
+		status, ids := list(t, "featureType="+url.QueryEscape(typeBase+"SF_SamplingPoint"))
+		require.Equal(t, http.StatusOK, status)
+		assert.ElementsMatch(t, pointIDs, ids)
+	})
+
+	t.Run("repeated values are OR-combined", func(t *testing.T) {
+		status, ids := list(t, "featureType="+url.QueryEscape(typeBase+"SF_SamplingCurve")+"&featureType="+url.QueryEscape(typeBase+"SF_SamplingSurface"))
+		require.Equal(t, http.StatusOK, status)
+		assert.ElementsMatch(t, []string{curveID, surfaceID}, ids)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		status, ids := list(t, "featureType="+url.QueryEscape("http://www.w3.org/ns/sosa/Sample"))
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("non-URI value is rejected", func(t *testing.T) {
+		status, _ := list(t, "featureType=SF_SamplingPoint")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestSamplingFeature_BulkDeleteByFilter(t *testing.T) {
+	cleanupDB(t)
+
+	systemA := createSystemViaAPI(t, "/systems", baseSystemPayload("Bulk Delete System A"))
+	systemB := createSystemViaAPI(t, "/systems", baseSystemPayload("Bulk Delete System B"))
+
+	createSamplingFeatureViaAPI(t, systemA, baseSamplingFeaturePayload("Bulk A1"))
+	createSamplingFeatureViaAPI(t, systemA, baseSamplingFeaturePayload("Bulk A2"))
+	sfB := createSamplingFeatureViaAPI(t, systemB, baseSamplingFeaturePayload("Bulk B1"))
+
+	bulkDelete := func(t *testing.T, query string) (int, map[string]interface{}) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodDelete, testServer.URL+"/samplingFeatures"+query, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+	listIDs := func(t *testing.T) []string {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/samplingFeatures")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return getFeatureCollectionIDs(t, body)
+	}
+
+	t.Run("unfiltered delete is rejected", func(t *testing.T) {
+		status, _ := bulkDelete(t, "")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = bulkDelete(t, "?limit=10")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Len(t, listIDs(t), 3)
+	})
+
+	t.Run("invalid filter is rejected", func(t *testing.T) {
+		status, _ := bulkDelete(t, "?system=")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Len(t, listIDs(t), 3)
+	})
+
+	t.Run("bad or open datetime deletes nothing", func(t *testing.T) {
+		for _, query := range []string{"?dateTime=garbage", "?datetime=garbage/..", "?dateTime=../..", "?dateTime=..&dateTime=.."} {
+			status, _ := bulkDelete(t, query)
+			assert.Equal(t, http.StatusBadRequest, status, query)
+		}
+		assert.Len(t, listIDs(t), 3)
+	})
+
+	t.Run("delete by system", func(t *testing.T) {
+		status, body := bulkDelete(t, "?system="+systemA)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 2.0, body["deleted"])
+		assert.Equal(t, []string{sfB}, listIDs(t))
+	})
+
+	t.Run("nothing left to match", func(t *testing.T) {
+		status, body := bulkDelete(t, "?system="+systemA)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 0.0, body["deleted"])
+	})
+}
+
+func TestSamplingFeature_SortBy(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("SF SortBy System"))
+	bravo := createSamplingFeatureViaAPI(t, systemID, baseSamplingFeaturePayload("Bravo"))
+	alpha := createSamplingFeatureViaAPI(t, systemID, baseSamplingFeaturePayload("Alpha"))
+	charlie := createSamplingFeatureViaAPI(t, systemID, baseSamplingFeaturePayload("Charlie"))
+
+	tests := map[string]struct {
+		query      string
+		wantStatus int
+		want       []string
+	}{
+		"default is creation order": {query: "", wantStatus: http.StatusOK, want: []string{bravo, alpha, charlie}},
+		"sortby name":               {query: "?sortby=name", wantStatus: http.StatusOK, want: []string{alpha, bravo, charlie}},
+		"sortby -created":           {query: "?sortby=-created", wantStatus: http.StatusOK, want: []string{charlie, alpha, bravo}},
+		"unknown sortby":            {query: "?sortby=featureType", wantStatus: http.StatusBadRequest},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := http.Get(testServer.URL + "/samplingFeatures" + tc.query)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, getFeatureCollectionIDs(t, body))
+		})
+	}
+}
+
+func TestSamplingFeature_SystemSubCollection_SpatialFilters(t *testing.T) {
+	cleanupDB(t)
+
+	systemA := createSystemViaAPI(t, "/systems", baseSystemPayload("Spatial SF System A"))
+	systemB := createSystemViaAPI(t, "/systems", baseSystemPayload("Spatial SF System B"))
+
+	at := func(name string, lon, lat float64) map[string]interface{} {
+		payload := baseSamplingFeaturePayload(name)
+		payload["geometry"] = map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []float64{lon, lat},
+		}
+		return payload
+	}
+	insideA := createSamplingFeatureViaAPI(t, systemA, at("Inside A", -117.1, 32.7))
+	createSamplingFeatureViaAPI(t, systemA, at("Outside A", -70.0, 40.0))
+	// Inside the bbox but attached to another system, so the scope must exclude it
+	createSamplingFeatureViaAPI(t, systemB, at("Inside B", -117.2, 32.8))
+
+	list := func(t *testing.T, query string) (int, []string) {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems/" + systemA + "/samplingFeatures?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		return resp.StatusCode, getFeatureCollectionIDs(t, body)
+	}
+
+	t.Run("bbox within system scope", func(t *testing.T) {
+		status, ids := list(t, "bbox=-118,32,-116,33")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{insideA}, ids)
+	})
+
+	t.Run("geom within system scope", func(t *testing.T) {
+		status, ids := list(t, "geom="+url.QueryEscape("POLYGON((-118 32,-116 32,-116 33,-118 33,-118 32))"))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{insideA}, ids)
+	})
+
+	t.Run("bbox with no match", func(t *testing.T) {
+		status, ids := list(t, "bbox=0,0,1,1")
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, ids)
+	})
+
+	t.Run("invalid bbox is rejected", func(t *testing.T) {
+		status, _ := list(t, "bbox=-118,32")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("invalid geom is rejected", func(t *testing.T) {
+		status, _ := list(t, "geom="+url.QueryEscape("POLYGON((-118 32"))
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
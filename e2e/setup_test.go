@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/yourusername/connected-systems-go/internal/api"
 	"github.com/yourusername/connected-systems-go/internal/config"
@@ -27,6 +28,7 @@ var (
 	testDB        *gorm.DB
 	testContainer *testutil.PostGISContainer
 	testRepos     *repository.Repositories
+	testConfig    *config.Config
 	// Serializer collections available for tests
 	testSystemFormatters          *formaters.MultiFormatFormatterCollection[*domains.System]
 	testDeploymentFormatters      *formaters.MultiFormatFormatterCollection[*domains.Deployment]
@@ -50,6 +52,22 @@ func TestMain(m *testing.M) {
 		EnableLogging: false,
 		Models:        testutil.AllModels(),
 	})
+	if err := repository.RegisterErrorClassification(testDB); err != nil {
+		panic(err)
+	}
+	// Only requests through a tenancy-enabled router carry a tenant, so this
+	// leaves testServer unscoped
+	if err := repository.RegisterTenantScoping(testDB); err != nil {
+		panic(err)
+	}
+
+	if err := repository.RegisterOutputReprojection(testDB); err != nil {
+		panic(err)
+	}
+
+	if err := repository.RegisterUTCTimestamps(testDB); err != nil {
+		panic(err)
+	}
 
 	// Initialize repositories
 	testRepos = repository.NewRepositories(testDB)
@@ -93,8 +111,6 @@ func TestMain(m *testing.M) {
 
 	// Properties
 	propCol := formaters.NewMultiFormatFormatterCollection[*domains.Property]("application/sml+json")
-	propGeo := geojson_formatters.NewPropertyGeoJSONFormatter(testRepos)
-	formaters.RegisterFormatterTyped(propCol, "application/geo+json", propGeo)
 	propSML := sensorml_formatters.NewPropertySensorMLFormatter(testRepos)
 	formaters.RegisterFormatterTyped(propCol, "application/sml+json", propSML)
 	formaters.RegisterFormatterTypedDefault(propCol, propSML, "application/sml+json")
@@ -110,12 +126,26 @@ func TestMain(m *testing.M) {
 	// Set up config
 	cfg := &config.Config{
 		API: config.APIConfig{
-			BaseURL: "http://localhost:8080",
-			Title:   "Test API",
-			Version: "1.0.0",
+			BaseURL:                 "http://localhost:8080",
+			Title:                   "Test API",
+			Version:                 "1.0.0",
+			MaxRequestBodySize:      1 << 20,
+			MaxBatchRequestBodySize: 4 << 20,
+			Cache: config.CacheConfig{
+				Items: map[string]time.Duration{"properties": time.Minute},
+			},
+		},
+		CORS: config.CORSConfig{
+			AllowedOrigins: []string{"http://allowed.example"},
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			AllowedHeaders: []string{"Accept", "Content-Type"},
+			ExposedHeaders: []string{"Location"},
+			MaxAge:         600,
 		},
 	}
 
+	testConfig = cfg
+
 	// Set up router
 	router := api.NewRouter(cfg, logger, testRepos)
 
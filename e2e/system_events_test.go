@@ -225,6 +225,60 @@ func TestSystemEventCRUD(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, getAfterDelete.StatusCode)
 }
 
+func TestSystemEvent_Create(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Event Create Parent"))
+
+	t.Run("persists the event under its system", func(t *testing.T) {
+		payload := baseSystemEventPayload("Mast replaced")
+		payload["definition"] = "https://example.org/event/maintenance"
+		payload["description"] = "Replaced the anemometer mast"
+		payload["time"] = "2026-04-02T09:30:00Z"
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		resp, err := http.Post(testServer.URL+"/systems/"+systemID+"/events", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		location := resp.Header.Get("Location")
+		assert.True(t, strings.HasSuffix(location, "/systems/"+systemID+"/events/"+parseID(location, "/events/")), "Location must point at the nested event, got %s", location)
+
+		getResp := doGet(t, "/systems/"+systemID+"/events/"+parseID(location, "/events/"))
+		defer getResp.Body.Close()
+		require.Equal(t, http.StatusOK, getResp.StatusCode)
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(getResp.Body).Decode(&got))
+		assert.Equal(t, "Mast replaced", got["label"])
+		assert.Equal(t, "https://example.org/event/maintenance", got["definition"])
+		assert.Equal(t, "Replaced the anemometer mast", got["description"])
+		assert.Equal(t, "2026-04-02T09:30:00Z", got["time"])
+	})
+
+	t.Run("unknown system is 404", func(t *testing.T) {
+		body, err := json.Marshal(baseSystemEventPayload("Orphan Event"))
+		require.NoError(t, err)
+
+		resp, err := http.Post(testServer.URL+"/systems/does-not-exist/events", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Location"))
+
+		listResp := doGet(t, "/systemEvents")
+		defer listResp.Body.Close()
+		var collection struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+		require.NoError(t, json.NewDecoder(listResp.Body).Decode(&collection))
+		for _, item := range collection.Items {
+			assert.NotEqual(t, "Orphan Event", item["label"], "no event may be stored for a missing system")
+		}
+	})
+}
+
 // =============================================================================
 // Conformance Class: /conf/system-event
 // Requirement: /req/system-event/filtering
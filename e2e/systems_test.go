@@ -2,16 +2,22 @@ package e2e
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	geom "github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/ewkbhex"
 )
 
 func createProcedureViaAPI(t *testing.T, payload map[string]interface{}) string {
@@ -265,6 +271,200 @@ func TestSystemSchema_GeoJSON(t *testing.T) {
 	requireSchemaOrSkip(t, body, SystemGeoSchema)
 }
 
+// Abstract groupings have no location; they must still be valid GeoJSON with
+// an explicit null geometry, both as a single item and inside a collection.
+func TestSystemSchema_GeoJSON_NoGeometry(t *testing.T) {
+	cleanupDB(t)
+
+	payload := baseSystemPayload("System Without Geometry")
+	delete(payload, "geometry")
+	systemID := createSystemViaAPI(t, "/systems", payload)
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+systemID, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/geo+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	requireSchemaOrSkip(t, body, SystemGeoSchema)
+
+	var feature map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &feature))
+	geometry, ok := feature["geometry"]
+	require.True(t, ok, "geometry member must be present")
+	assert.JSONEq(t, "null", string(geometry))
+
+	listResp, err := http.Get(testServer.URL + "/systems?f=geojson")
+	require.NoError(t, err)
+	defer listResp.Body.Close()
+	require.Equal(t, http.StatusOK, listResp.StatusCode)
+
+	listBody, err := io.ReadAll(listResp.Body)
+	require.NoError(t, err)
+
+	var collection struct {
+		Features []map[string]json.RawMessage `json:"features"`
+	}
+	require.NoError(t, json.Unmarshal(listBody, &collection))
+	require.Len(t, collection.Features, 1)
+	assert.JSONEq(t, "null", string(collection.Features[0]["geometry"]))
+}
+
+// validTime is a time period: always a [start, end] pair, with ".." standing
+// for an unbounded side.
+func TestSystemSchema_GeoJSON_OpenValidTime(t *testing.T) {
+	cleanupDB(t)
+
+	tests := map[string]struct {
+		validTime []string
+	}{
+		"closed":     {validTime: []string{"2024-01-01T00:00:00Z", "2024-12-31T23:59:59Z"}},
+		"open end":   {validTime: []string{"2024-01-01T00:00:00Z", ".."}},
+		"open start": {validTime: []string{"..", "2024-12-31T23:59:59Z"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			payload := baseSystemPayload("System Valid Time " + name)
+			payload["properties"].(map[string]interface{})["validTime"] = tt.validTime
+			systemID := createSystemViaAPI(t, "/systems", payload)
+
+			req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+systemID, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", "application/geo+json")
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			requireSchemaOrSkip(t, body, SystemGeoSchema)
+
+			var feature struct {
+				Properties struct {
+					ValidTime []string `json:"validTime"`
+				} `json:"properties"`
+			}
+			require.NoError(t, json.Unmarshal(body, &feature))
+			assert.Equal(t, tt.validTime, feature.Properties.ValidTime)
+		})
+	}
+}
+
+// Times are normalized to UTC when ingested and returned with a trailing "Z",
+// whatever offset the client wrote them with.
+func TestSystem_ValidTimeNormalizedToUTC(t *testing.T) {
+	cleanupDB(t)
+
+	payload := baseSystemPayload("System UTC Valid Time")
+	payload["properties"].(map[string]interface{})["validTime"] = []string{"2025-11-01T10:00:00+02:00", ".."}
+	systemID := createSystemViaAPI(t, "/systems", payload)
+
+	stored, err := testRepos.System.GetByID(systemID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.ValidTime)
+	require.NotNil(t, stored.ValidTime.Start)
+	assert.Equal(t, time.UTC, stored.ValidTime.Start.Location())
+	assert.True(t, stored.ValidTime.Start.Equal(time.Date(2025, time.November, 1, 8, 0, 0, 0, time.UTC)))
+
+	resp := doGet(t, "/systems/"+systemID)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var feature struct {
+		Properties struct {
+			ValidTime []string `json:"validTime"`
+		} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(body, &feature))
+	assert.Equal(t, []string{"2025-11-01T08:00:00Z", ".."}, feature.Properties.ValidTime)
+}
+
+func TestSystem_PolygonRingOrientation(t *testing.T) {
+	cleanupDB(t)
+	defer func() { testConfig.API.PolygonRingOrientation = "" }()
+
+	clockwise := [][][]float64{{{0, 0}, {0, 2}, {4, 2}, {4, 0}, {0, 0}}}
+	counterclockwise := [][][]float64{{{0, 0}, {4, 0}, {4, 2}, {0, 2}, {0, 0}}}
+	polygonPayload := func(name string, rings [][][]float64) []byte {
+		payload := baseSystemPayload(name)
+		payload["geometry"] = map[string]interface{}{"type": "Polygon", "coordinates": rings}
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		return body
+	}
+	post := func(t *testing.T, body []byte) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+	storedRings := func(t *testing.T, location string) [][][]float64 {
+		t.Helper()
+		resp := doGet(t, "/systems/"+parseID(location, "/systems/"))
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var feature struct {
+			Geometry struct {
+				Coordinates [][][]float64 `json:"coordinates"`
+			} `json:"geometry"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&feature))
+		return feature.Geometry.Coordinates
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		testConfig.API.PolygonRingOrientation = "reject"
+
+		ok := post(t, polygonPayload("Correctly Wound", counterclockwise))
+		ok.Body.Close()
+		assert.Equal(t, http.StatusCreated, ok.StatusCode)
+
+		bad := post(t, polygonPayload("Incorrectly Wound", clockwise))
+		defer bad.Body.Close()
+		require.Equal(t, http.StatusBadRequest, bad.StatusCode)
+		var problem struct {
+			Title  string `json:"title"`
+			Errors []struct {
+				Path string `json:"path"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.NewDecoder(bad.Body).Decode(&problem))
+		assert.Equal(t, "Invalid geometry", problem.Title)
+		require.Len(t, problem.Errors, 1)
+		assert.Equal(t, "geometry.coordinates[0]", problem.Errors[0].Path)
+	})
+
+	t.Run("fix", func(t *testing.T) {
+		testConfig.API.PolygonRingOrientation = "fix"
+
+		resp := post(t, polygonPayload("Rewound", clockwise))
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, counterclockwise, storedRings(t, resp.Header.Get("Location")))
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		testConfig.API.PolygonRingOrientation = "ignore"
+
+		resp := post(t, polygonPayload("Stored As Sent", clockwise))
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, clockwise, storedRings(t, resp.Header.Get("Location")))
+	})
+}
+
 func TestSystemSchema_SensorML(t *testing.T) {
 	cleanupDB(t)
 
@@ -284,6 +484,418 @@ func TestSystemSchema_SensorML(t *testing.T) {
 	requireSchemaOrSkip(t, body, SystemSMLSchema)
 }
 
+func TestSystemSchema_SensorML_Collection(t *testing.T) {
+	cleanupDB(t)
+
+	sensorPayload := baseSystemPayload("SensorML Collection Sensor")
+	platformPayload := baseSystemPayload("SensorML Collection Platform")
+	platformPayload["properties"].(map[string]interface{})["featureType"] = "http://www.w3.org/ns/sosa/Platform"
+	sensorID := createSystemViaAPI(t, "/systems", sensorPayload)
+	platformID := createSystemViaAPI(t, "/systems", platformPayload)
+
+	tests := []struct {
+		name   string
+		accept string
+	}{
+		{"exact media type", "application/sml+json"},
+		{"media type with parameters", "application/sml+json; charset=utf-8"},
+		{"preferred by q-value", "application/geo+json;q=0.5, application/sml+json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems", nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", tt.accept)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/sml+json; charset=utf-8", resp.Header.Get("Content-Type"))
+
+			var collection struct {
+				Features []json.RawMessage `json:"features"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+			require.Len(t, collection.Features, 2)
+
+			types := map[string]string{}
+			for _, raw := range collection.Features {
+				requireSchemaOrSkip(t, raw, SystemSMLSchema)
+
+				var item struct {
+					ID   string `json:"id"`
+					Type string `json:"type"`
+				}
+				require.NoError(t, json.Unmarshal(raw, &item))
+				types[item.ID] = item.Type
+			}
+			assert.Equal(t, "PhysicalComponent", types[sensorID])
+			assert.Equal(t, "PhysicalSystem", types[platformID])
+		})
+	}
+
+	t.Run("geo+json remains the default", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/geo+json; charset=utf-8", resp.Header.Get("Content-Type"))
+	})
+}
+
+func TestSystem_FormatLinks(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("System Format Links"))
+
+	fetchLinks := func(t *testing.T, target string) (string, []map[string]interface{}) {
+		t.Helper()
+		resp, err := http.Get(target)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Links      []map[string]interface{} `json:"links"`
+			Properties struct {
+				Links []map[string]interface{} `json:"links"`
+			} `json:"properties"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		if len(body.Links) == 0 {
+			body.Links = body.Properties.Links
+		}
+		return resp.Header.Get("Content-Type"), body.Links
+	}
+	linksByRel := func(links []map[string]interface{}, rel string) []map[string]interface{} {
+		var out []map[string]interface{}
+		for _, link := range links {
+			if link["rel"] == rel {
+				out = append(out, link)
+			}
+		}
+		return out
+	}
+
+	contentType, links := fetchLinks(t, testServer.URL+"/systems/"+systemID)
+	assert.Equal(t, "application/geo+json; charset=utf-8", contentType)
+
+	self := linksByRel(links, "self")
+	require.Len(t, self, 1)
+	assert.Equal(t, "application/geo+json", self[0]["type"])
+	require.Len(t, linksByRel(links, "canonical"), 1)
+	assert.True(t, strings.HasSuffix(linksByRel(links, "canonical")[0]["href"].(string), "/systems/"+systemID))
+
+	alternates := linksByRel(links, "alternate")
+	require.Len(t, alternates, 1, "only the other supported format is listed")
+	assert.Equal(t, "application/sml+json", alternates[0]["type"])
+
+	// The alternate link resolves to the SensorML representation
+	href, err := url.Parse(alternates[0]["href"].(string))
+	require.NoError(t, err)
+	contentType, smlLinks := fetchLinks(t, testServer.URL+href.RequestURI())
+	assert.Equal(t, "application/sml+json; charset=utf-8", contentType)
+	smlAlternates := linksByRel(smlLinks, "alternate")
+	require.Len(t, smlAlternates, 1)
+	assert.Equal(t, "application/geo+json", smlAlternates[0]["type"])
+}
+
+func TestSystemSchema_SensorML_ContactsAndIdentifiers(t *testing.T) {
+	cleanupDB(t)
+
+	payload := map[string]interface{}{
+		"type":       "PhysicalSystem",
+		"label":      "System Schema SensorML Metadata",
+		"uniqueId":   "urn:uuid:" + uuid.NewString(),
+		"definition": "http://www.w3.org/ns/sosa/Sensor",
+		"identifiers": []interface{}{
+			map[string]interface{}{
+				"definition": "http://sensorml.com/ont/swe/property/SerialNumber",
+				"label":      "Serial Number",
+				"value":      "SN-0042",
+			},
+			map[string]interface{}{
+				"label": "Short Name",
+				"value": "WX-42",
+			},
+		},
+		"contacts": []interface{}{
+			map[string]interface{}{
+				"organisationName": "Example Weather Service",
+				"role":             "http://sensorml.com/ont/swe/roles/Operator",
+				"contactInfo": map[string]interface{}{
+					"website": "https://weather.example.org",
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	postReq, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems", bytes.NewReader(body))
+	require.NoError(t, err)
+	postReq.Header.Set("Content-Type", "application/sml+json")
+	postResp, err := http.DefaultClient.Do(postReq)
+	require.NoError(t, err)
+	defer postResp.Body.Close()
+	require.Equal(t, http.StatusCreated, postResp.StatusCode)
+
+	systemID := parseID(postResp.Header.Get("Location"), "/systems/")
+	require.NotEmpty(t, systemID)
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+systemID, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/sml+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	requireSchemaOrSkip(t, respBody, SystemSMLSchema)
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(respBody, &got))
+
+	identifiers, ok := got["identifiers"].([]interface{})
+	require.True(t, ok, "identifiers must be present in SensorML output")
+	require.Len(t, identifiers, 2)
+	shortName := identifiers[1].(map[string]interface{})
+	assert.Equal(t, "Short Name", shortName["label"])
+	assert.NotContains(t, shortName, "definition")
+	assert.NotContains(t, shortName, "codeSpace")
+
+	contacts, ok := got["contacts"].([]interface{})
+	require.True(t, ok, "contacts must be present in SensorML output")
+	require.Len(t, contacts, 1)
+	assert.Equal(t, "Example Weather Service", contacts[0].(map[string]interface{})["organisationName"])
+}
+
+func TestSystemSchema_SensorML_CapabilitiesAndCharacteristics(t *testing.T) {
+	cleanupDB(t)
+
+	characteristics := []interface{}{
+		map[string]interface{}{
+			"label":      "Physical Properties",
+			"definition": "http://sensorml.com/ont/swe/property/PhysicalProperties",
+			"characteristics": []interface{}{
+				map[string]interface{}{
+					"type":       "Quantity",
+					"name":       "weight",
+					"definition": "http://qudt.org/vocab/quantitykind/Mass",
+					"label":      "Weight",
+					"uom":        map[string]interface{}{"code": "kg"},
+					"value":      0.4,
+				},
+			},
+		},
+	}
+	capabilities := []interface{}{
+		map[string]interface{}{
+			"label":      "Measurement Capabilities",
+			"definition": "http://sensorml.com/ont/swe/property/MeasurementProperties",
+			"capabilities": []interface{}{
+				map[string]interface{}{
+					"type":       "Quantity",
+					"name":       "accuracy",
+					"definition": "http://sensorml.com/ont/swe/property/Accuracy",
+					"label":      "Accuracy",
+					"uom":        map[string]interface{}{"code": "Cel"},
+					"value":      0.1,
+				},
+				map[string]interface{}{
+					"type":       "QuantityRange",
+					"name":       "range",
+					"definition": "http://sensorml.com/ont/swe/property/MeasurementRange",
+					"label":      "Measurement Range",
+					"uom":        map[string]interface{}{"code": "Cel"},
+					"value":      []interface{}{-40, 60},
+				},
+			},
+		},
+	}
+	payload := map[string]interface{}{
+		"type":            "PhysicalComponent",
+		"label":           "System Schema SensorML Capabilities",
+		"uniqueId":        "urn:uuid:" + uuid.NewString(),
+		"definition":      "http://www.w3.org/ns/sosa/Sensor",
+		"characteristics": characteristics,
+		"capabilities":    capabilities,
+	}
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	postReq, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems", bytes.NewReader(body))
+	require.NoError(t, err)
+	postReq.Header.Set("Content-Type", "application/sml+json")
+	postResp, err := http.DefaultClient.Do(postReq)
+	require.NoError(t, err)
+	defer postResp.Body.Close()
+	require.Equal(t, http.StatusCreated, postResp.StatusCode)
+
+	systemID := parseID(postResp.Header.Get("Location"), "/systems/")
+	require.NotEmpty(t, systemID)
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+systemID, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/sml+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	requireSchemaOrSkip(t, respBody, SystemSMLSchema)
+
+	var got struct {
+		Characteristics json.RawMessage `json:"characteristics"`
+		Capabilities    json.RawMessage `json:"capabilities"`
+	}
+	require.NoError(t, json.Unmarshal(respBody, &got))
+
+	wantCharacteristics, err := json.Marshal(characteristics)
+	require.NoError(t, err)
+	wantCapabilities, err := json.Marshal(capabilities)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(wantCharacteristics), string(got.Characteristics))
+	assert.JSONEq(t, string(wantCapabilities), string(got.Capabilities))
+}
+
+// =============================================================================
+// Conformance Class: /conf/sensorml
+// Requirement: /req/sensorml/system-sml (canonical URL)
+// The item route returns the full SensorML document for application/sml+json
+// and keeps GeoJSON as the default.
+// =============================================================================
+func TestSystemItem_SensorMLFullDocument(t *testing.T) {
+	cleanupDB(t)
+
+	components := []interface{}{
+		map[string]interface{}{
+			"name":  "thermometer",
+			"href":  "https://example.org/sensors/thermometer",
+			"title": "Thermometer",
+		},
+		map[string]interface{}{
+			"name":       "anemometer",
+			"type":       "PhysicalComponent",
+			"label":      "Anemometer",
+			"uniqueId":   "urn:uuid:" + uuid.NewString(),
+			"definition": "http://www.w3.org/ns/sosa/Sensor",
+		},
+	}
+	payload := map[string]interface{}{
+		"type":       "PhysicalSystem",
+		"label":      "System Item SensorML Station",
+		"uniqueId":   "urn:uuid:" + uuid.NewString(),
+		"definition": "http://www.w3.org/ns/sosa/Platform",
+		"contacts": []interface{}{
+			map[string]interface{}{
+				"organisationName": "Example Weather Service",
+				"role":             "http://sensorml.com/ont/swe/roles/Operator",
+			},
+		},
+		"capabilities": []interface{}{
+			map[string]interface{}{
+				"label":      "Operating Capabilities",
+				"definition": "http://sensorml.com/ont/swe/property/OperatingProperties",
+				"capabilities": []interface{}{
+					map[string]interface{}{
+						"type":       "Quantity",
+						"name":       "endurance",
+						"definition": "http://sensorml.com/ont/swe/property/BatteryLifetime",
+						"label":      "Endurance",
+						"uom":        map[string]interface{}{"code": "d"},
+						"value":      30,
+					},
+				},
+			},
+		},
+		"components": components,
+	}
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	postReq, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems", bytes.NewReader(body))
+	require.NoError(t, err)
+	postReq.Header.Set("Content-Type", "application/sml+json")
+	postResp, err := http.DefaultClient.Do(postReq)
+	require.NoError(t, err)
+	defer postResp.Body.Close()
+	require.Equal(t, http.StatusCreated, postResp.StatusCode)
+
+	systemID := parseID(postResp.Header.Get("Location"), "/systems/")
+	require.NotEmpty(t, systemID)
+
+	get := func(t *testing.T, accept string) (*http.Response, []byte) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+systemID, nil)
+		require.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, respBody
+	}
+
+	t.Run("application/sml+json", func(t *testing.T) {
+		resp, respBody := get(t, "application/sml+json")
+		assert.Equal(t, "application/sml+json; charset=utf-8", resp.Header.Get("Content-Type"))
+		requireSchemaOrSkip(t, respBody, SystemSMLSchema)
+
+		var got struct {
+			ID           string                   `json:"id"`
+			Type         string                   `json:"type"`
+			Label        string                   `json:"label"`
+			Contacts     []map[string]interface{} `json:"contacts"`
+			Capabilities []interface{}            `json:"capabilities"`
+			Components   json.RawMessage          `json:"components"`
+		}
+		require.NoError(t, json.Unmarshal(respBody, &got))
+		assert.Equal(t, systemID, got.ID)
+		assert.Equal(t, "PhysicalSystem", got.Type)
+		assert.Equal(t, "System Item SensorML Station", got.Label)
+		require.Len(t, got.Contacts, 1)
+		assert.Equal(t, "Example Weather Service", got.Contacts[0]["organisationName"])
+		assert.Len(t, got.Capabilities, 1)
+
+		wantComponents, err := json.Marshal(components)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(wantComponents), string(got.Components))
+	})
+
+	for _, accept := range []string{"", "application/geo+json"} {
+		name := accept
+		if name == "" {
+			name = "default"
+		}
+		t.Run(name, func(t *testing.T) {
+			resp, respBody := get(t, accept)
+			assert.Equal(t, "application/geo+json; charset=utf-8", resp.Header.Get("Content-Type"))
+			requireSchemaOrSkip(t, respBody, SystemGeoSchema)
+
+			var got struct {
+				Type       string                 `json:"type"`
+				Properties map[string]interface{} `json:"properties"`
+			}
+			require.NoError(t, json.Unmarshal(respBody, &got))
+			assert.Equal(t, "Feature", got.Type)
+			assert.Equal(t, "System Item SensorML Station", got.Properties["name"])
+		})
+	}
+}
+
 // =============================================================================
 // Conformance Class: /conf/subsystem
 // Requirement: /req/subsystem/collection
@@ -310,6 +922,58 @@ func TestSubsystemConformance_Collection(t *testing.T) {
 	assert.Contains(t, ids, childID)
 }
 
+// TestSystem_ExpandParentSystem checks that ?expand=parentSystem embeds the
+// parent summary in a child system, while the default stays link-only.
+func TestSystem_ExpandParentSystem(t *testing.T) {
+	cleanupDB(t)
+
+	parentPayload := baseSystemPayload("Breadcrumb Parent")
+	parentID := createSystemViaAPI(t, "/systems", parentPayload)
+	parentUID := parentPayload["properties"].(map[string]interface{})["uid"]
+	childID := createSystemViaAPI(t, "/systems/"+parentID+"/subsystems", baseSystemPayload("Breadcrumb Child"))
+
+	getProperties := func(path string) map[string]interface{} {
+		t.Helper()
+		resp := doGet(t, path)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var feature struct {
+			Properties map[string]interface{} `json:"properties"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&feature))
+		return feature.Properties
+	}
+
+	props := getProperties("/systems/" + childID + "?expand=parentSystem")
+	assert.Equal(t, map[string]interface{}{
+		"id":   parentID,
+		"uid":  parentUID,
+		"name": "Breadcrumb Parent",
+	}, props["parentSystem"])
+
+	assert.NotContains(t, getProperties("/systems/"+childID), "parentSystem", "the default keeps the link-only form")
+	assert.NotContains(t, getProperties("/systems/"+parentID+"?expand=parentSystem"), "parentSystem", "top-level systems have no parent to embed")
+
+	resp := doGet(t, "/systems/"+parentID+"/subsystems?expand=parentSystem")
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var collection struct {
+		Features []struct {
+			ID         string                 `json:"id"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+	require.Len(t, collection.Features, 1)
+	assert.Equal(t, childID, collection.Features[0].ID)
+	require.Contains(t, collection.Features[0].Properties, "parentSystem")
+	assert.Equal(t, parentID, collection.Features[0].Properties["parentSystem"].(map[string]interface{})["id"])
+
+	bad := doGet(t, "/systems/"+childID+"?expand=owner")
+	defer bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+}
+
 // =============================================================================
 // Conformance Class: /conf/system
 // Requirement: association links on systems must expose subsystem relationships.
@@ -511,6 +1175,56 @@ func TestSystem_AssociationLinks_AppearInSystemCollection(t *testing.T) {
 	assert.True(t, foundControlStreams, "system collection item must expose a controlstreams association link")
 }
 
+func TestSystem_AssociationLinks_FeaturesOfInterest(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Features Of Interest Parent"))
+
+	sampling := func(name, sampledHref string) map[string]interface{} {
+		payload := baseSamplingFeaturePayload(name)
+		payload["properties"].(map[string]interface{})["sampledFeature@link"] = map[string]interface{}{
+			"href":  sampledHref,
+			"title": name + " feature",
+		}
+		return payload
+	}
+	createSamplingFeatureViaAPI(t, systemID, sampling("Upstream", "http://example.org/features/river"))
+	createSamplingFeatureViaAPI(t, systemID, sampling("Downstream", "http://example.org/features/river"))
+	createSamplingFeatureViaAPI(t, systemID, sampling("Shore", "http://example.org/features/lake"))
+	createSamplingFeatureViaAPI(t, systemID, baseSamplingFeaturePayload("No Sampled Feature"))
+
+	for _, accept := range []string{"application/geo+json", "application/sml+json"} {
+		t.Run(accept, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+systemID, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", accept)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var body struct {
+				Links []struct {
+					Rel   string `json:"rel"`
+					Href  string `json:"href"`
+					Title string `json:"title"`
+				} `json:"links"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+			var hrefs []string
+			for _, link := range body.Links {
+				if link.Rel == "ogc-rel:featuresOfInterest" {
+					hrefs = append(hrefs, link.Href)
+					assert.NotEmpty(t, link.Title)
+				}
+			}
+			assert.Equal(t, []string{"http://example.org/features/river", "http://example.org/features/lake"}, hrefs)
+		})
+	}
+}
+
 // =============================================================================
 // Conformance Class: /conf/subsystem
 // Requirements: /req/subsystem/recursive-search-systems and /req/subsystem/recursive-search-subsystems
@@ -586,6 +1300,47 @@ func TestSubsystemConformance_RecursiveSearchSubsystems(t *testing.T) {
 	assert.Contains(t, recIDs, grandchildID)
 }
 
+func TestSubsystems_Pagination(t *testing.T) {
+	cleanupDB(t)
+
+	parentID := createSystemViaAPI(t, "/systems", baseSystemPayload("Paged Subsystems Parent"))
+	var childIDs []string
+	for i := 0; i < 3; i++ {
+		childIDs = append(childIDs, createSystemViaAPI(t, "/systems/"+parentID+"/subsystems", baseSystemPayload(fmt.Sprintf("Paged Subsystem %d", i))))
+	}
+
+	fetchPage := func(query string) map[string]interface{} {
+		resp, err := http.Get(testServer.URL + "/systems/" + parentID + "/subsystems?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var page map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+		return page
+	}
+
+	var seen []string
+	for _, query := range []string{"limit=2", "limit=2&offset=2"} {
+		page := fetchPage(query)
+		assert.EqualValues(t, 3, page["numberMatched"])
+		for _, feature := range page["features"].([]interface{}) {
+			seen = append(seen, feature.(map[string]interface{})["id"].(string))
+		}
+	}
+	assert.ElementsMatch(t, childIDs, seen)
+
+	first := fetchPage("limit=2")
+	assert.EqualValues(t, 2, first["numberReturned"])
+	hasNext := false
+	for _, link := range first["links"].([]interface{}) {
+		if link.(map[string]interface{})["rel"] == "next" {
+			hasNext = true
+		}
+	}
+	assert.True(t, hasNext, "first page must link to the next page")
+}
+
 // =============================================================================
 // Conformance Class: /conf/create-replace-delete
 // Requirements: /req/create-replace-delete/system and /req/create-replace-delete/subsystem
@@ -660,3 +1415,1160 @@ func TestSubsystemCRUD_CreateAndCanonicalRead(t *testing.T) {
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&child))
 	assert.Equal(t, childID, child["id"])
 }
+
+func TestSystem_ConditionalGet_IfNoneMatch(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Conditional GET System"))
+
+	req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+systemID, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/geo+json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	t.Run("matching etag returns 304 without body", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+systemID, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/geo+json")
+		req.Header.Set("If-None-Match", etag)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Equal(t, etag, resp.Header.Get("ETag"))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Empty(t, body)
+	})
+
+	t.Run("mismatched etag returns 200 with same etag", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+systemID, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/geo+json")
+		req.Header.Set("If-None-Match", `"does-not-match"`)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, etag, resp.Header.Get("ETag"))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.NotEmpty(t, body)
+	})
+}
+
+func TestSystem_SortByDistance(t *testing.T) {
+	cleanupDB(t)
+
+	withLocation := func(name string, lon, lat float64) map[string]interface{} {
+		payload := baseSystemPayload(name)
+		payload["geometry"] = map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []float64{lon, lat},
+		}
+		return payload
+	}
+
+	farID := createSystemViaAPI(t, "/systems", withLocation("Far Station", -100.0, 40.0))
+	nearID := createSystemViaAPI(t, "/systems", withLocation("Near Station", -117.2, 32.7))
+	midID := createSystemViaAPI(t, "/systems", withLocation("Mid Station", -118.2, 34.0))
+
+	t.Run("orders by ascending distance", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems?sortby=distance&near=" + url.QueryEscape("POINT(-117.1625 32.715)"))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, []string{nearID, midID, farID}, getFeatureCollectionIDs(t, body))
+
+		var collection struct {
+			Features []struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"features"`
+		}
+		require.NoError(t, json.Unmarshal(body, &collection))
+		require.Len(t, collection.Features, 3)
+
+		previous := -1.0
+		for _, feature := range collection.Features {
+			distance, ok := feature.Properties["distance"].(float64)
+			require.True(t, ok, "each feature must include a numeric distance property")
+			assert.GreaterOrEqual(t, distance, previous)
+			previous = distance
+		}
+	})
+
+	t.Run("near is required", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems?sortby=distance")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("malformed near", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems?sortby=distance&near=" + url.QueryEscape("POINT(abc)"))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestSystem_NearRadiusInMeters(t *testing.T) {
+	cleanupDB(t)
+
+	withLocation := func(name string, lon, lat float64) map[string]interface{} {
+		payload := baseSystemPayload(name)
+		payload["geometry"] = map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []float64{lon, lat},
+		}
+		return payload
+	}
+
+	// Near the pole a quarter turn of longitude is about 16 km while a few
+	// tenths of a degree of latitude are tens of kilometers, so planar
+	// degrees would rank these the other way round.
+	quarterTurnID := createSystemViaAPI(t, "/systems", withLocation("Quarter Turn", 90, 89.9))
+	acrossPoleID := createSystemViaAPI(t, "/systems", withLocation("Across The Pole", 180, 89.9))
+	southID := createSystemViaAPI(t, "/systems", withLocation("Further South", 0, 89.5))
+
+	list := func(t *testing.T, query string) []byte {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems?near=" + url.QueryEscape("POINT(0 89.9)") + "&" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		return body
+	}
+
+	t.Run("radius is in meters", func(t *testing.T) {
+		assert.Equal(t, []string{quarterTurnID}, getFeatureCollectionIDs(t, list(t, "radius=20000")))
+		assert.ElementsMatch(t, []string{quarterTurnID, acrossPoleID}, getFeatureCollectionIDs(t, list(t, "radius=25000")))
+		assert.Empty(t, getFeatureCollectionIDs(t, list(t, "radius=1000")))
+	})
+
+	t.Run("distances are geodesic", func(t *testing.T) {
+		body := list(t, "sortby=distance&radius=100000")
+		assert.Equal(t, []string{quarterTurnID, acrossPoleID, southID}, getFeatureCollectionIDs(t, body))
+
+		var collection struct {
+			Features []struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"features"`
+		}
+		require.NoError(t, json.Unmarshal(body, &collection))
+		require.Len(t, collection.Features, 3)
+		distance, ok := collection.Features[0].Properties["distance"].(float64)
+		require.True(t, ok)
+		assert.InDelta(t, 15800, distance, 500, "distance must be meters, not degrees")
+	})
+
+	t.Run("radius requires near", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems?radius=1000")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestSystem_BboxCrossingAntimeridian(t *testing.T) {
+	cleanupDB(t)
+
+	withLocation := func(name string, lon, lat float64) map[string]interface{} {
+		payload := baseSystemPayload(name)
+		payload["geometry"] = map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []float64{lon, lat},
+		}
+		return payload
+	}
+
+	eastID := createSystemViaAPI(t, "/systems", withLocation("East of Antimeridian", 179.0, 0.0))
+	westID := createSystemViaAPI(t, "/systems", withLocation("West of Antimeridian", -179.0, 0.0))
+	_ = createSystemViaAPI(t, "/systems", withLocation("Prime Meridian", 0.0, 0.0))
+
+	resp, err := http.Get(testServer.URL + "/systems?bbox=170,-10,-170,10")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{eastID, westID}, getFeatureCollectionIDs(t, body))
+
+	t.Run("invalid bbox", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems?bbox=170,-10,-170")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestSystems_CQL2Filter(t *testing.T) {
+	cleanupDB(t)
+
+	withLocation := func(name string, lon, lat float64) map[string]interface{} {
+		payload := baseSystemPayload(name)
+		payload["geometry"] = map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []float64{lon, lat},
+		}
+		return payload
+	}
+
+	alphaID := createSystemViaAPI(t, "/systems", withLocation("Alpha", -117.0, 32.0))
+	betaID := createSystemViaAPI(t, "/systems", withLocation("Beta", 10.0, 50.0))
+	gammaPayload := withLocation("Gamma", -118.0, 34.0)
+	gammaPayload["properties"].(map[string]interface{})["featureType"] = "http://www.w3.org/ns/sosa/Platform"
+	gammaID := createSystemViaAPI(t, "/systems", gammaPayload)
+
+	list := func(t *testing.T, filter string) (int, []string) {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems?filter=" + url.QueryEscape(filter))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		return resp.StatusCode, getFeatureCollectionIDs(t, body)
+	}
+
+	tests := map[string]struct {
+		filter string
+		want   []string
+	}{
+		"comparison":       {filter: "name = 'Beta'", want: []string{betaID}},
+		"or":               {filter: "name = 'Alpha' OR name = 'Beta'", want: []string{alphaID, betaID}},
+		"and":              {filter: "name > 'Alpha' AND systemType = 'http://www.w3.org/ns/sosa/Platform'", want: []string{gammaID}},
+		"spatial":          {filter: "S_INTERSECTS(geometry, POLYGON((-120 30, -110 30, -110 40, -120 40, -120 30)))", want: []string{alphaID, gammaID}},
+		"spatial and not":  {filter: "S_INTERSECTS(geometry, POLYGON((-120 30, -110 30, -110 40, -120 40, -120 30))) AND name <> 'Gamma'", want: []string{alphaID}},
+		"literal is bound": {filter: "name = 'x'' OR ''1''=''1'", want: []string{}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, ids := list(t, tc.filter)
+			require.Equal(t, http.StatusOK, status)
+			assert.ElementsMatch(t, tc.want, ids)
+		})
+	}
+
+	t.Run("unsupported constructs", func(t *testing.T) {
+		for _, filter := range []string{"description = 'x'", "name LIKE 'A%'", "name = 'a' AND"} {
+			status, _ := list(t, filter)
+			assert.Equal(t, http.StatusBadRequest, status, filter)
+		}
+	})
+}
+
+func TestSystem_DetailsFull_EmbedsImmediateChildren(t *testing.T) {
+	cleanupDB(t)
+
+	parentID := createSystemViaAPI(t, "/systems", baseSystemPayload("Details Parent"))
+	childID := createSystemViaAPI(t, "/systems/"+parentID+"/subsystems", baseSystemPayload("Details Child"))
+	_ = createSystemViaAPI(t, "/systems/"+childID+"/subsystems", baseSystemPayload("Details Grandchild"))
+	datastreamID := createDatastreamViaAPI(t, "/systems/"+parentID+"/datastreams", baseDatastreamPayload())
+	controlStreamID := createControlStreamViaAPI(t, parentID, baseControlStreamPayload())
+
+	getSystem := func(t *testing.T, query string) (int, map[string]interface{}) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+parentID+query, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/geo+json")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+
+	idsOf := func(t *testing.T, raw interface{}) []string {
+		t.Helper()
+		items, ok := raw.([]interface{})
+		require.True(t, ok, "embedded member must be an array")
+		ids := make([]string, 0, len(items))
+		for _, item := range items {
+			obj, ok := item.(map[string]interface{})
+			require.True(t, ok)
+			id, _ := obj["id"].(string)
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	t.Run("default keeps links only", func(t *testing.T) {
+		status, body := getSystem(t, "")
+		require.Equal(t, http.StatusOK, status)
+		assert.NotContains(t, body, "subsystems")
+		assert.NotContains(t, body, "datastreams")
+		assert.NotContains(t, body, "controlstreams")
+	})
+
+	t.Run("full embeds one level", func(t *testing.T) {
+		status, body := getSystem(t, "?details=full")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, parentID, body["id"])
+
+		assert.Equal(t, []string{childID}, idsOf(t, body["subsystems"]), "only immediate subsystems are embedded")
+		assert.Equal(t, []string{datastreamID}, idsOf(t, body["datastreams"]))
+		assert.Equal(t, []string{controlStreamID}, idsOf(t, body["controlstreams"]))
+
+		child := body["subsystems"].([]interface{})[0].(map[string]interface{})
+		assert.NotContains(t, child, "subsystems", "embedded subsystems must not be expanded further")
+	})
+
+	t.Run("SensorML links the streams", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems/"+parentID+"?details=full", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/sml+json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.Len(t, body["subsystems"], 1)
+		child := body["subsystems"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "PhysicalComponent", child["type"], "subsystems use the system's format")
+
+		assert.NotContains(t, body, "datastreams", "SensorML cannot carry the JSON encoding of datastreams")
+		assert.NotContains(t, body, "controlstreams")
+		datastreamsLink, _ := body["datastreams@link"].(map[string]interface{})
+		assert.Equal(t, testServer.URL+"/systems/"+parentID+"/datastreams", datastreamsLink["href"])
+		assert.Contains(t, body, "controlstreams@link")
+	})
+
+	t.Run("unknown details value", func(t *testing.T) {
+		status, _ := getSystem(t, "?details=everything")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestSystem_GetDetailsFull_CapsEmbeddedItems(t *testing.T) {
+	cleanupDB(t)
+
+	const maxItems = 50
+	parentID := createSystemViaAPI(t, "/systems", baseSystemPayload("Details Cap Parent"))
+	for i := 0; i <= maxItems; i++ {
+		createSystemViaAPI(t, "/systems/"+parentID+"/subsystems", baseSystemPayload(fmt.Sprintf("Details Cap Child %d", i)))
+	}
+
+	resp, err := http.Get(testServer.URL + "/systems/" + parentID + "?details=full")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body["subsystems"], maxItems)
+	next, _ := body["subsystems@link"].(map[string]interface{})
+	assert.Equal(t, fmt.Sprintf("%s/systems/%s/subsystems?offset=%d&limit=%d", testServer.URL, parentID, maxItems, maxItems), next["href"])
+	assert.NotContains(t, body, "datastreams@link", "members within the cap are not linked")
+}
+
+func TestSystem_GetByUID_RedirectsToCanonical(t *testing.T) {
+	cleanupDB(t)
+
+	payload := baseSystemPayload("UID Redirect System")
+	uid := payload["properties"].(map[string]interface{})["uid"].(string)
+	systemID := createSystemViaAPI(t, "/systems", payload)
+
+	noFollow := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	getByUID := func(t *testing.T, escapedUID string) *http.Response {
+		t.Helper()
+		resp, err := noFollow.Get(testServer.URL + "/systems/uid/" + escapedUID)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("url-encoded colons", func(t *testing.T) {
+		resp := getByUID(t, strings.ReplaceAll(uid, ":", "%3A"))
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		assert.True(t, strings.HasSuffix(resp.Header.Get("Location"), "/systems/"+systemID))
+	})
+
+	t.Run("literal colons", func(t *testing.T) {
+		resp := getByUID(t, uid)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		assert.True(t, strings.HasSuffix(resp.Header.Get("Location"), "/systems/"+systemID))
+	})
+
+	t.Run("unknown uid", func(t *testing.T) {
+		resp := getByUID(t, url.PathEscape("urn:uuid:"+uuid.NewString()))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestSystem_PreferReturn(t *testing.T) {
+	cleanupDB(t)
+
+	send := func(t *testing.T, method, path, prefer string, payload map[string]interface{}) (*http.Response, []byte) {
+		t.Helper()
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(method, testServer.URL+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+		req.Header.Set("Accept", "application/geo+json")
+		if prefer != "" {
+			req.Header.Set("Prefer", prefer)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, respBody
+	}
+
+	payload := baseSystemPayload("Prefer System")
+
+	resp, body := send(t, http.MethodPost, "/systems", "return=representation", payload)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "return=representation", resp.Header.Get("Preference-Applied"))
+	location := resp.Header.Get("Location")
+	require.NotEmpty(t, location)
+	systemID := parseID(location, "/systems/")
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, systemID, created["id"])
+
+	t.Run("update with representation", func(t *testing.T) {
+		payload["properties"].(map[string]interface{})["name"] = "Prefer System Renamed"
+		resp, body := send(t, http.MethodPut, "/systems/"+systemID, "return=representation", payload)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "return=representation", resp.Header.Get("Preference-Applied"))
+
+		var updated map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &updated))
+		props, _ := updated["properties"].(map[string]interface{})
+		assert.Equal(t, "Prefer System Renamed", props["name"])
+	})
+
+	t.Run("update with minimal", func(t *testing.T) {
+		resp, body := send(t, http.MethodPut, "/systems/"+systemID, "return=minimal", payload)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "return=minimal", resp.Header.Get("Preference-Applied"))
+		assert.Empty(t, body)
+	})
+
+	t.Run("create without preference", func(t *testing.T) {
+		resp, body := send(t, http.MethodPost, "/systems", "", baseSystemPayload("Prefer Default"))
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Preference-Applied"))
+		assert.Empty(t, body)
+	})
+}
+
+func TestSystems_IDFilterForms(t *testing.T) {
+	cleanupDB(t)
+
+	idA := createSystemViaAPI(t, "/systems", baseSystemPayload("ID Filter A"))
+	idB := createSystemViaAPI(t, "/systems", baseSystemPayload("ID Filter B"))
+	idC := createSystemViaAPI(t, "/systems", baseSystemPayload("ID Filter C"))
+
+	list := func(t *testing.T, query string) []string {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return getFeatureCollectionIDs(t, body)
+	}
+
+	tests := map[string]struct {
+		query string
+		want  []string
+	}{
+		"comma separated":  {query: "id=" + idA + "," + idB, want: []string{idA, idB}},
+		"repeated":         {query: "id=" + idA + "&id=" + idB, want: []string{idA, idB}},
+		"repeated and csv": {query: "id=" + idA + "," + idB + "&id=" + idC, want: []string{idA, idB, idC}},
+		"duplicates":       {query: "id=" + idA + "&id=" + idA + "," + idA, want: []string{idA}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, list(t, tt.query))
+		})
+	}
+}
+
+func TestSystemGeometry_WKB(t *testing.T) {
+	cleanupDB(t)
+
+	payload := baseSystemPayload("WKB System")
+	payload["geometry"] = map[string]interface{}{
+		"type":        "Point",
+		"coordinates": []float64{-117.1625, 32.715, 42.5},
+	}
+	systemID := createSystemViaAPI(t, "/systems", payload)
+
+	get := func(t *testing.T, query string) (*http.Response, []byte) {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems/" + systemID + "/geometry" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	t.Run("hex EWKB keeps Z and SRID", func(t *testing.T) {
+		resp, body := get(t, "?f=wkb")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+
+		decoded, err := ewkbhex.Decode(string(body))
+		require.NoError(t, err)
+		assert.Equal(t, geom.XYZ, decoded.Layout())
+		assert.Equal(t, 4326, decoded.SRID())
+		assert.InDeltaSlice(t, []float64{-117.1625, 32.715, 42.5}, decoded.FlatCoords(), 1e-6)
+
+		// PostGIS reads the output back as the same 3D point
+		var wkt string
+		require.NoError(t, testDB.Raw("SELECT ST_AsText(ST_GeomFromEWKB(decode(?, 'hex')))", string(body)).Scan(&wkt).Error)
+		assert.Equal(t, "POINT Z (-117.1625 32.715 42.5)", wkt)
+	})
+
+	t.Run("GeoJSON by default", func(t *testing.T) {
+		resp, body := get(t, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"type":"Point","coordinates":[-117.1625,32.715,42.5]}`, string(body))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		resp, _ := get(t, "?f=kml")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unknown system", func(t *testing.T) {
+		resp, err := http.Get(testServer.URL + "/systems/does-not-exist/geometry?f=wkb")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestSkipGeometry_Collections(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Skip Geometry System"))
+	createSamplingFeatureViaAPI(t, systemID, baseSamplingFeaturePayload("Skip Geometry Sampling Feature"))
+
+	collection, err := json.Marshal(map[string]interface{}{"id": "skip-geometry", "title": "Skip Geometry"})
+	require.NoError(t, err)
+	resp, err := http.Post(testServer.URL+"/collections", "application/json", bytes.NewReader(collection))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	feature, err := json.Marshal(map[string]interface{}{
+		"type":       "Feature",
+		"properties": map[string]interface{}{"name": "Skip Geometry Feature"},
+		"geometry":   map[string]interface{}{"type": "Point", "coordinates": []float64{1, 2}},
+	})
+	require.NoError(t, err)
+	resp, err = http.Post(testServer.URL+"/collections/skip-geometry/items", "application/json", bytes.NewReader(feature))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	geometries := func(t *testing.T, path string) []json.RawMessage {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var fc struct {
+			Features []map[string]json.RawMessage `json:"features"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&fc))
+		require.NotEmpty(t, fc.Features)
+
+		out := make([]json.RawMessage, 0, len(fc.Features))
+		for _, f := range fc.Features {
+			g, ok := f["geometry"]
+			require.True(t, ok, "geometry member must be present")
+			out = append(out, g)
+		}
+		return out
+	}
+
+	for _, path := range []string{
+		"/systems",
+		"/systems/" + systemID + "/samplingFeatures",
+		"/collections/skip-geometry/items",
+	} {
+		t.Run(path, func(t *testing.T) {
+			for _, g := range geometries(t, path+"?skipGeometry=true") {
+				assert.JSONEq(t, "null", string(g))
+			}
+			for _, g := range geometries(t, path) {
+				assert.NotEqual(t, "null", string(g))
+			}
+		})
+	}
+}
+
+func TestSystems_GeomFilterValidation(t *testing.T) {
+	cleanupDB(t)
+
+	inside := createSystemViaAPI(t, "/systems", baseSystemPayload("Geom Inside"))
+
+	get := func(t *testing.T, geom string) (int, []byte) {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems?geom=" + url.QueryEscape(geom))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, body
+	}
+
+	t.Run("malformed WKT", func(t *testing.T) {
+		status, body := get(t, "POINT(broken")
+		require.Equal(t, http.StatusBadRequest, status)
+
+		var problem map[string]string
+		require.NoError(t, json.Unmarshal(body, &problem))
+		assert.Contains(t, problem["error"], "invalid geom WKT")
+	})
+
+	t.Run("valid WKT", func(t *testing.T) {
+		status, body := get(t, "POLYGON((-118 32,-116 32,-116 34,-118 34,-118 32))")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{inside}, getFeatureCollectionIDs(t, body))
+	})
+}
+
+func TestSystems_EchoQuery(t *testing.T) {
+	cleanupDB(t)
+
+	createSystemViaAPI(t, "/systems", baseSystemPayload("Echo System"))
+
+	get := func(t *testing.T, query string) map[string]json.RawMessage {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]json.RawMessage
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	t.Run("absent by default", func(t *testing.T) {
+		body := get(t, "limit=5")
+		assert.NotContains(t, body, "query")
+	})
+
+	t.Run("effective parameters", func(t *testing.T) {
+		body := get(t, "echo=true&bbox=-120,30,-110,40&id=a,b&id=b")
+		require.Contains(t, body, "query")
+		assert.JSONEq(t, `{
+			"id": ["a", "b"],
+			"limit": 10,
+			"offset": 0,
+			"bbox": [-120, 30, -110, 40],
+			"datetimeOp": "intersects"
+		}`, string(body["query"]))
+	})
+}
+
+func TestPagination_OffsetBeyondTotal(t *testing.T) {
+	cleanupDB(t)
+
+	for i := 0; i < 3; i++ {
+		createSystemViaAPI(t, "/systems", baseSystemPayload(fmt.Sprintf("Paged System %d", i)))
+	}
+
+	resp, err := http.Get(testServer.URL + "/systems?limit=2&offset=10")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var collection struct {
+		Features       []json.RawMessage `json:"features"`
+		NumberMatched  *int              `json:"numberMatched"`
+		NumberReturned int               `json:"numberReturned"`
+		Links          []struct {
+			Rel  string `json:"rel"`
+			Href string `json:"href"`
+		} `json:"links"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+
+	require.NotNil(t, collection.Features, "features must be an empty array, not null")
+	assert.Empty(t, collection.Features)
+	require.NotNil(t, collection.NumberMatched)
+	assert.Equal(t, 3, *collection.NumberMatched)
+	assert.Equal(t, 0, collection.NumberReturned)
+	for _, link := range collection.Links {
+		assert.NotEqual(t, "next", link.Rel, "no next link past the end")
+		if link.Rel == "prev" {
+			assert.True(t, strings.HasSuffix(link.Href, "offset=1"), "prev should point at the last page, got %s", link.Href)
+		}
+	}
+
+	// Non-feature collections page the same way.
+	dsResp, err := http.Get(testServer.URL + "/datastreams?offset=50")
+	require.NoError(t, err)
+	defer dsResp.Body.Close()
+	require.Equal(t, http.StatusOK, dsResp.StatusCode)
+
+	var items struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	require.NoError(t, json.NewDecoder(dsResp.Body).Decode(&items))
+	require.NotNil(t, items.Items, "items must be an empty array, not null")
+	assert.Empty(t, items.Items)
+}
+
+func TestSystems_CollectionLinks(t *testing.T) {
+	cleanupDB(t)
+
+	for i := 0; i < 3; i++ {
+		createSystemViaAPI(t, "/systems", baseSystemPayload(fmt.Sprintf("Linked System %d", i)))
+	}
+
+	fetchLinks := func(t *testing.T, path string) map[string]string {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var collection struct {
+			Links []struct {
+				Rel  string `json:"rel"`
+				Href string `json:"href"`
+			} `json:"links"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+
+		byRel := map[string]string{}
+		for _, link := range collection.Links {
+			byRel[link.Rel] = link.Href
+		}
+		return byRel
+	}
+
+	t.Run("self matches the request", func(t *testing.T) {
+		links := fetchLinks(t, "/systems?limit=1&offset=1")
+		assert.Equal(t, testServer.URL+"/systems?limit=1&offset=1", links["self"])
+		assert.Equal(t, testServer.URL+"/systems?limit=1&offset=2", links["next"])
+		assert.Equal(t, testServer.URL+"/systems?limit=1", links["prev"])
+	})
+
+	t.Run("all formats", func(t *testing.T) {
+		for _, f := range []string{"geojson", "sml"} {
+			links := fetchLinks(t, "/systems?f="+f)
+			assert.Equal(t, testServer.URL+"/systems?f="+f, links["self"], f)
+			assert.NotContains(t, links, "next", f)
+		}
+	})
+
+	t.Run("same shape as sampling features", func(t *testing.T) {
+		systemLinks := fetchLinks(t, "/systems")
+		samplingFeatureLinks := fetchLinks(t, "/samplingFeatures")
+		assert.Equal(t, testServer.URL+"/systems", systemLinks["self"])
+		assert.Equal(t, testServer.URL+"/samplingFeatures", samplingFeatureLinks["self"])
+		assert.Len(t, systemLinks, len(samplingFeatureLinks))
+	})
+}
+
+func TestSystems_ParentNullListsRootSystems(t *testing.T) {
+	cleanupDB(t)
+
+	rootA := createSystemViaAPI(t, "/systems", baseSystemPayload("Root A"))
+	rootB := createSystemViaAPI(t, "/systems", baseSystemPayload("Root B"))
+	child := createSystemViaAPI(t, "/systems/"+rootA+"/subsystems", baseSystemPayload("Child of A"))
+
+	list := func(t *testing.T, query string) (int, []string) {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		return resp.StatusCode, getFeatureCollectionIDs(t, body)
+	}
+
+	for _, query := range []string{"parent=null", "rootOnly=true", "parent=null&recursive=true", "rootOnly=true&recursive=true"} {
+		t.Run(query, func(t *testing.T) {
+			status, ids := list(t, query)
+			require.Equal(t, http.StatusOK, status)
+			assert.ElementsMatch(t, []string{rootA, rootB}, ids)
+		})
+	}
+
+	t.Run("recursive without parent filter includes children", func(t *testing.T) {
+		status, ids := list(t, "recursive=true")
+		require.Equal(t, http.StatusOK, status)
+		assert.ElementsMatch(t, []string{rootA, rootB, child}, ids)
+	})
+
+	t.Run("parent id lists its children", func(t *testing.T) {
+		status, ids := list(t, "parent="+rootA)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{child}, ids)
+	})
+
+	t.Run("invalid rootOnly", func(t *testing.T) {
+		status, _ := list(t, "rootOnly=sometimes")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+func TestSystems_CSVExport(t *testing.T) {
+	cleanupDB(t)
+
+	pointID := createSystemViaAPI(t, "/systems", baseSystemPayload("Point, \"quoted\""))
+	polygonPayload := baseSystemPayload("Polygon")
+	polygonPayload["geometry"] = map[string]interface{}{
+		"type":        "Polygon",
+		"coordinates": [][][]float64{{{0, 0}, {4, 0}, {4, 2}, {0, 2}, {0, 0}}},
+	}
+	polygonID := createSystemViaAPI(t, "/systems", polygonPayload)
+	noLocationPayload := baseSystemPayload("Nowhere")
+	delete(noLocationPayload, "geometry")
+	noLocationID := createSystemViaAPI(t, "/systems", noLocationPayload)
+
+	getCSV := func(t *testing.T, path, accept string) [][]string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+		require.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Values("Vary"), "Accept")
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.NotEmpty(t, records)
+		assert.Equal(t, []string{"id", "uid", "name", "systemType", "lon", "lat", "created"}, records[0])
+		return records[1:]
+	}
+
+	t.Run("f=csv", func(t *testing.T) {
+		rows := getCSV(t, "/systems?f=csv", "")
+		byID := make(map[string][]string, len(rows))
+		for _, row := range rows {
+			require.Len(t, row, 7)
+			byID[row[0]] = row
+		}
+		require.Len(t, byID, 3)
+
+		point := byID[pointID]
+		assert.Equal(t, "Point, \"quoted\"", point[2])
+		assert.Equal(t, "http://www.w3.org/ns/sosa/Sensor", point[3])
+		assert.Equal(t, []string{"-117.1625", "32.715"}, point[4:6])
+		_, err := time.Parse(time.RFC3339, point[6])
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"2", "1"}, byID[polygonID][4:6], "polygons are reduced to their centroid")
+		assert.Equal(t, []string{"", ""}, byID[noLocationID][4:6])
+	})
+
+	t.Run("Accept text/csv with filters", func(t *testing.T) {
+		rows := getCSV(t, "/systems?q=Polygon", "text/csv")
+		require.Len(t, rows, 1)
+		assert.Equal(t, polygonID, rows[0][0])
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		assert.Len(t, getCSV(t, "/systems?limit=2", "text/csv"), 2)
+	})
+
+	t.Run("JSON preferred", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/systems", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/geo+json, text/csv;q=0.5")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "application/geo+json"))
+	})
+
+	t.Run("formula-like text is escaped", func(t *testing.T) {
+		formulaID := createSystemViaAPI(t, "/systems", baseSystemPayload(`=HYPERLINK("http://example.com")`))
+		rows := getCSV(t, "/systems?q=HYPERLINK", "text/csv")
+		require.Len(t, rows, 1)
+		assert.Equal(t, formulaID, rows[0][0])
+		assert.Equal(t, `'=HYPERLINK("http://example.com")`, rows[0][2])
+	})
+}
+
+func TestSystems_NDJSONExport(t *testing.T) {
+	cleanupDB(t)
+
+	pointID := createSystemViaAPI(t, "/systems", baseSystemPayload("Point"))
+	otherID := createSystemViaAPI(t, "/systems", baseSystemPayload("Other"))
+	noLocationPayload := baseSystemPayload("Nowhere")
+	delete(noLocationPayload, "geometry")
+	noLocationID := createSystemViaAPI(t, "/systems", noLocationPayload)
+
+	getNDJSON := func(t *testing.T, path, accept string) []map[string]json.RawMessage {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+		require.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-ndjson; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Values("Vary"), "Accept")
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if len(body) == 0 {
+			return nil
+		}
+		require.True(t, bytes.HasSuffix(body, []byte("\n")), "every line ends with a newline")
+
+		var features []map[string]json.RawMessage
+		for _, line := range bytes.Split(bytes.TrimSuffix(body, []byte("\n")), []byte("\n")) {
+			requireSchemaOrSkip(t, line, SystemGeoSchema)
+			var feature map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(line, &feature), "each line is one JSON document")
+			assert.JSONEq(t, `"Feature"`, string(feature["type"]))
+			features = append(features, feature)
+		}
+		return features
+	}
+	ids := func(features []map[string]json.RawMessage) []string {
+		out := make([]string, 0, len(features))
+		for _, feature := range features {
+			var id string
+			require.NoError(t, json.Unmarshal(feature["id"], &id))
+			out = append(out, id)
+		}
+		return out
+	}
+
+	t.Run("Accept application/x-ndjson", func(t *testing.T) {
+		features := getNDJSON(t, "/systems", "application/x-ndjson")
+		assert.ElementsMatch(t, []string{pointID, otherID, noLocationID}, ids(features))
+	})
+
+	t.Run("f=ndjson with filters", func(t *testing.T) {
+		features := getNDJSON(t, "/systems?f=ndjson&q=Nowhere", "")
+		require.Len(t, features, 1)
+		assert.Equal(t, []string{noLocationID}, ids(features))
+		assert.JSONEq(t, "null", string(features[0]["geometry"]))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		assert.Len(t, getNDJSON(t, "/systems?limit=2", "application/x-ndjson"), 2)
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		assert.Empty(t, getNDJSON(t, "/systems?q=Missing", "application/x-ndjson"))
+	})
+}
+
+// Unique identifiers are unique per resource type: a system and a procedure
+// (or any other resource type) may share a URN, while a second system may not.
+func TestSystem_UIDSharedWithOtherResourceTypes(t *testing.T) {
+	cleanupDB(t)
+
+	uid := "urn:uuid:" + uuid.NewString()
+	withUID := func(payload map[string]interface{}) map[string]interface{} {
+		payload["properties"].(map[string]interface{})["uid"] = uid
+		return payload
+	}
+
+	systemID := createSystemViaAPI(t, "/systems", withUID(baseSystemPayload("Shared UID System")))
+	procedureID := createProcedureViaAPI(t, map[string]interface{}{
+		"type": "Feature",
+		"properties": map[string]interface{}{
+			"uid":         uid,
+			"name":        "Shared UID Procedure",
+			"featureType": "http://www.w3.org/ns/sosa/Procedure",
+		},
+	})
+	sfID := createSamplingFeatureViaAPI(t, systemID, withUID(baseSamplingFeaturePayload("Shared UID Sampling Feature")))
+
+	for path, id := range map[string]string{
+		"/systems/" + systemID:       systemID,
+		"/procedures/" + procedureID: procedureID,
+		"/samplingFeatures/" + sfID:  sfID,
+	} {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/geo+json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+
+		var feature map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &feature))
+		assert.Equal(t, id, feature["id"], path)
+		assert.Equal(t, uid, feature["properties"].(map[string]interface{})["uid"], path)
+	}
+
+	body, err := json.Marshal(withUID(baseSystemPayload("Duplicate UID System")))
+	require.NoError(t, err)
+	resp, err := http.Post(testServer.URL+"/systems", "application/geo+json", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+// A system without validTime is valid at all times, so every datetime
+// filter that an unbounded validity can satisfy includes it.
+func TestSystems_DatetimeIncludesSystemsWithoutValidTime(t *testing.T) {
+	cleanupDB(t)
+
+	alwaysValid := createSystemViaAPI(t, "/systems", baseSystemPayload("Always Valid System"))
+	pastValid := createSystemViaAPI(t, "/systems", baseSystemWithValidTimePayload("Past Valid System", "2020-01-01T00:00:00Z", "2020-12-31T00:00:00Z"))
+
+	list := func(t *testing.T, query string) []string {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + "/systems?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		return getFeatureCollectionIDs(t, body)
+	}
+
+	for query, want := range map[string][]string{
+		"datetime=2020-06-01T00:00:00Z/2020-06-02T00:00:00Z":                     {alwaysValid, pastValid},
+		"datetime=2030-01-01T00:00:00Z/2030-01-02T00:00:00Z":                     {alwaysValid},
+		"datetime=1990-01-01T00:00:00Z/1991-01-01T00:00:00Z":                     {alwaysValid},
+		"datetime=../2020-06-01T00:00:00Z":                                       {alwaysValid, pastValid},
+		"datetime=2025-01-01T00:00:00Z/..":                                       {alwaysValid},
+		"dateTime=1990-01-01T00:00:00Z/2030-01-01T00:00:00Z&datetimeOp=contains": {alwaysValid},
+	} {
+		t.Run(query, func(t *testing.T) {
+			assert.ElementsMatch(t, want, list(t, query))
+		})
+	}
+}
+
+// TestSystem_OutputCRS checks that ?crs reprojects returned geometries to Web
+// Mercator and names the CRS in Content-Crs, without touching stored data.
+func TestSystem_OutputCRS(t *testing.T) {
+	cleanupDB(t)
+
+	payload := baseSystemPayload("Mercator Sensor")
+	payload["geometry"] = map[string]interface{}{"type": "Point", "coordinates": []float64{10, 0}}
+	id := createSystemViaAPI(t, "/systems", payload)
+
+	coordinates := func(path string, wantContentCRS string) []interface{} {
+		t.Helper()
+		resp := doGet(t, path)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, wantContentCRS, resp.Header.Get("Content-Crs"))
+		var feature struct {
+			Geometry struct {
+				Coordinates []interface{} `json:"coordinates"`
+			} `json:"geometry"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&feature))
+		require.Len(t, feature.Geometry.Coordinates, 2)
+		return feature.Geometry.Coordinates
+	}
+
+	mercator := coordinates("/systems/"+id+"?crs="+url.QueryEscape("http://www.opengis.net/def/crs/EPSG/0/3857"), "<http://www.opengis.net/def/crs/EPSG/0/3857>")
+	assert.InDelta(t, 1113194.91, mercator[0], 0.01)
+	assert.InDelta(t, 0, mercator[1], 0.01)
+
+	stored := coordinates("/systems/"+id, "")
+	assert.InDelta(t, 10, stored[0], 1e-9)
+	assert.InDelta(t, 0, stored[1], 1e-9)
+
+	resp := doGet(t, "/systems?crs="+url.QueryEscape("http://www.opengis.net/def/crs/EPSG/0/3857"))
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.Contains(t, getFeatureCollectionIDs(t, body), id)
+
+	t.Run("streamed exports are reprojected", func(t *testing.T) {
+		resp := doGet(t, "/systems?f=ndjson&crs="+url.QueryEscape("http://www.opengis.net/def/crs/EPSG/0/3857"))
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "<http://www.opengis.net/def/crs/EPSG/0/3857>", resp.Header.Get("Content-Crs"))
+		var feature struct {
+			Geometry struct {
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&feature))
+		require.Len(t, feature.Geometry.Coordinates, 2)
+		assert.InDelta(t, 1113194.91, feature.Geometry.Coordinates[0], 0.01)
+
+		resp = doGet(t, "/systems?f=csv&crs="+url.QueryEscape("http://www.opengis.net/def/crs/EPSG/0/3857"))
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "<http://www.opengis.net/def/crs/EPSG/0/3857>", resp.Header.Get("Content-Crs"))
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		lon, err := strconv.ParseFloat(records[1][4], 64)
+		require.NoError(t, err)
+		assert.InDelta(t, 1113194.91, lon, 0.01)
+	})
+
+	t.Run("responses without geometries name no crs", func(t *testing.T) {
+		resp := doGet(t, "/datastreams?crs="+url.QueryEscape("http://www.opengis.net/def/crs/EPSG/0/3857"))
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Content-Crs"))
+	})
+
+	bad := doGet(t, "/systems/"+id+"?crs="+url.QueryEscape("http://www.opengis.net/def/crs/EPSG/0/32611"))
+	bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+}
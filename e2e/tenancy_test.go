@@ -0,0 +1,162 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/api"
+	"go.uber.org/zap"
+)
+
+func TestTenancy_IsolatesTenants(t *testing.T) {
+	cleanupDB(t)
+
+	cfg := *testConfig
+	cfg.API.Tenancy.Enabled = true
+	server := httptest.NewServer(api.NewRouter(&cfg, zap.NewNop(), testRepos))
+	defer server.Close()
+
+	sendAs := func(t *testing.T, method, path, tenantID, contentType, accept string, payload interface{}) (*http.Response, []byte) {
+		t.Helper()
+		var body io.Reader
+		if payload != nil {
+			encoded, err := json.Marshal(payload)
+			require.NoError(t, err)
+			body = bytes.NewReader(encoded)
+		}
+		req, err := http.NewRequest(method, server.URL+path, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if tenantID != "" {
+			req.Header.Set("X-Tenant-ID", tenantID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, respBody
+	}
+	send := func(t *testing.T, method, path, tenantID string, payload interface{}) (*http.Response, []byte) {
+		t.Helper()
+		return sendAs(t, method, path, tenantID, "application/geo+json", "", payload)
+	}
+	create := func(t *testing.T, tenantID, name string) string {
+		t.Helper()
+		resp, body := send(t, http.MethodPost, "/systems", tenantID, baseSystemPayload(name))
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+		id := parseID(resp.Header.Get("Location"), "/systems/")
+		require.NotEmpty(t, id)
+		return id
+	}
+
+	systemA := create(t, "team-a", "Team A Sensor")
+	systemB := create(t, "team-b", "Team B Sensor")
+
+	t.Run("requests without a tenant are rejected", func(t *testing.T) {
+		resp, _ := send(t, http.MethodGet, "/systems", "", nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = send(t, http.MethodGet, "/conformance", "", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("listings only include the tenant's resources", func(t *testing.T) {
+		resp, body := send(t, http.MethodGet, "/systems", "team-a", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.ElementsMatch(t, []string{systemA}, getFeatureCollectionIDs(t, body))
+	})
+
+	t.Run("another tenant's resource is not found", func(t *testing.T) {
+		resp, _ := send(t, http.MethodGet, "/systems/"+systemB, "team-a", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, _ = send(t, http.MethodPut, "/systems/"+systemB, "team-a", baseSystemPayload("Hijacked"))
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		// Deleting is answered exactly like deleting an unknown id, and has no effect
+		unknown, _ := send(t, http.MethodDelete, "/systems/no-such-system", "team-a", nil)
+		resp, _ = send(t, http.MethodDelete, "/systems/"+systemB, "team-a", nil)
+		assert.Equal(t, unknown.StatusCode, resp.StatusCode)
+
+		resp, body := send(t, http.MethodGet, "/systems/"+systemB, "team-b", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), "Team B Sensor")
+	})
+
+	t.Run("exports only include the tenant's resources", func(t *testing.T) {
+		resp, body := sendAs(t, http.MethodGet, "/systems", "team-a", "", "text/csv", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), systemA)
+		assert.NotContains(t, string(body), systemB)
+
+		resp, body = sendAs(t, http.MethodGet, "/systems", "team-a", "", "application/x-ndjson", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), systemA)
+		assert.NotContains(t, string(body), systemB)
+	})
+
+	t.Run("unique identifiers are unique per tenant", func(t *testing.T) {
+		payload := baseSystemPayload("Team A Shared UID")
+		resp, body := send(t, http.MethodPost, "/systems", "team-a", payload)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+		payload["properties"].(map[string]interface{})["name"] = "Team B Shared UID"
+		resp, body = send(t, http.MethodPost, "/systems", "team-b", payload)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode, "another tenant's uid must not conflict: %s", body)
+
+		resp, _ = send(t, http.MethodPost, "/systems", "team-a", payload)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("collection ids are generated", func(t *testing.T) {
+		collection := map[string]interface{}{"id": "team-a-collection", "title": "Team A"}
+		resp, body := sendAs(t, http.MethodPost, "/collections", "team-a", "application/json", "application/json", collection)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+		var created map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &created))
+		assert.NotEqual(t, "team-a-collection", created["id"])
+
+		// The same id from another tenant is not answered with a conflict
+		resp, body = sendAs(t, http.MethodPost, "/collections", "team-b", "application/json", "application/json", collection)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+	})
+
+	t.Run("observation listings only include the tenant's observations", func(t *testing.T) {
+		observe := func(t *testing.T, tenantID, systemID string) (string, string) {
+			t.Helper()
+			resp, body := sendAs(t, http.MethodPost, "/systems/"+systemID+"/datastreams", tenantID, "application/json", "", baseDatastreamPayload())
+			require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+			datastreamID := parseID(resp.Header.Get("Location"), "/datastreams/")
+			require.NotEmpty(t, datastreamID)
+
+			observation := map[string]interface{}{
+				"resultTime": "2026-03-13T10:00:00Z",
+				"result":     map[string]interface{}{"temperature": 21.4, "humidity": 57.9},
+			}
+			resp, body = sendAs(t, http.MethodPost, "/datastreams/"+datastreamID+"/observations", tenantID, "application/json", "", observation)
+			require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+			observationID := parseID(resp.Header.Get("Location"), "/observations/")
+			require.NotEmpty(t, observationID)
+			return datastreamID, observationID
+		}
+		datastreamA, observationA := observe(t, "team-a", systemA)
+		_, observationB := observe(t, "team-b", systemB)
+
+		for _, path := range []string{"/observations", "/datastreams/" + datastreamA + "/observations"} {
+			resp, body := sendAs(t, http.MethodGet, path, "team-a", "", "application/json", nil)
+			require.Equal(t, http.StatusOK, resp.StatusCode, path)
+			assert.Contains(t, string(body), observationA, path)
+			assert.NotContains(t, string(body), observationB, path)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package e2e
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Every route answers identically with or without a trailing slash; the
+// canonical form, used in generated links, has none.
+func TestTrailingSlash_BothFormsServeTheSameResource(t *testing.T) {
+	cleanupDB(t)
+
+	systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Trailing Slash System"))
+	subsystemID := createSystemViaAPI(t, "/systems/"+systemID+"/subsystems/", baseSystemPayload("Trailing Slash Subsystem"))
+
+	get := func(t *testing.T, path string) (int, []byte) {
+		t.Helper()
+		resp, err := http.Get(testServer.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, body
+	}
+
+	paths := []string{
+		"/conformance",
+		"/collections",
+		"/systems",
+		"/systems/" + systemID,
+		"/systems/" + systemID + "/subsystems",
+		"/procedures",
+		"/samplingFeatures",
+		"/datastreams",
+		"/observations",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			status, body := get(t, path)
+			slashStatus, slashBody := get(t, path+"/")
+			require.Equal(t, http.StatusOK, status, "%s: %s", path, body)
+			assert.Equal(t, status, slashStatus, "%s/: %s", path, slashBody)
+			assert.JSONEq(t, string(withoutTimeStamp(t, body)), string(withoutTimeStamp(t, slashBody)))
+		})
+	}
+
+	t.Run("subsystem created through the slashed path", func(t *testing.T) {
+		status, body := get(t, "/systems/"+systemID+"/subsystems/")
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, getFeatureCollectionIDs(t, body), subsystemID)
+	})
+
+	t.Run("generated links use the canonical form", func(t *testing.T) {
+		status, body := get(t, "/systems/"+systemID+"/")
+		require.Equal(t, http.StatusOK, status)
+
+		var feature struct {
+			Links []struct {
+				Rel  string `json:"rel"`
+				Href string `json:"href"`
+			} `json:"links"`
+		}
+		require.NoError(t, json.Unmarshal(body, &feature))
+		for _, link := range feature.Links {
+			if link.Rel == "self" {
+				assert.False(t, strings.Contains(link.Href, systemID+"/?") || strings.HasSuffix(link.Href, "/"), "self: %s", link.Href)
+			}
+		}
+	})
+
+	t.Run("unknown routes stay 404", func(t *testing.T) {
+		status, _ := get(t, "/does-not-exist/")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
+
+// withoutTimeStamp drops the generation time of a collection response, which
+// legitimately differs between two requests.
+func withoutTimeStamp(t *testing.T, body []byte) []byte {
+	t.Helper()
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return body
+	}
+	delete(doc, "timeStamp")
+	out, err := json.Marshal(doc)
+	require.NoError(t, err)
+	return out
+}
@@ -0,0 +1,239 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+)
+
+type validationReport struct {
+	Valid  bool `json:"valid"`
+	Errors []struct {
+		Path    string `json:"path"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// postValidateOnly sends a create request that must only be validated and
+// decodes the returned report.
+func postValidateOnly(t *testing.T, path, contentType string, body []byte, header http.Header) validationReport {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, testServer.URL+path, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Location"))
+
+	var report validationReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	return report
+}
+
+func TestCreate_ValidateOnly(t *testing.T) {
+	cleanupDB(t)
+
+	countRows := func(t *testing.T, model interface{}) int64 {
+		t.Helper()
+		var count int64
+		require.NoError(t, testDB.Model(model).Count(&count).Error)
+		return count
+	}
+
+	t.Run("valid system is reported and not stored", func(t *testing.T) {
+		body, err := json.Marshal(baseSystemPayload("Validate Only System"))
+		require.NoError(t, err)
+
+		report := postValidateOnly(t, "/systems?validate=true", "application/geo+json", body, nil)
+		assert.True(t, report.Valid)
+		assert.Empty(t, report.Errors)
+		assert.Zero(t, countRows(t, &domains.System{}))
+	})
+
+	t.Run("Prefer handling=strict with validate", func(t *testing.T) {
+		body, err := json.Marshal(baseSystemPayload("Validate Only Prefer"))
+		require.NoError(t, err)
+
+		report := postValidateOnly(t, "/systems", "application/geo+json", body, http.Header{"Prefer": {"handling=strict; validate"}})
+		assert.True(t, report.Valid)
+		assert.Zero(t, countRows(t, &domains.System{}))
+	})
+
+	t.Run("malformed body is reported", func(t *testing.T) {
+		report := postValidateOnly(t, "/systems?validate=true", "application/geo+json", []byte(`{"type":`), nil)
+		assert.False(t, report.Valid)
+		require.NotEmpty(t, report.Errors)
+	})
+
+	t.Run("sampling feature without parent system", func(t *testing.T) {
+		body, err := json.Marshal(map[string]interface{}{
+			"type":     "Feature",
+			"geometry": map[string]interface{}{"type": "Point", "coordinates": []float64{-117.1, 32.7}},
+			"properties": map[string]interface{}{
+				"uid":         "urn:uuid:" + uuid.NewString(),
+				"name":        "Orphan Sampling Feature",
+				"featureType": "http://www.w3.org/ns/sosa/Sample",
+			},
+		})
+		require.NoError(t, err)
+
+		report := postValidateOnly(t, "/samplingFeatures?validate=true", "application/geo+json", body, nil)
+		assert.False(t, report.Valid)
+		require.Len(t, report.Errors, 1)
+		assert.Equal(t, "parentSystem@link", report.Errors[0].Path)
+	})
+
+	t.Run("property reports every violation at once", func(t *testing.T) {
+		uid := "urn:test:property:validate:" + uuid.NewString()
+		payload := func() []byte {
+			body, err := json.Marshal(map[string]interface{}{
+				"label":        "Validate Only Property",
+				"uniqueId":     uid,
+				"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+				"qualifiers": []interface{}{
+					map[string]interface{}{
+						"type":       "Quantity",
+						"label":      "Accuracy",
+						"definition": "http://sensorml.com/ont/swe/property/Accuracy",
+						"uom":        map[string]interface{}{"code": "m s"},
+					},
+				},
+			})
+			require.NoError(t, err)
+			return body
+		}
+
+		existing, err := json.Marshal(map[string]interface{}{
+			"label":        "Existing Property",
+			"uniqueId":     uid,
+			"baseProperty": "https://qudt.org/vocab/quantitykind/Temperature",
+		})
+		require.NoError(t, err)
+		resp, err := http.Post(testServer.URL+"/properties", "application/sml+json", bytes.NewReader(existing))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		report := postValidateOnly(t, "/properties?validate=true", "application/sml+json", payload(), nil)
+		assert.False(t, report.Valid)
+
+		paths := make([]string, 0, len(report.Errors))
+		for _, violation := range report.Errors {
+			paths = append(paths, violation.Path)
+		}
+		assert.Contains(t, paths, "qualifiers[0].uom.code")
+		assert.Contains(t, paths, "uniqueId")
+		assert.Equal(t, int64(1), countRows(t, &domains.Property{}))
+	})
+
+	t.Run("system with a taken uid", func(t *testing.T) {
+		payload := baseSystemPayload("Validate Only Existing")
+		createSystemViaAPI(t, "/systems", payload)
+		parentID := createSystemViaAPI(t, "/systems", baseSystemPayload("Validate Only Parent"))
+		before := countRows(t, &domains.System{})
+
+		payload["properties"].(map[string]interface{})["name"] = "Validate Only Duplicate"
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		for _, path := range []string{"/systems?validate=true", "/systems/" + parentID + "/subsystems?validate=true"} {
+			report := postValidateOnly(t, path, "application/geo+json", body, nil)
+			assert.False(t, report.Valid, path)
+			require.Len(t, report.Errors, 1, path)
+			assert.Equal(t, "uniqueId", report.Errors[0].Path)
+		}
+		assert.Equal(t, before, countRows(t, &domains.System{}))
+	})
+
+	t.Run("datastream with a taken uid", func(t *testing.T) {
+		systemID := createSystemViaAPI(t, "/systems", baseSystemPayload("Validate Only Datastream System"))
+		payload := baseDatastreamPayload()
+		createDatastreamViaAPI(t, "/systems/"+systemID+"/datastreams", payload)
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		report := postValidateOnly(t, "/systems/"+systemID+"/datastreams?validate=true", "application/json", body, nil)
+		assert.False(t, report.Valid)
+		require.Len(t, report.Errors, 1)
+		assert.Equal(t, "uniqueId", report.Errors[0].Path)
+	})
+
+	t.Run("unknown parent is not found", func(t *testing.T) {
+		missing := uuid.NewString()
+		systemBody, err := json.Marshal(baseSystemPayload("Validate Only Orphan"))
+		require.NoError(t, err)
+		datastreamBody, err := json.Marshal(baseDatastreamPayload())
+		require.NoError(t, err)
+		deploymentBody, err := json.Marshal(baseDeploymentPayload("Validate Only Orphan", missing))
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			path        string
+			contentType string
+			body        []byte
+		}{
+			{"/systems/" + missing + "/subsystems", "application/geo+json", systemBody},
+			{"/systems/" + missing + "/datastreams", "application/json", datastreamBody},
+			{"/deployments/" + missing + "/subdeployments", "application/geo+json", deploymentBody},
+		} {
+			for _, query := range []string{"?validate=true", ""} {
+				resp, err := http.Post(testServer.URL+tc.path+query, tc.contentType, bytes.NewReader(tc.body))
+				require.NoError(t, err)
+				resp.Body.Close()
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode, tc.path+query)
+			}
+		}
+	})
+
+	t.Run("observation batch reports all failing records", func(t *testing.T) {
+		datastream := seedDatastreamForObservationTests(t)
+		record := func(temperature interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"resultTime": "2026-03-13T10:00:00Z",
+				"result":     map[string]interface{}{"temperature": temperature, "humidity": 50.0},
+			}
+		}
+		body, err := json.Marshal([]interface{}{record("hot"), record(20.0), record("cold")})
+		require.NoError(t, err)
+
+		report := postValidateOnly(t, "/datastreams/"+datastream.ID+"/observations?validate=true", "application/json", body, nil)
+		assert.False(t, report.Valid)
+		require.Len(t, report.Errors, 2)
+		assert.Equal(t, "[0].result.temperature", report.Errors[0].Path)
+		assert.Equal(t, "[2].result.temperature", report.Errors[1].Path)
+		assert.Zero(t, countRows(t, &domains.Observation{}))
+	})
+
+	t.Run("does not consume the idempotency key", func(t *testing.T) {
+		body, err := json.Marshal(baseSystemPayload("Validate Only Idempotent"))
+		require.NoError(t, err)
+		key := uuid.NewString()
+
+		report := postValidateOnly(t, "/systems?validate=true", "application/geo+json", body, http.Header{"Idempotency-Key": {key}})
+		assert.True(t, report.Valid)
+
+		req, err := http.NewRequest(http.MethodPost, testServer.URL+"/systems", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/geo+json")
+		req.Header.Set("Idempotency-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Location"))
+	})
+}
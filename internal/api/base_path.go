@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// normalizeBasePath returns path with a single leading slash and no trailing
+// one, or "" when the API is served at the root.
+func normalizeBasePath(path string) string {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if path == "" {
+		return ""
+	}
+	return "/" + path
+}
+
+// mountAtBasePath serves next under basePath, stripping the prefix so routes,
+// middleware and handlers see root-relative paths. Requests outside the prefix
+// are answered with 404.
+func mountAtBasePath(basePath string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if path != basePath && !strings.HasPrefix(path, basePath+"/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		stripped := new(http.Request)
+		*stripped = *r
+		stripped.URL = new(url.URL)
+		*stripped.URL = *r.URL
+		stripped.URL.Path = strings.TrimPrefix(path, basePath)
+		if stripped.URL.Path == "" {
+			stripped.URL.Path = "/"
+		}
+		if r.URL.RawPath != "" {
+			stripped.URL.RawPath = strings.TrimPrefix(r.URL.RawPath, basePath)
+		}
+		next.ServeHTTP(w, stripped)
+	})
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeBasePath(t *testing.T) {
+	for in, want := range map[string]string{
+		"":         "",
+		"/":        "",
+		"csapi":    "/csapi",
+		"/csapi/":  "/csapi",
+		" /api/v1": "/api/v1",
+	} {
+		assert.Equal(t, want, normalizeBasePath(in), in)
+	}
+}
+
+func TestMountAtBasePath(t *testing.T) {
+	echoPath := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	})
+	handler := mountAtBasePath("/csapi", echoPath)
+
+	tests := map[string]struct {
+		path     string
+		wantCode int
+		wantPath string
+	}{
+		"prefixed route":  {path: "/csapi/systems", wantCode: http.StatusOK, wantPath: "/systems"},
+		"prefix only":     {path: "/csapi", wantCode: http.StatusOK, wantPath: "/"},
+		"prefix with /":   {path: "/csapi/", wantCode: http.StatusOK, wantPath: "/"},
+		"root route":      {path: "/systems", wantCode: http.StatusNotFound},
+		"partial segment": {path: "/csapix/systems", wantCode: http.StatusNotFound},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.wantPath, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxRequestBodySize is the request body limit used when none is configured.
+const DefaultMaxRequestBodySize int64 = 10 << 20
+
+type bodyLimitContextKey struct{}
+
+// MaxBodySize limits every request body to limit bytes. Handlers reading past
+// the limit get an *http.MaxBytesError, and whatever error response they write
+// is replaced with 413 Payload Too Large. A body whose Content-Length already
+// exceeds the limit fails on the first read. Individual routes can raise or
+// lower the limit with WithMaxBodySize.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body := &limitedBody{body: r.Body, limit: limit, contentLength: r.ContentLength}
+			r.Body = body
+			r = r.WithContext(context.WithValue(r.Context(), bodyLimitContextKey{}, body))
+			next.ServeHTTP(&bodyLimitWriter{ResponseWriter: w, body: body}, r)
+		})
+	}
+}
+
+// WithMaxBodySize overrides the limit installed by MaxBodySize for one route,
+// for example to allow larger batch creates. It must run before the body is read.
+func WithMaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if body, ok := r.Context().Value(bodyLimitContextKey{}).(*limitedBody); ok {
+				body.limit = limit
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limitedBody behaves like http.MaxBytesReader but its limit can still be
+// changed by route middleware before the first read.
+type limitedBody struct {
+	body          io.ReadCloser
+	limit         int64
+	read          int64
+	contentLength int64
+	exceeded      bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded || b.contentLength > b.limit {
+		b.exceeded = true
+		return 0, &http.MaxBytesError{Limit: b.limit}
+	}
+
+	// Read one byte past the limit so an oversized body is detected
+	remaining := b.limit - b.read
+	if int64(len(p)) > remaining+1 {
+		p = p[:remaining+1]
+	}
+	n, err := b.body.Read(p)
+	if int64(n) > remaining {
+		b.read = b.limit
+		b.exceeded = true
+		return int(remaining), &http.MaxBytesError{Limit: b.limit}
+	}
+	b.read += int64(n)
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// bodyLimitWriter turns the error response a handler writes after hitting the
+// body limit into a 413.
+type bodyLimitWriter struct {
+	http.ResponseWriter
+	body     *limitedBody
+	rejected bool
+}
+
+func (w *bodyLimitWriter) WriteHeader(status int) {
+	if w.body.exceeded && status >= http.StatusBadRequest && status != http.StatusRequestEntityTooLarge {
+		w.rejected = true
+		w.ResponseWriter.Header().Del("Content-Length")
+		w.ResponseWriter.Header().Set("Content-Type", "application/json")
+		w.ResponseWriter.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w.ResponseWriter).Encode(map[string]string{"error": "Request body too large"}) //nolint:errcheck
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *bodyLimitWriter) Write(b []byte) (int, error) {
+	if w.rejected {
+		// Discard the handler's own error body
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *bodyLimitWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
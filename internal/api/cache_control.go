@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/connected-systems-go/internal/config"
+)
+
+// cachePolicies builds per-route middleware that sets Cache-Control (and
+// Expires) on successful GET responses according to config.CacheConfig.
+type cachePolicies struct {
+	items       map[string]time.Duration
+	collections map[string]time.Duration
+}
+
+func newCachePolicies(cfg *config.Config) *cachePolicies {
+	c := &cachePolicies{items: map[string]time.Duration{}, collections: map[string]time.Duration{}}
+	if cfg == nil {
+		return c
+	}
+	// Config keys are case-insensitive (viper lower-cases them)
+	for resource, maxAge := range cfg.API.Cache.Items {
+		c.items[strings.ToLower(resource)] = maxAge
+	}
+	for resource, maxAge := range cfg.API.Cache.Collections {
+		c.collections[strings.ToLower(resource)] = maxAge
+	}
+	return c
+}
+
+// Item returns middleware for GET /{resource}/{id}. Without a configured
+// max-age the response must be revalidated with its ETag on every use.
+func (c *cachePolicies) Item(resource string) func(http.Handler) http.Handler {
+	return cacheControl(c.items[strings.ToLower(resource)], "no-cache")
+}
+
+// Collection returns middleware for GET /{resource}. Without a configured
+// max-age the response is not stored, since any write may change it.
+func (c *cachePolicies) Collection(resource string) func(http.Handler) http.Handler {
+	return cacheControl(c.collections[strings.ToLower(resource)], "no-store")
+}
+
+// cacheControl sets Cache-Control to max-age when positive and to fallback
+// otherwise. Only 200 and 304 responses to GET and HEAD are affected; errors
+// and writes keep whatever the handler set.
+func cacheControl(maxAge time.Duration, fallback string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(&cacheControlWriter{ResponseWriter: w, maxAge: maxAge, fallback: fallback}, r)
+		})
+	}
+}
+
+type cacheControlWriter struct {
+	http.ResponseWriter
+	maxAge      time.Duration
+	fallback    string
+	wroteHeader bool
+}
+
+func (cw *cacheControlWriter) WriteHeader(status int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		if status == http.StatusOK || status == http.StatusNotModified {
+			h := cw.Header()
+			if seconds := int(cw.maxAge / time.Second); seconds > 0 {
+				h.Set("Cache-Control", "max-age="+strconv.Itoa(seconds))
+				h.Set("Expires", time.Now().Add(cw.maxAge).UTC().Format(http.TimeFormat))
+			} else {
+				h.Set("Cache-Control", cw.fallback)
+				h.Del("Expires")
+			}
+		}
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *cacheControlWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
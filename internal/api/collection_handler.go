@@ -31,6 +31,9 @@ func NewCollectionHandler(cfg *config.Config, logger *zap.Logger, repo *reposito
 func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
 	var collection domains.Collection
 	if err := json.NewDecoder(r.Body).Decode(&collection); err != nil {
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte("Invalid request payload"))
 		return
@@ -77,6 +80,7 @@ func (h *CollectionHandler) ListCollections(w http.ResponseWriter, r *http.Reque
 	type collectionsResponse struct {
 		Links          common_shared.Links  `json:"links"`
 		Collections    []*domains.Collection `json:"collections"`
+		TimeStamp      string               `json:"timeStamp"`
 		NumberMatched  int                  `json:"numberMatched"`
 		NumberReturned int                  `json:"numberReturned"`
 	}
@@ -84,6 +88,7 @@ func (h *CollectionHandler) ListCollections(w http.ResponseWriter, r *http.Reque
 	resp := collectionsResponse{
 		Links:          common_shared.Links{},
 		Collections:    collections,
+		TimeStamp:      formaters.CollectionTimeStamp(),
 		NumberMatched:  len(collections),
 		NumberReturned: len(collections),
 	}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/formaters"
+)
+
+// ItemCollectionResponse is the collection shape of the dynamic-data
+// resources (datastreams, observations, control streams, commands and system
+// events), which list their members under items rather than as a GeoJSON
+// FeatureCollection.
+type ItemCollectionResponse struct {
+	Items []any `json:"items"`
+	// TimeStamp is when the response was generated, in RFC 3339 (UTC), as on
+	// feature collections (see formaters.CollectionTimeStamp)
+	TimeStamp string              `json:"timeStamp"`
+	Links     common_shared.Links `json:"links,omitempty"`
+}
+
+// newItemCollection wraps one page of serialized items, stamped with the time
+// it was generated.
+func newItemCollection(items []any, links common_shared.Links) ItemCollectionResponse {
+	return ItemCollectionResponse{Items: items, TimeStamp: formaters.CollectionTimeStamp(), Links: links}
+}
@@ -17,10 +17,7 @@ import (
 )
 
 // CommandCollectionResponse follows the collection shape used by other dynamic-data resources.
-type CommandCollectionResponse struct {
-	Items []any               `json:"items"`
-	Links common_shared.Links `json:"links,omitempty"`
-}
+type CommandCollectionResponse = ItemCollectionResponse
 
 // CommandHandler handles command endpoints.
 type CommandHandler struct {
@@ -46,13 +43,17 @@ func NewCommandHandler(
 
 // ListCommands handles GET /commands
 func (h *CommandHandler) ListCommands(w http.ResponseWriter, r *http.Request) {
-	params := queryparams.CommandsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.CommandsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	commands, total, err := h.repo.List(params, nil)
+	commands, total, err := h.repo.WithContext(r.Context()).List(params, nil)
 	if err != nil {
 		h.logger.Error("Failed to list commands", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Command not found", "Internal server error")
 		return
 	}
 
@@ -65,25 +66,28 @@ func (h *CommandHandler) ListCommands(w http.ResponseWriter, r *http.Request) {
 	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(commands))
 
 	w.Header().Set("Content-Type", "application/json")
-	render.JSON(w, r, CommandCollectionResponse{Items: items, Links: links})
+	render.JSON(w, r, newItemCollection(items, links))
 }
 
 // ListControlStreamCommands handles GET /controlstreams/{id}/commands
 func (h *CommandHandler) ListControlStreamCommands(w http.ResponseWriter, r *http.Request) {
 	controlStreamID := chi.URLParam(r, "controlStreamId")
-	if _, err := h.controlStreamRepo.GetByID(controlStreamID); err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Control stream not found"})
+	if _, err := h.controlStreamRepo.WithContext(r.Context()).GetByID(controlStreamID); err != nil {
+		renderRepositoryError(w, r, err, "Control stream not found", "Internal server error")
 		return
 	}
 
-	params := queryparams.CommandsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.CommandsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	commands, total, err := h.repo.ListByControlStream(controlStreamID, params)
+	commands, total, err := h.repo.WithContext(r.Context()).ListByControlStream(controlStreamID, params)
 	if err != nil {
 		h.logger.Error("Failed to list commands", zap.String("controlStreamId", controlStreamID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Command not found", "Internal server error")
 		return
 	}
 
@@ -96,46 +100,51 @@ func (h *CommandHandler) ListControlStreamCommands(w http.ResponseWriter, r *htt
 	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(commands))
 
 	w.Header().Set("Content-Type", "application/json")
-	render.JSON(w, r, CommandCollectionResponse{Items: items, Links: links})
+	render.JSON(w, r, newItemCollection(items, links))
 }
 
 // GetCommand handles GET /commands/{id}
 func (h *CommandHandler) GetCommand(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "cmdId")
 
-	cmd, err := h.repo.GetByID(id)
+	cmd, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get command", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Command not found"})
+		renderRepositoryError(w, r, err, "Command not found", "Internal server error")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	render.JSON(w, r, cmd)
+	renderJSONWithETag(w, r, "application/json", cmd)
 }
 
 // CreateControlStreamCommand handles POST /controlstreams/{id}/commands
 func (h *CommandHandler) CreateControlStreamCommand(w http.ResponseWriter, r *http.Request) {
 	controlStreamID := chi.URLParam(r, "controlStreamId")
-	if _, err := h.controlStreamRepo.GetByID(controlStreamID); err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Control stream not found"})
+	if _, err := h.controlStreamRepo.WithContext(r.Context()).GetByID(controlStreamID); err != nil {
+		renderRepositoryError(w, r, err, "Control stream not found", "Internal server error")
 		return
 	}
 
 	cmd, err := decodeCommandPayload(r)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": err.Error()})
 		return
 	}
+	if reportValidation(w, r, nil) {
+		return
+	}
 
 	cmd.ControlStreamID = controlStreamID
-	if err := h.repo.Create(cmd); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(cmd); err != nil {
 		h.logger.Error("Failed to create command", zap.String("controlStreamId", controlStreamID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create command"})
+		renderRepositoryError(w, r, err, "Command not found", "Failed to create command")
 		return
 	}
 
@@ -148,16 +157,18 @@ func (h *CommandHandler) CreateControlStreamCommand(w http.ResponseWriter, r *ht
 func (h *CommandHandler) UpdateCommand(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "cmdId")
 
-	existing, err := h.repo.GetByID(id)
+	existing, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Command not found", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Command not found"})
+		renderRepositoryError(w, r, err, "Command not found", "Internal server error")
 		return
 	}
 
 	cmd, err := decodeCommandPayload(r)
 	if err != nil {
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": err.Error()})
 		return
@@ -165,10 +176,9 @@ func (h *CommandHandler) UpdateCommand(w http.ResponseWriter, r *http.Request) {
 
 	cmd.ID = id
 	cmd.ControlStreamID = existing.ControlStreamID
-	if err := h.repo.Update(cmd); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(cmd); err != nil {
 		h.logger.Error("Failed to update command", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update command"})
+		renderRepositoryError(w, r, err, "Command not found", "Failed to update command")
 		return
 	}
 
@@ -179,17 +189,15 @@ func (h *CommandHandler) UpdateCommand(w http.ResponseWriter, r *http.Request) {
 func (h *CommandHandler) DeleteCommand(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "cmdId")
 
-	if _, err := h.repo.GetByID(id); err != nil {
+	if _, err := h.repo.WithContext(r.Context()).GetByID(id); err != nil {
 		h.logger.Error("Command not found", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Command not found"})
+		renderRepositoryError(w, r, err, "Command not found", "Internal server error")
 		return
 	}
 
-	if err := h.repo.Delete(id); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(id); err != nil {
 		h.logger.Error("Failed to delete command", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete command"})
+		renderRepositoryError(w, r, err, "Command not found", "Failed to delete command")
 		return
 	}
 
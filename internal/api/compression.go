@@ -0,0 +1,160 @@
+package api
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// compressibleTypes are the media types whose responses are gzipped for
+// clients that accept it.
+var compressibleTypes = map[string]bool{
+	"application/json":                 true,
+	"application/geo+json":             true,
+	"application/sml+json":             true,
+	"application/problem+json":         true,
+	"application/schema+json":          true,
+	"application/x-ndjson":             true,
+	"application/vnd.oai.openapi+json": true,
+	"text/csv":                         true,
+	"text/plain":                       true,
+}
+
+var gzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+
+// compressResponses gzips responses of compressibleTypes for clients that
+// accept gzip. The compressed length is not known until the body has been
+// written, so a Content-Length set by the handler is dropped and the response
+// is sent chunked; streamed responses stay streamed, each flush emitting what
+// has been compressed so far. Uncompressed responses keep their Content-Length,
+// which is still accurate.
+func compressResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cw := &compressWriter{
+			ResponseWriter: w,
+			acceptsGzip:    r.Method != http.MethodHead && acceptsGzip(r.Header.Get("Accept-Encoding")),
+		}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, either by
+// name or through "*", with a non-zero quality.
+func acceptsGzip(header string) bool {
+	accepted := false
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "x-gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if quality, err := strconv.ParseFloat(q, 64); err == nil && quality == 0 {
+				if coding != "*" {
+					return false
+				}
+				continue
+			}
+		}
+		accepted = true
+	}
+	return accepted
+}
+
+// compressWriter decides on the first WriteHeader whether the response is
+// compressed, and then routes the body through a gzip writer.
+type compressWriter struct {
+	http.ResponseWriter
+	acceptsGzip bool
+	wroteHeader bool
+	gz          *gzip.Writer
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.wroteHeader = true
+
+	h := w.Header()
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	if compressibleTypes[mediaType] && h.Get("Content-Encoding") == "" {
+		// Shared caches must keep compressed and identity bodies apart
+		addVary(h, "Accept-Encoding")
+		if w.acceptsGzip && (bodyAllowed(status) || status == http.StatusNotModified) {
+			// The gzipped body is a different representation, so a strong
+			// validator must differ from the identity one; a 304 carries the
+			// validator the 200 would have
+			if etag := h.Get("ETag"); etag != "" {
+				h.Set("ETag", gzipETag(etag))
+			}
+		}
+		if w.acceptsGzip && bodyAllowed(status) {
+			h.Del("Content-Length")
+			h.Set("Content-Encoding", "gzip")
+			w.gz = gzipWriters.Get().(*gzip.Writer)
+			w.gz.Reset(w.ResponseWriter)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz != nil {
+		return w.gz.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends what has been compressed so far, so streamed responses reach
+// the client as they are written.
+func (w *compressWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz != nil {
+		w.gz.Flush() //nolint:errcheck
+	}
+	http.NewResponseController(w.ResponseWriter).Flush() //nolint:errcheck
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close writes the gzip trailer once the handler is done.
+func (w *compressWriter) close() {
+	if w.gz == nil {
+		return
+	}
+	w.gz.Close() //nolint:errcheck
+	w.gz.Reset(nil)
+	gzipWriters.Put(w.gz)
+	w.gz = nil
+}
+
+// gzipETagSuffix marks the strong ETag of a gzipped representation.
+const gzipETagSuffix = "-gzip"
+
+// gzipETag returns the ETag of the gzipped form of the representation tagged
+// etag. Weak tags already allow byte differences and are kept as they are.
+func gzipETag(etag string) string {
+	if strings.HasPrefix(etag, "W/") || !strings.HasSuffix(etag, `"`) || strings.HasSuffix(etag, gzipETagSuffix+`"`) {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + gzipETagSuffix + `"`
+}
+
+// bodyAllowed reports whether a response with status carries a body.
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
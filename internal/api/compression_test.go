@@ -0,0 +1,175 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	for header, want := range map[string]bool{
+		"":                    false,
+		"gzip":                true,
+		"deflate, gzip;q=0.5": true,
+		"GZIP":                true,
+		"*":                   true,
+		"gzip;q=0":            false,
+		"*;q=0":               false,
+		"identity":            false,
+		"br, *;q=0.1":         true,
+		"gzip;q=0, *":         false,
+		"x-gzip":              true,
+	} {
+		assert.Equal(t, want, acceptsGzip(header), "Accept-Encoding %q", header)
+	}
+}
+
+func TestCompressResponses(t *testing.T) {
+	body := `{"type":"FeatureCollection","features":[` + strings.Repeat(`{"type":"Feature"},`, 200) + `{}]}`
+	// The handler declares the length of the uncompressed body, which is stale
+	// once the body is gzipped
+	handler := compressResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.Header().Set("Content-Length", "17")
+		w.Write([]byte(body))
+	}))
+
+	t.Run("gzip is decodable and drops the stale length", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/systems", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		assert.Empty(t, rec.Header().Get("Content-Length"))
+		assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+		gz, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, body, string(decoded))
+	})
+
+	t.Run("identity keeps the body and its length", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/systems", nil))
+
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "17", rec.Header().Get("Content-Length"))
+		assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+		assert.Equal(t, body, rec.Body.String())
+	})
+
+	t.Run("responses without a body are not compressed", func(t *testing.T) {
+		notModified := compressResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotModified)
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/systems/1", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		notModified.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Zero(t, rec.Body.Len())
+	})
+
+	t.Run("strong etags of gzipped bodies are suffixed", func(t *testing.T) {
+		tagged := compressResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			renderJSONWithETag(w, r, "application/geo+json", map[string]string{"type": "Feature"})
+		}))
+		identity := httptest.NewRecorder()
+		tagged.ServeHTTP(identity, httptest.NewRequest(http.MethodGet, "/systems/1", nil))
+		etag := identity.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		req := httptest.NewRequest(http.MethodGet, "/systems/1", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		gzipped := httptest.NewRecorder()
+		tagged.ServeHTTP(gzipped, req)
+		assert.Equal(t, "gzip", gzipped.Header().Get("Content-Encoding"))
+		gzipETag := gzipped.Header().Get("ETag")
+		assert.Equal(t, strings.TrimSuffix(etag, `"`)+`-gzip"`, gzipETag)
+
+		// Revalidating with the gzip tag answers 304 with the same tag
+		req = httptest.NewRequest(http.MethodGet, "/systems/1", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		req.Header.Set("If-None-Match", gzipETag)
+		revalidated := httptest.NewRecorder()
+		tagged.ServeHTTP(revalidated, req)
+		assert.Equal(t, http.StatusNotModified, revalidated.Code)
+		assert.Equal(t, gzipETag, revalidated.Header().Get("ETag"))
+	})
+
+	t.Run("other media types are passed through", func(t *testing.T) {
+		binary := compressResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png"))
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/icon", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		binary.ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "png", rec.Body.String())
+	})
+
+	t.Run("flushes emit the compressed stream so far", func(t *testing.T) {
+		streamed := make(chan struct{})
+		stream := compressResponses(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Write([]byte("{\"id\":\"1\"}\n"))
+			assert.NoError(t, http.NewResponseController(w).Flush())
+			<-streamed
+			w.Write([]byte("{\"id\":\"2\"}\n"))
+		}))
+		server := httptest.NewServer(stream)
+		defer server.Close()
+
+		// The standard client asks for gzip and decodes it transparently
+		resp, err := http.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.True(t, resp.Uncompressed)
+		assert.EqualValues(t, -1, resp.ContentLength)
+
+		first := make([]byte, len("{\"id\":\"1\"}\n"))
+		_, err = io.ReadFull(resp.Body, first)
+		require.NoError(t, err, "the first line must arrive before the handler finishes")
+		assert.Equal(t, "{\"id\":\"1\"}\n", string(first))
+		close(streamed)
+
+		rest, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "{\"id\":\"2\"}\n", string(rest))
+	})
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := map[string]struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		"same tag":          {`"abc"`, true},
+		"weak form":         {`W/"abc"`, true},
+		"gzip form":         {`"abc-gzip"`, true},
+		"one of several":    {`"other", "abc-gzip"`, true},
+		"any":               {`*`, true},
+		"different tag":     {`"abd"`, false},
+		"different gzipped": {`"abd-gzip"`, false},
+		"empty":             {``, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, etagMatches(tt.ifNoneMatch, `"abc"`))
+		})
+	}
+}
@@ -16,37 +16,40 @@ import (
 )
 
 // ControlStreamCollectionResponse follows the collection shape used by other dynamic-data resources.
-type ControlStreamCollectionResponse struct {
-	Items []any               `json:"items"`
-	Links common_shared.Links `json:"links,omitempty"`
-}
+type ControlStreamCollectionResponse = ItemCollectionResponse
 
 // ControlStreamHandler handles control stream endpoints.
 type ControlStreamHandler struct {
-	cfg    *config.Config
-	logger *zap.Logger
-	repo   *repository.ControlStreamRepository
-	fc     *formaters.MultiFormatFormatterCollection[*domains.ControlStream]
+	cfg        *config.Config
+	logger     *zap.Logger
+	repo       *repository.ControlStreamRepository
+	systemRepo *repository.SystemRepository
+	fc         *formaters.MultiFormatFormatterCollection[*domains.ControlStream]
 }
 
 func NewControlStreamHandler(
 	cfg *config.Config,
 	logger *zap.Logger,
 	repo *repository.ControlStreamRepository,
+	systemRepo *repository.SystemRepository,
 	fc *formaters.MultiFormatFormatterCollection[*domains.ControlStream],
 ) *ControlStreamHandler {
-	return &ControlStreamHandler{cfg: cfg, logger: logger, repo: repo, fc: fc}
+	return &ControlStreamHandler{cfg: cfg, logger: logger, repo: repo, systemRepo: systemRepo, fc: fc}
 }
 
 // ListControlStreams handles GET /controlstreams
 func (h *ControlStreamHandler) ListControlStreams(w http.ResponseWriter, r *http.Request) {
-	params := queryparams.ControlStreamsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.ControlStreamsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	controlStreams, total, err := h.repo.List(params, nil)
+	controlStreams, total, err := h.repo.WithContext(r.Context()).List(params, nil)
 	if err != nil {
 		h.logger.Error("Failed to list control streams", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Control stream not found", "Internal server error")
 		return
 	}
 
@@ -62,8 +65,7 @@ func (h *ControlStreamHandler) ListControlStreams(w http.ResponseWriter, r *http
 	totalInt := int(total)
 	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(controlStreams))
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, ControlStreamCollectionResponse{Items: items, Links: links})
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), newItemCollection(items, links))
 }
 
 // ListSystemControlStreams handles GET /systems/{id}/controlstreams
@@ -72,13 +74,17 @@ func (h *ControlStreamHandler) ListSystemControlStreams(w http.ResponseWriter, r
 	if systemID == "" {
 		systemID = chi.URLParam(r, "id")
 	}
-	params := queryparams.ControlStreamsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.ControlStreamsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	controlStreams, total, err := h.repo.List(params, &systemID)
+	controlStreams, total, err := h.repo.WithContext(r.Context()).ListBySystem(params, systemID)
 	if err != nil {
 		h.logger.Error("Failed to list control streams for system", zap.String("systemId", systemID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
 		return
 	}
 
@@ -94,19 +100,17 @@ func (h *ControlStreamHandler) ListSystemControlStreams(w http.ResponseWriter, r
 	totalInt := int(total)
 	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(controlStreams))
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, ControlStreamCollectionResponse{Items: items, Links: links})
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), newItemCollection(items, links))
 }
 
 // GetControlStream handles GET /controlstreams/{id}
 func (h *ControlStreamHandler) GetControlStream(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "controlStreamId")
 
-	cs, err := h.repo.GetByID(id)
+	cs, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get control stream", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Control stream not found"})
+		renderRepositoryError(w, r, err, "Control stream not found", "Internal server error")
 		return
 	}
 
@@ -119,8 +123,7 @@ func (h *ControlStreamHandler) GetControlStream(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, serialized)
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), serialized)
 }
 
 // CreateControlStream handles POST /systems/{id}/controlstreams
@@ -129,11 +132,24 @@ func (h *ControlStreamHandler) CreateControlStream(w http.ResponseWriter, r *htt
 	if systemID == "" {
 		systemID = chi.URLParam(r, "id")
 	}
+	if systemID != "" {
+		if _, err := h.systemRepo.WithContext(r.Context()).GetByID(systemID); err != nil {
+			h.logger.Error("Failed to look up system for control stream", zap.String("systemId", systemID), zap.Error(err))
+			renderRepositoryError(w, r, err, "System not found", "Internal server error")
+			return
+		}
+	}
 
 	contentType := r.Header.Get("Content-Type")
 	cs, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to deserialize control stream", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
@@ -145,11 +161,13 @@ func (h *ControlStreamHandler) CreateControlStream(w http.ResponseWriter, r *htt
 			cs.SystemLink = &common_shared.Link{Href: "systems/" + systemID}
 		}
 	}
+	if reportCreateValidation(w, r, nil, h.repo.WithContext(r.Context()), string(cs.UniqueIdentifier)) {
+		return
+	}
 
-	if err := h.repo.Create(cs); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(cs); err != nil {
 		h.logger.Error("Failed to create control stream", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create control stream"})
+		renderRepositoryError(w, r, err, "Control stream not found", "Failed to create control stream")
 		return
 	}
 
@@ -161,11 +179,10 @@ func (h *ControlStreamHandler) CreateControlStream(w http.ResponseWriter, r *htt
 // UpdateControlStream handles PUT /controlstreams/{id}
 func (h *ControlStreamHandler) UpdateControlStream(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "controlStreamId")
-	existing, err := h.repo.GetByID(id)
+	existing, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get control stream before update", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Control stream not found"})
+		renderRepositoryError(w, r, err, "Control stream not found", "Internal server error")
 		return
 	}
 
@@ -173,6 +190,9 @@ func (h *ControlStreamHandler) UpdateControlStream(w http.ResponseWriter, r *htt
 	cs, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
 		h.logger.Error("Failed to deserialize control stream", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
@@ -183,10 +203,9 @@ func (h *ControlStreamHandler) UpdateControlStream(w http.ResponseWriter, r *htt
 		cs.SystemLink = existing.SystemLink
 		cs.SystemID = existing.SystemID
 	}
-	if err := h.repo.Update(cs); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(cs); err != nil {
 		h.logger.Error("Failed to update control stream", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update control stream"})
+		renderRepositoryError(w, r, err, "Control stream not found", "Failed to update control stream")
 		return
 	}
 
@@ -197,10 +216,9 @@ func (h *ControlStreamHandler) UpdateControlStream(w http.ResponseWriter, r *htt
 func (h *ControlStreamHandler) DeleteControlStream(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "controlStreamId")
 	cascade := r.URL.Query().Get("cascade") == "true"
-	if err := h.repo.Delete(id, cascade); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(id, cascade); err != nil {
 		h.logger.Error("Failed to delete control stream", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete control stream"})
+		renderRepositoryError(w, r, err, "Control stream not found", "Failed to delete control stream")
 		return
 	}
 
@@ -210,11 +228,10 @@ func (h *ControlStreamHandler) DeleteControlStream(w http.ResponseWriter, r *htt
 // GetControlStreamSchema handles GET /controlstreams/{id}/schema
 func (h *ControlStreamHandler) GetControlStreamSchema(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "controlStreamId")
-	schema, err := h.repo.GetSchema(id)
+	schema, err := h.repo.WithContext(r.Context()).GetSchema(id)
 	if err != nil {
 		h.logger.Error("Failed to get control stream schema", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Control stream not found"})
+		renderRepositoryError(w, r, err, "Control stream not found", "Internal server error")
 		return
 	}
 
@@ -234,15 +251,17 @@ func (h *ControlStreamHandler) UpdateControlStreamSchema(w http.ResponseWriter,
 
 	var schema domains.ControlStreamSchema
 	if err := render.DecodeJSON(r.Body, &schema); err != nil {
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
 
-	if err := h.repo.UpdateSchema(id, &schema); err != nil {
+	if err := h.repo.WithContext(r.Context()).UpdateSchema(id, &schema); err != nil {
 		h.logger.Error("Failed to update control stream schema", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update control stream schema"})
+		renderRepositoryError(w, r, err, "Control stream not found", "Failed to update control stream schema")
 		return
 	}
 
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/cors"
+	"github.com/yourusername/connected-systems-go/internal/config"
+)
+
+// NewCORSMiddleware builds the CORS middleware from cfg. An empty AllowedOrigins
+// list denies every cross-origin request (the underlying library would otherwise
+// treat it as allow-all), and preflight requests are answered with 204 No Content.
+func NewCORSMiddleware(cfg config.CORSConfig) func(http.Handler) http.Handler {
+	options := cors.Options{
+		AllowedOrigins:     cfg.AllowedOrigins,
+		AllowedMethods:     cfg.AllowedMethods,
+		AllowedHeaders:     cfg.AllowedHeaders,
+		ExposedHeaders:     cfg.ExposedHeaders,
+		AllowCredentials:   cfg.AllowCredentials,
+		MaxAge:             cfg.MaxAge,
+		OptionsPassthrough: true,
+	}
+	if len(cfg.AllowedOrigins) == 0 {
+		options.AllowOriginFunc = func(r *http.Request, origin string) bool { return false }
+	}
+	corsHandler := cors.Handler(options)
+
+	return func(next http.Handler) http.Handler {
+		return corsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
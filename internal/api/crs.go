@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/connected-systems-go/internal/model/formaters/geojson_formatters"
+	"github.com/yourusername/connected-systems-go/internal/model/formaters/sensorml_formatters"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+)
+
+// Coordinate reference systems geometries can be returned in. CRS84 is the
+// storage CRS; Web Mercator serves web maps.
+const (
+	CRS84       = "http://www.opengis.net/def/crs/OGC/1.3/CRS84"
+	WebMercator = "http://www.opengis.net/def/crs/EPSG/0/3857"
+)
+
+// supportedCRS maps each CRS accepted by ?crs to its PostGIS SRID.
+var supportedCRS = map[string]int{
+	CRS84:       repository.StorageSRID,
+	WebMercator: 3857,
+}
+
+// geometryContentTypes are the media types whose documents carry geometries.
+var geometryContentTypes = map[string]bool{
+	geojson_formatters.GeoJSONContentType:   true,
+	sensorml_formatters.SensorMLContentType: true,
+	ndjsonContentType:                       true,
+	csvContentType:                          true,
+}
+
+// outputCRS lets clients choose the CRS of returned geometries with ?crs. The
+// geometries are reprojected as they are read (see
+// repository.RegisterOutputReprojection) and responses that return them name
+// their CRS in a Content-Crs header; other resources, such as datastreams,
+// accept the parameter but get no header. Only reads take the parameter;
+// stored geometries are always CRS84. A CRS outside supportedCRS is answered
+// with 400.
+func outputCRS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		crs := r.URL.Query().Get("crs")
+		if crs == "" || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		srid, ok := supportedCRS[crs]
+		if !ok {
+			renderQueryParamError(w, r, &queryparams.InvalidParameterError{Name: "crs", Value: crs, Reason: "must be one of: " + strings.Join(supportedCRSList(), ", ")})
+			return
+		}
+		ctx := repository.WithOutputSRID(r.Context(), srid)
+		next.ServeHTTP(&crsWriter{ResponseWriter: w, ctx: ctx, crs: crs}, r.WithContext(ctx))
+	})
+}
+
+// crsWriter sets Content-Crs when the header is written, once it is known
+// whether the response carries geometries read in the requested CRS.
+type crsWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	crs         string
+	wroteHeader bool
+}
+
+func (cw *crsWriter) WriteHeader(status int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		mediaType, _, _ := mime.ParseMediaType(cw.Header().Get("Content-Type"))
+		if status < http.StatusBadRequest && geometryContentTypes[mediaType] && repository.GeometriesRead(cw.ctx) {
+			cw.Header().Set("Content-Crs", "<"+cw.crs+">")
+		}
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *crsWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *crsWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// supportedCRSList returns the CRS URIs accepted by ?crs, sorted.
+func supportedCRSList() []string {
+	list := make([]string, 0, len(supportedCRS))
+	for crs := range supportedCRS {
+		list = append(list, crs)
+	}
+	sort.Strings(list)
+	return list
+}
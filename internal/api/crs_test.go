@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestOutputCRS(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=unused"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, repository.RegisterOutputReprojection(db))
+
+	// echoSRID writes the output SRID after reading either systems, which
+	// carry geometries, or datastreams, which do not
+	echoSRID := func(path string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if path == "/datastreams" {
+				var datastreams []domains.Datastream
+				db.WithContext(r.Context()).Find(&datastreams)
+				w.Header().Set("Content-Type", "application/json")
+			} else {
+				var systems []domains.System
+				db.WithContext(r.Context()).Find(&systems)
+				w.Header().Set("Content-Type", "application/geo+json")
+			}
+			srid, _ := repository.OutputSRIDFromContext(r.Context())
+			w.Write([]byte(strconv.Itoa(srid)))
+		})
+	}
+
+	tests := map[string]struct {
+		method         string
+		path           string
+		crs            string
+		wantCode       int
+		wantSRID       string
+		wantContentCRS string
+	}{
+		"no crs":                       {method: http.MethodGet, wantCode: http.StatusOK, wantSRID: "0"},
+		"storage crs":                  {method: http.MethodGet, crs: CRS84, wantCode: http.StatusOK, wantSRID: "0", wantContentCRS: "<" + CRS84 + ">"},
+		"web mercator":                 {method: http.MethodGet, crs: WebMercator, wantCode: http.StatusOK, wantSRID: "3857", wantContentCRS: "<" + WebMercator + ">"},
+		"responses without geometries": {method: http.MethodGet, path: "/datastreams", crs: WebMercator, wantCode: http.StatusOK, wantSRID: "3857"},
+		"unsupported crs":              {method: http.MethodGet, crs: "http://www.opengis.net/def/crs/EPSG/0/32611", wantCode: http.StatusBadRequest},
+		"writes ignore crs":            {method: http.MethodPut, crs: WebMercator, wantCode: http.StatusOK, wantSRID: "0"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			target := "/systems"
+			if tt.path != "" {
+				target = tt.path
+			}
+			if tt.crs != "" {
+				target += "?crs=" + url.QueryEscape(tt.crs)
+			}
+			rec := httptest.NewRecorder()
+			outputCRS(echoSRID(tt.path)).ServeHTTP(rec, httptest.NewRequest(tt.method, target, nil))
+			require.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantContentCRS, rec.Header().Get("Content-Crs"))
+			if tt.wantCode != http.StatusOK {
+				var problem ValidationProblem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Len(t, problem.Errors, 1)
+				assert.Equal(t, "crs", problem.Errors[0].Path)
+				return
+			}
+			assert.Equal(t, tt.wantSRID, rec.Body.String())
+		})
+	}
+}
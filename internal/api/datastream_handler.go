@@ -16,31 +16,33 @@ import (
 )
 
 // DatastreamCollectionResponse follows datastreams-only.yaml collection shape.
-type DatastreamCollectionResponse struct {
-	Items []any               `json:"items"`
-	Links common_shared.Links `json:"links,omitempty"`
-}
+type DatastreamCollectionResponse = ItemCollectionResponse
 
 // DatastreamHandler handles datastream endpoints.
 type DatastreamHandler struct {
-	cfg    *config.Config
-	logger *zap.Logger
-	repo   *repository.DatastreamRepository
-	fc     *formaters.MultiFormatFormatterCollection[*domains.Datastream]
+	cfg        *config.Config
+	logger     *zap.Logger
+	repo       *repository.DatastreamRepository
+	systemRepo *repository.SystemRepository
+	fc         *formaters.MultiFormatFormatterCollection[*domains.Datastream]
 }
 
-func NewDatastreamHandler(cfg *config.Config, logger *zap.Logger, repo *repository.DatastreamRepository, fc *formaters.MultiFormatFormatterCollection[*domains.Datastream]) *DatastreamHandler {
-	return &DatastreamHandler{cfg: cfg, logger: logger, repo: repo, fc: fc}
+func NewDatastreamHandler(cfg *config.Config, logger *zap.Logger, repo *repository.DatastreamRepository, systemRepo *repository.SystemRepository, fc *formaters.MultiFormatFormatterCollection[*domains.Datastream]) *DatastreamHandler {
+	return &DatastreamHandler{cfg: cfg, logger: logger, repo: repo, systemRepo: systemRepo, fc: fc}
 }
 
 func (h *DatastreamHandler) ListDatastreams(w http.ResponseWriter, r *http.Request) {
-	params := queryparams.DatastreamsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.DatastreamsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	datastreams, total, err := h.repo.List(params, nil)
+	datastreams, total, err := h.repo.WithContext(r.Context()).List(params, nil)
 	if err != nil {
 		h.logger.Error("Failed to list datastreams", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Datastream not found", "Internal server error")
 		return
 	}
 
@@ -56,8 +58,7 @@ func (h *DatastreamHandler) ListDatastreams(w http.ResponseWriter, r *http.Reque
 	totalInt := int(total)
 	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(datastreams))
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, DatastreamCollectionResponse{Items: items, Links: links})
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), newItemCollection(items, links))
 }
 
 func (h *DatastreamHandler) ListSystemDatastreams(w http.ResponseWriter, r *http.Request) {
@@ -65,13 +66,17 @@ func (h *DatastreamHandler) ListSystemDatastreams(w http.ResponseWriter, r *http
 	if systemID == "" {
 		systemID = chi.URLParam(r, "id")
 	}
-	params := queryparams.DatastreamsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.DatastreamsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	datastreams, total, err := h.repo.List(params, &systemID)
+	datastreams, total, err := h.repo.WithContext(r.Context()).ListBySystem(params, systemID)
 	if err != nil {
 		h.logger.Error("Failed to list datastreams for system", zap.String("systemId", systemID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
 		return
 	}
 
@@ -87,18 +92,16 @@ func (h *DatastreamHandler) ListSystemDatastreams(w http.ResponseWriter, r *http
 	totalInt := int(total)
 	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(datastreams))
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, DatastreamCollectionResponse{Items: items, Links: links})
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), newItemCollection(items, links))
 }
 
 func (h *DatastreamHandler) GetDatastream(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "dataStreamId")
 
-	datastream, err := h.repo.GetByID(id)
+	datastream, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get datastream", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Datastream not found"})
+		renderRepositoryError(w, r, err, "Datastream not found", "Internal server error")
 		return
 	}
 
@@ -111,8 +114,7 @@ func (h *DatastreamHandler) GetDatastream(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, serialized)
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), serialized)
 }
 
 func (h *DatastreamHandler) CreateDatastream(w http.ResponseWriter, r *http.Request) {
@@ -120,11 +122,24 @@ func (h *DatastreamHandler) CreateDatastream(w http.ResponseWriter, r *http.Requ
 	if systemID == "" {
 		systemID = chi.URLParam(r, "id")
 	}
+	if systemID != "" {
+		if _, err := h.systemRepo.WithContext(r.Context()).GetByID(systemID); err != nil {
+			h.logger.Error("Failed to look up system for datastream", zap.String("systemId", systemID), zap.Error(err))
+			renderRepositoryError(w, r, err, "System not found", "Internal server error")
+			return
+		}
+	}
 
 	contentType := r.Header.Get("Content-Type")
 	datastream, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to deserialize datastream", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
@@ -136,11 +151,13 @@ func (h *DatastreamHandler) CreateDatastream(w http.ResponseWriter, r *http.Requ
 			datastream.SystemLink = &common_shared.Link{Href: "systems/" + systemID}
 		}
 	}
+	if reportCreateValidation(w, r, nil, h.repo.WithContext(r.Context()), string(datastream.UniqueIdentifier)) {
+		return
+	}
 
-	if err := h.repo.Create(datastream); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(datastream); err != nil {
 		h.logger.Error("Failed to create datastream", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create datastream"})
+		renderRepositoryError(w, r, err, "Datastream not found", "Failed to create datastream")
 		return
 	}
 
@@ -151,11 +168,10 @@ func (h *DatastreamHandler) CreateDatastream(w http.ResponseWriter, r *http.Requ
 
 func (h *DatastreamHandler) UpdateDatastream(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "dataStreamId")
-	existing, err := h.repo.GetByID(id)
+	existing, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get datastream before update", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Datastream not found"})
+		renderRepositoryError(w, r, err, "Datastream not found", "Internal server error")
 		return
 	}
 
@@ -163,6 +179,9 @@ func (h *DatastreamHandler) UpdateDatastream(w http.ResponseWriter, r *http.Requ
 	datastream, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
 		h.logger.Error("Failed to deserialize datastream", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
@@ -173,10 +192,9 @@ func (h *DatastreamHandler) UpdateDatastream(w http.ResponseWriter, r *http.Requ
 		datastream.SystemLink = existing.SystemLink
 		datastream.SystemID = existing.SystemID
 	}
-	if err := h.repo.Update(datastream); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(datastream); err != nil {
 		h.logger.Error("Failed to update datastream", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update datastream"})
+		renderRepositoryError(w, r, err, "Datastream not found", "Failed to update datastream")
 		return
 	}
 
@@ -185,11 +203,10 @@ func (h *DatastreamHandler) UpdateDatastream(w http.ResponseWriter, r *http.Requ
 
 func (h *DatastreamHandler) DeleteDatastream(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "dataStreamId")
-	cascade := r.URL.Query().Get("cascade") == "true"
-	if err := h.repo.Delete(id, cascade); err != nil {
+	// Observations always go with their datastream, so ?cascade makes no difference here
+	if err := h.repo.WithContext(r.Context()).Delete(id); err != nil {
 		h.logger.Error("Failed to delete datastream", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete datastream"})
+		renderRepositoryError(w, r, err, "Datastream not found", "Failed to delete datastream")
 		return
 	}
 
@@ -198,11 +215,10 @@ func (h *DatastreamHandler) DeleteDatastream(w http.ResponseWriter, r *http.Requ
 
 func (h *DatastreamHandler) GetDatastreamSchema(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "dataStreamId")
-	schema, err := h.repo.GetSchema(id)
+	schema, err := h.repo.WithContext(r.Context()).GetSchema(id)
 	if err != nil {
 		h.logger.Error("Failed to get datastream schema", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Datastream not found"})
+		renderRepositoryError(w, r, err, "Datastream not found", "Internal server error")
 		return
 	}
 
@@ -221,15 +237,17 @@ func (h *DatastreamHandler) UpdateDatastreamSchema(w http.ResponseWriter, r *htt
 
 	var schema domains.DatastreamSchema
 	if err := render.DecodeJSON(r.Body, &schema); err != nil {
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
 
-	if err := h.repo.UpdateSchema(id, &schema); err != nil {
+	if err := h.repo.WithContext(r.Context()).UpdateSchema(id, &schema); err != nil {
 		h.logger.Error("Failed to update datastream schema", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update datastream schema"})
+		renderRepositoryError(w, r, err, "Datastream not found", "Failed to update datastream schema")
 		return
 	}
 
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// decodeViolations describes a failure to decode a JSON request body: the
+// byte offset and message of a syntax error, the member and expected JSON
+// type of a type mismatch, or a truncated or empty body. It returns nil for
+// any other error, such as a payload that is well-formed but invalid.
+func decodeViolations(err error) SchemaViolations {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return SchemaViolations{{Message: fmt.Sprintf("malformed JSON at byte offset %d: %s", syntaxErr.Offset, syntaxErr.Error())}}
+	case errors.As(err, &typeErr):
+		return SchemaViolations{{Path: typeErr.Field, Message: fmt.Sprintf("must be %s, got JSON %s (at byte offset %d)", jsonTypeName(typeErr.Type), typeErr.Value, typeErr.Offset)}}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return SchemaViolations{{Message: "malformed JSON: the body ends before the JSON value is complete"}}
+	case errors.Is(err, io.EOF):
+		return SchemaViolations{{Message: "the request body is empty"}}
+	}
+	return nil
+}
+
+// jsonTypeName names the JSON type a Go value of type t is decoded from.
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "a JSON value"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return "a JSON value"
+}
+
+// renderDecodeError writes a 400 problem document describing err when it is a
+// JSON decoding failure (see decodeViolations) and reports whether it did.
+// Callers fall back to their own response for other errors.
+func renderDecodeError(w http.ResponseWriter, r *http.Request, err error) bool {
+	violations := decodeViolations(err)
+	if violations == nil {
+		return false
+	}
+	renderValidationProblem(w, r, "Malformed request body", violations)
+	return true
+}
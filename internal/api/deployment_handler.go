@@ -28,31 +28,34 @@ func NewDeploymentHandler(cfg *config.Config, logger *zap.Logger, repo *reposito
 }
 
 func (h *DeploymentHandler) ListDeployments(w http.ResponseWriter, r *http.Request) {
-	params := queryparams.DeploymentsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.DeploymentsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	deployments, total, err := h.repo.List(params, nil)
+	deployments, total, err := h.repo.WithContext(r.Context()).List(params, nil)
 	if err != nil {
 		h.logger.Error("Failed to list deployments", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Deployment not found", "Internal server error")
 		return
 	}
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.fc.BuildCollection(acceptHeader, deployments, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
 }
 
 func (h *DeploymentHandler) GetDeployment(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	deployment, err := h.repo.GetByID(id)
+	deployment, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get deployment", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Deployment not found"})
+		renderRepositoryError(w, r, err, "Deployment not found", "Internal server error")
 		return
 	}
 
@@ -65,25 +68,36 @@ func (h *DeploymentHandler) GetDeployment(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.Status(r, http.StatusOK)
-	render.JSON(w, r, serialized)
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), serialized)
 }
 
 func (h *DeploymentHandler) CreateDeployment(w http.ResponseWriter, r *http.Request) {
 	contentType := r.Header.Get("Content-Type")
 	deployment, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to deserialize deployment", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
+	geometryErr := checkRingOrientation(h.cfg, deployment.Geometry)
+	if reportCreateValidation(w, r, geometryErr, h.repo.WithContext(r.Context()), string(deployment.UniqueIdentifier)) {
+		return
+	}
+	if geometryErr != nil {
+		renderValidationProblem(w, r, "Invalid geometry", geometryErr)
+		return
+	}
 
-	if err := h.repo.Create(deployment); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(deployment); err != nil {
 		h.logger.Error("Failed to create deployment", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create deployment"})
+		renderRepositoryError(w, r, err, "Deployment not found", "Failed to create deployment")
 		return
 	}
 
@@ -99,16 +113,22 @@ func (h *DeploymentHandler) UpdateDeployment(w http.ResponseWriter, r *http.Requ
 	deployment, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
 		h.logger.Error("Failed to deserialize deployment", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
+	if err := checkRingOrientation(h.cfg, deployment.Geometry); err != nil {
+		renderValidationProblem(w, r, "Invalid geometry", err)
+		return
+	}
 
 	deployment.ID = id
-	if err := h.repo.Update(deployment); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(deployment); err != nil {
 		h.logger.Error("Failed to update deployment", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update deployment"})
+		renderRepositoryError(w, r, err, "Deployment not found", "Failed to update deployment")
 		return
 	}
 
@@ -118,10 +138,9 @@ func (h *DeploymentHandler) UpdateDeployment(w http.ResponseWriter, r *http.Requ
 func (h *DeploymentHandler) DeleteDeployment(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := h.repo.Delete(id); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(id); err != nil {
 		h.logger.Error("Failed to delete deployment", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete deployment"})
+		renderRepositoryError(w, r, err, "Deployment not found", "Failed to delete deployment")
 		return
 	}
 
@@ -131,42 +150,64 @@ func (h *DeploymentHandler) DeleteDeployment(w http.ResponseWriter, r *http.Requ
 // List all subdeployments
 func (h *DeploymentHandler) ListSubdeployments(w http.ResponseWriter, r *http.Request) {
 	parentID := chi.URLParam(r, "id")
-	params := queryparams.DeploymentsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.DeploymentsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	deployments, total, err := h.repo.List(params, &parentID)
+	deployments, total, err := h.repo.WithContext(r.Context()).List(params, &parentID)
 	if err != nil {
 		h.logger.Error("Failed to list subdeployments", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Deployment not found", "Internal server error")
 		return
 	}
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.fc.BuildCollection(acceptHeader, deployments, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
 }
 
 // Add subdeployment to a deployment
 func (h *DeploymentHandler) AddSubdeployment(w http.ResponseWriter, r *http.Request) {
 	parentID := chi.URLParam(r, "id")
+	if _, err := h.repo.WithContext(r.Context()).GetByID(parentID); err != nil {
+		h.logger.Error("Failed to look up parent deployment", zap.String("id", parentID), zap.Error(err))
+		renderRepositoryError(w, r, err, "Deployment not found", "Internal server error")
+		return
+	}
 
 	contentType := r.Header.Get("Content-Type")
 	subdeployment, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to deserialize subdeployment", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
 
 	subdeployment.ParentDeploymentID = &parentID
+	geometryErr := checkRingOrientation(h.cfg, subdeployment.Geometry)
+	if reportCreateValidation(w, r, geometryErr, h.repo.WithContext(r.Context()), string(subdeployment.UniqueIdentifier)) {
+		return
+	}
+	if geometryErr != nil {
+		renderValidationProblem(w, r, "Invalid geometry", geometryErr)
+		return
+	}
 
-	if err := h.repo.Create(subdeployment); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(subdeployment); err != nil {
 		h.logger.Error("Failed to create subdeployment", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create subdeployment"})
+		renderRepositoryError(w, r, err, "Deployment not found", "Failed to create subdeployment")
 		return
 	}
 
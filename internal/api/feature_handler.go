@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -36,6 +37,12 @@ func redirectToCanonical(w http.ResponseWriter, r *http.Request, target string)
 	http.Redirect(w, r, target, http.StatusTemporaryRedirect)
 }
 
+// canonicalURL returns the URL of path on the canonical endpoints, for
+// redirecting there.
+func (h *FeatureHandler) canonicalURL(path string) string {
+	return strings.TrimRight(h.cfg.API.BaseURL, "/") + path
+}
+
 // FeatureHandler handles Feature resource requests (OGC API Features Part 1)
 type FeatureHandler struct {
 	cfg    *config.Config
@@ -58,25 +65,30 @@ func NewFeatureHandler(cfg *config.Config, logger *zap.Logger, repo *repository.
 func (h *FeatureHandler) ListFeatures(w http.ResponseWriter, r *http.Request) {
 	collectionID := chi.URLParam(r, "collectionId")
 	if basePath, ok := canonicalCollectionPaths[collectionID]; ok {
-		redirectToCanonical(w, r, basePath)
+		redirectToCanonical(w, r, h.canonicalURL(basePath))
 		return
 	}
 
-	params := queryparams.FeatureQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.FeatureQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 	params.CollectionID = collectionID
 
-	features, total, err := h.repo.ListByCollection(collectionID, params)
+	features, total, err := h.repo.WithContext(r.Context()).ListByCollection(collectionID, params)
 	if err != nil {
 		h.logger.Error("Failed to list features", zap.String("collectionId", collectionID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Feature not found", "Internal server error")
 		return
 	}
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.fc.BuildCollection(acceptHeader, features, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
 
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
 }
 
 // GetFeature retrieves a single feature by ID (OGC path: /collections/{collectionId}/items/{featureId})
@@ -84,18 +96,17 @@ func (h *FeatureHandler) GetFeature(w http.ResponseWriter, r *http.Request) {
 	collectionID := chi.URLParam(r, "collectionId")
 	featureID := chi.URLParam(r, "featureId")
 	if basePath, ok := canonicalCollectionPaths[collectionID]; ok {
-		redirectToCanonical(w, r, basePath+"/"+featureID)
+		redirectToCanonical(w, r, h.canonicalURL(basePath+"/"+featureID))
 		return
 	}
 
-	feature, err := h.repo.GetByCollectionAndID(collectionID, featureID)
+	feature, err := h.repo.WithContext(r.Context()).GetByCollectionAndID(collectionID, featureID)
 	if err != nil {
 		h.logger.Error("Failed to get feature",
 			zap.String("collectionId", collectionID),
 			zap.String("featureId", featureID),
 			zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Feature not found"})
+		renderRepositoryError(w, r, err, "Feature not found", "Internal server error")
 		return
 	}
 
@@ -112,14 +123,14 @@ func (h *FeatureHandler) GetFeature(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	render.JSON(w, r, json)
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), json)
 }
 
 // CreateFeature creates a new feature in a collection
 func (h *FeatureHandler) CreateFeature(w http.ResponseWriter, r *http.Request) {
 	collectionID := chi.URLParam(r, "collectionId")
 	if basePath, ok := canonicalCollectionPaths[collectionID]; ok {
-		redirectToCanonical(w, r, basePath)
+		redirectToCanonical(w, r, h.canonicalURL(basePath))
 		return
 	}
 
@@ -129,16 +140,24 @@ func (h *FeatureHandler) CreateFeature(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		h.logger.Error("Failed to decode feature", zap.Error(err))
-		return // BuildFromRequest already wrote error response
+		if renderDecodeError(w, r, err) {
+			return
+		}
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		return
+	}
+	if err := checkRingOrientation(h.cfg, feature.Geometry); err != nil {
+		renderValidationProblem(w, r, "Invalid geometry", err)
+		return
 	}
 
 	// Set collection ID from path
 	feature.CollectionID = collectionID
 
-	if err := h.repo.Create(feature); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(feature); err != nil {
 		h.logger.Error("Failed to create feature", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create feature"})
+		renderRepositoryError(w, r, err, "Feature not found", "Failed to create feature")
 		return
 	}
 
@@ -152,39 +171,45 @@ func (h *FeatureHandler) UpdateFeature(w http.ResponseWriter, r *http.Request) {
 	collectionID := chi.URLParam(r, "collectionId")
 	featureID := chi.URLParam(r, "featureId")
 	if basePath, ok := canonicalCollectionPaths[collectionID]; ok {
-		redirectToCanonical(w, r, basePath+"/"+featureID)
+		redirectToCanonical(w, r, h.canonicalURL(basePath+"/"+featureID))
 		return
 	}
 
-	existing, err := h.repo.GetByCollectionAndID(collectionID, featureID)
+	existing, err := h.repo.WithContext(r.Context()).GetByCollectionAndID(collectionID, featureID)
 	if err != nil {
 		h.logger.Error("Feature not found",
 			zap.String("collectionId", collectionID),
 			zap.String("featureId", featureID))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Feature not found"})
+		renderRepositoryError(w, r, err, "Feature not found", "Internal server error")
 		return
 	}
 
 	updated, err := h.fc.Deserialize(r.Header.Get("content-type"), r.Body)
 	if err != nil {
 		h.logger.Error("Failed to decode feature", zap.Error(err))
-		return // BuildFromRequest already wrote error response
+		if renderDecodeError(w, r, err) {
+			return
+		}
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+		return
+	}
+	if err := checkRingOrientation(h.cfg, updated.Geometry); err != nil {
+		renderValidationProblem(w, r, "Invalid geometry", err)
+		return
 	}
 
 	// Preserve ID and collection
 	updated.ID = existing.ID
 	updated.CollectionID = collectionID
 
-	if err := h.repo.Update(updated); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(updated); err != nil {
 		h.logger.Error("Failed to update feature", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update feature"})
+		renderRepositoryError(w, r, err, "Feature not found", "Failed to update feature")
 		return
 	}
 
-	json, _ := h.fc.Serialize(r.Header.Get("Accept"), updated)
-	render.JSON(w, r, json)
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // DeleteFeature deletes a feature
@@ -192,24 +217,22 @@ func (h *FeatureHandler) DeleteFeature(w http.ResponseWriter, r *http.Request) {
 	collectionID := chi.URLParam(r, "collectionId")
 	featureID := chi.URLParam(r, "featureId")
 	if basePath, ok := canonicalCollectionPaths[collectionID]; ok {
-		redirectToCanonical(w, r, basePath+"/"+featureID)
+		redirectToCanonical(w, r, h.canonicalURL(basePath+"/"+featureID))
 		return
 	}
 
-	_, err := h.repo.GetByCollectionAndID(collectionID, featureID)
+	_, err := h.repo.WithContext(r.Context()).GetByCollectionAndID(collectionID, featureID)
 	if err != nil {
 		h.logger.Error("Feature not found",
 			zap.String("collectionId", collectionID),
 			zap.String("featureId", featureID))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Feature not found"})
+		renderRepositoryError(w, r, err, "Feature not found", "Internal server error")
 		return
 	}
 
-	if err := h.repo.Delete(featureID); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(featureID); err != nil {
 		h.logger.Error("Failed to delete feature", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete feature"})
+		renderRepositoryError(w, r, err, "Feature not found", "Failed to delete feature")
 		return
 	}
 
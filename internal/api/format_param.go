@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yourusername/connected-systems-go/internal/model/formaters"
+)
+
+// formatQueryParam lets clients choose a representation with ?f=geojson,
+// ?f=sml or ?f=json (and ?f=csv or ?f=ndjson on the systems collection)
+// instead of an Accept header, which makes the alternate links emitted by the
+// formatters directly dereferenceable. Unknown values are ignored and normal
+// content negotiation applies.
+func formatQueryParam(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f := r.URL.Query().Get("f"); f != "" {
+			if contentType, ok := formaters.ContentTypeForFormat(f); ok {
+				r.Header.Set("Accept", contentType)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
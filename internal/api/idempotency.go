@@ -0,0 +1,218 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+)
+
+// IdempotencyKeyHeader is the request header clients use to make create
+// requests safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyPendingTTL bounds how long a key stays reserved by a request
+// that has not finished, so a lost request cannot hold it forever.
+const idempotencyPendingTTL = 5 * time.Minute
+
+// idempotencyPruneEvery is how often expired entries are dropped from the
+// store.
+const idempotencyPruneEvery = time.Minute
+
+// IdempotencyStore remembers the outcome of create requests carrying an
+// Idempotency-Key so that a retried request replays the original response
+// instead of creating the resource a second time. Keys are scoped per resource
+// type and kept in memory until their TTL expires.
+type IdempotencyStore struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	maxBodySize int64
+	entries     map[string]*idempotencyEntry
+	lastPrune   time.Time
+	now         func() time.Time
+}
+
+type idempotencyEntry struct {
+	requestHash [sha256.Size]byte
+	completed   bool
+	expiresAt   time.Time
+
+	status int
+	header http.Header
+	body   []byte
+}
+
+// NewIdempotencyStore creates an IdempotencyStore whose keys expire after ttl.
+// Request bodies, which are hashed to detect a reused key, are read up to
+// maxBodySize bytes; larger ones are answered with 413.
+func NewIdempotencyStore(ttl time.Duration, maxBodySize int64) *IdempotencyStore {
+	return &IdempotencyStore{ttl: ttl, maxBodySize: maxBodySize, entries: make(map[string]*idempotencyEntry), now: time.Now}
+}
+
+// Middleware wraps a create handler for the given resource scope, and for the
+// request's tenant when tenancy is enabled. Only
+// successful (2xx) responses are remembered; failures, including a handler
+// panic, release the key so the client can retry. A key identifies one
+// request: reusing it with a different method, path (such as another parent
+// system) or body is rejected with 422, and a repeat that arrives while
+// the first request is still being processed is rejected with 409. Validate-only
+// requests create nothing, so they neither use nor consume the key.
+func (s *IdempotencyStore) Middleware(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || validateOnlyRequested(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBodySize))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					render.Status(r, http.StatusRequestEntityTooLarge)
+					render.JSON(w, r, map[string]string{"error": "Request body too large"})
+					return
+				}
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, map[string]string{"error": "Invalid request body"})
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			requestHash := idempotencyRequestHash(r, body)
+
+			storeKey := scope + "\x00" + key
+			if tenantID, ok := repository.TenantFromContext(r.Context()); ok {
+				storeKey = tenantID + "\x00" + storeKey
+			}
+			entry, reserved := s.reserve(storeKey, requestHash)
+			if reserved == nil {
+				switch {
+				case entry.requestHash != requestHash:
+					render.Status(r, http.StatusUnprocessableEntity)
+					render.JSON(w, r, map[string]string{"error": "Idempotency-Key was already used with a different request"})
+				case !entry.completed:
+					render.Status(r, http.StatusConflict)
+					render.JSON(w, r, map[string]string{"error": "A request with this Idempotency-Key is still being processed"})
+				default:
+					replayIdempotentResponse(w, entry)
+				}
+				return
+			}
+
+			rec := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+			panicked := true
+			defer func() {
+				if panicked {
+					rec.status = http.StatusInternalServerError
+				}
+				s.complete(storeKey, reserved, rec)
+			}()
+			next.ServeHTTP(rec, r)
+			panicked = false
+		})
+	}
+}
+
+// idempotencyRequestHash identifies the request a key was first used with by
+// its method, path and body.
+func idempotencyRequestHash(r *http.Request, body []byte) [sha256.Size]byte {
+	h := sha256.New()
+	io.WriteString(h, r.Method+"\x00"+r.URL.Path+"\x00")
+	h.Write(body)
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// reserve returns a copy of the live entry for key or, when there is none,
+// inserts a pending one and returns it as reserved.
+func (s *IdempotencyStore) reserve(key string, requestHash [sha256.Size]byte) (entry idempotencyEntry, reserved *idempotencyEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastPrune) >= idempotencyPruneEvery {
+		s.prune(now)
+	}
+
+	if existing, ok := s.entries[key]; ok && !now.After(existing.expiresAt) {
+		return *existing, nil
+	}
+
+	reserved = &idempotencyEntry{requestHash: requestHash, expiresAt: now.Add(idempotencyPendingTTL)}
+	s.entries[key] = reserved
+	return idempotencyEntry{}, reserved
+}
+
+// prune drops the entries that have expired at now, completed or not.
+func (s *IdempotencyStore) prune(now time.Time) {
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastPrune = now
+}
+
+// complete stores the recorded response in entry, or forgets the key when the
+// request did not succeed. Nothing happens when entry expired and key has
+// since been reserved again.
+func (s *IdempotencyStore) complete(key string, entry *idempotencyEntry, rec *idempotencyRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries[key] != entry {
+		return
+	}
+	if rec.status < 200 || rec.status >= 300 {
+		delete(s.entries, key)
+		return
+	}
+
+	entry.completed = true
+	entry.expiresAt = s.now().Add(s.ttl)
+	entry.status = rec.status
+	entry.header = rec.Header().Clone()
+	entry.body = rec.body.Bytes()
+}
+
+func replayIdempotentResponse(w http.ResponseWriter, entry idempotencyEntry) {
+	for name, values := range entry.header {
+		w.Header()[name] = values
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(entry.status)
+	w.Write(entry.body) //nolint:errcheck
+}
+
+// idempotencyRecorder passes the response through while keeping a copy of the
+// status and body for later replay.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *idempotencyRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *idempotencyRecorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func idempotentPost(t *testing.T, handler http.Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/systems", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyStore_ReplaysSuccess(t *testing.T) {
+	created := 0
+	handler := NewIdempotencyStore(time.Hour, DefaultMaxRequestBodySize).Middleware("systems")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	assert.Equal(t, http.StatusCreated, idempotentPost(t, handler, `{"name":"x"}`).Code)
+	replay := idempotentPost(t, handler, `{"name":"x"}`)
+	assert.Equal(t, http.StatusCreated, replay.Code)
+	assert.Equal(t, "true", replay.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, http.StatusUnprocessableEntity, idempotentPost(t, handler, `{"name":"y"}`).Code)
+	assert.Equal(t, 1, created)
+}
+
+func TestIdempotencyStore_PanicReleasesKey(t *testing.T) {
+	calls := 0
+	handler := NewIdempotencyStore(time.Hour, DefaultMaxRequestBodySize).Middleware("systems")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	require.Panics(t, func() { idempotentPost(t, handler, `{}`) })
+	assert.Equal(t, http.StatusCreated, idempotentPost(t, handler, `{}`).Code, "the retry must not be answered with 409")
+}
+
+func TestIdempotencyStore_PendingKeysExpire(t *testing.T) {
+	store := NewIdempotencyStore(time.Hour, DefaultMaxRequestBodySize)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	hash := [32]byte{1}
+	_, reserved := store.reserve("key", hash)
+	require.NotNil(t, reserved)
+	entry, again := store.reserve("key", hash)
+	assert.Nil(t, again, "a pending key is not reserved twice")
+	assert.False(t, entry.completed)
+
+	now = now.Add(idempotencyPendingTTL + time.Second)
+	_, again = store.reserve("key", hash)
+	assert.NotNil(t, again, "an abandoned reservation expires")
+
+	// The late request must not overwrite the new reservation
+	store.complete("key", reserved, &idempotencyRecorder{ResponseWriter: httptest.NewRecorder(), status: http.StatusCreated})
+	assert.False(t, store.entries["key"].completed)
+}
+
+func TestIdempotencyStore_PrunesExpiredEntries(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute, DefaultMaxRequestBodySize)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.reserve("stale", [32]byte{1})
+	now = now.Add(idempotencyPendingTTL + idempotencyPruneEvery)
+	store.reserve("fresh", [32]byte{2})
+
+	assert.NotContains(t, store.entries, "stale")
+	assert.Contains(t, store.entries, "fresh")
+}
+
+func TestIdempotencyStore_LimitsBody(t *testing.T) {
+	called := false
+	handler := NewIdempotencyStore(time.Hour, 8).Middleware("systems")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	rec := idempotentPost(t, handler, `{"name":"too long"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.False(t, called)
+}
+
+func TestIdempotencyStore_KeyIsBoundToPath(t *testing.T) {
+	var created []string
+	handler := NewIdempotencyStore(time.Hour, DefaultMaxRequestBodySize).Middleware("datastreams")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created = append(created, r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	post := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name":"x"}`))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusCreated, post("/systems/A/datastreams").Code)
+	other := post("/systems/B/datastreams")
+	assert.Equal(t, http.StatusUnprocessableEntity, other.Code, "another parent must not replay the first response")
+	assert.Empty(t, other.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, []string{"/systems/A/datastreams"}, created)
+}
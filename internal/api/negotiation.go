@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/yourusername/connected-systems-go/internal/model/formaters"
+)
+
+// negotiatedVary lists the request headers a content-negotiated response
+// depends on. Accept-Encoding is added by compressResponses.
+var negotiatedVary = []string{"Accept"}
+
+// setNegotiatedContentType sets the Content-Type picked by content negotiation
+// and adds Vary so shared caches keep one entry per representation.
+func setNegotiatedContentType(w http.ResponseWriter, contentType string) {
+	addVary(w.Header(), negotiatedVary...)
+	w.Header().Set("Content-Type", withUTF8Charset(contentType))
+}
+
+// renderNegotiatedJSON writes v as JSON with the Content-Type picked by
+// content negotiation. It replaces render.JSON, which always sends
+// application/json, and honors a status set with render.Status.
+func renderNegotiatedJSON(w http.ResponseWriter, r *http.Request, contentType string, v any) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(true)
+	if err := enc.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	setNegotiatedContentType(w, contentType)
+	if status, ok := r.Context().Value(render.StatusCtxKey).(int); ok {
+		w.WriteHeader(status)
+	}
+	w.Write(buf.Bytes()) //nolint:errcheck
+}
+
+// renderNegotiatedJSONWithETag is renderJSONWithETag for a representation
+// picked by content negotiation. Vary is sent on both the 200 and 304 paths.
+func renderNegotiatedJSONWithETag(w http.ResponseWriter, r *http.Request, contentType string, v any) {
+	addVary(w.Header(), negotiatedVary...)
+	renderJSONWithETag(w, r, withUTF8Charset(contentType), v)
+}
+
+// withUTF8Charset adds charset=utf-8 to a JSON media type that does not
+// already name a charset, e.g. application/geo+json becomes
+// "application/geo+json; charset=utf-8".
+func withUTF8Charset(contentType string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	if _, ok := params["charset"]; ok {
+		return contentType
+	}
+	params["charset"] = "utf-8"
+	return mime.FormatMediaType(mediaType, params)
+}
+
+// NotAcceptableProblem is the problem document returned with 406 when the
+// Accept header admits none of the media types a resource is available in.
+type NotAcceptableProblem struct {
+	Type                string   `json:"type"`
+	Title               string   `json:"title"`
+	Status              int      `json:"status"`
+	Detail              string   `json:"detail"`
+	Error               string   `json:"error"`
+	SupportedMediaTypes []string `json:"supportedMediaTypes"`
+}
+
+// requireAcceptable reports whether fc can produce a representation the
+// request accepts. When it cannot, it writes a 406 problem listing the
+// supported media types instead of falling back to the default format.
+func requireAcceptable[T any](w http.ResponseWriter, r *http.Request, fc *formaters.MultiFormatFormatterCollection[T]) bool {
+	if fc.Acceptable(r.Header.Get("Accept")) {
+		return true
+	}
+
+	supported := fc.ContentTypes()
+	detail := "supported media types: " + strings.Join(supported, ", ")
+	addVary(w.Header(), negotiatedVary...)
+	render.Status(r, http.StatusNotAcceptable)
+	render.JSON(w, r, NotAcceptableProblem{
+		Type:                "about:blank",
+		Title:               "Not Acceptable",
+		Status:              http.StatusNotAcceptable,
+		Detail:              detail,
+		Error:               "Not Acceptable: " + detail,
+		SupportedMediaTypes: supported,
+	})
+	return false
+}
+
+// addVary appends each of names to the Vary header unless already listed.
+func addVary(h http.Header, names ...string) {
+	present := make(map[string]bool)
+	for _, value := range h.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			present[strings.ToLower(strings.TrimSpace(name))] = true
+		}
+	}
+	for _, name := range names {
+		if present[strings.ToLower(name)] {
+			continue
+		}
+		present[strings.ToLower(name)] = true
+		h.Add("Vary", name)
+	}
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -17,10 +19,7 @@ import (
 )
 
 // ObservationCollectionResponse follows observation-only.yaml collection shape.
-type ObservationCollectionResponse struct {
-	Items []any               `json:"items"`
-	Links common_shared.Links `json:"links,omitempty"`
-}
+type ObservationCollectionResponse = ItemCollectionResponse
 
 // ObservationHandler handles Observation resource requests.
 type ObservationHandler struct {
@@ -40,109 +39,118 @@ func NewObservationHandler(cfg *config.Config, logger *zap.Logger, repo *reposit
 }
 
 func (h *ObservationHandler) ListObservations(w http.ResponseWriter, r *http.Request) {
-	params := queryparams.ObservationsQueryParams{}.BuildFromRequest(r)
-
-	observations, total, err := h.repo.List(params, nil)
+	params, err := queryparams.ObservationsQueryParams{}.BuildFromRequest(r)
 	if err != nil {
-		h.logger.Error("Failed to list observations", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
 		return
 	}
 
-	items := make([]any, 0, len(observations))
-	for _, obs := range observations {
-		items = append(items, obs)
-	}
-
-	totalInt := int(total)
-	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(observations))
-
-	w.Header().Set("Content-Type", "application/json")
-	render.JSON(w, r, ObservationCollectionResponse{Items: items, Links: links})
+	h.streamObservations(w, r, params, nil)
 }
 
 func (h *ObservationHandler) ListDatastreamObservations(w http.ResponseWriter, r *http.Request) {
 	datastreamID := chi.URLParam(r, "dataStreamId")
-	if _, err := h.datastreamRepo.GetByID(datastreamID); err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Datastream not found"})
+	if _, err := h.datastreamRepo.WithContext(r.Context()).GetByID(datastreamID); err != nil {
+		renderRepositoryError(w, r, err, "Datastream not found", "Internal server error")
 		return
 	}
 
-	params := queryparams.ObservationsQueryParams{}.BuildFromRequest(r)
-
-	observations, total, err := h.repo.ListByDatastream(datastreamID, params)
+	params, err := queryparams.ObservationsQueryParams{}.BuildFromRequest(r)
 	if err != nil {
-		h.logger.Error("Failed to list observations", zap.String("dataStreamId", datastreamID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
 		return
 	}
 
-	items := make([]any, 0, len(observations))
-	for _, obs := range observations {
-		items = append(items, obs)
-	}
+	h.streamObservations(w, r, params, &datastreamID)
+}
 
-	totalInt := int(total)
-	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(observations))
+// streamObservations writes the observations matching params as a collection,
+// encoding each row as it is scanned so large exports keep memory use flat.
+// Errors before the first item still get a JSON 500; later ones can only be
+// logged and end the response early.
+func (h *ObservationHandler) streamObservations(w http.ResponseWriter, r *http.Request, params *queryparams.ObservationsQueryParams, datastreamID *string) {
+	var total int64
+	each := func(fn func(*domains.Observation) error) error {
+		var err error
+		total, err = h.repo.WithContext(r.Context()).Each(params, datastreamID, fn)
+		return err
+	}
+	links := func(returned int) common_shared.Links {
+		totalInt := int(total)
+		return params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, returned)
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	render.JSON(w, r, ObservationCollectionResponse{Items: items, Links: links})
+	started, err := streamObservationCollection(w, each, links)
+	if err != nil {
+		fields := []zap.Field{zap.Error(err)}
+		if datastreamID != nil {
+			fields = append(fields, zap.String("dataStreamId", *datastreamID))
+		}
+		h.logger.Error("Failed to list observations", fields...)
+		if !started {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		}
+	}
 }
 
 func (h *ObservationHandler) GetObservation(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "obsId")
 
-	obs, err := h.repo.GetByID(id)
+	obs, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get observation", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Observation not found"})
+		renderRepositoryError(w, r, err, "Observation not found", "Internal server error")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	render.JSON(w, r, obs)
+	renderJSONWithETag(w, r, "application/json", obs)
 }
 
 func (h *ObservationHandler) UpdateObservation(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "obsId")
 
-	existing, err := h.repo.GetByID(id)
+	existing, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Observation not found", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Observation not found"})
+		renderRepositoryError(w, r, err, "Observation not found", "Internal server error")
 		return
 	}
 
 	obs, err := decodeObservationPayload(r)
 	if err != nil {
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": err.Error()})
 		return
 	}
 
-	datastream, err := h.datastreamRepo.GetByID(existing.DatastreamID)
+	datastream, err := h.datastreamRepo.WithContext(r.Context()).GetByID(existing.DatastreamID)
 	if err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Parent datastream not found"})
+		renderRepositoryError(w, r, err, "Parent datastream not found", "Internal server error")
 		return
 	}
 	if err := validateObservationAgainstDatastreamSchema(obs, datastream, r.Header.Get("Content-Type")); err != nil {
-		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Observation does not match parent datastream schema: " + err.Error()})
+		renderValidationProblem(w, r, "Observation update does not match parent datastream schema", err)
 		return
 	}
+	obs.ApplyTimeDefaults()
+	if h.cfg.API.EnforceObservationValidTime {
+		if err := validateObservationWithinValidTime(obs, datastream); err != nil {
+			renderValidationProblem(w, r, "Observation update is outside the datastream validTime", err)
+			return
+		}
+	}
 
 	obs.ID = id
 	obs.DatastreamID = existing.DatastreamID
-	if err := h.repo.Update(obs); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(obs); err != nil {
 		h.logger.Error("Failed to update observation", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update observation"})
+		renderRepositoryError(w, r, err, "Observation not found", "Failed to update observation")
 		return
 	}
 
@@ -152,56 +160,176 @@ func (h *ObservationHandler) UpdateObservation(w http.ResponseWriter, r *http.Re
 func (h *ObservationHandler) DeleteObservation(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "obsId")
 
-	if _, err := h.repo.GetByID(id); err != nil {
+	if _, err := h.repo.WithContext(r.Context()).GetByID(id); err != nil {
 		h.logger.Error("Observation not found", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Observation not found"})
+		renderRepositoryError(w, r, err, "Observation not found", "Internal server error")
 		return
 	}
 
-	if err := h.repo.Delete(id); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(id); err != nil {
 		h.logger.Error("Failed to delete observation", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete observation"})
+		renderRepositoryError(w, r, err, "Observation not found", "Failed to delete observation")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ObservationBatchSummary is returned when an array of observations is created in one request.
+type ObservationBatchSummary struct {
+	Count int                 `json:"count"`
+	IDs   []string            `json:"ids"`
+	Links common_shared.Links `json:"links,omitempty"`
+}
+
+// CreateDatastreamObservation accepts a single observation or a JSON array of
+// observations. A single observation is answered with 201 and a Location header;
+// an array is stored atomically and answered with a summary of the created ids.
 func (h *ObservationHandler) CreateDatastreamObservation(w http.ResponseWriter, r *http.Request) {
 	datastreamID := chi.URLParam(r, "dataStreamId")
-	datastream, err := h.datastreamRepo.GetByID(datastreamID)
+	datastream, err := h.datastreamRepo.WithContext(r.Context()).GetByID(datastreamID)
 	if err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Datastream not found"})
+		renderRepositoryError(w, r, err, "Datastream not found", "Internal server error")
 		return
 	}
 
-	obs, err := decodeObservationPayload(r)
+	observations, batch, err := decodeObservationPayloads(r)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": err.Error()})
 		return
 	}
 
-	if err := validateObservationAgainstDatastreamSchema(obs, datastream, r.Header.Get("Content-Type")); err != nil {
-		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Observation does not match parent datastream schema: " + err.Error()})
+	var violations SchemaViolations
+	for i, obs := range observations {
+		obs.ApplyTimeDefaults()
+		title := "Observation does not match parent datastream schema"
+		err := validateObservationAgainstDatastreamSchema(obs, datastream, r.Header.Get("Content-Type"))
+		if err == nil && h.cfg.API.EnforceObservationValidTime {
+			title = "Observation is outside the datastream validTime"
+			err = validateObservationWithinValidTime(obs, datastream)
+		}
+		if err == nil {
+			continue
+		}
+		if !batch {
+			if reportValidation(w, r, err) {
+				return
+			}
+			renderValidationProblem(w, r, title, err)
+			return
+		}
+		violations = append(violations, prefixViolations(err, fmt.Sprintf("[%d]", i))...)
+	}
+	if reportValidation(w, r, violations.errOrNil()) {
+		return
+	}
+	if len(violations) > 0 {
+		renderValidationProblem(w, r, "Observations do not match parent datastream schema or validTime", violations)
+		return
+	}
+
+	for _, obs := range observations {
+		obs.DatastreamID = datastreamID
+	}
+
+	if !batch {
+		obs := observations[0]
+		if err := h.repo.WithContext(r.Context()).Create(obs); err != nil {
+			h.logger.Error("Failed to create observation", zap.String("dataStreamId", datastreamID), zap.Error(err))
+			renderRepositoryError(w, r, err, "Observation not found", "Failed to create observation")
+			return
+		}
+
+		location := strings.TrimRight(h.cfg.API.BaseURL, "/") + "/observations/" + obs.ID
+		w.Header().Set("Location", location)
+		w.WriteHeader(http.StatusCreated)
 		return
 	}
 
-	obs.DatastreamID = datastreamID
-	if err := h.repo.Create(obs); err != nil {
-		h.logger.Error("Failed to create observation", zap.String("dataStreamId", datastreamID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create observation"})
+	if err := h.repo.WithContext(r.Context()).CreateBatch(observations); err != nil {
+		h.logger.Error("Failed to create observations", zap.String("dataStreamId", datastreamID), zap.Int("count", len(observations)), zap.Error(err))
+		renderRepositoryError(w, r, err, "Observation not found", "Failed to create observations")
 		return
 	}
 
-	location := strings.TrimRight(h.cfg.API.BaseURL, "/") + "/observations/" + obs.ID
-	w.Header().Set("Location", location)
-	w.WriteHeader(http.StatusCreated)
+	summary := ObservationBatchSummary{Count: len(observations), IDs: make([]string, 0, len(observations))}
+	for _, obs := range observations {
+		summary.IDs = append(summary.IDs, obs.ID)
+		summary.Links = append(summary.Links, common_shared.Link{
+			Rel:  "item",
+			Href: strings.TrimRight(h.cfg.API.BaseURL, "/") + "/observations/" + obs.ID,
+		})
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, summary)
+}
+
+// validateObservationWithinValidTime rejects observations whose phenomenon time
+// falls outside the datastream's declared validTime. It checks the time that
+// will be stored, so callers apply the observation's time defaults first.
+// Datastreams without a validTime accept any time. Callers only apply it when
+// api.enforce_observation_valid_time is enabled.
+func validateObservationWithinValidTime(obs *domains.Observation, ds *domains.Datastream) error {
+	if obs == nil || obs.PhenomenonTime == nil || ds == nil || ds.ValidTime == nil {
+		return nil
+	}
+
+	instant := *obs.PhenomenonTime
+	if ds.ValidTime.Start != nil && instant.Before(*ds.ValidTime.Start) {
+		return SchemaViolations{{Path: "phenomenonTime", Message: "is before the datastream validTime start " + ds.ValidTime.Start.Format(time.RFC3339)}}
+	}
+	if ds.ValidTime.End != nil && instant.After(*ds.ValidTime.End) {
+		return SchemaViolations{{Path: "phenomenonTime", Message: "is after the datastream validTime end " + ds.ValidTime.End.Format(time.RFC3339)}}
+	}
+	return nil
+}
+
+// decodeObservationPayloads decodes either a single observation object or an
+// array of them; batch reports whether the body was an array.
+func decodeObservationPayloads(r *http.Request) (observations []*domains.Observation, batch bool, err error) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, false, err
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		var raw map[string]any
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, false, err
+		}
+		obs, err := observationFromRaw(raw)
+		if err != nil {
+			return nil, false, err
+		}
+		return []*domains.Observation{obs}, false, nil
+	}
+
+	var raws []map[string]any
+	if err := json.Unmarshal(trimmed, &raws); err != nil {
+		return nil, true, err
+	}
+	if len(raws) == 0 {
+		return nil, true, &decodeError{msg: "Observation array must not be empty"}
+	}
+
+	observations = make([]*domains.Observation, 0, len(raws))
+	for i, raw := range raws {
+		obs, err := observationFromRaw(raw)
+		if err != nil {
+			return nil, true, &decodeError{msg: fmt.Sprintf("[%d]: %s", i, err.Error())}
+		}
+		observations = append(observations, obs)
+	}
+	return observations, true, nil
 }
 
 func decodeObservationPayload(r *http.Request) (*domains.Observation, error) {
@@ -209,7 +337,10 @@ func decodeObservationPayload(r *http.Request) (*domains.Observation, error) {
 	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
 		return nil, err
 	}
+	return observationFromRaw(raw)
+}
 
+func observationFromRaw(raw map[string]any) (*domains.Observation, error) {
 	obs := &domains.Observation{}
 
 	if sfID, ok := raw["samplingFeature@id"].(string); ok && sfID != "" {
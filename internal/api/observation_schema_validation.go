@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/emicklei/proto"
@@ -56,19 +58,19 @@ func validateObservationResultWithJSONLikeSchema(obs *domains.Observation, schem
 
 	var value any
 	if err := json.Unmarshal(obs.Result, &value); err != nil {
-		return fmt.Errorf("result is not valid JSON: %w", err)
+		return SchemaViolations{{Path: "result", Message: "is not valid JSON: " + err.Error()}}
 	}
 
-	if err := validateDataComponentValue(component, value, "result"); err != nil {
-		return err
-	}
-
-	return nil
+	var violations SchemaViolations
+	validateDataComponentValue(component, value, "result", &violations)
+	return violations.errOrNil()
 }
 
-func validateDataComponentValue(component *domains.DatastreamDataComponent, value any, path string) error {
-	if component == nil {
-		return nil
+// validateDataComponentValue records every mismatch between value and component
+// in violations rather than stopping at the first one.
+func validateDataComponentValue(component *domains.DatastreamDataComponent, value any, path string, violations *SchemaViolations) {
+	if component == nil || isDeclaredNilValue(component, value) {
+		return
 	}
 
 	// Infer a record shape if explicit type is not provided but fields exist.
@@ -81,7 +83,8 @@ func validateDataComponentValue(component *domains.DatastreamDataComponent, valu
 	case "datarecord":
 		obj, ok := value.(map[string]any)
 		if !ok {
-			return fmt.Errorf("%s must be an object", path)
+			violations.add(path, "must be an object")
+			return
 		}
 		for _, field := range component.Fields {
 			if field.Name == "" {
@@ -92,19 +95,19 @@ func validateDataComponentValue(component *domains.DatastreamDataComponent, valu
 				if field.Optional != nil && *field.Optional {
 					continue
 				}
-				return fmt.Errorf("%s.%s is required by datastream schema", path, field.Name)
-			}
-			if err := validateDataComponentValue(&field.DatastreamDataComponent, fieldVal, path+"."+field.Name); err != nil {
-				return err
+				violations.add(path+"."+field.Name, "is required by datastream schema")
+				continue
 			}
+			validateDataComponentValue(&field.DatastreamDataComponent, fieldVal, path+"."+field.Name, violations)
 		}
-		return nil
+		addUndeclaredMembers(obj, component.Fields, path, violations)
 
 	case "vector":
 		// Vectors are commonly encoded as objects in this API.
 		obj, ok := value.(map[string]any)
 		if !ok {
-			return fmt.Errorf("%s must be an object for Vector schema", path)
+			violations.add(path, "must be an object for Vector schema")
+			return
 		}
 		for _, coord := range component.Coordinates {
 			if coord.Name == "" {
@@ -115,86 +118,115 @@ func validateDataComponentValue(component *domains.DatastreamDataComponent, valu
 				if coord.Optional != nil && *coord.Optional {
 					continue
 				}
-				return fmt.Errorf("%s.%s is required by datastream vector schema", path, coord.Name)
-			}
-			if err := validateDataComponentValue(&coord.DatastreamDataComponent, coordVal, path+"."+coord.Name); err != nil {
-				return err
+				violations.add(path+"."+coord.Name, "is required by datastream vector schema")
+				continue
 			}
+			validateDataComponentValue(&coord.DatastreamDataComponent, coordVal, path+"."+coord.Name, violations)
 		}
-		return nil
+		addUndeclaredMembers(obj, component.Coordinates, path, violations)
 
 	case "dataarray", "matrix":
 		arr, ok := value.([]any)
 		if !ok {
-			return fmt.Errorf("%s must be an array", path)
+			violations.add(path, "must be an array")
+			return
 		}
 		if component.ElementType != nil {
 			for i, item := range arr {
-				if err := validateDataComponentValue(&component.ElementType.DatastreamDataComponent, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
-					return err
-				}
+				validateDataComponentValue(&component.ElementType.DatastreamDataComponent, item, fmt.Sprintf("%s[%d]", path, i), violations)
 			}
 		}
-		return nil
 
 	case "datachoice":
 		for _, item := range component.Items {
-			if err := validateDataComponentValue(&item.DatastreamDataComponent, value, path); err == nil {
-				return nil
+			var itemViolations SchemaViolations
+			validateDataComponentValue(&item.DatastreamDataComponent, value, path, &itemViolations)
+			if len(itemViolations) == 0 {
+				return
 			}
 		}
-		return fmt.Errorf("%s does not match any allowed DataChoice item", path)
+		violations.add(path, "does not match any allowed DataChoice item")
 
 	case "geometry":
 		obj, ok := value.(map[string]any)
 		if !ok {
-			return fmt.Errorf("%s must be a geometry object", path)
+			violations.add(path, "must be a geometry object")
+			return
 		}
 		if _, ok := obj["type"]; !ok {
-			return fmt.Errorf("%s.type is required for geometry", path)
+			violations.add(path+".type", "is required for geometry")
 		}
 		if _, ok := obj["coordinates"]; !ok {
-			return fmt.Errorf("%s.coordinates is required for geometry", path)
+			violations.add(path+".coordinates", "is required for geometry")
 		}
-		return nil
 
 	case "boolean":
 		if _, ok := value.(bool); !ok {
-			return fmt.Errorf("%s must be a boolean", path)
+			violations.add(path, "must be a boolean")
 		}
-		return nil
 
 	case "count":
 		if !isIntegerNumber(value) {
-			return fmt.Errorf("%s must be an integer", path)
+			violations.add(path, "must be an integer")
 		}
-		return nil
 
 	case "quantity":
 		if !isNumber(value) {
-			return fmt.Errorf("%s must be a number", path)
+			violations.add(path, "must be a number")
 		}
-		return nil
 
 	case "time", "category", "text":
 		if _, ok := value.(string); !ok {
-			return fmt.Errorf("%s must be a string", path)
+			violations.add(path, "must be a string")
 		}
-		return nil
 
 	case "countrange", "quantityrange", "timerange", "categoryrange":
 		arr, ok := value.([]any)
 		if !ok || len(arr) != 2 {
-			return fmt.Errorf("%s must be a 2-item array", path)
+			violations.add(path, "must be a 2-item array")
 		}
-		return nil
 
 	default:
 		// Unknown/extension component: accept.
-		return nil
 	}
 }
 
+// addUndeclaredMembers records every member of obj that is not one of the
+// declared components, in sorted order, since a misspelled field name would
+// otherwise only surface as the missing field it was meant to be.
+func addUndeclaredMembers(obj map[string]any, declared []domains.DatastreamNamedComponent, path string, violations *SchemaViolations) {
+	if len(declared) == 0 {
+		return
+	}
+	names := make(map[string]bool, len(declared))
+	for _, component := range declared {
+		names[component.Name] = true
+	}
+	var undeclared []string
+	for name := range obj {
+		if !names[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		violations.add(path+"."+name, "is not defined by datastream schema")
+	}
+}
+
+// isDeclaredNilValue reports whether value is one of the component's reserved
+// nilValues (e.g. -9999 or "NaN" for a missing measurement), which stand in
+// for a value of any type.
+func isDeclaredNilValue(component *domains.DatastreamDataComponent, value any) bool {
+	for _, nilValue := range component.NilValues {
+		var reserved any
+		if err := json.Unmarshal(nilValue.Value, &reserved); err == nil && reflect.DeepEqual(reserved, value) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateObservationResultWithProtobufSchema(obs *domains.Observation, schema *domains.DatastreamSchema) error {
 	if obs.ResultLink != nil || len(obs.Result) == 0 {
 		return nil
@@ -218,19 +250,17 @@ func validateObservationResultWithProtobufSchema(obs *domains.Observation, schem
 
 	var result any
 	if err := json.Unmarshal(obs.Result, &result); err != nil {
-		return fmt.Errorf("protobuf result must be JSON object in this API: %w", err)
+		return SchemaViolations{{Path: "result", Message: "must be a JSON object in this API: " + err.Error()}}
 	}
 
 	obj, ok := result.(map[string]any)
 	if !ok {
-		return fmt.Errorf("protobuf result must be a JSON object")
+		return SchemaViolations{{Path: "result", Message: "must be a JSON object"}}
 	}
 
-	if err := validateAgainstProtoMessage(message, obj, "result"); err != nil {
-		return err
-	}
-
-	return nil
+	var violations SchemaViolations
+	validateAgainstProtoMessage(message, obj, "result", &violations)
+	return violations.errOrNil()
 }
 
 func firstMessage(definition *proto.Proto) *proto.Message {
@@ -245,31 +275,36 @@ func firstMessage(definition *proto.Proto) *proto.Message {
 	return nil
 }
 
-func validateAgainstProtoMessage(msg *proto.Message, obj map[string]any, path string) error {
+func validateAgainstProtoMessage(msg *proto.Message, obj map[string]any, path string, violations *SchemaViolations) {
 	if msg == nil {
-		return nil
+		return
 	}
 
 	fields := map[string]*proto.NormalField{}
 	mapFields := map[string]*proto.MapField{}
 	nested := map[string]*proto.Message{}
+	var fieldNames, mapFieldNames []string
 
 	for _, element := range msg.Elements {
 		switch e := element.(type) {
 		case *proto.NormalField:
 			fields[e.Name] = e
+			fieldNames = append(fieldNames, e.Name)
 		case *proto.MapField:
 			mapFields[e.Name] = e
+			mapFieldNames = append(mapFieldNames, e.Name)
 		case *proto.Message:
 			nested[e.Name] = e
 		}
 	}
 
-	for name, field := range fields {
+	// Walk fields in declaration order so violations are reported deterministically.
+	for _, name := range fieldNames {
+		field := fields[name]
 		val, exists := obj[name]
 		if !exists {
 			if field.Required {
-				return fmt.Errorf("%s.%s is required by protobuf schema", path, name)
+				violations.add(path+"."+name, "is required by protobuf schema")
 			}
 			continue
 		}
@@ -277,65 +312,60 @@ func validateAgainstProtoMessage(msg *proto.Message, obj map[string]any, path st
 		if field.Repeated {
 			arr, ok := val.([]any)
 			if !ok {
-				return fmt.Errorf("%s.%s must be an array (repeated field)", path, name)
+				violations.add(path+"."+name, "must be an array (repeated field)")
+				continue
 			}
 			for i, item := range arr {
-				if err := validateProtoScalarOrMessage(field.Type, nested, item, fmt.Sprintf("%s.%s[%d]", path, name, i)); err != nil {
-					return err
-				}
+				validateProtoScalarOrMessage(field.Type, nested, item, fmt.Sprintf("%s.%s[%d]", path, name, i), violations)
 			}
 			continue
 		}
 
-		if err := validateProtoScalarOrMessage(field.Type, nested, val, path+"."+name); err != nil {
-			return err
-		}
+		validateProtoScalarOrMessage(field.Type, nested, val, path+"."+name, violations)
 	}
 
-	for name := range mapFields {
+	for _, name := range mapFieldNames {
 		if val, exists := obj[name]; exists {
 			if _, ok := val.(map[string]any); !ok {
-				return fmt.Errorf("%s.%s must be an object (map field)", path, name)
+				violations.add(path+"."+name, "must be an object (map field)")
 			}
 		}
 	}
-
-	return nil
 }
 
-func validateProtoScalarOrMessage(fieldType string, nested map[string]*proto.Message, value any, path string) error {
+func validateProtoScalarOrMessage(fieldType string, nested map[string]*proto.Message, value any, path string, violations *SchemaViolations) {
 	lower := strings.ToLower(fieldType)
 
 	if nestedMsg, ok := nested[fieldType]; ok {
 		obj, ok := value.(map[string]any)
 		if !ok {
-			return fmt.Errorf("%s must be an object for message type %s", path, fieldType)
+			violations.add(path, "must be an object for message type "+fieldType)
+			return
 		}
-		return validateAgainstProtoMessage(nestedMsg, obj, path)
+		validateAgainstProtoMessage(nestedMsg, obj, path, violations)
+		return
 	}
 
 	switch lower {
 	case "double", "float":
 		if !isNumber(value) {
-			return fmt.Errorf("%s must be numeric", path)
+			violations.add(path, "must be numeric")
 		}
 	case "int32", "sint32", "sfixed32", "fixed32", "uint32", "int64", "sint64", "sfixed64", "fixed64", "uint64":
 		if !isIntegerNumber(value) {
-			return fmt.Errorf("%s must be an integer", path)
+			violations.add(path, "must be an integer")
 		}
 	case "bool":
 		if _, ok := value.(bool); !ok {
-			return fmt.Errorf("%s must be a boolean", path)
+			violations.add(path, "must be a boolean")
 		}
 	case "string", "bytes":
 		if _, ok := value.(string); !ok {
-			return fmt.Errorf("%s must be a string", path)
+			violations.add(path, "must be a string")
 		}
 	default:
 		// Unknown/custom type: allow object or scalar.
 	}
-
-	return nil
 }
 
 func isNumber(v any) bool {
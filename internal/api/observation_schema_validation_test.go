@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"github.com/yourusername/connected-systems-go/internal/model/generators"
+)
+
+func TestValidateObservationAgainstDatastreamSchema_JSONRecord(t *testing.T) {
+	datastream := generators.FakeDatastreamJSONRecord()
+	datastream.Schema.ResultSchema.Fields[0].NilValues = []domains.DatastreamNilValue{
+		{Reason: "http://www.opengis.net/def/nil/OGC/0/missing", Value: json.RawMessage(`"NaN"`)},
+	}
+
+	tests := map[string]struct {
+		result    string
+		wantPaths []string
+	}{
+		"valid":                {result: `{"temperature": 21.5, "humidity": 40}`},
+		"optional field given": {result: `{"temperature": 21.5, "humidity": 40, "status": "ok"}`},
+		"nil value":            {result: `{"temperature": "NaN", "humidity": 40}`},
+		"missing field":        {result: `{"temperature": 21.5}`, wantPaths: []string{"result.humidity"}},
+		"wrong type":           {result: `{"temperature": "warm", "humidity": 40}`, wantPaths: []string{"result.temperature"}},
+		"undeclared fields": {
+			result:    `{"temperature": 21.5, "humidity": 40, "pressure": 1013, "Humidity": 41}`,
+			wantPaths: []string{"result.Humidity", "result.pressure"},
+		},
+		"misspelled field": {
+			result:    `{"temperature": 21.5, "humidty": 40}`,
+			wantPaths: []string{"result.humidity", "result.humidty"},
+		},
+		"not a record": {result: `[21.5, 40]`, wantPaths: []string{"result"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			obs := &domains.Observation{Result: json.RawMessage(tt.result)}
+			err := validateObservationAgainstDatastreamSchema(obs, &datastream, "application/json")
+			if len(tt.wantPaths) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var violations SchemaViolations
+			require.True(t, errors.As(err, &violations), "expected SchemaViolations, got %v", err)
+			paths := make([]string, 0, len(violations))
+			for _, violation := range violations {
+				paths = append(paths, violation.Path)
+			}
+			assert.Equal(t, tt.wantPaths, paths)
+		})
+	}
+}
+
+func TestValidateObservationWithinValidTime_AfterTimeDefaults(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	datastream := &domains.Datastream{ValidTime: &common_shared.TimeRange{Start: &start, End: &end}}
+
+	tests := map[string]struct {
+		obs      domains.Observation
+		wantPath string
+	}{
+		"result time inside": {obs: domains.Observation{ResultTime: start.Add(time.Hour)}},
+		"result time outside": {
+			obs:      domains.Observation{ResultTime: end.Add(time.Hour)},
+			wantPath: "phenomenonTime",
+		},
+		"no times defaults to now": {obs: domains.Observation{}, wantPath: "phenomenonTime"},
+		"phenomenon time wins": {obs: domains.Observation{
+			ResultTime:     end.Add(time.Hour),
+			PhenomenonTime: &start,
+		}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			obs := tt.obs
+			obs.ApplyTimeDefaults()
+			err := validateObservationWithinValidTime(&obs, datastream)
+			if tt.wantPath == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var violations SchemaViolations
+			require.True(t, errors.As(err, &violations), "expected SchemaViolations, got %v", err)
+			require.Len(t, violations, 1)
+			assert.Equal(t, tt.wantPath, violations[0].Path)
+		})
+	}
+}
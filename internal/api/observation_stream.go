@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"github.com/yourusername/connected-systems-go/internal/model/formaters"
+)
+
+// observationStreamFlushEvery is how many items are encoded between flushes
+// of a streamed observation collection.
+const observationStreamFlushEvery = 100
+
+// streamObservationCollection writes an ObservationCollectionResponse whose
+// items are produced one at a time by each, stamped with the time the first
+// item was written, encoding and periodically
+// flushing them as they arrive so memory use does not grow with the result.
+// links is called once every item has been written, with the number written.
+// The status line is only sent with the first item (or after an empty run),
+// so started reports whether an error can still be answered with a 500.
+func streamObservationCollection(w http.ResponseWriter, each func(fn func(*domains.Observation) error) error, links func(returned int) common_shared.Links) (started bool, err error) {
+	out := bufio.NewWriter(w)
+	rc := http.NewResponseController(w)
+	returned := 0
+	var timeStamp string
+
+	start := func() error {
+		started = true
+		timeStamp = formaters.CollectionTimeStamp()
+		w.Header().Set("Content-Type", withUTF8Charset("application/json"))
+		w.WriteHeader(http.StatusOK)
+		_, err := out.WriteString(`{"items":[`)
+		return err
+	}
+	flush := func() error {
+		if err := out.Flush(); err != nil {
+			return err
+		}
+		// Not every writer can flush; buffered output still reaches the client
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+
+	err = each(func(obs *domains.Observation) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if returned > 0 {
+			if err := out.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		encoded, err := json.Marshal(obs)
+		if err != nil {
+			return err
+		}
+		if _, err := out.Write(encoded); err != nil {
+			return err
+		}
+		returned++
+		if returned%observationStreamFlushEvery == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err != nil {
+		return started, err
+	}
+
+	if _, err := out.WriteString(`],"timeStamp":"` + timeStamp + `"`); err != nil {
+		return started, err
+	}
+	if l := links(returned); len(l) > 0 {
+		encoded, err := json.Marshal(l)
+		if err != nil {
+			return started, err
+		}
+		if _, err := out.WriteString(`,"links":`); err != nil {
+			return started, err
+		}
+		if _, err := out.Write(encoded); err != nil {
+			return started, err
+		}
+	}
+	if _, err := out.WriteString("}\n"); err != nil {
+		return started, err
+	}
+	return started, flush()
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+)
+
+func testObservation(i int) *domains.Observation {
+	obs := &domains.Observation{DatastreamID: "ds-1", Result: json.RawMessage(strconv.Itoa(i))}
+	obs.ID = "obs-" + strconv.Itoa(i)
+	return obs
+}
+
+func TestStreamObservationCollection_WritesBeforeAllRowsAreRead(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rows := 3*observationStreamFlushEvery + 7
+
+	each := func(fn func(*domains.Observation) error) error {
+		for i := 0; i < rows; i++ {
+			// Once a flush interval has passed, earlier rows must already be
+			// on the wire while later rows are still unread
+			if i == observationStreamFlushEvery+1 {
+				assert.True(t, rec.Flushed, "response must be flushed while rows are still being read")
+				assert.Contains(t, rec.Body.String(), `"id":"obs-0"`)
+				assert.NotContains(t, rec.Body.String(), `"id":"obs-`+strconv.Itoa(i)+`"`)
+			}
+			if err := fn(testObservation(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var returned int
+	links := func(n int) common_shared.Links {
+		returned = n
+		return common_shared.Links{{Rel: "next", Href: "http://example.test/observations?offset=10"}}
+	}
+
+	started, err := streamObservationCollection(rec, each, links)
+	require.NoError(t, err)
+	assert.True(t, started)
+	assert.Equal(t, rows, returned)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	var body struct {
+		Items     []map[string]any    `json:"items"`
+		TimeStamp string              `json:"timeStamp"`
+		Links     common_shared.Links `json:"links"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Items, rows)
+	_, err = time.Parse(time.RFC3339, body.TimeStamp)
+	assert.NoError(t, err, "timeStamp %q", body.TimeStamp)
+	for i, item := range body.Items {
+		assert.Equal(t, "obs-"+strconv.Itoa(i), item["id"])
+	}
+	require.Len(t, body.Links, 1)
+	assert.Equal(t, "next", body.Links[0].Rel)
+}
+
+func TestStreamObservationCollection_Empty(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	started, err := streamObservationCollection(rec,
+		func(func(*domains.Observation) error) error { return nil },
+		func(int) common_shared.Links { return nil })
+	require.NoError(t, err)
+	assert.True(t, started)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, []any{}, body["items"])
+	assert.Contains(t, body, "timeStamp")
+}
+
+func TestStreamObservationCollection_ErrorBeforeFirstRow(t *testing.T) {
+	rec := httptest.NewRecorder()
+	queryErr := errors.New("query failed")
+
+	started, err := streamObservationCollection(rec,
+		func(func(*domains.Observation) error) error { return queryErr },
+		func(int) common_shared.Links { return nil })
+	assert.ErrorIs(t, err, queryErr)
+	assert.False(t, started, "nothing may be written so the caller can still send a 500")
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestStreamObservationCollection_ErrorMidStream(t *testing.T) {
+	rec := httptest.NewRecorder()
+	scanErr := errors.New("scan failed")
+
+	started, err := streamObservationCollection(rec,
+		func(fn func(*domains.Observation) error) error {
+			for i := 0; i < observationStreamFlushEvery; i++ {
+				if err := fn(testObservation(i)); err != nil {
+					return err
+				}
+			}
+			return scanErr
+		},
+		func(int) common_shared.Links { return nil })
+	assert.ErrorIs(t, err, scanErr)
+	assert.True(t, started)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), `{"items":[`))
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// routedMethods are the methods probed when listing what a path supports, in
+// the order they appear in Allow headers.
+var routedMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// routeMethods lists the methods a router serves for a path. chi's Mux.Match
+// cannot answer this on its own: mounting a subrouter also registers the bare
+// mount prefix for every method, so Match reports any method there. Instead
+// the router is walked once, after all routes are registered, into a flat mux
+// of placeholder endpoints that has no mounts.
+type routeMethods struct {
+	routes chi.Routes
+	once   sync.Once
+	flat   *chi.Mux
+}
+
+func newRouteMethods(routes chi.Routes) *routeMethods {
+	return &routeMethods{routes: routes}
+}
+
+// allowed returns the methods served for the request's path, followed by
+// OPTIONS, or nil when no route matches the path at all.
+func (rm *routeMethods) allowed(r *http.Request) []string {
+	rm.once.Do(func() {
+		rm.flat = chi.NewRouter()
+		_ = chi.Walk(rm.routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			// Requests arrive without trailing slashes; see stripTrailingSlash
+			if len(route) > 1 {
+				route = strings.TrimSuffix(route, "/")
+			}
+			rm.flat.Method(method, route, http.NotFoundHandler())
+			return nil
+		})
+	})
+
+	path := r.URL.RawPath
+	if path == "" {
+		path = r.URL.Path
+	}
+
+	var methods []string
+	for _, method := range routedMethods {
+		if rm.flat.Match(chi.NewRouteContext(), method, path) {
+			methods = append(methods, method)
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+	return append(methods, http.MethodOptions)
+}
+
+// answerOptions answers OPTIONS requests for any routed path with 204 No
+// Content and an Allow header listing the methods the path supports. CORS
+// preflights never get here; NewCORSMiddleware answers them first.
+func answerOptions(rm *routeMethods) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+			methods := rm.allowed(r)
+			if methods == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// methodNotAllowed answers requests whose path is routed but whose method is
+// not, listing the supported methods in the Allow header like answerOptions.
+func methodNotAllowed(rm *routeMethods) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if methods := rm.allowed(r); methods != nil {
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+		}
+		render.Status(r, http.StatusMethodNotAllowed)
+		render.JSON(w, r, map[string]string{"error": "Method not allowed"})
+	}
+}
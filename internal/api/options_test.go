@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOptionsTestRouter() http.Handler {
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	r := chi.NewRouter()
+	routeMethods := newRouteMethods(r)
+	r.Use(answerOptions(routeMethods))
+	r.MethodNotAllowed(methodNotAllowed(routeMethods))
+	r.Route("/systems", func(r chi.Router) {
+		r.Get("/", ok)
+		r.Post("/", ok)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", ok)
+			r.Put("/", ok)
+			r.Delete("/", ok)
+		})
+	})
+	r.Get("/conformance", ok)
+	return r
+}
+
+func TestAnswerOptions(t *testing.T) {
+	router := newOptionsTestRouter()
+
+	tests := map[string]struct {
+		path      string
+		wantCode  int
+		wantAllow string
+	}{
+		"collection":      {path: "/systems", wantCode: http.StatusNoContent, wantAllow: "GET, POST, OPTIONS"},
+		"item":            {path: "/systems/abc", wantCode: http.StatusNoContent, wantAllow: "GET, PUT, DELETE, OPTIONS"},
+		"top-level route": {path: "/conformance", wantCode: http.StatusNoContent, wantAllow: "GET, OPTIONS"},
+		"unknown path":    {path: "/nowhere", wantCode: http.StatusNotFound},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, tt.path, nil))
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantAllow, rec.Header().Get("Allow"))
+		})
+	}
+}
+
+func TestMethodNotAllowed_ListsAllowedMethods(t *testing.T) {
+	router := newOptionsTestRouter()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/systems/abc", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, PUT, DELETE, OPTIONS", rec.Header().Get("Allow"))
+	assert.JSONEq(t, `{"error":"Method not allowed"}`, rec.Body.String())
+}
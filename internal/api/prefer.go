@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/yourusername/connected-systems-go/internal/model/formaters"
+)
+
+// Values of the RFC 7240 "return" preference.
+const (
+	preferReturnRepresentation = "representation"
+	preferReturnMinimal        = "minimal"
+)
+
+// requestedReturnPreference extracts the return= preference from the Prefer
+// header(s) of r, or "" when the client expressed none.
+func requestedReturnPreference(r *http.Request) string {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			// Parameters after ';' do not apply to the return preference
+			token, _, _ := strings.Cut(preference, ";")
+			name, value, ok := strings.Cut(strings.TrimSpace(token), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "return") {
+				continue
+			}
+			value = strings.ToLower(strings.Trim(strings.TrimSpace(value), `"`))
+			if value == preferReturnRepresentation || value == preferReturnMinimal {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// writeCreated finishes a successful create with 201 and a Location header.
+// With Prefer: return=representation the resource returned by load is included
+// in the body.
+func writeCreated[T any](w http.ResponseWriter, r *http.Request, fc *formaters.MultiFormatFormatterCollection[T], location string, load func() (T, error)) {
+	w.Header().Set("Location", location)
+	writePreferredResult(w, r, fc, http.StatusCreated, http.StatusCreated, load)
+}
+
+// writeUpdated finishes a successful replace with 204, or with 200 and the
+// resource returned by load when the client sent Prefer: return=representation.
+func writeUpdated[T any](w http.ResponseWriter, r *http.Request, fc *formaters.MultiFormatFormatterCollection[T], load func() (T, error)) {
+	writePreferredResult(w, r, fc, http.StatusNoContent, http.StatusOK, load)
+}
+
+// writePreferredResult honors the return preference. If the representation
+// cannot be produced the preference is ignored and the minimal response is sent,
+// as RFC 7240 allows.
+func writePreferredResult[T any](w http.ResponseWriter, r *http.Request, fc *formaters.MultiFormatFormatterCollection[T], minimalStatus, representationStatus int, load func() (T, error)) {
+	preference := requestedReturnPreference(r)
+	addVary(w.Header(), "Prefer")
+
+	if preference == preferReturnRepresentation {
+		acceptHeader := r.Header.Get("Accept")
+		if resource, err := load(); err == nil {
+			if serialized, err := fc.Serialize(acceptHeader, resource); err == nil {
+				if body, err := json.Marshal(serialized); err == nil {
+					w.Header().Set("Preference-Applied", "return="+preferReturnRepresentation)
+					setNegotiatedContentType(w, fc.GetResponseContentType(acceptHeader))
+					w.WriteHeader(representationStatus)
+					w.Write(body) //nolint:errcheck
+					return
+				}
+			}
+		}
+	}
+
+	if preference == preferReturnMinimal {
+		w.Header().Set("Preference-Applied", "return="+preferReturnMinimal)
+	}
+	w.WriteHeader(minimalStatus)
+}
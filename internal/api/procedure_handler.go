@@ -1,7 +1,6 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -29,32 +28,45 @@ func NewProcedureHandler(cfg *config.Config, logger *zap.Logger, repo *repositor
 }
 
 func (h *ProcedureHandler) ListProcedures(w http.ResponseWriter, r *http.Request) {
-	params := queryparams.ProceduresQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.ProceduresQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	procedures, total, err := h.repo.List(params)
+	procedures, total, err := h.repo.WithContext(r.Context()).List(params)
 	if err != nil {
 		h.logger.Error("Failed to list procedures", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Procedure not found", "Internal server error")
 		return
 	}
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.fc.BuildCollection(acceptHeader, procedures, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
+
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
+}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.Status(r, http.StatusOK)
-	json.NewEncoder(w).Encode(collection)
+// GetProcedureByUID redirects GET /procedures/uid/{uid} to the canonical /procedures/{id}
+func (h *ProcedureHandler) GetProcedureByUID(w http.ResponseWriter, r *http.Request) {
+	redirectByUID(w, r, h.logger, h.cfg.API.BaseURL, "procedures", "Procedure", func(uid string) (string, error) {
+		procedure, err := h.repo.WithContext(r.Context()).GetByUID(uid)
+		if err != nil {
+			return "", err
+		}
+		return procedure.ID, nil
+	})
 }
 
 func (h *ProcedureHandler) GetProcedure(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	procedure, err := h.repo.GetByID(id)
+	procedure, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get procedure", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Procedure not found"})
+		renderRepositoryError(w, r, err, "Procedure not found", "Internal server error")
 		return
 	}
 
@@ -67,31 +79,36 @@ func (h *ProcedureHandler) GetProcedure(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.Status(r, http.StatusOK)
-	json.NewEncoder(w).Encode(serialized)
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), serialized)
 }
 
 func (h *ProcedureHandler) CreateProcedure(w http.ResponseWriter, r *http.Request) {
 	contentType := r.Header.Get("Content-Type")
 	procedure, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to deserialize procedure", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
+	if reportCreateValidation(w, r, nil, h.repo.WithContext(r.Context()), string(procedure.UniqueIdentifier)) {
+		return
+	}
 
-	if err := h.repo.Create(procedure); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(procedure); err != nil {
 		h.logger.Error("Failed to create procedure", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create procedure"})
+		renderRepositoryError(w, r, err, "Procedure not found", "Failed to create procedure")
 		return
 	}
 
 	location := strings.TrimRight(h.cfg.API.BaseURL, "/") + "/procedures/" + procedure.ID
-	w.Header().Set("Location", location)
-	w.WriteHeader(http.StatusCreated)
+	writeCreated(w, r, h.fc, location, func() (*domains.Procedure, error) { return h.repo.WithContext(r.Context()).GetByID(procedure.ID) })
 }
 
 func (h *ProcedureHandler) UpdateProcedure(w http.ResponseWriter, r *http.Request) {
@@ -101,29 +118,30 @@ func (h *ProcedureHandler) UpdateProcedure(w http.ResponseWriter, r *http.Reques
 	procedure, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
 		h.logger.Error("Failed to deserialize procedure", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
 
 	procedure.ID = id
-	if err := h.repo.Update(procedure); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(procedure); err != nil {
 		h.logger.Error("Failed to update procedure", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update procedure"})
+		renderRepositoryError(w, r, err, "Procedure not found", "Failed to update procedure")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	writeUpdated(w, r, h.fc, func() (*domains.Procedure, error) { return h.repo.WithContext(r.Context()).GetByID(id) })
 }
 
 func (h *ProcedureHandler) DeleteProcedure(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := h.repo.Delete(id); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(id); err != nil {
 		h.logger.Error("Failed to delete procedure", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete procedure"})
+		renderRepositoryError(w, r, err, "Procedure not found", "Failed to delete procedure")
 		return
 	}
 
@@ -20,41 +20,65 @@ type PropertyHandler struct {
 	logger *zap.Logger
 	repo   *repository.PropertyRepository
 	fc     *formaters.MultiFormatFormatterCollection[*domains.Property]
+
+	datastreamRepo *repository.DatastreamRepository
+	datastreamFC   *formaters.MultiFormatFormatterCollection[*domains.Datastream]
 }
 
 // NewPropertyHandler creates a new PropertyHandler
-func NewPropertyHandler(cfg *config.Config, logger *zap.Logger, repo *repository.PropertyRepository, fc *formaters.MultiFormatFormatterCollection[*domains.Property]) *PropertyHandler {
-	return &PropertyHandler{cfg: cfg, logger: logger, repo: repo, fc: fc}
+func NewPropertyHandler(cfg *config.Config, logger *zap.Logger, repo *repository.PropertyRepository, fc *formaters.MultiFormatFormatterCollection[*domains.Property], datastreamRepo *repository.DatastreamRepository, datastreamFC *formaters.MultiFormatFormatterCollection[*domains.Datastream]) *PropertyHandler {
+	return &PropertyHandler{cfg: cfg, logger: logger, repo: repo, fc: fc, datastreamRepo: datastreamRepo, datastreamFC: datastreamFC}
 }
 
 func (h *PropertyHandler) ListProperties(w http.ResponseWriter, r *http.Request) {
-	params := queryparams.PropertiesQueryParams{}.BuildFromRequest(r)
+	if !requireAcceptable(w, r, h.fc) {
+		return
+	}
+
+	params, err := queryparams.PropertiesQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	properties, total, err := h.repo.List(params)
+	properties, total, err := h.repo.WithContext(r.Context()).List(params)
 	if err != nil {
 		h.logger.Error("Failed to list properties", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Property not found", "Internal server error")
 		return
 	}
 
 	// Use Accept header for content negotiation (not Content-Type)
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.fc.BuildCollection(acceptHeader, properties, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
 
 	// Set the response content type based on the serializer used
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
+}
+
+// GetPropertyByUID redirects GET /properties/uid/{uid} to the canonical /properties/{id}
+func (h *PropertyHandler) GetPropertyByUID(w http.ResponseWriter, r *http.Request) {
+	redirectByUID(w, r, h.logger, h.cfg.API.BaseURL, "properties", "Property", func(uid string) (string, error) {
+		property, err := h.repo.WithContext(r.Context()).GetByUID(uid)
+		if err != nil {
+			return "", err
+		}
+		return property.ID, nil
+	})
 }
 
 func (h *PropertyHandler) GetProperty(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
+	if !requireAcceptable(w, r, h.fc) {
+		return
+	}
 
-	property, err := h.repo.GetByID(id)
+	property, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get property", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Property not found"})
+		renderRepositoryError(w, r, err, "Property not found", "Internal server error")
 		return
 	}
 
@@ -67,33 +91,81 @@ func (h *PropertyHandler) GetProperty(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.Status(r, http.StatusOK)
-	render.JSON(w, r, serialized)
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), serialized)
+}
+
+// ListPropertyDatastreams lists the datastreams observing the property, those
+// whose observedProperties reference its uniqueId. The usual datastream
+// filters and paging apply.
+func (h *PropertyHandler) ListPropertyDatastreams(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	params, err := queryparams.DatastreamsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
+
+	datastreams, total, err := h.datastreamRepo.WithContext(r.Context()).ListByProperty(params, id)
+	if err != nil {
+		h.logger.Error("Failed to list datastreams for property", zap.String("propertyId", id), zap.Error(err))
+		renderRepositoryError(w, r, err, "Property not found", "Internal server error")
+		return
+	}
+
+	acceptHeader := r.Header.Get("Accept")
+	items, err := h.datastreamFC.SerializeAll(acceptHeader, datastreams)
+	if err != nil {
+		h.logger.Error("Failed to serialize datastreams", zap.Error(err))
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to serialize datastreams"})
+		return
+	}
+
+	totalInt := int(total)
+	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(datastreams))
+
+	renderNegotiatedJSON(w, r, h.datastreamFC.GetResponseContentType(acceptHeader), newItemCollection(items, links))
 }
 
 func (h *PropertyHandler) CreateProperty(w http.ResponseWriter, r *http.Request) {
 	contentType := r.Header.Get("Content-Type")
 	property, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to deserialize property", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
+	qualifierErr := validatePropertyQualifiers(property)
+	if reportCreateValidation(w, r, qualifierErr, h.repo.WithContext(r.Context()), string(property.UniqueIdentifier)) {
+		return
+	}
+	if qualifierErr != nil {
+		renderValidationProblem(w, r, "Property qualifiers violate their constraints", qualifierErr)
+		return
+	}
 
-	if err := h.repo.Create(property); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(property); err != nil {
+		if renderDuplicateUID(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to create property", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create property"})
+		renderRepositoryError(w, r, err, "Property not found", "Failed to create property")
 		return
 	}
 	// Per conformance behavior, respond with 201 Created and a Location header
-	// pointing to the newly created resource. Do not include a response body.
+	// pointing to the newly created resource. The body is only included when the
+	// client asks for it with Prefer: return=representation.
 	base := strings.TrimRight(h.cfg.API.BaseURL, "/")
 	location := base + "/properties/" + property.ID
-	w.Header().Set("Location", location)
-	w.WriteHeader(http.StatusCreated)
+	writeCreated(w, r, h.fc, location, func() (*domains.Property, error) { return h.repo.WithContext(r.Context()).GetByID(property.ID) })
 }
 
 func (h *PropertyHandler) UpdateProperty(w http.ResponseWriter, r *http.Request) {
@@ -103,29 +175,37 @@ func (h *PropertyHandler) UpdateProperty(w http.ResponseWriter, r *http.Request)
 	property, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
 		h.logger.Error("Failed to deserialize property", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
+	if err := validatePropertyQualifiers(property); err != nil {
+		renderValidationProblem(w, r, "Property qualifiers violate their constraints", err)
+		return
+	}
 
 	property.ID = id
-	if err := h.repo.Update(property); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(property); err != nil {
+		if renderDuplicateUID(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to update property", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update property"})
+		renderRepositoryError(w, r, err, "Property not found", "Failed to update property")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	writeUpdated(w, r, h.fc, func() (*domains.Property, error) { return h.repo.WithContext(r.Context()).GetByID(id) })
 }
 
 func (h *PropertyHandler) DeleteProperty(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := h.repo.Delete(id); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(id); err != nil {
 		h.logger.Error("Failed to delete property", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete property"})
+		renderRepositoryError(w, r, err, "Property not found", "Failed to delete property")
 		return
 	}
 
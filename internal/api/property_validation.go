@@ -0,0 +1,193 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+)
+
+// validatePropertyQualifiers checks that every Quantity and QuantityRange
+// qualifier has a valid uom and that its value satisfies the AllowedValues
+// constraint declared on it. Qualifiers of other types are accepted as-is, as
+// are values without a constraint.
+func validatePropertyQualifiers(property *domains.Property) error {
+	if property == nil {
+		return nil
+	}
+
+	var violations SchemaViolations
+	for i, qualifier := range property.Qualifiers {
+		path := fmt.Sprintf("qualifiers[%d]", i)
+		switch qualifier.Type {
+		case "Quantity":
+			validateQuantityQualifier(qualifier, path, &violations)
+		case "QuantityRange":
+			validateQuantityRangeQualifier(qualifier, path, &violations)
+		}
+	}
+	return violations.errOrNil()
+}
+
+func validateQuantityQualifier(qualifier common_shared.ComponentWrapper, path string, violations *SchemaViolations) {
+	validateUOM(qualifier.UOM, path, violations)
+
+	allowed, ok := parseAllowedValues(qualifier.Constraint, path, violations)
+	if !ok || isJSONNull(qualifier.Value) {
+		return
+	}
+
+	value, err := parseSWENumber(qualifier.Value)
+	if err != nil {
+		violations.add(path+".value", "must be a number")
+		return
+	}
+	if !allowed.permits(value) {
+		violations.add(path+".value", fmt.Sprintf("%g is not within the allowed values of the constraint", value))
+	}
+}
+
+func validateQuantityRangeQualifier(qualifier common_shared.ComponentWrapper, path string, violations *SchemaViolations) {
+	validateUOM(qualifier.UOM, path, violations)
+
+	allowed, ok := parseAllowedValues(qualifier.Constraint, path, violations)
+	if !ok || isJSONNull(qualifier.Value) {
+		return
+	}
+
+	var bounds []json.RawMessage
+	if err := json.Unmarshal(qualifier.Value, &bounds); err != nil || len(bounds) != 2 {
+		violations.add(path+".value", "must be a 2-item array")
+		return
+	}
+	for i, bound := range bounds {
+		value, err := parseSWENumber(bound)
+		if err != nil {
+			violations.add(fmt.Sprintf("%s.value[%d]", path, i), "must be a number")
+			continue
+		}
+		if !allowed.permits(value) {
+			violations.add(fmt.Sprintf("%s.value[%d]", path, i), fmt.Sprintf("%g is not within the allowed values of the constraint", value))
+		}
+	}
+}
+
+// allowedNumbers is the numeric form of an AllowedValues constraint.
+type allowedNumbers struct {
+	values    []float64
+	intervals [][2]float64
+}
+
+// permits reports whether v equals one of the enumerated values or lies within
+// one of the closed intervals. An empty constraint permits everything.
+func (a allowedNumbers) permits(v float64) bool {
+	if len(a.values) == 0 && len(a.intervals) == 0 {
+		return true
+	}
+	for _, allowed := range a.values {
+		if v == allowed {
+			return true
+		}
+	}
+	for _, interval := range a.intervals {
+		if v >= interval[0] && v <= interval[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAllowedValues decodes an AllowedValues constraint. The second result is
+// false when there is nothing to check against: no constraint, a constraint of
+// another type, or a malformed one (which is recorded in violations).
+func parseAllowedValues(raw json.RawMessage, path string, violations *SchemaViolations) (allowedNumbers, bool) {
+	if isJSONNull(raw) {
+		return allowedNumbers{}, false
+	}
+
+	var constraint common_shared.AllowedValues
+	if err := json.Unmarshal(raw, &constraint); err != nil {
+		violations.add(path+".constraint", "is not a valid constraint: "+err.Error())
+		return allowedNumbers{}, false
+	}
+	if constraint.Type != "" && constraint.Type != "AllowedValues" {
+		return allowedNumbers{}, false
+	}
+
+	var allowed allowedNumbers
+	valid := true
+	for i, item := range constraint.Values {
+		v, ok := valueItemNumber(item)
+		if !ok {
+			violations.add(fmt.Sprintf("%s.constraint.values[%d]", path, i), "must be a number")
+			valid = false
+			continue
+		}
+		allowed.values = append(allowed.values, v)
+	}
+	for i, interval := range constraint.Intervals {
+		intervalPath := fmt.Sprintf("%s.constraint.intervals[%d]", path, i)
+		if len(interval) != 2 {
+			violations.add(intervalPath, "must be a 2-item array")
+			valid = false
+			continue
+		}
+		lo, loOK := valueItemNumber(interval[0])
+		hi, hiOK := valueItemNumber(interval[1])
+		if !loOK || !hiOK {
+			violations.add(intervalPath, "bounds must be numbers")
+			valid = false
+			continue
+		}
+		if lo > hi {
+			violations.add(intervalPath, "lower bound must not exceed upper bound")
+			valid = false
+			continue
+		}
+		allowed.intervals = append(allowed.intervals, [2]float64{lo, hi})
+	}
+	return allowed, valid
+}
+
+// valueItemNumber returns the numeric value of item, accepting the SWE JSON
+// encodings "+INF", "-INF" (and "INF") for unbounded interval ends.
+func valueItemNumber(item common_shared.ValueItem) (float64, bool) {
+	if item.Number != nil {
+		return *item.Number, true
+	}
+	if item.String != nil {
+		return sweInfinity(*item.String)
+	}
+	return 0, false
+}
+
+// parseSWENumber decodes a JSON number, or one of the SWE infinity strings.
+func parseSWENumber(raw json.RawMessage) (float64, error) {
+	var item common_shared.ValueItem
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return 0, err
+	}
+	v, ok := valueItemNumber(item)
+	if !ok {
+		return 0, fmt.Errorf("not a number: %s", string(raw))
+	}
+	return v, nil
+}
+
+func sweInfinity(s string) (float64, bool) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "+INF", "INF":
+		return math.Inf(1), true
+	case "-INF":
+		return math.Inf(-1), true
+	}
+	return 0, false
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	trimmed := strings.TrimSpace(string(raw))
+	return trimmed == "" || trimmed == "null"
+}
@@ -0,0 +1,14 @@
+package api
+
+import (
+	"github.com/yourusername/connected-systems-go/internal/model/formaters"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+)
+
+// echoQuery attaches the effective query parameters, after defaults and
+// normalization, to collection when the client asked for them with ?echo=true.
+func echoQuery(collection *formaters.AnyFeatureCollection, base queryparams.QueryParams, params any) {
+	if base.Echo {
+		collection.Query = params
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestListHandlers_RejectInvalidPagination(t *testing.T) {
+	logger := zap.NewNop()
+	handlers := map[string]http.HandlerFunc{
+		"/systems":          (&SystemHandler{logger: logger}).ListSystems,
+		"/procedures":       (&ProcedureHandler{logger: logger}).ListProcedures,
+		"/deployments":      (&DeploymentHandler{logger: logger}).ListDeployments,
+		"/samplingFeatures": (&SamplingFeatureHandler{logger: logger}).ListSamplingFeatures,
+		"/datastreams":      (&DatastreamHandler{logger: logger}).ListDatastreams,
+		"/observations":     (&ObservationHandler{logger: logger}).ListObservations,
+		"/controlstreams":   (&ControlStreamHandler{logger: logger}).ListControlStreams,
+		"/commands":         (&CommandHandler{logger: logger}).ListCommands,
+		"/systemEvents":     (&SystemEventHandler{logger: logger}).ListSystemEvents,
+	}
+	queries := map[string]string{
+		"limit=abc": "limit",
+		"offset=-1": "offset",
+	}
+
+	for path, handler := range handlers {
+		for query, param := range queries {
+			t.Run(path+"?"+query, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				handler(rec, httptest.NewRequest(http.MethodGet, path+"?"+query, nil))
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+
+				var problem ValidationProblem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				assert.Equal(t, "Invalid query parameter", problem.Title)
+				assert.Equal(t, http.StatusBadRequest, problem.Status)
+				require.Len(t, problem.Errors, 1)
+				assert.Equal(t, param, problem.Errors[0].Path)
+				assert.Contains(t, problem.Errors[0].Message, "must be a non-negative integer")
+			})
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/yourusername/connected-systems-go/internal/config"
+)
+
+// rateLimitExemptPaths are never throttled, so health probes keep working
+// while a client is being limited.
+var rateLimitExemptPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// rateLimitPruneEvery is how often buckets that have refilled completely are
+// dropped; a full bucket behaves exactly like a missing one.
+const rateLimitPruneEvery = time.Minute
+
+// RateLimiter throttles requests with one token bucket per client. Buckets
+// are kept in memory, so each server instance limits independently.
+type RateLimiter struct {
+	mu             sync.Mutex
+	rate           float64
+	burst          float64
+	keyHeader      string
+	trustedProxies []netip.Prefix
+	buckets        map[string]*tokenBucket
+	lastPrune      time.Time
+	now            func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter from cfg, which must pass
+// cfg.Validate. A burst below one is raised to one so that a client can make
+// at least a single request.
+func NewRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
+	burst := float64(cfg.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	var trustedProxies []netip.Prefix
+	for _, proxy := range cfg.TrustedProxies {
+		if prefix, err := config.ParseTrustedProxy(proxy); err == nil {
+			trustedProxies = append(trustedProxies, prefix)
+		}
+	}
+	return &RateLimiter{
+		rate:           cfg.Rate,
+		burst:          burst,
+		keyHeader:      cfg.KeyHeader,
+		trustedProxies: trustedProxies,
+		buckets:        make(map[string]*tokenBucket),
+		now:            time.Now,
+	}
+}
+
+type peerAddrContextKey struct{}
+
+// rememberPeerAddr records the address of the connection's peer before
+// middleware.RealIP replaces r.RemoteAddr with a client-supplied header, so
+// the rate limiter can tell whether that header comes from a trusted proxy.
+func rememberPeerAddr(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), peerAddrContextKey{}, r.RemoteAddr)))
+	})
+}
+
+// peerAddr returns the address recorded by rememberPeerAddr, or r.RemoteAddr
+// when there is none.
+func peerAddr(r *http.Request) string {
+	if addr, ok := r.Context().Value(peerAddrContextKey{}).(string); ok {
+		return addr
+	}
+	return r.RemoteAddr
+}
+
+// Middleware answers requests from a client whose bucket is empty with 429
+// Too Many Requests and a Retry-After header giving the seconds until the
+// next request is allowed. Clients behind a trusted proxy are told apart by
+// the address it forwards; rememberPeerAddr must run before middleware.RealIP
+// for that address not to be taken from anyone else.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rateLimitExemptPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if ok, retryAfter := l.allow(l.clientKey(r)); !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			render.Status(r, http.StatusTooManyRequests)
+			render.JSON(w, r, map[string]string{"error": "Too many requests"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientKey identifies the client of r by the address of the connection's
+// peer. When the peer is a trusted proxy, the client is identified by the
+// configured key header if the request carries it, and otherwise by the
+// address the proxy forwarded.
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	peer := addrHost(peerAddr(r))
+	if !l.trusted(peer) {
+		return "ip:" + peer
+	}
+	if l.keyHeader != "" {
+		if key := r.Header.Get(l.keyHeader); key != "" {
+			return "key:" + key
+		}
+	}
+	return "ip:" + l.forwardedClient(r, peer)
+}
+
+// forwardedClient returns the client address forwarded by the trusted proxy
+// peer: the last X-Forwarded-For entry not added by another trusted proxy,
+// since earlier entries are whatever the client sent, or else X-Real-IP.
+func (l *RateLimiter) forwardedClient(r *http.Request, peer string) string {
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+		if !l.trusted(addr.String()) {
+			return addr.Unmap().String()
+		}
+	}
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return addr.Unmap().String()
+	}
+	return peer
+}
+
+// trusted reports whether host is one of the trusted proxies.
+func (l *RateLimiter) trusted(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range l.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// addrHost strips the port from a host:port address.
+func addrHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// allow takes a token from the bucket of key. When none is left it reports
+// how long until one is.
+func (l *RateLimiter) allow(key string) (ok bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastPrune) >= rateLimitPruneEvery {
+		l.prune(now)
+	}
+
+	bucket, exists := l.buckets[key]
+	if !exists {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens = l.refill(bucket, now)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+}
+
+// refill returns the tokens bucket holds at now.
+func (l *RateLimiter) refill(bucket *tokenBucket, now time.Time) float64 {
+	elapsed := now.Sub(bucket.last).Seconds()
+	if elapsed <= 0 {
+		return bucket.tokens
+	}
+	return math.Min(l.burst, bucket.tokens+elapsed*l.rate)
+}
+
+// prune drops the buckets that are full again at now.
+func (l *RateLimiter) prune(now time.Time) {
+	for key, bucket := range l.buckets {
+		if l.refill(bucket, now) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPrune = now
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/connected-systems-go/internal/config"
+)
+
+func newTestRateLimiter(cfg config.RateLimitConfig) (http.Handler, *time.Time) {
+	limiter := NewRateLimiter(cfg)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	return handler, &now
+}
+
+func rateLimitedRequest(handler http.Handler, path, remoteAddr string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimiter_Boundary(t *testing.T) {
+	handler, now := newTestRateLimiter(config.RateLimitConfig{Enabled: true, Rate: 2, Burst: 3})
+
+	for i := 0; i < 3; i++ {
+		rec := rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", nil)
+		assert.Equal(t, http.StatusOK, rec.Code, "request %d is within the burst", i+1)
+	}
+
+	rec := rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"Too many requests"}`, rec.Body.String())
+
+	// Another client has its own bucket; the same host on another port does not
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "192.0.2.2:1234", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "192.0.2.1:5678", nil).Code)
+
+	// One token is back after 1/rate seconds, and only one
+	*now = now.Add(400 * time.Millisecond)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", nil).Code)
+	*now = now.Add(100 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", nil).Code)
+}
+
+func TestRateLimiter_RetryAfterRoundsUp(t *testing.T) {
+	handler, _ := newTestRateLimiter(config.RateLimitConfig{Enabled: true, Rate: 0.25, Burst: 1})
+
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", nil).Code)
+	rec := rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "4", rec.Header().Get("Retry-After"))
+}
+
+func TestRateLimiter_KeyHeader(t *testing.T) {
+	handler, _ := newTestRateLimiter(config.RateLimitConfig{Enabled: true, Rate: 1, Burst: 1, KeyHeader: "X-API-Key", TrustedProxies: []string{"10.0.0.0/8"}})
+	keyA := http.Header{"X-Api-Key": {"a"}}
+	keyB := http.Header{"X-Api-Key": {"b"}}
+
+	// Clients behind the trusted proxy are told apart by their key
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "10.0.0.1:1234", keyA).Code)
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "10.0.0.1:1234", keyB).Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "10.0.0.2:1234", keyA).Code)
+
+	// Without the header the client falls back to the forwarded address
+	forwarded := http.Header{"X-Forwarded-For": {"192.0.2.9"}}
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "10.0.0.1:1234", forwarded).Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "10.0.0.1:1234", forwarded).Code)
+
+	// Keys sent straight to the server are ignored, so rotating them gains nothing
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", keyA).Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", keyB).Code)
+}
+
+// Forwarding headers only count when a trusted proxy sets them: a client
+// rotating X-Forwarded-For or X-Real-IP is still limited by its own address,
+// even though middleware.RealIP has rewritten r.RemoteAddr from them.
+func TestRateLimiter_ForwardedHeaders(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimitConfig{Enabled: true, Rate: 1, Burst: 1, TrustedProxies: []string{"10.0.0.1"}})
+	handler := rememberPeerAddr(middleware.RealIP(limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))))
+
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.1"}}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.2"}}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", http.Header{"X-Real-Ip": {"198.51.100.3"}}).Code)
+
+	// Through the trusted proxy, the entry it appended identifies the client;
+	// the ones before it are the client's own and are ignored
+	spoofed := func(client string) http.Header {
+		return http.Header{"X-Forwarded-For": {client + ", 203.0.113.7"}}
+	}
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "10.0.0.1:1234", spoofed("198.51.100.4")).Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "10.0.0.1:1234", spoofed("198.51.100.5")).Code)
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {"203.0.113.8"}}).Code)
+}
+
+func TestRateLimiter_ExemptPaths(t *testing.T) {
+	handler, _ := newTestRateLimiter(config.RateLimitConfig{Enabled: true, Rate: 1, Burst: 1})
+
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(handler, "/systems", "192.0.2.1:1234", nil).Code)
+	for _, path := range []string{"/healthz", "/readyz"} {
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, rateLimitedRequest(handler, path, "192.0.2.1:1234", nil).Code, path)
+		}
+	}
+}
+
+func TestRateLimiter_PrunesFullBuckets(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimitConfig{Enabled: true, Rate: 1, Burst: 2})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	limiter.allow("ip:192.0.2.1")
+	assert.Len(t, limiter.buckets, 1)
+
+	now = now.Add(rateLimitPruneEvery)
+	limiter.allow("ip:192.0.2.2")
+	assert.Len(t, limiter.buckets, 1, "the refilled bucket is dropped")
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+)
+
+// statusForError maps the error of a failed repository call to the HTTP
+// status it is answered with: 404 for a missing record, 409 for a conflict
+// with existing records or a broken constraint, 400 for input that cannot be
+// stored and 500 for anything else.
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrConflict), errors.Is(err, repository.ErrConstraint):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrValidation):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// renderRepositoryError answers a failed repository call with the status
+// statusForError picks for err. notFound is the message for a missing
+// record and failed the message for an internal error. The database's own
+// description of a conflict is only logged, never sent to the client.
+func renderRepositoryError(w http.ResponseWriter, r *http.Request, err error, notFound, failed string) {
+	message := failed
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		message = notFound
+	case errors.Is(err, repository.ErrConflict):
+		message = "Conflicts with an existing resource"
+	case errors.Is(err, repository.ErrConstraint):
+		message = "Conflicts with related resources"
+	case errors.Is(err, repository.ErrValidation):
+		message = "Invalid input"
+	}
+	render.Status(r, statusForError(err))
+	render.JSON(w, r, map[string]string{"error": message})
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+)
+
+func TestStatusForError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{repository.ErrNotFound, http.StatusNotFound},
+		{fmt.Errorf("get system: %w", repository.ErrNotFound), http.StatusNotFound},
+		{repository.ErrConflict, http.StatusConflict},
+		{&repository.DuplicateUIDError{UID: "urn:x:1"}, http.StatusConflict},
+		{repository.ErrConstraint, http.StatusConflict},
+		{repository.ErrValidation, http.StatusBadRequest},
+		{repository.ErrUnfilteredDelete, http.StatusBadRequest},
+		{errors.New("connection reset"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.status, statusForError(tt.err), tt.err.Error())
+	}
+}
+
+func TestRenderRepositoryError(t *testing.T) {
+	tests := []struct {
+		err  error
+		body string
+	}{
+		{repository.ErrNotFound, `{"error":"System not found"}`},
+		{repository.ErrConstraint, `{"error":"Conflicts with related resources"}`},
+		{errors.New("pq: relation does not exist"), `{"error":"Internal server error"}`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/systems/1", nil)
+
+		renderRepositoryError(rec, req, tt.err, "System not found", "Internal server error")
+
+		assert.Equal(t, statusForError(tt.err), rec.Code)
+		assert.JSONEq(t, tt.body, rec.Body.String())
+	}
+}
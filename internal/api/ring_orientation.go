@@ -0,0 +1,28 @@
+package api
+
+import (
+	"github.com/yourusername/connected-systems-go/internal/config"
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+)
+
+// checkRingOrientation applies the configured polygon ring orientation policy
+// to a submitted geometry. With "fix" misoriented rings are rewound in place;
+// with "reject" each of them is returned as a violation under "geometry".
+// "ignore", or an unset policy, accepts the geometry as sent.
+func checkRingOrientation(cfg *config.Config, geometry *common_shared.GoGeom) error {
+	if cfg == nil || geometry == nil {
+		return nil
+	}
+
+	switch cfg.API.PolygonRingOrientation {
+	case config.RingOrientationReject:
+		var violations SchemaViolations
+		for _, path := range geometry.MisorientedRings("geometry") {
+			violations.add(path, "ring must follow the right-hand rule: exterior rings counterclockwise, holes clockwise")
+		}
+		return violations.errOrNil()
+	case config.RingOrientationFix:
+		geometry.OrientRings()
+	}
+	return nil
+}
@@ -3,10 +3,11 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	"github.com/go-chi/render"
 	"github.com/yourusername/connected-systems-go/internal/config"
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
@@ -22,27 +23,82 @@ import (
 func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Repositories) http.Handler {
 	r := chi.NewRouter()
 
+	// Under a base path every link and Location must carry the prefix, and
+	// handlers build them all from BaseURL
+	basePath := ""
+	if cfg != nil {
+		basePath = normalizeBasePath(cfg.API.BasePath)
+		if basePath != "" {
+			prefixed := *cfg
+			prefixed.API.BaseURL = strings.TrimRight(cfg.API.BaseURL, "/") + basePath
+			cfg = &prefixed
+		}
+	}
+
 	// Ensure association links generated by formatters are functional absolute URLs.
 	if cfg != nil {
 		serializers.SetAssociationLinksBaseURL(cfg.API.BaseURL)
 	}
 
 	// Middleware
+	// Trailing slashes are normalized away before routing; see stripTrailingSlash
+	r.Use(stripTrailingSlash)
 	r.Use(middleware.RequestID)
+	r.Use(rememberPeerAddr)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(compressResponses)
+
+	// Per-client rate limiting (off by default); keyed by the peer address, or
+	// by what a trusted proxy forwards (see RateLimiter.clientKey)
+	if cfg != nil && cfg.API.RateLimit.Enabled {
+		r.Use(NewRateLimiter(cfg.API.RateLimit).Middleware)
+	}
+
 	r.Use(render.SetContentType(render.ContentTypeJSON))
+	r.Use(formatQueryParam)
+	r.Use(outputCRS)
+
+	// CORS (deny-all unless origins are configured)
+	corsConfig := config.CORSConfig{}
+	if cfg != nil {
+		corsConfig = cfg.CORS
+	}
+	r.Use(NewCORSMiddleware(corsConfig))
+
+	// OPTIONS and 405 responses list the methods each path supports
+	routeMethods := newRouteMethods(r)
+	r.Use(answerOptions(routeMethods))
+	r.MethodNotAllowed(methodNotAllowed(routeMethods))
+
+	// Scope every statement to the request's tenant (off by default)
+	if cfg != nil && cfg.API.Tenancy.Enabled {
+		r.Use(requireTenant(cfg.API.Tenancy.Header))
+	}
+
+	// Idempotency-Key support for create requests, scoped per resource type
+	idempotencyTTL := 24 * time.Hour
+	if cfg != nil && cfg.API.IdempotencyKeyTTL > 0 {
+		idempotencyTTL = cfg.API.IdempotencyKeyTTL
+	}
 
-	// CORS
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link", "Location"},
-		AllowCredentials: false,
-		MaxAge:           300,
-	}))
+	// Request body size limits; batch creates get their own, larger limit
+	maxBodySize, maxBatchBodySize := DefaultMaxRequestBodySize, DefaultMaxRequestBodySize
+	if cfg != nil && cfg.API.MaxRequestBodySize > 0 {
+		maxBodySize = cfg.API.MaxRequestBodySize
+		maxBatchBodySize = maxBodySize
+	}
+	if cfg != nil && cfg.API.MaxBatchRequestBodySize > 0 {
+		maxBatchBodySize = cfg.API.MaxBatchRequestBodySize
+	}
+	// Bodies are hashed before the route's own limit is known, so allow the
+	// largest; each route still enforces its limit through MaxBodySize
+	idempotency := NewIdempotencyStore(idempotencyTTL, max(maxBodySize, maxBatchBodySize))
+	r.Use(MaxBodySize(maxBodySize))
+
+	// Cache-Control per resource type for successful GETs
+	cache := newCachePolicies(cfg)
 
 	// Create handlers
 	landingHandler := NewLandingHandler(cfg, logger)
@@ -61,14 +117,14 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 
 	collectionHandler := NewCollectionHandler(cfg, logger, repos.Collection, collectionFormatterCollection)
 	deploymentHandler := NewDeploymentHandler(cfg, logger, repos.Deployment, deploymentFormatterCollection)
-	systemHandler := NewSystemHandler(cfg, logger, repos.System, repos.SystemHistory, systemFormatterCollection, repos.Deployment, deploymentFormatterCollection, repos.Procedure, procedureFormatterCollection)
+	systemHandler := NewSystemHandler(cfg, logger, repos.System, repos.SystemHistory, systemFormatterCollection, repos.Deployment, deploymentFormatterCollection, repos.Procedure, procedureFormatterCollection, repos.Datastream, datastreamFormatterCollection, repos.ControlStream, controlStreamFormatterCollection)
 	procedureHandler := NewProcedureHandler(cfg, logger, repos.Procedure, procedureFormatterCollection)
-	samplingFeatureHandler := NewSamplingFeatureHandler(cfg, logger, repos.SamplingFeature, samplingFeatureFormatterCollection)
-	propertyHandler := NewPropertyHandler(cfg, logger, repos.Property, propertyFormatterCollection)
+	samplingFeatureHandler := NewSamplingFeatureHandler(cfg, logger, repos.SamplingFeature, samplingFeatureFormatterCollection, repos.System)
+	propertyHandler := NewPropertyHandler(cfg, logger, repos.Property, propertyFormatterCollection, repos.Datastream, datastreamFormatterCollection)
 	featureHandler := NewFeatureHandler(cfg, logger, repos.Feature, featureFormatterCollection)
-	datastreamHandler := NewDatastreamHandler(cfg, logger, repos.Datastream, datastreamFormatterCollection)
+	datastreamHandler := NewDatastreamHandler(cfg, logger, repos.Datastream, repos.System, datastreamFormatterCollection)
 	observationHandler := NewObservationHandler(cfg, logger, repos.Observation, repos.Datastream)
-	controlStreamHandler := NewControlStreamHandler(cfg, logger, repos.ControlStream, controlStreamFormatterCollection)
+	controlStreamHandler := NewControlStreamHandler(cfg, logger, repos.ControlStream, repos.System, controlStreamFormatterCollection)
 	commandHandler := NewCommandHandler(cfg, logger, repos.Command, repos.ControlStream)
 	systemEventHandler := NewSystemEventHandler(cfg, logger, repos.SystemEvent, repos.System)
 
@@ -81,17 +137,17 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 	r.Get("/conformance", conformanceHandler.GetConformance)
 
 	// Collections
-	r.Post("/collections", collectionHandler.CreateCollection)
-	r.Get("/collections", collectionHandler.ListCollections)
-	r.Get("/collections/{collectionId}", collectionHandler.GetCollection)
+	r.With(idempotency.Middleware("collections")).Post("/collections", collectionHandler.CreateCollection)
+	r.With(cache.Collection("collections")).Get("/collections", collectionHandler.ListCollections)
+	r.With(cache.Item("collections")).Get("/collections/{collectionId}", collectionHandler.GetCollection)
 
 	// OGC API Features endpoints (within collections)
 	r.Route("/collections/{collectionId}/items", func(r chi.Router) {
-		r.Get("/", featureHandler.ListFeatures)
-		r.Post("/", featureHandler.CreateFeature)
+		r.With(cache.Collection("items")).Get("/", featureHandler.ListFeatures)
+		r.With(idempotency.Middleware("features")).Post("/", featureHandler.CreateFeature)
 
 		r.Route("/{featureId}", func(r chi.Router) {
-			r.Get("/", featureHandler.GetFeature)
+			r.With(cache.Item("items")).Get("/", featureHandler.GetFeature)
 			r.Put("/", featureHandler.UpdateFeature)
 			r.Delete("/", featureHandler.DeleteFeature)
 		})
@@ -99,32 +155,38 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 
 	// Systems (canonical endpoints)
 	r.Route("/systems", func(r chi.Router) {
-		r.Get("/", systemHandler.ListSystems)
-		r.Post("/", systemHandler.CreateSystem)
+		r.With(cache.Collection("systems")).Get("/", systemHandler.ListSystems)
+		r.With(idempotency.Middleware("systems")).Post("/", systemHandler.CreateSystem)
+
+		// Resolve external uid (URN) references to the canonical resource
+		r.Get("/uid/{uid}", systemHandler.GetSystemByUID)
 
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", systemHandler.GetSystem)
+			r.With(cache.Item("systems")).Get("/", systemHandler.GetSystem)
 			r.Put("/", systemHandler.UpdateSystem)
 			r.Delete("/", systemHandler.DeleteSystem)
+			r.Get("/geometry", systemHandler.GetSystemGeometry)
 
 			// Nested Systems endpoints
 			r.Get("/subsystems", systemHandler.GetSubsystems)
-			r.Post("/subsystems", systemHandler.AddSubsystem)
+			r.With(idempotency.Middleware("systems")).Post("/subsystems", systemHandler.AddSubsystem)
 
 			// Associated resource endpoint
 			r.Get("/deployments", systemHandler.GetDeployments)
 			r.Get("/procedures", systemHandler.GetProcedures)
 			r.Get("/samplingFeatures", samplingFeatureHandler.GetSystemSamplingFeatures)
+			r.Get("/samplingFeatures/{sfId}", samplingFeatureHandler.GetSystemSamplingFeature)
 			r.Get("/datastreams", datastreamHandler.ListSystemDatastreams)
 			r.Get("/controlstreams", controlStreamHandler.ListSystemControlStreams)
+			r.Get("/controlStreams", controlStreamHandler.ListSystemControlStreams)
 			r.Get("/events", systemEventHandler.ListEventsBySystem)
-			r.Post("/events", systemEventHandler.CreateEventBySystem)
+			r.With(idempotency.Middleware("systemEvents")).Post("/events", systemEventHandler.CreateEventBySystem)
 			r.Get("/history", systemHandler.ListSystemHistory)
 
 			// Sampling Features endpoint
-			r.Post("/samplingFeatures", samplingFeatureHandler.CreateSamplingFeature)
-			r.Post("/datastreams", datastreamHandler.CreateDatastream)
-			r.Post("/controlstreams", controlStreamHandler.CreateControlStream)
+			r.With(idempotency.Middleware("samplingFeatures")).Post("/samplingFeatures", samplingFeatureHandler.CreateSamplingFeature)
+			r.With(idempotency.Middleware("datastreams")).Post("/datastreams", datastreamHandler.CreateDatastream)
+			r.With(idempotency.Middleware("controlstreams")).Post("/controlstreams", controlStreamHandler.CreateControlStream)
 
 			r.Route("/events/{eventId}", func(r chi.Router) {
 				r.Get("/", systemEventHandler.GetEventByID)
@@ -142,15 +204,15 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 
 	// System Events
 	r.Route("/systemEvents", func(r chi.Router) {
-		r.Get("/", systemEventHandler.ListSystemEvents)
+		r.With(cache.Collection("systemEvents")).Get("/", systemEventHandler.ListSystemEvents)
 	})
 
 	// Datastreams (Part 2: Dynamic Data)
 	r.Route("/datastreams", func(r chi.Router) {
-		r.Get("/", datastreamHandler.ListDatastreams)
+		r.With(cache.Collection("datastreams")).Get("/", datastreamHandler.ListDatastreams)
 
 		r.Route("/{dataStreamId}", func(r chi.Router) {
-			r.Get("/", datastreamHandler.GetDatastream)
+			r.With(cache.Item("datastreams")).Get("/", datastreamHandler.GetDatastream)
 			r.Put("/", datastreamHandler.UpdateDatastream)
 			r.Delete("/", datastreamHandler.DeleteDatastream)
 
@@ -158,16 +220,16 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 			r.Put("/schema", datastreamHandler.UpdateDatastreamSchema)
 
 			r.Get("/observations", observationHandler.ListDatastreamObservations)
-			r.Post("/observations", observationHandler.CreateDatastreamObservation)
+			r.With(WithMaxBodySize(maxBatchBodySize), idempotency.Middleware("observations")).Post("/observations", observationHandler.CreateDatastreamObservation)
 		})
 	})
 
 	// Control Streams (Part 2: Dynamic Data - Tasking)
 	r.Route("/controlstreams", func(r chi.Router) {
-		r.Get("/", controlStreamHandler.ListControlStreams)
+		r.With(cache.Collection("controlstreams")).Get("/", controlStreamHandler.ListControlStreams)
 
 		r.Route("/{controlStreamId}", func(r chi.Router) {
-			r.Get("/", controlStreamHandler.GetControlStream)
+			r.With(cache.Item("controlstreams")).Get("/", controlStreamHandler.GetControlStream)
 			r.Put("/", controlStreamHandler.UpdateControlStream)
 			r.Delete("/", controlStreamHandler.DeleteControlStream)
 
@@ -175,16 +237,16 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 			r.Put("/schema", controlStreamHandler.UpdateControlStreamSchema)
 
 			r.Get("/commands", commandHandler.ListControlStreamCommands)
-			r.Post("/commands", commandHandler.CreateControlStreamCommand)
+			r.With(idempotency.Middleware("commands")).Post("/commands", commandHandler.CreateControlStreamCommand)
 		})
 	})
 
 	// Commands (Part 2: Dynamic Data - Tasking)
 	r.Route("/commands", func(r chi.Router) {
-		r.Get("/", commandHandler.ListCommands)
+		r.With(cache.Collection("commands")).Get("/", commandHandler.ListCommands)
 
 		r.Route("/{cmdId}", func(r chi.Router) {
-			r.Get("/", commandHandler.GetCommand)
+			r.With(cache.Item("commands")).Get("/", commandHandler.GetCommand)
 			r.Put("/", commandHandler.UpdateCommand)
 			r.Delete("/", commandHandler.DeleteCommand)
 		})
@@ -192,10 +254,10 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 
 	// Observations (Part 2: Dynamic Data)
 	r.Route("/observations", func(r chi.Router) {
-		r.Get("/", observationHandler.ListObservations)
+		r.With(cache.Collection("observations")).Get("/", observationHandler.ListObservations)
 
 		r.Route("/{obsId}", func(r chi.Router) {
-			r.Get("/", observationHandler.GetObservation)
+			r.With(cache.Item("observations")).Get("/", observationHandler.GetObservation)
 			r.Put("/", observationHandler.UpdateObservation)
 			r.Delete("/", observationHandler.DeleteObservation)
 		})
@@ -203,27 +265,30 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 
 	// Deployments (canonical endpoints)
 	r.Route("/deployments", func(r chi.Router) {
-		r.Get("/", deploymentHandler.ListDeployments)
-		r.Post("/", deploymentHandler.CreateDeployment)
+		r.With(cache.Collection("deployments")).Get("/", deploymentHandler.ListDeployments)
+		r.With(idempotency.Middleware("deployments")).Post("/", deploymentHandler.CreateDeployment)
 
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", deploymentHandler.GetDeployment)
+			r.With(cache.Item("deployments")).Get("/", deploymentHandler.GetDeployment)
 			r.Put("/", deploymentHandler.UpdateDeployment)
 			r.Delete("/", deploymentHandler.DeleteDeployment)
 
 			// Subdeployments endpoint
 			r.Get("/subdeployments", deploymentHandler.ListSubdeployments)
-			r.Post("/subdeployments", deploymentHandler.AddSubdeployment)
+			r.With(idempotency.Middleware("deployments")).Post("/subdeployments", deploymentHandler.AddSubdeployment)
 		})
 	})
 
 	// Procedures (canonical endpoints)
 	r.Route("/procedures", func(r chi.Router) {
-		r.Get("/", procedureHandler.ListProcedures)
-		r.Post("/", procedureHandler.CreateProcedure)
+		r.With(cache.Collection("procedures")).Get("/", procedureHandler.ListProcedures)
+		r.With(idempotency.Middleware("procedures")).Post("/", procedureHandler.CreateProcedure)
+
+		// Resolve external uid (URN) references to the canonical resource
+		r.Get("/uid/{uid}", procedureHandler.GetProcedureByUID)
 
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", procedureHandler.GetProcedure)
+			r.With(cache.Item("procedures")).Get("/", procedureHandler.GetProcedure)
 			r.Put("/", procedureHandler.UpdateProcedure)
 			r.Delete("/", procedureHandler.DeleteProcedure)
 		})
@@ -231,10 +296,12 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 
 	// Sampling Features (canonical endpoints)
 	r.Route("/samplingFeatures", func(r chi.Router) {
-		r.Get("/", samplingFeatureHandler.ListSamplingFeatures)
+		r.With(cache.Collection("samplingFeatures")).Get("/", samplingFeatureHandler.ListSamplingFeatures)
+		r.With(idempotency.Middleware("samplingFeatures")).Post("/", samplingFeatureHandler.CreateSamplingFeature)
+		r.Delete("/", samplingFeatureHandler.DeleteSamplingFeatures)
 
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", samplingFeatureHandler.GetSamplingFeature)
+			r.With(cache.Item("samplingFeatures")).Get("/", samplingFeatureHandler.GetSamplingFeature)
 			r.Put("/", samplingFeatureHandler.UpdateSamplingFeature)
 			r.Delete("/", samplingFeatureHandler.DeleteSamplingFeature)
 		})
@@ -242,13 +309,19 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 
 	// Properties (canonical endpoints)
 	r.Route("/properties", func(r chi.Router) {
-		r.Get("/", propertyHandler.ListProperties)
-		r.Post("/", propertyHandler.CreateProperty)
+		r.With(cache.Collection("properties")).Get("/", propertyHandler.ListProperties)
+		r.With(idempotency.Middleware("properties")).Post("/", propertyHandler.CreateProperty)
+
+		// Resolve external uid (URN) references to the canonical resource
+		r.Get("/uid/{uid}", propertyHandler.GetPropertyByUID)
 
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", propertyHandler.GetProperty)
+			r.With(cache.Item("properties")).Get("/", propertyHandler.GetProperty)
 			r.Put("/", propertyHandler.UpdateProperty)
 			r.Delete("/", propertyHandler.DeleteProperty)
+
+			// Datastreams observing this property
+			r.Get("/datastreams", propertyHandler.ListPropertyDatastreams)
 		})
 	})
 
@@ -258,6 +331,9 @@ func NewRouter(cfg *config.Config, logger *zap.Logger, repos *repository.Reposit
 		fmt.Fprint(w, getOpenAPISpec(cfg))
 	})
 
+	if basePath != "" {
+		return mountAtBasePath(basePath, r)
+	}
 	return r
 }
 
@@ -340,15 +416,10 @@ func buildSamplingFeatureFormatterCollection(repos *repository.Repositories) *se
 func buildPropertyFormatterCollection(repos *repository.Repositories) *serializers.MultiFormatFormatterCollection[*domains.Property] {
 	collection := serializers.NewMultiFormatFormatterCollection[*domains.Property]("application/sml+json")
 
-	// Register GeoJSON formatter
-	geoJSONFormatter := geojson_formatters.NewPropertyGeoJSONFormatter(repos)
-	serializers.RegisterFormatterTyped(collection, "application/geo+json", geoJSONFormatter)
-
-	// Register SensorML formatter
+	// Properties have no GeoJSON encoding; SensorML is their only format, so
+	// other requested media types are answered with 406
 	sensorMLFormatter := sensorml_formatters.NewPropertySensorMLFormatter(repos)
 	serializers.RegisterFormatterTyped(collection, "application/sml+json", sensorMLFormatter)
-
-	// Set default (SensorML is the default for properties per OGC Connected Systems)
 	serializers.RegisterFormatterTypedDefault(collection, sensorMLFormatter, "application/sml+json")
 
 	return collection
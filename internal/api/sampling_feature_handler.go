@@ -1,7 +1,10 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -20,46 +23,44 @@ type SamplingFeatureHandler struct {
 	logger *zap.Logger
 	repo   *repository.SamplingFeatureRepository
 	fc     *formaters.MultiFormatFormatterCollection[*domains.SamplingFeature]
+	// system hierarchy traversal for recursive listings
+	systemRepo *repository.SystemRepository
 }
 
 // NewSamplingFeatureHandler creates a new SamplingFeatureHandler
-func NewSamplingFeatureHandler(cfg *config.Config, logger *zap.Logger, repo *repository.SamplingFeatureRepository, fc *formaters.MultiFormatFormatterCollection[*domains.SamplingFeature]) *SamplingFeatureHandler {
-	return &SamplingFeatureHandler{cfg: cfg, logger: logger, repo: repo, fc: fc}
+func NewSamplingFeatureHandler(cfg *config.Config, logger *zap.Logger, repo *repository.SamplingFeatureRepository, fc *formaters.MultiFormatFormatterCollection[*domains.SamplingFeature], systemRepo *repository.SystemRepository) *SamplingFeatureHandler {
+	return &SamplingFeatureHandler{cfg: cfg, logger: logger, repo: repo, fc: fc, systemRepo: systemRepo}
 }
 
 func (h *SamplingFeatureHandler) ListSamplingFeatures(w http.ResponseWriter, r *http.Request) {
 	params, err := queryparams.SamplingFeatureQueryParams{}.BuildFromRequest(r)
-
 	if err != nil {
 		h.logger.Error("Failed to parse query parameters", zap.Error(err))
-		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Invalid query parameters"})
+		renderQueryParamError(w, r, err)
 		return
 	}
 
-	sampledFeatures, total, err := h.repo.List(params)
+	sampledFeatures, total, err := h.repo.WithContext(r.Context()).List(params)
 	if err != nil {
 		h.logger.Error("Failed to list sampling features", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Sampling Feature not found", "Internal server error")
 		return
 	}
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.fc.BuildCollection(acceptHeader, sampledFeatures, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
 }
 
 func (h *SamplingFeatureHandler) GetSamplingFeature(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	samplingFeature, err := h.repo.GetByID(id)
+	samplingFeature, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get sampling feature", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "Sampling Feature not found"})
+		renderRepositoryError(w, r, err, "Sampling Feature not found", "Internal server error")
 		return
 	}
 
@@ -72,16 +73,48 @@ func (h *SamplingFeatureHandler) GetSamplingFeature(w http.ResponseWriter, r *ht
 		return
 	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.Status(r, http.StatusOK)
-	render.JSON(w, r, serialized)
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), serialized)
+}
+
+// GetSystemSamplingFeature serves /systems/{id}/samplingFeatures/{sfId}. The
+// sampling feature must belong to the system in the path; otherwise it is
+// reported as not found.
+func (h *SamplingFeatureHandler) GetSystemSamplingFeature(w http.ResponseWriter, r *http.Request) {
+	systemID := chi.URLParam(r, "id")
+	id := chi.URLParam(r, "sfId")
+
+	samplingFeature, err := h.repo.WithContext(r.Context()).GetBySystemAndID(systemID, id)
+	if err != nil {
+		h.logger.Error("Failed to get sampling feature", zap.String("systemId", systemID), zap.String("id", id), zap.Error(err))
+		renderRepositoryError(w, r, err, "Sampling Feature not found", "Internal server error")
+		return
+	}
+
+	// self reflects the nested route; canonical still points at /samplingFeatures/{id}
+	ctx := formaters.WithSelfPath(r.Context(), "/systems/"+url.PathEscape(systemID)+"/samplingFeatures/"+url.PathEscape(id))
+	acceptHeader := r.Header.Get("Accept")
+	serialized, err := h.fc.SerializeWithContext(ctx, acceptHeader, samplingFeature)
+	if err != nil {
+		h.logger.Error("Failed to serialize sampling feature", zap.String("id", id), zap.Error(err))
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to serialize sampling feature"})
+		return
+	}
+
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), serialized)
 }
 
 func (h *SamplingFeatureHandler) CreateSamplingFeature(w http.ResponseWriter, r *http.Request) {
 	contentType := r.Header.Get("Content-Type")
 	sampledFeature, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to deserialize sampling feature", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
@@ -90,22 +123,58 @@ func (h *SamplingFeatureHandler) CreateSamplingFeature(w http.ResponseWriter, r
 	// If this request is scoped under a system (POST /systems/{id}/samplingFeatures)
 	// set the ParentSystemID from the URL param so the created sampling feature
 	// is associated with the parent system.
-	if parentID := chi.URLParam(r, "id"); parentID != "" {
+	parentID := chi.URLParam(r, "id")
+	if parentID != "" {
+		// An unknown parent in the path is a 404, like listing its features
+		if _, err := h.systemRepo.WithContext(r.Context()).GetByID(parentID); err != nil {
+			h.logger.Error("Failed to look up system for sampling feature", zap.String("systemId", parentID), zap.Error(err))
+			renderRepositoryError(w, r, err, "System not found", "Failed to create sampling feature")
+			return
+		}
 		sampledFeature.ParentSystemID = &parentID
+	}
 
+	// Top-level creates (POST /samplingFeatures) must name the parent system in the body
+	if sampledFeature.ParentSystemID == nil || strings.TrimSpace(*sampledFeature.ParentSystemID) == "" {
+		if reportValidation(w, r, SchemaViolations{{Path: "parentSystem@link", Message: "is required to create a sampling feature"}}) {
+			return
+		}
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "A parentSystem link is required to create a sampling feature"})
+		return
+	}
+	if parentID == "" && !h.checkParentSystem(w, r, *sampledFeature.ParentSystemID) {
+		return
+	}
+	// Create checks sampleOf itself, in the same transaction as the write
+	sampleOf := resolveSampleOf(sampledFeature)
+	if validateOnlyRequested(r) && !h.checkSampleOf(w, r, sampleOf) {
+		return
+	}
+	geometryErr := checkRingOrientation(h.cfg, sampledFeature.Geometry)
+	if reportCreateValidation(w, r, geometryErr, h.repo.WithContext(r.Context()), string(sampledFeature.UniqueIdentifier)) {
+		return
+	}
+	if geometryErr != nil {
+		renderValidationProblem(w, r, "Invalid geometry", geometryErr)
+		return
 	}
 
-	if err := h.repo.Create(sampledFeature); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(sampledFeature); err != nil {
+		if renderUnknownSampleOf(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to create sampling feature", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create sampling feature"})
+		renderRepositoryError(w, r, err, "Sampling Feature not found", "Failed to create sampling feature")
 		return
 	}
 
 	// Per spec: return 201 Created with Location header and no response body
+	// unless Prefer: return=representation asks for the created resource
 	location := strings.TrimRight(h.cfg.API.BaseURL, "/") + "/samplingFeatures/" + sampledFeature.ID
-	w.Header().Set("Location", location)
-	w.WriteHeader(http.StatusCreated)
+	writeCreated(w, r, h.fc, location, func() (*domains.SamplingFeature, error) {
+		return h.repo.WithContext(r.Context()).GetByID(sampledFeature.ID)
+	})
 }
 
 func (h *SamplingFeatureHandler) UpdateSamplingFeature(w http.ResponseWriter, r *http.Request) {
@@ -115,58 +184,174 @@ func (h *SamplingFeatureHandler) UpdateSamplingFeature(w http.ResponseWriter, r
 	sampledFeature, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
 		h.logger.Error("Failed to deserialize sampling feature", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
+	if sampledFeature.ParentSystemID != nil && !h.checkParentSystem(w, r, *sampledFeature.ParentSystemID) {
+		return
+	}
+	resolveSampleOf(sampledFeature)
+	if err := checkRingOrientation(h.cfg, sampledFeature.Geometry); err != nil {
+		renderValidationProblem(w, r, "Invalid geometry", err)
+		return
+	}
 
 	sampledFeature.ID = id
-	if err := h.repo.Update(sampledFeature); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(sampledFeature); err != nil {
+		if renderUnknownSampleOf(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to update sampling feature", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update sampling feature"})
+		renderRepositoryError(w, r, err, "Sampling Feature not found", "Failed to update sampling feature")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	writeUpdated(w, r, h.fc, func() (*domains.SamplingFeature, error) { return h.repo.WithContext(r.Context()).GetByID(id) })
+}
+
+// checkParentSystem looks up the parent system named by a sampling feature's
+// parentSystem link and reports whether it exists. An unknown system is
+// answered with a 400 problem document, or an invalid ValidationReport for a
+// validate-only create; other lookup failures with a 500.
+func (h *SamplingFeatureHandler) checkParentSystem(w http.ResponseWriter, r *http.Request, systemID string) bool {
+	_, err := h.systemRepo.WithContext(r.Context()).GetByID(systemID)
+	if err == nil {
+		return true
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		h.logger.Error("Failed to look up parent system", zap.String("systemId", systemID), zap.Error(err))
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
+		return false
+	}
+	violations := SchemaViolations{{Path: "parentSystem@link", Message: "references system " + strconv.Quote(systemID) + ", which does not exist"}}
+	if !reportValidation(w, r, violations) {
+		renderValidationProblem(w, r, "Invalid parentSystem link", violations)
+	}
+	return false
+}
+
+// checkSampleOf validates the sampleOf ids of a validate-only create, which
+// never reaches the repository's own check. It reports whether every id names
+// an existing sampling feature, answering with an invalid ValidationReport
+// when one does not.
+func (h *SamplingFeatureHandler) checkSampleOf(w http.ResponseWriter, r *http.Request, ids []string) bool {
+	err := h.repo.WithContext(r.Context()).CheckSampleOf(ids)
+	if err == nil {
+		return true
+	}
+	var unknown *repository.UnknownSampleOfError
+	if errors.As(err, &unknown) {
+		reportValidation(w, r, sampleOfViolations(unknown))
+		return false
+	}
+	h.logger.Error("Failed to look up sampleOf features", zap.Error(err))
+	renderRepositoryError(w, r, err, "Sampling Feature not found", "Internal server error")
+	return false
+}
+
+// resolveSampleOf sets the SampleOfIDs of sf to the sampling features on this
+// server that its sampleOf links name, and returns them. Links to other
+// servers are opaque references with no relation.
+func resolveSampleOf(sf *domains.SamplingFeature) []string {
+	if sf.SampleOf == nil {
+		sf.SampleOfIDs = nil
+		return nil
+	}
+	ids := make([]string, 0, len(*sf.SampleOf))
+	for _, link := range *sf.SampleOf {
+		if id, ok := formaters.LocalResourceID(link.Href, "samplingFeatures"); ok {
+			ids = append(ids, id)
+		}
+	}
+	sf.SampleOfIDs = &ids
+	return ids
 }
 
 func (h *SamplingFeatureHandler) DeleteSamplingFeature(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	if err := h.repo.Delete(id); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(id); err != nil {
 		h.logger.Error("Failed to delete sampling feature", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete sampling feature"})
+		renderRepositoryError(w, r, err, "Sampling Feature not found", "Failed to delete sampling feature")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// DeleteSamplingFeatures deletes every sampling feature matching the query
+// filters (e.g. DELETE /samplingFeatures?system={id}) in one transaction and
+// reports how many were deleted. A request without any filter is rejected.
+func (h *SamplingFeatureHandler) DeleteSamplingFeatures(w http.ResponseWriter, r *http.Request) {
+	params, err := queryparams.SamplingFeatureQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
+
+	deleted, err := h.repo.WithContext(r.Context()).DeleteMatching(params)
+	if errors.Is(err, repository.ErrUnfilteredDelete) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "At least one filter is required to delete sampling features"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete sampling features", zap.Error(err))
+		renderRepositoryError(w, r, err, "Sampling Feature not found", "Failed to delete sampling features")
+		return
+	}
+
+	render.JSON(w, r, map[string]int64{"deleted": deleted})
+}
+
 func (h *SamplingFeatureHandler) GetSystemSamplingFeatures(w http.ResponseWriter, r *http.Request) {
 	systemID := chi.URLParam(r, "id")
 
 	params, err := queryparams.SamplingFeatureQueryParams{}.BuildFromRequest(r)
 	if err != nil {
 		h.logger.Error("Failed to parse query parameters", zap.Error(err))
-		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Invalid query parameters"})
+		renderQueryParamError(w, r, err)
 		return
 	}
 
-	sampledFeatures, total, err := h.repo.ListSystem(params, &systemID)
+	// An unknown parent is a 404, not an empty collection
+	_, err = h.systemRepo.WithContext(r.Context()).GetByID(systemID)
+	if err != nil {
+		h.logger.Error("Failed to look up system for sampling features", zap.String("systemId", systemID), zap.Error(err))
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
+		return
+	}
+
+	// recursive=true also collects sampling features of every descendant subsystem
+	systemIDs := []string{systemID}
+	if r.URL.Query().Get("recursive") == "true" {
+		subsystems, err := h.systemRepo.WithContext(r.Context()).GetSubsystems(systemID, true)
+		if err != nil {
+			h.logger.Error("Failed to get subsystems", zap.String("systemId", systemID), zap.Error(err))
+			renderRepositoryError(w, r, err, "System not found", "Internal server error")
+			return
+		}
+		for _, subsystem := range subsystems {
+			systemIDs = append(systemIDs, subsystem.ID)
+		}
+	}
+
+	sampledFeatures, total, err := h.repo.WithContext(r.Context()).ListSystem(params, systemIDs)
 	if err != nil {
 		h.logger.Error("Failed to list sampling features", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "Sampling Feature not found", "Internal server error")
 		return
 	}
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.fc.BuildCollection(acceptHeader, sampledFeatures, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
 
 }
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// ShutdownGate rejects new requests with 503 Service Unavailable once graceful
+// shutdown has begun, so clients back off instead of seeing connection resets.
+// Requests that were already in flight when the gate closed are left to drain.
+type ShutdownGate struct {
+	shuttingDown atomic.Bool
+	retryAfter   time.Duration
+}
+
+// NewShutdownGate creates a ShutdownGate that advertises retryAfter in the
+// Retry-After header of rejected requests.
+func NewShutdownGate(retryAfter time.Duration) *ShutdownGate {
+	return &ShutdownGate{retryAfter: retryAfter}
+}
+
+// BeginShutdown flips the gate; every request arriving afterwards is rejected.
+func (g *ShutdownGate) BeginShutdown() {
+	g.shuttingDown.Store(true)
+}
+
+// ShuttingDown reports whether BeginShutdown has been called.
+func (g *ShutdownGate) ShuttingDown() bool {
+	return g.shuttingDown.Load()
+}
+
+// Middleware wraps next so that requests are answered with 503 and Retry-After
+// while the server is shutting down.
+func (g *ShutdownGate) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !g.ShuttingDown() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		seconds := int(g.retryAfter.Seconds())
+		if seconds < 1 {
+			seconds = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		w.Header().Set("Connection", "close")
+		render.Status(r, http.StatusServiceUnavailable)
+		render.JSON(w, r, map[string]string{"error": "Server is shutting down"})
+	})
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+	"go.uber.org/zap"
+)
+
+// csvContentType is the media type of the tabular systems export, selected
+// with Accept: text/csv or ?f=csv.
+const csvContentType = "text/csv"
+
+// systemCSVHeader names the columns of the systems CSV export. lon and lat
+// are the centroid of the system geometry and are empty without a location.
+var systemCSVHeader = []string{"id", "uid", "name", "systemType", "lon", "lat", "created"}
+
+// writeSystemsCSV streams the systems matching params as CSV, one row per
+// system as it is read from the database. Errors before the first row still
+// get a JSON 500; later ones can only be logged and end the response early.
+func (h *SystemHandler) writeSystemsCSV(w http.ResponseWriter, r *http.Request, params *queryparams.SystemQueryParams) {
+	out := csv.NewWriter(w)
+	started := false
+	start := func() error {
+		started = true
+		addVary(w.Header(), negotiatedVary...)
+		w.Header().Set("Content-Type", withUTF8Charset(csvContentType))
+		w.WriteHeader(http.StatusOK)
+		return out.Write(systemCSVHeader)
+	}
+
+	err := h.repo.WithContext(r.Context()).EachSummary(params, func(system repository.SystemSummary) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return out.Write([]string{
+			csvText(system.ID),
+			csvText(system.UniqueIdentifier),
+			csvText(system.Name),
+			csvText(system.SystemType),
+			formatCSVFloat(system.Lon),
+			formatCSVFloat(system.Lat),
+			system.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err != nil {
+		h.logger.Error("Failed to export systems as CSV", zap.Error(err))
+		if !started {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		}
+		return
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		h.logger.Error("Failed to write systems CSV", zap.Error(err))
+	}
+}
+
+// csvText guards a text cell against formula injection: spreadsheets run a
+// cell starting with =, +, - or @ as a formula, so such a value is prefixed
+// with a single quote to be shown as text.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// formatCSVFloat renders v with the fewest digits that round-trip, or an
+// empty cell when v is nil.
+func formatCSVFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
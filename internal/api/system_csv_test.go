@@ -0,0 +1,23 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVText(t *testing.T) {
+	tests := map[string]string{
+		"":                 "",
+		"Weather Station":  "Weather Station",
+		"=SUM(A1:A2)":      "'=SUM(A1:A2)",
+		"+1":               "'+1",
+		"-1":               "'-1",
+		"@cmd":             "'@cmd",
+		"a=b":              "a=b",
+		"urn:uuid:1234-56": "urn:uuid:1234-56",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, csvText(in), in)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/yourusername/connected-systems-go/internal/config"
-	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
 	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
 	"github.com/yourusername/connected-systems-go/internal/repository"
@@ -16,10 +15,7 @@ import (
 )
 
 // SystemEventCollectionResponse follows the collection shape used by dynamic-data resources.
-type SystemEventCollectionResponse struct {
-	Items []any               `json:"items"`
-	Links common_shared.Links `json:"links,omitempty"`
-}
+type SystemEventCollectionResponse = ItemCollectionResponse
 
 // SystemEventHandler handles /systemEvents and /systems/{id}/events resources.
 type SystemEventHandler struct {
@@ -34,13 +30,17 @@ func NewSystemEventHandler(cfg *config.Config, logger *zap.Logger, repo *reposit
 }
 
 func (h *SystemEventHandler) ListSystemEvents(w http.ResponseWriter, r *http.Request) {
-	params := queryparams.SystemEventsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.SystemEventsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	events, total, err := h.repo.List(params, nil)
+	events, total, err := h.repo.WithContext(r.Context()).List(params, nil)
 	if err != nil {
 		h.logger.Error("Failed to list system events", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "System event not found", "Internal server error")
 		return
 	}
 
@@ -53,7 +53,7 @@ func (h *SystemEventHandler) ListSystemEvents(w http.ResponseWriter, r *http.Req
 	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(events))
 
 	w.Header().Set("Content-Type", "application/json")
-	render.JSON(w, r, SystemEventCollectionResponse{Items: items, Links: links})
+	render.JSON(w, r, newItemCollection(items, links))
 }
 
 func (h *SystemEventHandler) ListEventsBySystem(w http.ResponseWriter, r *http.Request) {
@@ -62,18 +62,21 @@ func (h *SystemEventHandler) ListEventsBySystem(w http.ResponseWriter, r *http.R
 		systemID = chi.URLParam(r, "id")
 	}
 
-	if _, err := h.systemRepo.GetByID(systemID); err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System not found"})
+	if _, err := h.systemRepo.WithContext(r.Context()).GetByID(systemID); err != nil {
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
 		return
 	}
 
-	params := queryparams.SystemEventsQueryParams{}.BuildFromRequest(r)
-	events, total, err := h.repo.List(params, &systemID)
+	params, err := queryparams.SystemEventsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
+	events, total, err := h.repo.WithContext(r.Context()).List(params, &systemID)
 	if err != nil {
 		h.logger.Error("Failed to list system events", zap.String("systemId", systemID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "System event not found", "Internal server error")
 		return
 	}
 
@@ -86,7 +89,7 @@ func (h *SystemEventHandler) ListEventsBySystem(w http.ResponseWriter, r *http.R
 	links := params.QueryParams.BuildPagintationLinks(h.cfg.API.BaseURL+r.URL.Path, r.URL.Query(), &totalInt, len(events))
 
 	w.Header().Set("Content-Type", "application/json")
-	render.JSON(w, r, SystemEventCollectionResponse{Items: items, Links: links})
+	render.JSON(w, r, newItemCollection(items, links))
 }
 
 func (h *SystemEventHandler) CreateEventBySystem(w http.ResponseWriter, r *http.Request) {
@@ -95,84 +98,89 @@ func (h *SystemEventHandler) CreateEventBySystem(w http.ResponseWriter, r *http.
 		systemID = chi.URLParam(r, "id")
 	}
 
-	if _, err := h.systemRepo.GetByID(systemID); err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System not found"})
+	_, err := h.systemRepo.WithContext(r.Context()).GetByID(systemID)
+	if err != nil {
+		h.logger.Error("Failed to look up system for event", zap.String("systemId", systemID), zap.Error(err))
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
 		return
 	}
 
 	// OpenAPI allows either a single event or an array.
 	var raw any
 	if err := render.DecodeJSON(r.Body, &raw); err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
 
-	createdIDs := make([]string, 0, 1)
-	createOne := func(e *domains.SystemEvent) error {
+	events, err := decodeSystemEvents(raw)
+	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": err.Error()})
+		return
+	}
+	if reportValidation(w, r, nil) {
+		return
+	}
+
+	createdIDs := make([]string, 0, len(events))
+	for _, e := range events {
 		e.SystemID = systemID
 		if e.Label == "" {
 			e.Label = "System Event"
 		}
-		if err := h.repo.Create(e); err != nil {
-			return err
+		if err := h.repo.WithContext(r.Context()).Create(e); err != nil {
+			h.logger.Error("Failed to create system event", zap.String("systemId", systemID), zap.Error(err))
+			renderRepositoryError(w, r, err, "System event not found", "Failed to create system event")
+			return
 		}
 		createdIDs = append(createdIDs, e.ID)
-		return nil
 	}
 
+	location := strings.TrimRight(h.cfg.API.BaseURL, "/") + "/systems/" + systemID + "/events/" + createdIDs[0]
+	w.Header().Set("Location", location)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// decodeSystemEvents converts a decoded request body holding a single event
+// object or a non-empty array of them into system events.
+func decodeSystemEvents(raw any) ([]*domains.SystemEvent, error) {
+	var items []any
 	switch v := raw.(type) {
 	case map[string]any:
-		var evt domains.SystemEvent
-		bytes, _ := json.Marshal(v)
-		if err := json.Unmarshal(bytes, &evt); err != nil {
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, map[string]string{"error": "Invalid system event payload"})
-			return
-		}
-		if err := createOne(&evt); err != nil {
-			h.logger.Error("Failed to create system event", zap.String("systemId", systemID), zap.Error(err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, map[string]string{"error": "Failed to create system event"})
-			return
-		}
+		items = []any{v}
 	case []any:
 		if len(v) == 0 {
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, map[string]string{"error": "At least one event is required"})
-			return
-		}
-		for _, item := range v {
-			itemObj, ok := item.(map[string]any)
-			if !ok {
-				render.Status(r, http.StatusBadRequest)
-				render.JSON(w, r, map[string]string{"error": "Invalid system event payload"})
-				return
-			}
-			var evt domains.SystemEvent
-			bytes, _ := json.Marshal(itemObj)
-			if err := json.Unmarshal(bytes, &evt); err != nil {
-				render.Status(r, http.StatusBadRequest)
-				render.JSON(w, r, map[string]string{"error": "Invalid system event payload"})
-				return
-			}
-			if err := createOne(&evt); err != nil {
-				h.logger.Error("Failed to create system event", zap.String("systemId", systemID), zap.Error(err))
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, map[string]string{"error": "Failed to create system event"})
-				return
-			}
+			return nil, &decodeError{msg: "At least one event is required"}
 		}
+		items = v
 	default:
-		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, map[string]string{"error": "Invalid system event payload"})
-		return
+		return nil, &decodeError{msg: "Invalid system event payload"}
 	}
 
-	location := strings.TrimRight(h.cfg.API.BaseURL, "/") + "/systems/" + systemID + "/events/" + createdIDs[0]
-	w.Header().Set("Location", location)
-	w.WriteHeader(http.StatusCreated)
+	events := make([]*domains.SystemEvent, 0, len(items))
+	for _, item := range items {
+		itemObj, ok := item.(map[string]any)
+		if !ok {
+			return nil, &decodeError{msg: "Invalid system event payload"}
+		}
+		var evt domains.SystemEvent
+		bytes, _ := json.Marshal(itemObj)
+		if err := json.Unmarshal(bytes, &evt); err != nil {
+			return nil, &decodeError{msg: "Invalid system event payload"}
+		}
+		events = append(events, &evt)
+	}
+	return events, nil
 }
 
 func (h *SystemEventHandler) GetEventByID(w http.ResponseWriter, r *http.Request) {
@@ -182,16 +190,14 @@ func (h *SystemEventHandler) GetEventByID(w http.ResponseWriter, r *http.Request
 	}
 	eventID := chi.URLParam(r, "eventId")
 
-	event, err := h.repo.GetByID(systemID, eventID)
+	event, err := h.repo.WithContext(r.Context()).GetByID(systemID, eventID)
 	if err != nil {
 		h.logger.Error("Failed to get system event", zap.String("systemId", systemID), zap.String("eventId", eventID), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System event not found"})
+		renderRepositoryError(w, r, err, "System event not found", "Internal server error")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	render.JSON(w, r, event)
+	renderJSONWithETag(w, r, "application/json", event)
 }
 
 func (h *SystemEventHandler) UpdateEventByID(w http.ResponseWriter, r *http.Request) {
@@ -201,15 +207,17 @@ func (h *SystemEventHandler) UpdateEventByID(w http.ResponseWriter, r *http.Requ
 	}
 	eventID := chi.URLParam(r, "eventId")
 
-	existing, err := h.repo.GetByID(systemID, eventID)
+	existing, err := h.repo.WithContext(r.Context()).GetByID(systemID, eventID)
 	if err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System event not found"})
+		renderRepositoryError(w, r, err, "System event not found", "Internal server error")
 		return
 	}
 
 	var event domains.SystemEvent
 	if err := render.DecodeJSON(r.Body, &event); err != nil {
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
@@ -217,10 +225,9 @@ func (h *SystemEventHandler) UpdateEventByID(w http.ResponseWriter, r *http.Requ
 
 	event.ID = eventID
 	event.SystemID = existing.SystemID
-	if err := h.repo.Update(&event); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(&event); err != nil {
 		h.logger.Error("Failed to update system event", zap.String("eventId", eventID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update system event"})
+		renderRepositoryError(w, r, err, "System event not found", "Failed to update system event")
 		return
 	}
 
@@ -234,16 +241,14 @@ func (h *SystemEventHandler) DeleteEventByID(w http.ResponseWriter, r *http.Requ
 	}
 	eventID := chi.URLParam(r, "eventId")
 
-	if _, err := h.repo.GetByID(systemID, eventID); err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System event not found"})
+	if _, err := h.repo.WithContext(r.Context()).GetByID(systemID, eventID); err != nil {
+		renderRepositoryError(w, r, err, "System event not found", "Internal server error")
 		return
 	}
 
-	if err := h.repo.Delete(systemID, eventID); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(systemID, eventID); err != nil {
 		h.logger.Error("Failed to delete system event", zap.String("eventId", eventID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete system event"})
+		renderRepositoryError(w, r, err, "System event not found", "Failed to delete system event")
 		return
 	}
 
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"github.com/yourusername/connected-systems-go/internal/model/formaters"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+)
+
+// systemExpandParentSystem is the ?expand value that embeds a summary of the
+// parent system in each system.
+const systemExpandParentSystem = "parentSystem"
+
+// parentSystemSummary is the parent system embedded with ?expand=parentSystem,
+// enough to build a breadcrumb without following the parentSystem link.
+type parentSystemSummary struct {
+	ID   string `json:"id"`
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+// parseSystemExpand reports whether ?expand asks for the parent system. The
+// parameter takes a comma-separated list; unknown values are rejected.
+func parseSystemExpand(r *http.Request) (bool, error) {
+	raw := r.URL.Query().Get("expand")
+	expandParent := false
+	for _, value := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(value) {
+		case "":
+		case systemExpandParentSystem:
+			expandParent = true
+		default:
+			return false, &queryparams.InvalidParameterError{Name: "expand", Value: raw, Reason: "must be one of: " + systemExpandParentSystem}
+		}
+	}
+	return expandParent, nil
+}
+
+// newParentSystemCache returns a cache of parent systems loaded through
+// GetByID, so siblings on a page share one lookup of their parent. Parents
+// that no longer exist are left out rather than failing the request.
+func (h *SystemHandler) newParentSystemCache(ctx context.Context) *formaters.ResourceCache[*domains.System] {
+	repo := h.repo.WithContext(ctx)
+	return formaters.NewResourceCache(func(ctx context.Context, ids []string) (map[string]*domains.System, error) {
+		found := make(map[string]*domains.System, len(ids))
+		for _, id := range ids {
+			system, err := repo.GetByID(id)
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			found[id] = system
+		}
+		return found, nil
+	})
+}
+
+// embedParentSystem adds the summary of the system's parent to the serialized
+// system under properties.parentSystem, or at the top level for encodings
+// without a properties member such as SensorML. Systems without a parent, or
+// whose parent cannot be found, keep only the parentSystem link.
+func embedParentSystem(ctx context.Context, parents *formaters.ResourceCache[*domains.System], system *domains.System, serialized any) (any, error) {
+	if system.ParentSystemID == nil {
+		return serialized, nil
+	}
+	parent, ok := parents.Get(ctx, *system.ParentSystemID)
+	if !ok {
+		return serialized, nil
+	}
+	summary := parentSystemSummary{ID: parent.ID, UID: string(parent.UniqueIdentifier), Name: parent.Name}
+
+	raw, err := json.Marshal(serialized)
+	if err != nil {
+		return nil, err
+	}
+	out := common_shared.NewOrderedMap()
+	if err := json.Unmarshal(raw, out); err != nil {
+		return nil, err
+	}
+
+	rawProperties, ok := out.Get("properties")
+	if !ok {
+		out.Set("parentSystem", summary)
+		return out, nil
+	}
+	properties := common_shared.NewOrderedMap()
+	if err := json.Unmarshal(rawProperties.(json.RawMessage), properties); err != nil {
+		return nil, err
+	}
+	properties.Set("parentSystem", summary)
+	out.Set("properties", properties)
+	return out, nil
+}
+
+// embedParentSystems embeds the parent summary in each serialized feature of a
+// page, where features[i] is the serialization of systems[i]. Parents are
+// fetched up front so siblings share a single lookup.
+func (h *SystemHandler) embedParentSystems(ctx context.Context, systems []*domains.System, features []any) error {
+	if len(features) != len(systems) {
+		return nil
+	}
+	parentIDs := make([]string, 0, len(systems))
+	for _, system := range systems {
+		if system.ParentSystemID != nil {
+			parentIDs = append(parentIDs, *system.ParentSystemID)
+		}
+	}
+	parents := h.newParentSystemCache(ctx)
+	if err := parents.Prefetch(ctx, parentIDs); err != nil {
+		return err
+	}
+	for i, system := range systems {
+		expanded, err := embedParentSystem(ctx, parents, system, features[i])
+		if err != nil {
+			return err
+		}
+		features[i] = expanded
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"github.com/yourusername/connected-systems-go/internal/model/formaters"
+)
+
+func TestEmbedParentSystem(t *testing.T) {
+	parentID := "parent-1"
+	parent := &domains.System{Base: domains.Base{ID: parentID}, CommonSSN: domains.CommonSSN{UniqueIdentifier: "urn:test:parent", Name: "Weather Station"}}
+	parents := formaters.NewResourceCache(func(ctx context.Context, ids []string) (map[string]*domains.System, error) {
+		return map[string]*domains.System{parentID: parent}, nil
+	})
+	child := &domains.System{Base: domains.Base{ID: "child-1"}, ParentSystemID: &parentID}
+
+	t.Run("child system embeds the parent under properties", func(t *testing.T) {
+		serialized := map[string]any{"type": "Feature", "id": "child-1", "properties": map[string]any{"name": "Thermometer"}}
+		expanded, err := embedParentSystem(context.Background(), parents, child, serialized)
+		require.NoError(t, err)
+
+		raw, err := json.Marshal(expanded)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"Feature","id":"child-1","properties":{"name":"Thermometer","parentSystem":{"id":"parent-1","uid":"urn:test:parent","name":"Weather Station"}}}`, string(raw))
+	})
+
+	t.Run("encodings without properties embed at the top level", func(t *testing.T) {
+		serialized := map[string]any{"type": "PhysicalSystem", "id": "child-1"}
+		expanded, err := embedParentSystem(context.Background(), parents, child, serialized)
+		require.NoError(t, err)
+
+		raw, err := json.Marshal(expanded)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"PhysicalSystem","id":"child-1","parentSystem":{"id":"parent-1","uid":"urn:test:parent","name":"Weather Station"}}`, string(raw))
+	})
+
+	t.Run("systems without a parent are unchanged", func(t *testing.T) {
+		serialized := map[string]any{"id": "parent-1"}
+		expanded, err := embedParentSystem(context.Background(), parents, parent, serialized)
+		require.NoError(t, err)
+		assert.Equal(t, serialized, expanded)
+	})
+}
+
+func TestParseSystemExpand(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		want    bool
+		wantErr bool
+	}{
+		"absent":        {query: "", want: false},
+		"parentSystem":  {query: "?expand=parentSystem", want: true},
+		"list":          {query: "?expand=parentSystem,", want: true},
+		"unknown value": {query: "?expand=owner", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseSystemExpand(httptest.NewRequest(http.MethodGet, "/systems/1"+tt.query, nil))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
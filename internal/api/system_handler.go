@@ -1,12 +1,16 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/yourusername/connected-systems-go/internal/config"
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
 	"github.com/yourusername/connected-systems-go/internal/model/formaters"
 	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
@@ -27,57 +31,124 @@ type SystemHandler struct {
 	// procedure dependencies for server-side association endpoint
 	procedureRepo *repository.ProcedureRepository
 	procedureFC   *formaters.MultiFormatFormatterCollection[*domains.Procedure]
+	// stream dependencies for inlining with ?details=full
+	datastreamRepo    *repository.DatastreamRepository
+	datastreamFC      *formaters.MultiFormatFormatterCollection[*domains.Datastream]
+	controlStreamRepo *repository.ControlStreamRepository
+	controlStreamFC   *formaters.MultiFormatFormatterCollection[*domains.ControlStream]
 }
 
 // NewSystemHandler creates a new SystemHandler
-func NewSystemHandler(cfg *config.Config, logger *zap.Logger, repo *repository.SystemRepository, historyRepo *repository.SystemHistoryRepository, fc *formaters.MultiFormatFormatterCollection[*domains.System], deploymentRepo *repository.DeploymentRepository, deploymentFC *formaters.MultiFormatFormatterCollection[*domains.Deployment], procedureRepo *repository.ProcedureRepository, procedureFC *formaters.MultiFormatFormatterCollection[*domains.Procedure]) *SystemHandler {
+func NewSystemHandler(cfg *config.Config, logger *zap.Logger, repo *repository.SystemRepository, historyRepo *repository.SystemHistoryRepository, fc *formaters.MultiFormatFormatterCollection[*domains.System], deploymentRepo *repository.DeploymentRepository, deploymentFC *formaters.MultiFormatFormatterCollection[*domains.Deployment], procedureRepo *repository.ProcedureRepository, procedureFC *formaters.MultiFormatFormatterCollection[*domains.Procedure], datastreamRepo *repository.DatastreamRepository, datastreamFC *formaters.MultiFormatFormatterCollection[*domains.Datastream], controlStreamRepo *repository.ControlStreamRepository, controlStreamFC *formaters.MultiFormatFormatterCollection[*domains.ControlStream]) *SystemHandler {
 	return &SystemHandler{
-		cfg:            cfg,
-		logger:         logger,
-		repo:           repo,
-		historyRepo:    historyRepo,
-		fc:             fc,
-		deploymentRepo: deploymentRepo,
-		deploymentFC:   deploymentFC,
-		procedureRepo:  procedureRepo,
-		procedureFC:    procedureFC,
+		cfg:               cfg,
+		logger:            logger,
+		repo:              repo,
+		historyRepo:       historyRepo,
+		fc:                fc,
+		deploymentRepo:    deploymentRepo,
+		deploymentFC:      deploymentFC,
+		procedureRepo:     procedureRepo,
+		procedureFC:       procedureFC,
+		datastreamRepo:    datastreamRepo,
+		datastreamFC:      datastreamFC,
+		controlStreamRepo: controlStreamRepo,
+		controlStreamFC:   controlStreamFC,
 	}
 }
 
-// ListSystems retrieves a list of systems
+// ListSystems retrieves a list of systems. Clients preferring text/csv get
+// the tabular export written by writeSystemsCSV, and clients preferring
+// application/x-ndjson the line-delimited features written by
+// writeSystemsNDJSON, instead of a feature collection. ?expand=parentSystem
+// embeds the parent of each system in the collection, as on GetSystem.
 func (h *SystemHandler) ListSystems(w http.ResponseWriter, r *http.Request) {
-	params := queryparams.SystemQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.SystemQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
+	expandParent, err := parseSystemExpand(r)
+	if err != nil {
+		renderQueryParamError(w, r, err)
+		return
+	}
+
+	if formaters.Prefers(r.Header.Get("Accept"), csvContentType) {
+		h.writeSystemsCSV(w, r, params)
+		return
+	}
+	if formaters.Prefers(r.Header.Get("Accept"), ndjsonContentType) {
+		h.writeSystemsNDJSON(w, r, params)
+		return
+	}
 
-	systems, total, err := h.repo.List(params)
+	systems, total, err := h.repo.WithContext(r.Context()).List(params)
 	if err != nil {
 		h.logger.Error("Failed to list systems", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
 		return
 	}
 
-	h.populateSystemAssociationLinks(systems)
+	h.populateSystemAssociationLinks(r.Context(), systems)
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.fc.BuildCollection(acceptHeader, systems, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
+
+	if expandParent {
+		if err := h.embedParentSystems(r.Context(), systems, collection.Features); err != nil {
+			h.logger.Error("Failed to embed parent systems", zap.Error(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Failed to load parent systems"})
+			return
+		}
+	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
 }
 
-// GetSystem retrieves a single system by ID
+// GetSystemByUID redirects GET /systems/uid/{uid} to the canonical /systems/{id}
+func (h *SystemHandler) GetSystemByUID(w http.ResponseWriter, r *http.Request) {
+	redirectByUID(w, r, h.logger, h.cfg.API.BaseURL, "systems", "System", func(uid string) (string, error) {
+		system, err := h.repo.WithContext(r.Context()).GetByUID(uid)
+		if err != nil {
+			return "", err
+		}
+		return system.ID, nil
+	})
+}
+
+// GetSystem retrieves a single system by ID.
+// With ?details=full the immediate subsystems, datastreams and control streams are
+// embedded inline; embedding stops at one level, so deeper trees must be paged
+// through the association endpoints. The default, details=links, returns links only.
+// With ?expand=parentSystem a summary of the parent system is embedded as well
+// (see embedParentSystem).
 func (h *SystemHandler) GetSystem(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	system, err := h.repo.GetByID(id)
+	details := r.URL.Query().Get("details")
+	if details != "" && details != systemDetailsLinks && details != systemDetailsFull {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "details must be one of: links, full"})
+		return
+	}
+	expandParent, err := parseSystemExpand(r)
+	if err != nil {
+		renderQueryParamError(w, r, err)
+		return
+	}
+
+	system, err := h.repo.WithContext(r.Context()).GetByID(id)
 	if err != nil {
 		h.logger.Error("Failed to get system", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System not found"})
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
 		return
 	}
 
-	system.Links = append(system.Links, h.repo.BuildSystemAssociations(id)...)
+	system.Links = append(system.Links, h.repo.WithContext(r.Context()).BuildSystemAssociations(id)...)
 
 	acceptHeader := r.Header.Get("Accept")
 	serialized, err := h.fc.Serialize(acceptHeader, system)
@@ -88,8 +159,154 @@ func (h *SystemHandler) GetSystem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, serialized)
+	if expandParent {
+		serialized, err = embedParentSystem(r.Context(), h.newParentSystemCache(r.Context()), system, serialized)
+		if err != nil {
+			h.logger.Error("Failed to embed parent system", zap.String("id", id), zap.Error(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Failed to load parent system"})
+			return
+		}
+	}
+
+	if details == systemDetailsFull {
+		serialized, err = h.embedSystemDetails(r.Context(), h.fc.GetResponseContentType(acceptHeader), id, serialized)
+		if err != nil {
+			h.logger.Error("Failed to embed system details", zap.String("id", id), zap.Error(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Failed to load system details"})
+			return
+		}
+	}
+
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), serialized)
+}
+
+// GetSystemGeometry serves the system's geometry on its own: GeoJSON by
+// default, or hex-encoded EWKB (see common_shared.GoGeom.EWKBHex for the byte
+// layout) with ?f=wkb, for loading straight into another PostGIS database.
+func (h *SystemHandler) GetSystemGeometry(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	format := strings.ToLower(r.URL.Query().Get("f"))
+	if format != "" && format != "wkb" && format != "geojson" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "f must be one of: geojson, wkb"})
+		return
+	}
+
+	system, err := h.repo.WithContext(r.Context()).GetByID(id)
+	if err != nil {
+		h.logger.Error("Failed to get system", zap.String("id", id), zap.Error(err))
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
+		return
+	}
+	if system.Geometry == nil || system.Geometry.T == nil {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, map[string]string{"error": "System has no geometry"})
+		return
+	}
+
+	if format != "wkb" {
+		renderJSONWithETag(w, r, "application/geo+json", system.Geometry)
+		return
+	}
+
+	encoded, err := system.Geometry.EWKBHex()
+	if err != nil {
+		h.logger.Error("Failed to encode system geometry", zap.String("id", id), zap.Error(err))
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Failed to encode geometry"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(encoded)) //nolint:errcheck
+}
+
+const (
+	systemDetailsLinks = "links"
+	systemDetailsFull  = "full"
+)
+
+// systemDetailsMaxItems caps each member embedded by details=full; the rest
+// are paged through the association endpoint named by the member's @link.
+const systemDetailsMaxItems = 50
+
+// embedSystemDetails adds the system's immediate subsystems, datastreams and control
+// streams to the serialized system. Embedded items keep their own association links
+// rather than being expanded further. Each member holds at most
+// systemDetailsMaxItems items; a longer one also gets a <member>@link to the
+// association endpoint, offset past the embedded items.
+//
+// Subsystems are serialized in the system's negotiated format. Datastreams and
+// control streams only have a JSON encoding, which a GeoJSON document can carry
+// but a SensorML one cannot, so for SensorML they are only linked.
+func (h *SystemHandler) embedSystemDetails(ctx context.Context, contentType, id string, serialized any) (any, error) {
+	raw, err := json.Marshal(serialized)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the formatter's member order; embedded collections are appended after it
+	out := common_shared.NewOrderedMap()
+	if err := json.Unmarshal(raw, out); err != nil {
+		return nil, err
+	}
+
+	basePath := strings.TrimRight(h.cfg.API.BaseURL, "/") + "/systems/" + id + "/"
+	page := queryparams.QueryParams{Limit: systemDetailsMaxItems}
+	embed := func(member string, items []any, total int64) {
+		out.Set(member, items)
+		if total > int64(len(items)) {
+			out.Set(member+"@link", common_shared.Link{
+				Href: fmt.Sprintf("%s%s?offset=%d&limit=%d", basePath, member, len(items), systemDetailsMaxItems),
+				Rel:  member,
+			})
+		}
+	}
+	link := func(member string) {
+		out.Set(member+"@link", common_shared.Link{Href: basePath + member, Rel: member})
+	}
+
+	subsystems, total, err := h.repo.WithContext(ctx).ListSubsystems(id, false, &queryparams.SystemQueryParams{QueryParams: page})
+	if err != nil {
+		return nil, err
+	}
+	h.populateSystemAssociationLinks(ctx, subsystems)
+	serializedSubsystems, err := h.fc.SerializeAll(contentType, subsystems)
+	if err != nil {
+		return nil, err
+	}
+	embed("subsystems", serializedSubsystems, total)
+
+	if contentType != "application/geo+json" {
+		link("datastreams")
+		link("controlstreams")
+		return out, nil
+	}
+
+	datastreams, total, err := h.datastreamRepo.WithContext(ctx).List(&queryparams.DatastreamsQueryParams{QueryParams: page}, &id)
+	if err != nil {
+		return nil, err
+	}
+	serializedDatastreams, err := h.datastreamFC.SerializeAll("application/json", datastreams)
+	if err != nil {
+		return nil, err
+	}
+	embed("datastreams", serializedDatastreams, total)
+
+	controlStreams, total, err := h.controlStreamRepo.WithContext(ctx).List(&queryparams.ControlStreamsQueryParams{QueryParams: page}, &id)
+	if err != nil {
+		return nil, err
+	}
+	serializedControlStreams, err := h.controlStreamFC.SerializeAll("application/json", controlStreams)
+	if err != nil {
+		return nil, err
+	}
+	embed("controlstreams", serializedControlStreams, total)
+
+	return out, nil
 }
 
 // CreateSystem creates a new system
@@ -97,26 +314,38 @@ func (h *SystemHandler) CreateSystem(w http.ResponseWriter, r *http.Request) {
 	contentType := r.Header.Get("Content-Type")
 	system, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to deserialize system", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
+	geometryErr := checkRingOrientation(h.cfg, system.Geometry)
+	if reportCreateValidation(w, r, geometryErr, h.repo.WithContext(r.Context()), string(system.UniqueIdentifier)) {
+		return
+	}
+	if geometryErr != nil {
+		renderValidationProblem(w, r, "Invalid geometry", geometryErr)
+		return
+	}
 
-	if err := h.repo.Create(system); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(system); err != nil {
 		h.logger.Error("Failed to create system", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create system"})
+		renderRepositoryError(w, r, err, "System not found", "Failed to create system")
 		return
 	}
 
-	if _, err := h.historyRepo.CreateFromSystem(system); err != nil {
+	if _, err := h.historyRepo.WithContext(r.Context()).CreateFromSystem(system); err != nil {
 		h.logger.Warn("Failed to create initial system history snapshot", zap.String("systemId", system.ID), zap.Error(err))
 	}
 
 	location := strings.TrimRight(h.cfg.API.BaseURL, "/") + "/systems/" + system.ID
-	w.Header().Set("Location", location)
-	w.WriteHeader(http.StatusCreated)
+	writeCreated(w, r, h.fc, location, func() (*domains.System, error) { return h.repo.WithContext(r.Context()).GetByID(system.ID) })
 }
 
 // UpdateSystem updates a system (PUT)
@@ -127,24 +356,30 @@ func (h *SystemHandler) UpdateSystem(w http.ResponseWriter, r *http.Request) {
 	system, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
 		h.logger.Error("Failed to deserialize system", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
+	if err := checkRingOrientation(h.cfg, system.Geometry); err != nil {
+		renderValidationProblem(w, r, "Invalid geometry", err)
+		return
+	}
 
 	system.ID = id
-	if err := h.repo.Update(system.ID, system); err != nil {
+	if err := h.repo.WithContext(r.Context()).Update(system.ID, system); err != nil {
 		h.logger.Error("Failed to update system", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update system"})
+		renderRepositoryError(w, r, err, "System not found", "Failed to update system")
 		return
 	}
 
-	if _, err := h.historyRepo.CreateFromSystem(system); err != nil {
+	if _, err := h.historyRepo.WithContext(r.Context()).CreateFromSystem(system); err != nil {
 		h.logger.Warn("Failed to create system history snapshot after update", zap.String("systemId", system.ID), zap.Error(err))
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	writeUpdated(w, r, h.fc, func() (*domains.System, error) { return h.repo.WithContext(r.Context()).GetByID(id) })
 }
 
 // DeleteSystem deletes a system
@@ -152,10 +387,9 @@ func (h *SystemHandler) DeleteSystem(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	cascade := r.URL.Query().Get("cascade") == "true"
 
-	if err := h.repo.Delete(id, cascade); err != nil {
+	if err := h.repo.WithContext(r.Context()).Delete(id, cascade); err != nil {
 		h.logger.Error("Failed to delete system", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete system"})
+		renderRepositoryError(w, r, err, "System not found", "Failed to delete system")
 		return
 	}
 
@@ -166,31 +400,49 @@ func (h *SystemHandler) DeleteSystem(w http.ResponseWriter, r *http.Request) {
 func (h *SystemHandler) GetSubsystems(w http.ResponseWriter, r *http.Request) {
 	parentID := chi.URLParam(r, "id")
 	recursive := r.URL.Query().Get("recursive") == "true"
-	params := queryparams.SystemQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.SystemQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
+	expandParent, err := parseSystemExpand(r)
+	if err != nil {
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	systems, err := h.repo.GetSubsystems(parentID, recursive)
+	systems, total, err := h.repo.WithContext(r.Context()).ListSubsystems(parentID, recursive, params)
 	if err != nil {
 		h.logger.Error("Failed to get subsystems", zap.String("parentID", parentID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to get subsystems"})
+		renderRepositoryError(w, r, err, "System not found", "Failed to get subsystems")
 		return
 	}
 
-	h.populateSystemAssociationLinks(systems)
+	h.populateSystemAssociationLinks(r.Context(), systems)
 
 	acceptHeader := r.Header.Get("Accept")
-	collection := h.fc.BuildCollection(acceptHeader, systems, h.cfg.API.BaseURL+r.URL.Path, len(systems), r.URL.Query(), params.QueryParams)
+	collection := h.fc.BuildCollection(acceptHeader, systems, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
+
+	if expandParent {
+		if err := h.embedParentSystems(r.Context(), systems, collection.Features); err != nil {
+			h.logger.Error("Failed to embed parent systems", zap.Error(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Failed to load parent systems"})
+			return
+		}
+	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
 }
 
-func (h *SystemHandler) populateSystemAssociationLinks(systems []*domains.System) {
+func (h *SystemHandler) populateSystemAssociationLinks(ctx context.Context, systems []*domains.System) {
 	for _, system := range systems {
 		if system == nil || strings.TrimSpace(system.ID) == "" {
 			continue
 		}
-		system.Links = append(system.Links, h.repo.BuildSystemAssociations(system.ID)...)
+		system.Links = append(system.Links, h.repo.WithContext(ctx).BuildSystemAssociations(system.ID)...)
 	}
 }
 
@@ -199,72 +451,97 @@ func (h *SystemHandler) GetDeployments(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	// Build optional pagination params from request
-	params := queryparams.DeploymentsQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.DeploymentsQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 	params.System = append(params.System, id)
 
 	// Use deployment repository helper to find deployments associated with this system
-	deployments, total, err := h.deploymentRepo.List(params, nil)
+	deployments, total, err := h.deploymentRepo.WithContext(r.Context()).List(params, nil)
 	if err != nil {
 		h.logger.Error("Failed to get deployments for system", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to get deployments"})
+		renderRepositoryError(w, r, err, "System not found", "Failed to get deployments")
 		return
 	}
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.deploymentFC.BuildCollection(acceptHeader, deployments, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
 
-	w.Header().Set("Content-Type", h.deploymentFC.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.deploymentFC.GetResponseContentType(acceptHeader), collection)
 }
 
 // GetProcedures retrieves procedures associated with a system.
 func (h *SystemHandler) GetProcedures(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	params := queryparams.ProceduresQueryParams{}.BuildFromRequest(r)
+	params, err := queryparams.ProceduresQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
 
-	procedures, total, err := h.procedureRepo.ListBySystem(id, params)
+	procedures, total, err := h.procedureRepo.WithContext(r.Context()).ListBySystem(id, params)
 	if err != nil {
 		h.logger.Error("Failed to get procedures for system", zap.String("id", id), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to get procedures"})
+		renderRepositoryError(w, r, err, "System not found", "Failed to get procedures")
 		return
 	}
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.procedureFC.BuildCollection(acceptHeader, procedures, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
 
-	w.Header().Set("Content-Type", h.procedureFC.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.procedureFC.GetResponseContentType(acceptHeader), collection)
 }
 
 // Add subsystem to a system
 func (h *SystemHandler) AddSubsystem(w http.ResponseWriter, r *http.Request) {
 	parentID := chi.URLParam(r, "id")
+	if _, err := h.repo.WithContext(r.Context()).GetByID(parentID); err != nil {
+		h.logger.Error("Failed to look up parent system", zap.String("id", parentID), zap.Error(err))
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
+		return
+	}
 
 	contentType := r.Header.Get("Content-Type")
 	system, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if reportValidation(w, r, err) {
+			return
+		}
 		h.logger.Error("Failed to deserialize system", zap.Error(err))
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
 
 	system.ParentSystemID = &parentID
+	geometryErr := checkRingOrientation(h.cfg, system.Geometry)
+	if reportCreateValidation(w, r, geometryErr, h.repo.WithContext(r.Context()), string(system.UniqueIdentifier)) {
+		return
+	}
+	if geometryErr != nil {
+		renderValidationProblem(w, r, "Invalid geometry", geometryErr)
+		return
+	}
 
-	if err := h.repo.Create(system); err != nil {
+	if err := h.repo.WithContext(r.Context()).Create(system); err != nil {
 		h.logger.Error("Failed to create subsystem", zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to create subsystem"})
+		renderRepositoryError(w, r, err, "System not found", "Failed to create subsystem")
 		return
 	}
 
-	if _, err := h.historyRepo.CreateFromSystem(system); err != nil {
+	if _, err := h.historyRepo.WithContext(r.Context()).CreateFromSystem(system); err != nil {
 		h.logger.Warn("Failed to create subsystem history snapshot", zap.String("systemId", system.ID), zap.Error(err))
 	}
 
 	location := strings.TrimRight(h.cfg.API.BaseURL, "/") + "/systems/" + system.ID
-	w.Header().Set("Location", location)
-	w.WriteHeader(http.StatusCreated)
+	writeCreated(w, r, h.fc, location, func() (*domains.System, error) { return h.repo.WithContext(r.Context()).GetByID(system.ID) })
 }
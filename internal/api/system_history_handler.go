@@ -18,18 +18,21 @@ func (h *SystemHandler) ListSystemHistory(w http.ResponseWriter, r *http.Request
 		systemID = chi.URLParam(r, "id")
 	}
 
-	if _, err := h.repo.GetByID(systemID); err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System not found"})
+	if _, err := h.repo.WithContext(r.Context()).GetByID(systemID); err != nil {
+		renderRepositoryError(w, r, err, "System not found", "Internal server error")
 		return
 	}
 
-	params := queryparams.SystemHistoryQueryParams{}.BuildFromRequest(r)
-	revisions, total, err := h.historyRepo.List(systemID, params)
+	params, err := queryparams.SystemHistoryQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		h.logger.Error("Failed to parse query parameters", zap.Error(err))
+		renderQueryParamError(w, r, err)
+		return
+	}
+	revisions, total, err := h.historyRepo.WithContext(r.Context()).List(systemID, params)
 	if err != nil {
 		h.logger.Error("Failed to list system history", zap.String("systemId", systemID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		renderRepositoryError(w, r, err, "System history not found", "Internal server error")
 		return
 	}
 
@@ -45,9 +48,9 @@ func (h *SystemHandler) ListSystemHistory(w http.ResponseWriter, r *http.Request
 
 	acceptHeader := r.Header.Get("Accept")
 	collection := h.fc.BuildCollection(acceptHeader, systems, h.cfg.API.BaseURL+r.URL.Path, int(total), r.URL.Query(), params.QueryParams)
+	echoQuery(&collection, params.QueryParams, params)
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, collection)
+	renderNegotiatedJSON(w, r, h.fc.GetResponseContentType(acceptHeader), collection)
 }
 
 // GetSystemHistoryRevision handles GET /systems/{id}/history/{revId}.
@@ -58,10 +61,9 @@ func (h *SystemHandler) GetSystemHistoryRevision(w http.ResponseWriter, r *http.
 	}
 	revID := chi.URLParam(r, "revId")
 
-	revision, err := h.historyRepo.GetByID(systemID, revID)
+	revision, err := h.historyRepo.WithContext(r.Context()).GetByID(systemID, revID)
 	if err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System history revision not found"})
+		renderRepositoryError(w, r, err, "System history revision not found", "Internal server error")
 		return
 	}
 
@@ -82,8 +84,7 @@ func (h *SystemHandler) GetSystemHistoryRevision(w http.ResponseWriter, r *http.
 		return
 	}
 
-	w.Header().Set("Content-Type", h.fc.GetResponseContentType(acceptHeader))
-	render.JSON(w, r, serialized)
+	renderNegotiatedJSONWithETag(w, r, h.fc.GetResponseContentType(acceptHeader), serialized)
 }
 
 // UpdateSystemHistoryRevision handles PUT /systems/{id}/history/{revId}.
@@ -94,10 +95,9 @@ func (h *SystemHandler) UpdateSystemHistoryRevision(w http.ResponseWriter, r *ht
 	}
 	revID := chi.URLParam(r, "revId")
 
-	existingRevision, err := h.historyRepo.GetByID(systemID, revID)
+	existingRevision, err := h.historyRepo.WithContext(r.Context()).GetByID(systemID, revID)
 	if err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System history revision not found"})
+		renderRepositoryError(w, r, err, "System history revision not found", "Internal server error")
 		return
 	}
 
@@ -111,10 +111,17 @@ func (h *SystemHandler) UpdateSystemHistoryRevision(w http.ResponseWriter, r *ht
 	contentType := r.Header.Get("Content-Type")
 	updatedSystem, err := h.fc.Deserialize(contentType, r.Body)
 	if err != nil {
+		if renderDecodeError(w, r, err) {
+			return
+		}
 		render.Status(r, http.StatusBadRequest)
 		render.JSON(w, r, map[string]string{"error": "Invalid request body"})
 		return
 	}
+	if err := checkRingOrientation(h.cfg, updatedSystem.Geometry); err != nil {
+		renderValidationProblem(w, r, "Invalid geometry", err)
+		return
+	}
 
 	// Requirement: validTime of the provided revision must not change.
 	if !sameTimeRange(existingSystem.ValidTime, updatedSystem.ValidTime) {
@@ -124,10 +131,9 @@ func (h *SystemHandler) UpdateSystemHistoryRevision(w http.ResponseWriter, r *ht
 	}
 
 	updatedSystem.ID = existingSystem.ID
-	if err := h.historyRepo.UpdateSnapshot(systemID, revID, updatedSystem); err != nil {
+	if err := h.historyRepo.WithContext(r.Context()).UpdateSnapshot(systemID, revID, updatedSystem); err != nil {
 		h.logger.Error("Failed to update system history revision", zap.String("revId", revID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to update system history revision"})
+		renderRepositoryError(w, r, err, "System history not found", "Failed to update system history revision")
 		return
 	}
 
@@ -142,16 +148,14 @@ func (h *SystemHandler) DeleteSystemHistoryRevision(w http.ResponseWriter, r *ht
 	}
 	revID := chi.URLParam(r, "revId")
 
-	if _, err := h.historyRepo.GetByID(systemID, revID); err != nil {
-		render.Status(r, http.StatusNotFound)
-		render.JSON(w, r, map[string]string{"error": "System history revision not found"})
+	if _, err := h.historyRepo.WithContext(r.Context()).GetByID(systemID, revID); err != nil {
+		renderRepositoryError(w, r, err, "System history revision not found", "Internal server error")
 		return
 	}
 
-	if err := h.historyRepo.Delete(systemID, revID); err != nil {
+	if err := h.historyRepo.WithContext(r.Context()).Delete(systemID, revID); err != nil {
 		h.logger.Error("Failed to delete system history revision", zap.String("revId", revID), zap.Error(err))
-		render.Status(r, http.StatusInternalServerError)
-		render.JSON(w, r, map[string]string{"error": "Failed to delete system history revision"})
+		renderRepositoryError(w, r, err, "System history not found", "Failed to delete system history revision")
 		return
 	}
 
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"github.com/yourusername/connected-systems-go/internal/model/formaters/geojson_formatters"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"go.uber.org/zap"
+)
+
+// ndjsonContentType is the media type of the line-delimited systems export,
+// selected with Accept: application/x-ndjson or ?f=ndjson.
+const ndjsonContentType = "application/x-ndjson"
+
+// systemNDJSONFlushEvery is how many lines are written between flushes of
+// the NDJSON export.
+const systemNDJSONFlushEvery = 100
+
+// writeSystemsNDJSON streams the systems matching params as newline-delimited
+// JSON: one GeoJSON Feature per line, as each row is scanned from the database,
+// with no enclosing FeatureCollection. Errors before the first line still get
+// a JSON 500; later ones can only be logged and end the response early.
+func (h *SystemHandler) writeSystemsNDJSON(w http.ResponseWriter, r *http.Request, params *queryparams.SystemQueryParams) {
+	out := bufio.NewWriter(w)
+	rc := http.NewResponseController(w)
+	started := false
+	written := 0
+	start := func() {
+		started = true
+		addVary(w.Header(), negotiatedVary...)
+		w.Header().Set("Content-Type", withUTF8Charset(ndjsonContentType))
+		w.WriteHeader(http.StatusOK)
+	}
+	flush := func() error {
+		if err := out.Flush(); err != nil {
+			return err
+		}
+		// Not every writer can flush; buffered output still reaches the client
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+
+	err := h.repo.WithContext(r.Context()).Each(params, func(system *domains.System) error {
+		system.Links = append(system.Links, h.repo.WithContext(r.Context()).BuildSystemAssociations(system.ID)...)
+		feature, err := h.fc.SerializeWithContext(r.Context(), geojson_formatters.GeoJSONContentType, system)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(feature)
+		if err != nil {
+			return err
+		}
+
+		if !started {
+			start()
+		}
+		if _, err := out.Write(encoded); err != nil {
+			return err
+		}
+		if err := out.WriteByte('\n'); err != nil {
+			return err
+		}
+		written++
+		if written%systemNDJSONFlushEvery == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		start()
+	}
+	if err != nil {
+		h.logger.Error("Failed to export systems as NDJSON", zap.Error(err))
+		if !started {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, map[string]string{"error": "Internal server error"})
+			return
+		}
+	}
+
+	if err := flush(); err != nil {
+		h.logger.Error("Failed to write systems NDJSON", zap.Error(err))
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+)
+
+// DefaultTenantHeader names the tenant of a request when tenancy is enabled
+// without a header configured.
+const DefaultTenantHeader = "X-Tenant-ID"
+
+// maxTenantIDLength matches the width of the tenant_id column.
+const maxTenantIDLength = 255
+
+// tenantExemptPaths serve no tenant data, so they answer without a tenant.
+var tenantExemptPaths = map[string]bool{
+	"/":            true,
+	"/conformance": true,
+}
+
+// requireTenant stores the tenant named by the header of each request in its
+// context, where repositories pick it up to scope their statements (see
+// repository.RegisterTenantScoping). Requests without the header are answered
+// with 400.
+func requireTenant(header string) func(http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultTenantHeader
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tenantExemptPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tenantID := r.Header.Get(header)
+			if tenantID == "" || len(tenantID) > maxTenantIDLength {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, map[string]string{"error": "A tenant of at most 255 characters must be given in the " + header + " header"})
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(repository.WithTenant(r.Context(), tenantID)))
+		})
+	}
+}
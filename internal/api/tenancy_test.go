@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+)
+
+func TestRequireTenant(t *testing.T) {
+	echoTenant := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID, _ := repository.TenantFromContext(r.Context())
+		w.Write([]byte(tenantID))
+	})
+
+	tests := map[string]struct {
+		header   string
+		path     string
+		headers  map[string]string
+		wantCode int
+		wantBody string
+	}{
+		"default header":      {path: "/systems", headers: map[string]string{"X-Tenant-ID": "team-a"}, wantCode: http.StatusOK, wantBody: "team-a"},
+		"configured header":   {header: "X-Org", path: "/systems", headers: map[string]string{"X-Org": "team-b"}, wantCode: http.StatusOK, wantBody: "team-b"},
+		"missing header":      {path: "/systems", wantCode: http.StatusBadRequest},
+		"other header":        {header: "X-Org", path: "/systems", headers: map[string]string{"X-Tenant-ID": "team-a"}, wantCode: http.StatusBadRequest},
+		"oversized tenant":    {path: "/systems", headers: map[string]string{"X-Tenant-ID": strings.Repeat("t", 256)}, wantCode: http.StatusBadRequest},
+		"landing page exempt": {path: "/", wantCode: http.StatusOK},
+		"conformance exempt":  {path: "/conformance", wantCode: http.StatusOK},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			requireTenant(tt.header)(echoTenant).ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestIdempotency_KeysAreScopedPerTenant(t *testing.T) {
+	created := 0
+	handler := requireTenant("")(NewIdempotencyStore(time.Hour, DefaultMaxRequestBodySize).Middleware("systems")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created++
+		w.WriteHeader(http.StatusCreated)
+	})))
+
+	post := func(tenantID string) {
+		req := httptest.NewRequest(http.MethodPost, "/systems", strings.NewReader(`{"name":"x"}`))
+		req.Header.Set("X-Tenant-ID", tenantID)
+		req.Header.Set(IdempotencyKeyHeader, "same-key")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	post("team-a")
+	post("team-a")
+	assert.Equal(t, 1, created, "a repeat within the tenant is replayed")
+	post("team-b")
+	assert.Equal(t, 2, created, "another tenant's key must not replay team A's response")
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// stripTrailingSlash serves /systems/ and /systems/{id}/ exactly like their
+// canonical forms without the trailing slash. Unlike chi's StripSlashes it
+// rewrites the request URL itself rather than only the routing path, so
+// self, pagination and Location links are always built from the canonical
+// path. The landing page "/" is left alone.
+func stripTrailingSlash(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path := r.URL.Path; len(path) > 1 && strings.HasSuffix(path, "/") {
+			r.URL.Path = strings.TrimRight(path, "/")
+			if r.URL.Path == "" {
+				r.URL.Path = "/"
+			}
+			if r.URL.RawPath != "" {
+				r.URL.RawPath = strings.TrimRight(r.URL.RawPath, "/")
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
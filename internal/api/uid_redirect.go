@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+	"go.uber.org/zap"
+)
+
+// uidURLParam returns the decoded {uid} path segment. URNs are usually sent with
+// their colons (and any slashes) percent-encoded, and chi hands the segment back
+// in its raw form when the request path carries escapes.
+func uidURLParam(r *http.Request) (string, error) {
+	return url.PathUnescape(chi.URLParam(r, "uid"))
+}
+
+// redirectByUID resolves the {uid} path segment with lookup and answers with a
+// 307 redirect to the canonical /{collection}/{id} resource.
+func redirectByUID(w http.ResponseWriter, r *http.Request, logger *zap.Logger, baseURL, collection, resourceName string, lookup func(uid string) (string, error)) {
+	uid, err := uidURLParam(r)
+	if err != nil || strings.TrimSpace(uid) == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, map[string]string{"error": "Invalid uid"})
+		return
+	}
+
+	id, err := lookup(uid)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			render.Status(r, http.StatusNotFound)
+			render.JSON(w, r, map[string]string{"error": resourceName + " not found"})
+			return
+		}
+		logger.Error("Failed to resolve uid", zap.String("collection", collection), zap.String("uid", uid), zap.Error(err))
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, map[string]string{"error": "Internal server error"})
+		return
+	}
+
+	redirectToCanonical(w, r, strings.TrimRight(baseURL, "/")+"/"+collection+"/"+url.PathEscape(id))
+}
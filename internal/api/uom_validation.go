@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// validateUOM checks the uom of a SWE Quantity or QuantityRange. The unit must
+// be an object carrying a UCUM code or an href to a unit definition. Codes are
+// only checked for UCUM syntax, so unknown but well-formed units are accepted.
+func validateUOM(raw json.RawMessage, path string, violations *SchemaViolations) {
+	path += ".uom"
+	if isJSONNull(raw) {
+		violations.add(path, "is required")
+		return
+	}
+
+	var uom struct {
+		Code *string `json:"code"`
+		Href *string `json:"href"`
+	}
+	if err := json.Unmarshal(raw, &uom); err != nil {
+		violations.add(path, "must be an object with a code or href")
+		return
+	}
+
+	hasCode := uom.Code != nil && *uom.Code != ""
+	hasHref := uom.Href != nil && strings.TrimSpace(*uom.Href) != ""
+	if !hasCode && !hasHref {
+		violations.add(path, "must have a non-empty code or href")
+		return
+	}
+	if hasCode {
+		if err := checkUCUMSyntax(*uom.Code); err != nil {
+			violations.add(path+".code", fmt.Sprintf("%q is not a valid UCUM code: %v", *uom.Code, err))
+		}
+	}
+}
+
+// checkUCUMSyntax parses code against the UCUM case-sensitive grammar:
+//
+//	term      = component { ("." | "/") component }
+//	component = "(" term ")" | annotation | factor | symbol [exponent] [annotation]
+//
+// Symbols are not looked up, so "m", "Cel", "[degF]" and "furlong" all pass,
+// while "m s", "m//s" or "(m" do not.
+func checkUCUMSyntax(code string) error {
+	p := &ucumParser{s: code}
+	// A leading "/" is allowed, e.g. "/s" for "per second".
+	if p.peek() == '/' {
+		p.pos++
+	}
+	if err := p.term(); err != nil {
+		return err
+	}
+	if p.pos != len(p.s) {
+		return fmt.Errorf("unexpected %q at position %d", p.s[p.pos], p.pos)
+	}
+	return nil
+}
+
+type ucumParser struct {
+	s   string
+	pos int
+}
+
+func (p *ucumParser) peek() byte {
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *ucumParser) term() error {
+	if err := p.component(); err != nil {
+		return err
+	}
+	for p.peek() == '.' || p.peek() == '/' {
+		p.pos++
+		if err := p.component(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *ucumParser) component() error {
+	switch c := p.peek(); {
+	case c == 0:
+		return fmt.Errorf("unexpected end of code")
+	case c == '(':
+		p.pos++
+		if err := p.term(); err != nil {
+			return err
+		}
+		if p.peek() != ')' {
+			return fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return nil
+	case c == '{':
+		return p.annotation()
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '[' {
+			end := strings.IndexByte(p.s[p.pos:], ']')
+			if end < 0 {
+				return fmt.Errorf("missing closing bracket")
+			}
+			p.pos += end + 1
+			continue
+		}
+		if !isUCUMSymbolChar(c) {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return fmt.Errorf("unexpected %q at position %d", p.s[p.pos], p.pos)
+	}
+	if p.peek() == '{' {
+		return p.annotation()
+	}
+	return nil
+}
+
+func (p *ucumParser) annotation() error {
+	end := strings.IndexByte(p.s[p.pos:], '}')
+	if end < 0 {
+		return fmt.Errorf("missing closing brace")
+	}
+	if strings.ContainsAny(p.s[p.pos+1:p.pos+end], "{") {
+		return fmt.Errorf("nested annotation")
+	}
+	p.pos += end + 1
+	return nil
+}
+
+// isUCUMSymbolChar reports whether c may appear in a unit symbol, factor or
+// exponent outside of brackets: printable ASCII other than the operators,
+// grouping characters and spaces.
+func isUCUMSymbolChar(c byte) bool {
+	if c <= ' ' || c > '~' {
+		return false
+	}
+	return !strings.ContainsRune(".()/{}[]", rune(c))
+}
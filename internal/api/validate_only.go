@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+)
+
+// ValidationReport is returned with 200 instead of creating anything when a
+// create request asks to be validated only.
+type ValidationReport struct {
+	Valid  bool             `json:"valid"`
+	Errors SchemaViolations `json:"errors"`
+}
+
+// validateOnlyRequested reports whether a create request asks for validation
+// without persisting, either with ?validate=true or with the preference
+// "Prefer: handling=strict; validate".
+func validateOnlyRequested(r *http.Request) bool {
+	if validate, err := strconv.ParseBool(r.URL.Query().Get("validate")); err == nil && validate {
+		return true
+	}
+
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			token, params, _ := strings.Cut(preference, ";")
+			name, value, ok := strings.Cut(strings.TrimSpace(token), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "handling") ||
+				!strings.EqualFold(strings.Trim(strings.TrimSpace(value), `"`), "strict") {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				if strings.EqualFold(strings.TrimSpace(param), "validate") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// reportValidation answers a validate-only create with a ValidationReport
+// built from err (nil meaning the payload is valid) and reports whether it
+// did. For any other request it writes nothing and returns false, so the
+// caller carries on with its usual error handling or persistence.
+func reportValidation(w http.ResponseWriter, r *http.Request, err error) bool {
+	if !validateOnlyRequested(r) {
+		return false
+	}
+
+	report := ValidationReport{Valid: err == nil, Errors: SchemaViolations{}}
+	if err != nil {
+		report.Errors = violationsOf(err)
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, report)
+	return true
+}
+
+// uniqueIDChecker is implemented by the repositories of resources that carry
+// a uniqueId.
+type uniqueIDChecker interface {
+	UniqueIDInUse(uid string) (bool, error)
+}
+
+// reportCreateValidation is reportValidation for creates that are stored under
+// a uniqueId. Besides err it reports a uid that another resource of the same
+// type already uses, which the real create is rejected for by the unique
+// index, so a validate-only request never calls valid what the create refuses.
+// A failed lookup is answered with a 500.
+func reportCreateValidation(w http.ResponseWriter, r *http.Request, err error, repo uniqueIDChecker, uid string) bool {
+	if !validateOnlyRequested(r) {
+		return false
+	}
+
+	var violations SchemaViolations
+	if err != nil {
+		violations = violationsOf(err)
+	}
+	inUse, lookupErr := repo.UniqueIDInUse(uid)
+	if lookupErr != nil {
+		renderRepositoryError(w, r, lookupErr, "Not found", "Failed to validate request")
+		return true
+	}
+	if inUse {
+		violations.add("uniqueId", (&repository.DuplicateUIDError{UID: uid}).Error())
+	}
+	return reportValidation(w, r, violations.errOrNil())
+}
+
+// violationsOf converts a validation error into the violations it reports.
+func violationsOf(err error) SchemaViolations {
+	var violations SchemaViolations
+	if errors.As(err, &violations) {
+		return violations
+	}
+	if violations = decodeViolations(err); violations != nil {
+		return violations
+	}
+	return SchemaViolations{{Message: err.Error()}}
+}
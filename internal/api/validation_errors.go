@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"github.com/yourusername/connected-systems-go/internal/repository"
+)
+
+// SchemaViolation describes a single payload location that failed validation.
+type SchemaViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// SchemaViolations collects every violation found while validating a payload so
+// clients can fix all bad fields in one round trip.
+type SchemaViolations []SchemaViolation
+
+func (v SchemaViolations) Error() string {
+	parts := make([]string, 0, len(v))
+	for _, violation := range v {
+		if violation.Path == "" {
+			parts = append(parts, violation.Message)
+			continue
+		}
+		parts = append(parts, violation.Path+" "+violation.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (v *SchemaViolations) add(path, message string) {
+	*v = append(*v, SchemaViolation{Path: path, Message: message})
+}
+
+// errOrNil returns v as an error, or nil when nothing was recorded.
+func (v SchemaViolations) errOrNil() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// prefixViolations returns the violations in err with prefix prepended to each path,
+// e.g. to locate failures within an array of payloads.
+func prefixViolations(err error, prefix string) SchemaViolations {
+	var violations SchemaViolations
+	if !errors.As(err, &violations) {
+		return SchemaViolations{{Path: prefix, Message: err.Error()}}
+	}
+
+	prefixed := make(SchemaViolations, 0, len(violations))
+	for _, violation := range violations {
+		path := prefix
+		if violation.Path != "" {
+			path += "." + violation.Path
+		}
+		prefixed = append(prefixed, SchemaViolation{Path: path, Message: violation.Message})
+	}
+	return prefixed
+}
+
+// ValidationProblem is the problem document returned when a payload fails validation.
+type ValidationProblem struct {
+	Type   string           `json:"type"`
+	Title  string           `json:"title"`
+	Status int              `json:"status"`
+	Detail string           `json:"detail,omitempty"`
+	Error  string           `json:"error"`
+	Errors SchemaViolations `json:"errors"`
+}
+
+// renderValidationProblem writes a 400 problem document listing every violation in err.
+// Errors that are not SchemaViolations are reported as a single pathless entry.
+func renderValidationProblem(w http.ResponseWriter, r *http.Request, title string, err error) {
+	renderProblem(w, r, http.StatusBadRequest, title, err)
+}
+
+// renderConflictProblem writes a 409 problem document for a request that
+// clashes with an existing resource, such as a duplicate uniqueId.
+func renderConflictProblem(w http.ResponseWriter, r *http.Request, title string, err error) {
+	renderProblem(w, r, http.StatusConflict, title, err)
+}
+
+func renderProblem(w http.ResponseWriter, r *http.Request, status int, title string, err error) {
+	var violations SchemaViolations
+	if !errors.As(err, &violations) {
+		violations = SchemaViolations{{Message: err.Error()}}
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, ValidationProblem{
+		Type:   "about:blank",
+		Title:  title,
+		Status: status,
+		Detail: violations.Error(),
+		Error:  title + ": " + violations.Error(),
+		Errors: violations,
+	})
+}
+
+// renderDuplicateUID writes a 409 problem naming the conflicting uniqueId when
+// err is a *repository.DuplicateUIDError, and reports whether it did.
+func renderDuplicateUID(w http.ResponseWriter, r *http.Request, err error) bool {
+	var duplicate *repository.DuplicateUIDError
+	if !errors.As(err, &duplicate) {
+		return false
+	}
+	renderConflictProblem(w, r, "Resource with this uniqueId already exists", SchemaViolations{{Path: "uniqueId", Message: duplicate.Error()}})
+	return true
+}
+
+// renderUnknownSampleOf writes a 400 problem naming the missing sampling
+// features when err is a *repository.UnknownSampleOfError, and reports
+// whether it did. External sampleOf links are never checked.
+func renderUnknownSampleOf(w http.ResponseWriter, r *http.Request, err error) bool {
+	var unknown *repository.UnknownSampleOfError
+	if !errors.As(err, &unknown) {
+		return false
+	}
+	renderValidationProblem(w, r, "Invalid sampleOf link", sampleOfViolations(unknown))
+	return true
+}
+
+// sampleOfViolations lists one violation per missing sampling feature.
+func sampleOfViolations(unknown *repository.UnknownSampleOfError) SchemaViolations {
+	violations := make(SchemaViolations, 0, len(unknown.IDs))
+	for _, id := range unknown.IDs {
+		violations.add("sampleOf", "references sampling feature "+strconv.Quote(id)+", which does not exist")
+	}
+	return violations
+}
+
+// renderQueryParamError writes a 400 problem document for query parameters
+// that cannot be parsed, locating the error at the parameter when err names it.
+func renderQueryParamError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalid *queryparams.InvalidParameterError
+	if errors.As(err, &invalid) {
+		err = SchemaViolations{{Path: invalid.Name, Message: invalid.Reason + ", got " + strconv.Quote(invalid.Value)}}
+	}
+	renderValidationProblem(w, r, "Invalid query parameter", err)
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/netip"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -11,6 +15,19 @@ type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
 	Database DatabaseConfig `mapstructure:"database"`
 	API      APIConfig      `mapstructure:"api"`
+	CORS     CORSConfig     `mapstructure:"cors"`
+	Log      LogConfig      `mapstructure:"log"`
+}
+
+// Validate reports settings that are unknown or could never take effect as
+// configured.
+func (c *Config) Validate() error {
+	switch c.API.PolygonRingOrientation {
+	case "", RingOrientationIgnore, RingOrientationReject, RingOrientationFix:
+	default:
+		return fmt.Errorf("invalid api.polygon_ring_orientation %q: must be one of ignore, reject or fix", c.API.PolygonRingOrientation)
+	}
+	return c.API.RateLimit.Validate()
 }
 
 // ServerConfig holds server configuration
@@ -26,14 +43,147 @@ type DatabaseConfig struct {
 	Name     string `mapstructure:"name"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
+	// AutoMigrate runs schema migrations at startup. When false the server
+	// refuses to start unless the database is already at the expected version.
+	AutoMigrate bool `mapstructure:"auto_migrate"`
+	// QueryTimeout cancels any single database statement that runs longer than
+	// this; zero disables the limit. Statements are also canceled when the
+	// client disconnects.
+	QueryTimeout time.Duration `mapstructure:"query_timeout"`
 }
 
 // APIConfig holds API-specific configuration
 type APIConfig struct {
-	BaseURL     string `mapstructure:"base_url"`
+	BaseURL string `mapstructure:"base_url"`
+	// BasePath mounts the API under a path prefix such as "/csapi", for
+	// serving behind a gateway. Routes and every generated link and Location
+	// include it; BaseURL must not.
+	BasePath    string `mapstructure:"base_path"`
 	Title       string `mapstructure:"title"`
 	Description string `mapstructure:"description"`
 	Version     string `mapstructure:"version"`
+	// EnforceObservationValidTime rejects observations whose phenomenon time falls
+	// outside their datastream's declared validTime.
+	EnforceObservationValidTime bool `mapstructure:"enforce_observation_valid_time"`
+	// IdempotencyKeyTTL is how long the response to a create request carrying an
+	// Idempotency-Key header is remembered for replay.
+	IdempotencyKeyTTL time.Duration `mapstructure:"idempotency_key_ttl"`
+	// MaxRequestBodySize is the largest request body accepted, in bytes; larger
+	// bodies are rejected with 413.
+	MaxRequestBodySize int64 `mapstructure:"max_request_body_size"`
+	// MaxBatchRequestBodySize replaces MaxRequestBodySize on batch create
+	// endpoints such as POST /datastreams/{id}/observations.
+	MaxBatchRequestBodySize int64 `mapstructure:"max_batch_request_body_size"`
+	// Cache sets the Cache-Control max-age of successful GET responses.
+	Cache CacheConfig `mapstructure:"cache"`
+	// PolygonRingOrientation is what happens to submitted polygons whose rings
+	// break the right-hand rule of RFC 7946 (exterior rings counterclockwise,
+	// holes clockwise): "ignore" stores them as sent, "reject" answers 400 and
+	// "fix" rewinds the rings before storing.
+	PolygonRingOrientation string `mapstructure:"polygon_ring_orientation"`
+	// RateLimit throttles requests per client.
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	// Tenancy isolates the data of several tenants sharing one instance.
+	Tenancy TenancyConfig `mapstructure:"tenancy"`
+}
+
+// Values of APIConfig.PolygonRingOrientation.
+const (
+	RingOrientationIgnore = "ignore"
+	RingOrientationReject = "reject"
+	RingOrientationFix    = "fix"
+)
+
+// CacheConfig maps resource types, named by their collection path segment
+// (e.g. "properties"), to the max-age of successful GET responses. Single
+// resources not listed must be revalidated with their ETag (no-cache);
+// collections not listed are not stored at all (no-store).
+type CacheConfig struct {
+	Items       map[string]time.Duration `mapstructure:"items"`
+	Collections map[string]time.Duration `mapstructure:"collections"`
+}
+
+// RateLimitConfig configures a token bucket per client: each client may make
+// Burst requests at once, refilled at Rate requests per second. Limiting is
+// off unless Enabled is set, which requires a positive Rate.
+//
+// Clients are identified by the address of the connection's peer. Requests
+// arriving from one of the TrustedProxies (IPs or CIDRs) are instead
+// identified by the client address the proxy forwarded, or by the KeyHeader
+// value (e.g. an API key) when it is set and present. Both are sent by the
+// client otherwise, so KeyHeader must be set or verified by the trusted proxy
+// and is only honoured from it.
+type RateLimitConfig struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	Rate           float64  `mapstructure:"rate"`
+	Burst          int      `mapstructure:"burst"`
+	KeyHeader      string   `mapstructure:"key_header"`
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// Validate reports a rate limit that is enabled but could never take effect
+// as configured.
+func (c RateLimitConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Rate <= 0 {
+		return fmt.Errorf("api.rate_limit.rate must be positive when rate limiting is enabled, got %v", c.Rate)
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, err := ParseTrustedProxy(proxy); err != nil {
+			return fmt.Errorf("api.rate_limit.trusted_proxies: %w", err)
+		}
+	}
+	if c.KeyHeader != "" && len(c.TrustedProxies) == 0 {
+		return errors.New("api.rate_limit.key_header is only honoured from api.rate_limit.trusted_proxies, which is empty")
+	}
+	return nil
+}
+
+// ParseTrustedProxy parses a trusted proxy given as an IP address or a CIDR
+// block.
+func ParseTrustedProxy(proxy string) (netip.Prefix, error) {
+	if prefix, err := netip.ParsePrefix(proxy); err == nil {
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%q is neither an IP address nor a CIDR block", proxy)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// TenancyConfig scopes every resource to the tenant named by the Header of
+// each request. Requests without the header are rejected, and a tenant can
+// neither see nor change another tenant's resources. Off unless Enabled is
+// set, in which case resources created before it was enabled belong to no
+// tenant and are no longer reachable through the API.
+type TenancyConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Header  string `mapstructure:"header"`
+}
+
+// CORSConfig holds cross-origin resource sharing configuration. With no allowed
+// origins every cross-origin request is denied, so enabling CORS is explicit.
+type CORSConfig struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"`
+	AllowedMethods   []string `mapstructure:"allowed_methods"`
+	AllowedHeaders   []string `mapstructure:"allowed_headers"`
+	ExposedHeaders   []string `mapstructure:"exposed_headers"`
+	MaxAge           int      `mapstructure:"max_age"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+}
+
+// LogConfig holds logging configuration
+type LogConfig struct {
+	// Level is the minimum level logged: debug, info, warn, error, dpanic,
+	// panic or fatal.
+	Level string `mapstructure:"level"`
+	// Encoding is "json" for structured production logs or "console" for
+	// human-readable output during development.
+	Encoding string `mapstructure:"encoding"`
 }
 
 // Load loads configuration from file and environment
@@ -51,9 +201,34 @@ func Load() (*Config, error) {
 	viper.SetDefault("database.password", "postgres")
 	viper.SetDefault("database.port", 5432)
 	viper.SetDefault("database.name", "connected_systems")
+	viper.SetDefault("database.auto_migrate", false)
+	viper.SetDefault("database.query_timeout", "30s")
+	viper.SetDefault("api.base_path", "")
 	viper.SetDefault("api.title", "OGC Connected Systems API")
 	viper.SetDefault("api.version", "1.0.0")
 	viper.SetDefault("api.description", "OGC API - Connected Systems - Part 1: Feature Resources")
+	viper.SetDefault("api.enforce_observation_valid_time", false)
+	viper.SetDefault("api.idempotency_key_ttl", "24h")
+	viper.SetDefault("api.max_request_body_size", 10<<20)
+	viper.SetDefault("api.max_batch_request_body_size", 100<<20)
+	viper.SetDefault("api.cache.items", map[string]string{"properties": "60s", "procedures": "60s"})
+	viper.SetDefault("api.cache.collections", map[string]string{})
+	viper.SetDefault("api.polygon_ring_orientation", "ignore")
+	viper.SetDefault("api.rate_limit.enabled", false)
+	viper.SetDefault("api.rate_limit.rate", 10)
+	viper.SetDefault("api.rate_limit.burst", 20)
+	viper.SetDefault("api.rate_limit.key_header", "")
+	viper.SetDefault("api.rate_limit.trusted_proxies", []string{})
+	viper.SetDefault("api.tenancy.enabled", false)
+	viper.SetDefault("api.tenancy.header", "X-Tenant-ID")
+	viper.SetDefault("cors.allowed_origins", []string{})
+	viper.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	viper.SetDefault("cors.allowed_headers", []string{"Accept", "Authorization", "Content-Type"})
+	viper.SetDefault("cors.exposed_headers", []string{"Link", "Location"})
+	viper.SetDefault("cors.max_age", 300)
+	viper.SetDefault("cors.allow_credentials", false)
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.encoding", "json")
 
 	// Read from environment — replace "." with "_" so database.host → DATABASE_HOST
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
@@ -70,6 +245,9 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, err
 	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		cfg     RateLimitConfig
+		wantErr bool
+	}{
+		"disabled":                     {cfg: RateLimitConfig{Rate: 0}},
+		"enabled":                      {cfg: RateLimitConfig{Enabled: true, Rate: 10}},
+		"zero rate":                    {cfg: RateLimitConfig{Enabled: true, Rate: 0}, wantErr: true},
+		"negative rate":                {cfg: RateLimitConfig{Enabled: true, Rate: -1}, wantErr: true},
+		"trusted proxies":              {cfg: RateLimitConfig{Enabled: true, Rate: 10, TrustedProxies: []string{"10.0.0.1", "192.168.0.0/16", "::1"}}},
+		"malformed proxy":              {cfg: RateLimitConfig{Enabled: true, Rate: 10, TrustedProxies: []string{"gateway"}}, wantErr: true},
+		"key header from proxy":        {cfg: RateLimitConfig{Enabled: true, Rate: 10, KeyHeader: "X-API-Key", TrustedProxies: []string{"10.0.0.1"}}},
+		"key header without any proxy": {cfg: RateLimitConfig{Enabled: true, Rate: 10, KeyHeader: "X-API-Key"}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_PolygonRingOrientation(t *testing.T) {
+	for _, value := range []string{"", RingOrientationIgnore, RingOrientationReject, RingOrientationFix} {
+		cfg := Config{API: APIConfig{PolygonRingOrientation: value}}
+		assert.NoError(t, cfg.Validate(), value)
+	}
+
+	cfg := Config{API: APIConfig{PolygonRingOrientation: "rewind"}}
+	assert.ErrorContains(t, cfg.Validate(), "api.polygon_ring_orientation")
+}
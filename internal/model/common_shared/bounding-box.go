@@ -1,8 +1,68 @@
 package common_shared
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type BoundingBox struct {
 	MinX float64
 	MinY float64
 	MaxX float64
 	MaxY float64
 }
+
+// ParseBoundingBox parses an OGC bbox parameter ("minx,miny,maxx,maxy" or the
+// 3D "minx,miny,minz,maxx,maxy,maxz" form, whose z values are ignored).
+// A minx greater than maxx is kept as-is: it denotes a box crossing the antimeridian.
+func ParseBoundingBox(value string) (*BoundingBox, error) {
+	parts := strings.Split(value, ",")
+	coords := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bbox coordinate %q", part)
+		}
+		coords = append(coords, v)
+	}
+
+	var bbox BoundingBox
+	switch len(coords) {
+	case 4:
+		bbox = BoundingBox{MinX: coords[0], MinY: coords[1], MaxX: coords[2], MaxY: coords[3]}
+	case 6:
+		bbox = BoundingBox{MinX: coords[0], MinY: coords[1], MaxX: coords[3], MaxY: coords[4]}
+	default:
+		return nil, fmt.Errorf("bbox must have 4 or 6 coordinates, got %d", len(coords))
+	}
+
+	if bbox.MinY > bbox.MaxY {
+		return nil, fmt.Errorf("bbox miny %v is greater than maxy %v", bbox.MinY, bbox.MaxY)
+	}
+
+	return &bbox, nil
+}
+
+// MarshalJSON writes the box in the OGC bbox order [minx, miny, maxx, maxy].
+func (b BoundingBox) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]float64{b.MinX, b.MinY, b.MaxX, b.MaxY})
+}
+
+// CrossesAntimeridian reports whether the box wraps across 180° longitude.
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.MinX > b.MaxX
+}
+
+// Split returns the boxes to intersect against: the box itself, or its eastern
+// (minx..180) and western (-180..maxx) halves when it crosses the antimeridian.
+func (b BoundingBox) Split() []BoundingBox {
+	if !b.CrossesAntimeridian() {
+		return []BoundingBox{b}
+	}
+	return []BoundingBox{
+		{MinX: b.MinX, MinY: b.MinY, MaxX: 180, MaxY: b.MaxY},
+		{MinX: -180, MinY: b.MinY, MaxX: b.MaxX, MaxY: b.MaxY},
+	}
+}
@@ -0,0 +1,55 @@
+package common_shared
+
+import "testing"
+
+func TestParseBoundingBox(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    BoundingBox
+		wantErr bool
+	}{
+		"2D":           {value: "-10,-5,10,5", want: BoundingBox{MinX: -10, MinY: -5, MaxX: 10, MaxY: 5}},
+		"3D drops z":   {value: "-10,-5,0,10,5,100", want: BoundingBox{MinX: -10, MinY: -5, MaxX: 10, MaxY: 5}},
+		"antimeridian": {value: "170,-10,-170,10", want: BoundingBox{MinX: 170, MinY: -10, MaxX: -170, MaxY: 10}},
+		"wrong arity":  {value: "1,2,3", wantErr: true},
+		"not a number": {value: "a,2,3,4", wantErr: true},
+		"inverted y":   {value: "0,10,5,-10", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseBoundingBox(tc.value)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tc.want {
+				t.Fatalf("expected %+v, got %+v", tc.want, *got)
+			}
+		})
+	}
+}
+
+func TestBoundingBoxSplit(t *testing.T) {
+	plain := BoundingBox{MinX: -10, MinY: -5, MaxX: 10, MaxY: 5}
+	if parts := plain.Split(); len(parts) != 1 || parts[0] != plain {
+		t.Fatalf("expected a non-crossing box to be returned unchanged, got %+v", parts)
+	}
+
+	crossing := BoundingBox{MinX: 170, MinY: -10, MaxX: -170, MaxY: 10}
+	parts := crossing.Split()
+	if len(parts) != 2 {
+		t.Fatalf("expected antimeridian box to split in two, got %+v", parts)
+	}
+	if parts[0] != (BoundingBox{MinX: 170, MinY: -10, MaxX: 180, MaxY: 10}) {
+		t.Fatalf("unexpected eastern half %+v", parts[0])
+	}
+	if parts[1] != (BoundingBox{MinX: -180, MinY: -10, MaxX: -170, MaxY: 10}) {
+		t.Fatalf("unexpected western half %+v", parts[1])
+	}
+}
@@ -224,6 +224,18 @@ func (c *ComponentWrapper) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON writes back the original payload when the wrapper was decoded
+// from JSON, so members without a dedicated field (e.g. "name",
+// "description", "constraint") survive a round trip. Wrappers built in code
+// are encoded from their generic fields.
+func (c ComponentWrapper) MarshalJSON() ([]byte, error) {
+	if len(c.Raw) > 0 {
+		return c.Raw, nil
+	}
+	type plain ComponentWrapper
+	return json.Marshal(plain(c))
+}
+
 // --- Concrete component variants ---
 
 // BooleanComponent implements a simple boolean component.
@@ -22,7 +22,7 @@ type GoGeom struct {
 
 // Value returns WKB bytes for storage in PostGIS; falls back to GeoJSON bytes on error
 func (gg GoGeom) Value() (driver.Value, error) {
-	if gg.T == nil {
+	if gg.IsEmpty() {
 		return nil, nil
 	}
 	// Prefer returning a PostGIS-friendly WKT string (with SRID if present).
@@ -66,20 +66,39 @@ func WKBHexToWKT(hexStr string) (string, error) {
 	return wktFromGeom(t), nil
 }
 
-// Scan accepts WKB bytes, WKT strings (optionally SRID-prefixed), or GeoJSON bytes
-// and sets the inner geom.T.
+// IsEmpty reports whether there is no geometry to encode: either none was
+// set or it has no coordinates (e.g. POINT EMPTY).
+func (gg GoGeom) IsEmpty() bool {
+	return gg.T == nil || gg.T.Empty()
+}
+
+// Scan accepts hex-encoded EWKB, as returned by PostGIS for geometry columns,
+// either as a string or as bytes, and sets the inner geom.T. A NULL or blank
+// column leaves the geometry unset.
 func (gg *GoGeom) Scan(value interface{}) error {
-	if value == nil {
-		gg.T = nil
+	gg.T = nil
+
+	var hexStr string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		hexStr = v
+	case []byte:
+		hexStr = string(v)
+	default:
+		return fmt.Errorf("unsupported geometry column type %T", value)
+	}
+	if strings.TrimSpace(hexStr) == "" {
 		return nil
 	}
 
-	testBytes, err := hex.DecodeString(value.(string))
-	testText, err := ewkb.Unmarshal(testBytes)
-
-	if err == nil {
+	testBytes, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil
+	}
+	if testText, err := ewkb.Unmarshal(testBytes); err == nil {
 		gg.T = testText
-
 	}
 	return nil
 }
@@ -97,10 +116,12 @@ func isHexString(s string) bool {
 	return true
 }
 
-// MarshalJSON encodes as GeoJSON using a JSON-friendly representation
+// MarshalJSON encodes as GeoJSON using a JSON-friendly representation.
+// A missing or empty geometry is encoded as null, which is how GeoJSON
+// represents an unlocated feature.
 func (gg GoGeom) MarshalJSON() ([]byte, error) {
-	if gg.T == nil {
-		return json.Marshal(nil)
+	if gg.IsEmpty() {
+		return []byte("null"), nil
 	}
 	out := fromGeomToGeoJSON(gg.T)
 	return json.Marshal(out)
@@ -112,6 +133,10 @@ func (gg *GoGeom) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
+	if raw == nil {
+		gg.T = nil
+		return nil
+	}
 	if tg, err := toGeomFromGeoJSON(raw); err == nil {
 		gg.T = tg
 		return nil
@@ -133,35 +158,8 @@ func toGeomFromGeoJSON(v interface{}) (geom.T, error) {
 	if raw, ok := v.(map[string]interface{}); ok {
 		if tval, _ := raw["type"].(string); tval != "" {
 			switch tval {
-			case "Point":
-				if coords, ok := ifaceToFloat64Slice(raw["coordinates"]); ok && len(coords) >= 2 {
-					return geom.NewPointFlat(geom.XY, coords[:2]), nil
-				}
-			case "LineString":
-				if coords, ok := ifaceTo2DFloat64Slice(raw["coordinates"]); ok {
-					return geom.NewLineStringFlat(geom.XY, flatten2D(coords)), nil
-				}
-			case "Polygon":
-				if rings, ok := ifaceTo3DFloat64Slice(raw["coordinates"]); ok {
-					return geom.NewPolygonFlat(geom.XY, flattenRings(rings), ringEnds(rings)), nil
-				}
-			case "MultiPoint":
-				if coords, ok := ifaceTo2DFloat64Slice(raw["coordinates"]); ok {
-					return geom.NewMultiPointFlat(geom.XY, flatten2D(coords)), nil
-				}
-			case "MultiLineString":
-				if lines, ok := ifaceTo3DFloat64Slice(raw["coordinates"]); ok {
-					return geom.NewMultiLineStringFlat(geom.XY, flattenRings(lines), ringEnds(lines)), nil
-				}
-			case "MultiPolygon":
-				if polys, ok := ifaceTo4DFloat64Slice(raw["coordinates"]); ok {
-					mp := geom.NewMultiPolygon(geom.XY)
-					for _, poly := range polys {
-						p := geom.NewPolygonFlat(geom.XY, flattenRings(poly), ringEnds(poly))
-						mp.Push(p)
-					}
-					return mp, nil
-				}
+			case "Point", "LineString", "Polygon", "MultiPoint", "MultiLineString", "MultiPolygon":
+				return geomFromCoordinates(tval, raw["coordinates"])
 			case "GeometryCollection":
 				geomsRaw, ok := raw["geometries"].([]interface{})
 				if !ok {
@@ -186,35 +184,8 @@ func toGeomFromGeoJSON(v interface{}) (geom.T, error) {
 	// handle the common types. Attempt to cast.
 	if g, ok := v.(*Geometry); ok {
 		switch g.Type {
-		case "Point":
-			if coords, ok := ifaceToFloat64Slice(g.Coordinates); ok && len(coords) >= 2 {
-				return geom.NewPointFlat(geom.XY, coords[:2]), nil
-			}
-		case "LineString":
-			if coords, ok := ifaceTo2DFloat64Slice(g.Coordinates); ok {
-				return geom.NewLineStringFlat(geom.XY, flatten2D(coords)), nil
-			}
-		case "Polygon":
-			if rings, ok := ifaceTo3DFloat64Slice(g.Coordinates); ok {
-				return geom.NewPolygonFlat(geom.XY, flattenRings(rings), ringEnds(rings)), nil
-			}
-		case "MultiPoint":
-			if coords, ok := ifaceTo2DFloat64Slice(g.Coordinates); ok {
-				return geom.NewMultiPointFlat(geom.XY, flatten2D(coords)), nil
-			}
-		case "MultiLineString":
-			if lines, ok := ifaceTo3DFloat64Slice(g.Coordinates); ok {
-				return geom.NewMultiLineStringFlat(geom.XY, flattenRings(lines), ringEnds(lines)), nil
-			}
-		case "MultiPolygon":
-			if polys, ok := ifaceTo4DFloat64Slice(g.Coordinates); ok {
-				mp := geom.NewMultiPolygon(geom.XY)
-				for _, poly := range polys {
-					p := geom.NewPolygonFlat(geom.XY, flattenRings(poly), ringEnds(poly))
-					mp.Push(p)
-				}
-				return mp, nil
-			}
+		case "Point", "LineString", "Polygon", "MultiPoint", "MultiLineString", "MultiPolygon":
+			return geomFromCoordinates(g.Type, g.Coordinates)
 		}
 		return nil, fmt.Errorf("unsupported or invalid geometry type: %s", g.Type)
 	}
@@ -222,6 +193,87 @@ func toGeomFromGeoJSON(v interface{}) (geom.T, error) {
 	return nil, fmt.Errorf("unsupported geojson value type: %T", v)
 }
 
+// geomFromCoordinates builds a simple geometry from GeoJSON coordinates. A
+// geometry whose first position carries an elevation is built with the XYZ
+// layout, and every other position must then have one as well; any
+// ordinates beyond the layout are ignored.
+func geomFromCoordinates(geometryType string, coordinates interface{}) (geom.T, error) {
+	invalid := fmt.Errorf("invalid coordinates for %s", geometryType)
+
+	switch geometryType {
+	case "Point":
+		coords, ok := ifaceToFloat64Slice(coordinates)
+		if !ok || len(coords) < 2 {
+			return nil, invalid
+		}
+		layout := positionLayout(coords)
+		return geom.NewPointFlat(layout, coords[:layout.Stride()]), nil
+	case "LineString", "MultiPoint":
+		coords, ok := ifaceTo2DFloat64Slice(coordinates)
+		if !ok {
+			return nil, invalid
+		}
+		layout := geom.XY
+		if len(coords) > 0 {
+			layout = positionLayout(coords[0])
+		}
+		flat, ok := flatten2D(coords, layout.Stride())
+		if !ok {
+			return nil, invalid
+		}
+		if geometryType == "MultiPoint" {
+			return geom.NewMultiPointFlat(layout, flat), nil
+		}
+		return geom.NewLineStringFlat(layout, flat), nil
+	case "Polygon", "MultiLineString":
+		rings, ok := ifaceTo3DFloat64Slice(coordinates)
+		if !ok {
+			return nil, invalid
+		}
+		layout := geom.XY
+		if len(rings) > 0 && len(rings[0]) > 0 {
+			layout = positionLayout(rings[0][0])
+		}
+		flat, ok := flattenRings(rings, layout.Stride())
+		if !ok {
+			return nil, invalid
+		}
+		if geometryType == "MultiLineString" {
+			return geom.NewMultiLineStringFlat(layout, flat, ringEnds(rings, layout.Stride())), nil
+		}
+		return geom.NewPolygonFlat(layout, flat, ringEnds(rings, layout.Stride())), nil
+	case "MultiPolygon":
+		polys, ok := ifaceTo4DFloat64Slice(coordinates)
+		if !ok {
+			return nil, invalid
+		}
+		layout := geom.XY
+		if len(polys) > 0 && len(polys[0]) > 0 && len(polys[0][0]) > 0 {
+			layout = positionLayout(polys[0][0][0])
+		}
+		mp := geom.NewMultiPolygon(layout)
+		for _, poly := range polys {
+			flat, ok := flattenRings(poly, layout.Stride())
+			if !ok {
+				return nil, invalid
+			}
+			if err := mp.Push(geom.NewPolygonFlat(layout, flat, ringEnds(poly, layout.Stride()))); err != nil {
+				return nil, err
+			}
+		}
+		return mp, nil
+	}
+	return nil, fmt.Errorf("unsupported geometry type: %s", geometryType)
+}
+
+// positionLayout returns XYZ for a position with an elevation and XY otherwise
+func positionLayout(position []float64) geom.Layout {
+	if len(position) >= 3 {
+		return geom.XYZ
+	}
+	return geom.XY
+}
+
 // fromGeomToGeoJSON returns a JSON-friendly representation (either *Geometry
 // for simple types or a map[string]interface{} for collections/complex types).
 func fromGeomToGeoJSON(t geom.T) interface{} {
@@ -232,15 +284,15 @@ func fromGeomToGeoJSON(t geom.T) interface{} {
 	case *geom.Point:
 		coords := tt.FlatCoords()
 		if len(coords) >= 2 {
-			return &Geometry{Type: "Point", Coordinates: []float64{coords[0], coords[1]}}
+			return &Geometry{Type: "Point", Coordinates: geoJSONPosition(coords, tt.Layout())}
 		}
 	case *geom.LineString:
 		coords := tt.FlatCoords()
-		return &Geometry{Type: "LineString", Coordinates: unflattenCoords(coords)}
+		return &Geometry{Type: "LineString", Coordinates: unflattenCoords(coords, tt.Layout())}
 	case *geom.Polygon:
 		coords := tt.FlatCoords()
 		ends := tt.Ends()
-		rings := unflattenRings(coords, ends)
+		rings := unflattenRings(coords, ends, tt.Layout())
 		// ensure rings are closed for GeoJSON output
 		for i, r := range rings {
 			rings[i] = closeRing(r)
@@ -248,18 +300,18 @@ func fromGeomToGeoJSON(t geom.T) interface{} {
 		return &Geometry{Type: "Polygon", Coordinates: rings}
 	case *geom.MultiPoint:
 		coords := tt.FlatCoords()
-		return &Geometry{Type: "MultiPoint", Coordinates: unflattenCoords(coords)}
+		return &Geometry{Type: "MultiPoint", Coordinates: unflattenCoords(coords, tt.Layout())}
 	case *geom.MultiLineString:
 		coords := tt.FlatCoords()
 		ends := tt.Ends()
-		return &Geometry{Type: "MultiLineString", Coordinates: unflattenLines(coords, ends)}
+		return &Geometry{Type: "MultiLineString", Coordinates: unflattenLines(coords, ends, tt.Layout())}
 	case *geom.MultiPolygon:
 		var polys [][][][]float64
 		for i := 0; i < tt.NumPolygons(); i++ {
 			p := tt.Polygon(i)
 			flat := p.FlatCoords()
 			ends := p.Ends()
-			rings := unflattenRings(flat, ends)
+			rings := unflattenRings(flat, ends, p.Layout())
 			for j, r := range rings {
 				rings[j] = closeRing(r)
 			}
@@ -370,62 +422,75 @@ func ifaceTo4DFloat64Slice(v interface{}) ([][][][]float64, bool) {
 	return nil, false
 }
 
-func flatten2D(coords [][]float64) []float64 {
+func flatten2D(coords [][]float64, stride int) ([]float64, bool) {
 	var out []float64
 	for _, c := range coords {
-		out = append(out, c[0], c[1])
+		if len(c) < stride {
+			return nil, false
+		}
+		out = append(out, c[:stride]...)
 	}
-	return out
+	return out, true
 }
 
-func flattenRings(rings [][][]float64) []float64 {
+func flattenRings(rings [][][]float64, stride int) ([]float64, bool) {
 	var out []float64
 	for _, ring := range rings {
-		for _, pt := range ring {
-			out = append(out, pt[0], pt[1])
+		flat, ok := flatten2D(ring, stride)
+		if !ok {
+			return nil, false
 		}
+		out = append(out, flat...)
 	}
-	return out
+	return out, true
 }
 
-func ringEnds(rings [][][]float64) []int {
+func ringEnds(rings [][][]float64, stride int) []int {
 	var ends []int
 	idx := 0
 	for _, ring := range rings {
-		// ends are indexes into the flat coordinate array (stride=2 for XY)
-		idx += len(ring) * 2
+		// ends are indexes into the flat coordinate array
+		idx += len(ring) * stride
 		ends = append(ends, idx)
 	}
 	return ends
 }
 
-func unflattenCoords(flat []float64) [][]float64 {
+// geoJSONPosition returns the X, Y and (for 3D layouts) Z ordinates of the
+// position starting at flat[0]; M ordinates have no GeoJSON representation.
+func geoJSONPosition(flat []float64, layout geom.Layout) []float64 {
+	if layout.ZIndex() >= 0 {
+		return []float64{flat[0], flat[1], flat[layout.ZIndex()]}
+	}
+	return []float64{flat[0], flat[1]}
+}
+
+func unflattenCoords(flat []float64, layout geom.Layout) [][]float64 {
 	var out [][]float64
-	for i := 0; i < len(flat); i += 2 {
-		out = append(out, []float64{flat[i], flat[i+1]})
+	stride := layout.Stride()
+	for i := 0; i+stride <= len(flat); i += stride {
+		out = append(out, geoJSONPosition(flat[i:i+stride], layout))
 	}
 	return out
 }
 
-func unflattenRings(flat []float64, ends []int) [][][]float64 {
+func unflattenRings(flat []float64, ends []int, layout geom.Layout) [][][]float64 {
 	var out [][][]float64
 	start := 0
 	for _, end := range ends {
-		var ring [][]float64
-		for i := start; i < end; i += 2 {
-			ring = append(ring, []float64{flat[i], flat[i+1]})
-		}
-		out = append(out, ring)
+		out = append(out, unflattenCoords(flat[start:end], layout))
 		start = end
 	}
 	return out
 }
 
-func unflattenLines(flat []float64, ends []int) [][][]float64 {
-	return unflattenRings(flat, ends)
+func unflattenLines(flat []float64, ends []int, layout geom.Layout) [][][]float64 {
+	return unflattenRings(flat, ends, layout)
 }
 
-// wktFromGeom returns a WKT representation for common geom.T types.
+// wktFromGeom returns a WKT representation for common geom.T types. 3D
+// geometries are written with three ordinates per position, which PostGIS
+// reads as a Z geometry.
 func wktFromGeom(t geom.T) string {
 	if t == nil {
 		return ""
@@ -434,68 +499,30 @@ func wktFromGeom(t geom.T) string {
 	case *geom.Point:
 		c := tt.FlatCoords()
 		if len(c) >= 2 {
-			return fmt.Sprintf("POINT(%f %f)", c[0], c[1])
+			return fmt.Sprintf("POINT(%s)", wktPosition(geoJSONPosition(c, tt.Layout())))
 		}
 	case *geom.LineString:
-		coords := unflattenCoords(tt.FlatCoords())
-		var pts []string
-		for _, p := range coords {
-			if len(p) >= 2 {
-				pts = append(pts, fmt.Sprintf("%f %f", p[0], p[1]))
-			}
-		}
-		return fmt.Sprintf("LINESTRING(%s)", joinWKT(pts))
+		return fmt.Sprintf("LINESTRING(%s)", wktPositions(unflattenCoords(tt.FlatCoords(), tt.Layout())))
 	case *geom.Polygon:
-		flat := tt.FlatCoords()
-		ends := tt.Ends()
-		rings := unflattenRings(flat, ends)
-		var ringStrs []string
-		for _, ring := range rings {
-			// ensure ring is closed (first == last) for valid WKT
-			closed := closeRing(ring)
-			var pts []string
-			for _, p := range closed {
-				if len(p) >= 2 {
-					pts = append(pts, fmt.Sprintf("%f %f", p[0], p[1]))
-				}
-			}
-			ringStrs = append(ringStrs, fmt.Sprintf("(%s)", joinWKT(pts)))
-		}
-		return fmt.Sprintf("POLYGON(%s)", joinWKT(ringStrs))
+		return fmt.Sprintf("POLYGON(%s)", wktRings(unflattenRings(tt.FlatCoords(), tt.Ends(), tt.Layout())))
 	case *geom.MultiPoint:
-		coords := unflattenCoords(tt.FlatCoords())
 		var pts []string
-		for _, p := range coords {
-			pts = append(pts, fmt.Sprintf("(%f %f)", p[0], p[1]))
+		for _, p := range unflattenCoords(tt.FlatCoords(), tt.Layout()) {
+			pts = append(pts, fmt.Sprintf("(%s)", wktPosition(p)))
 		}
 		return fmt.Sprintf("MULTIPOINT(%s)", joinWKT(pts))
 	case *geom.MultiLineString:
 		var lineStrs []string
 		for i := 0; i < tt.NumLineStrings(); i++ {
 			ls := tt.LineString(i)
-			coords := unflattenCoords(ls.FlatCoords())
-			var pts []string
-			for _, p := range coords {
-				pts = append(pts, fmt.Sprintf("%f %f", p[0], p[1]))
-			}
-			lineStrs = append(lineStrs, fmt.Sprintf("(%s)", joinWKT(pts)))
+			lineStrs = append(lineStrs, fmt.Sprintf("(%s)", wktPositions(unflattenCoords(ls.FlatCoords(), ls.Layout()))))
 		}
 		return fmt.Sprintf("MULTILINESTRING(%s)", joinWKT(lineStrs))
 	case *geom.MultiPolygon:
 		var polyStrs []string
 		for i := 0; i < tt.NumPolygons(); i++ {
 			p := tt.Polygon(i)
-			rings := unflattenRings(p.FlatCoords(), p.Ends())
-			var ringStrs []string
-			for _, ring := range rings {
-				closed := closeRing(ring)
-				var pts []string
-				for _, pt := range closed {
-					pts = append(pts, fmt.Sprintf("%f %f", pt[0], pt[1]))
-				}
-				ringStrs = append(ringStrs, fmt.Sprintf("(%s)", joinWKT(pts)))
-			}
-			polyStrs = append(polyStrs, fmt.Sprintf("(%s)", joinWKT(ringStrs)))
+			polyStrs = append(polyStrs, fmt.Sprintf("(%s)", wktRings(unflattenRings(p.FlatCoords(), p.Ends(), p.Layout()))))
 		}
 		return fmt.Sprintf("MULTIPOLYGON(%s)", joinWKT(polyStrs))
 	case *geom.GeometryCollection:
@@ -511,6 +538,30 @@ func wktFromGeom(t geom.T) string {
 	return ""
 }
 
+func wktPosition(p []float64) string {
+	if len(p) >= 3 {
+		return fmt.Sprintf("%f %f %f", p[0], p[1], p[2])
+	}
+	return fmt.Sprintf("%f %f", p[0], p[1])
+}
+
+func wktPositions(coords [][]float64) string {
+	var pts []string
+	for _, p := range coords {
+		pts = append(pts, wktPosition(p))
+	}
+	return joinWKT(pts)
+}
+
+// wktRings writes each ring closed (first == last) for valid WKT
+func wktRings(rings [][][]float64) string {
+	var ringStrs []string
+	for _, ring := range rings {
+		ringStrs = append(ringStrs, fmt.Sprintf("(%s)", wktPositions(closeRing(ring))))
+	}
+	return joinWKT(ringStrs)
+}
+
 // joinWKT reuses the helper defined earlier
 func joinWKT(parts []string) string {
 	if len(parts) == 0 {
@@ -0,0 +1,78 @@
+package common_shared
+
+import (
+	"encoding/json"
+	"testing"
+
+	geom "github.com/twpayne/go-geom"
+)
+
+func TestGoGeom_MarshalJSON_Empty(t *testing.T) {
+	tests := map[string]GoGeom{
+		"nil geometry":   {},
+		"empty point":    {T: geom.NewPointEmpty(geom.XY)},
+		"empty polygon":  {T: geom.NewPolygon(geom.XY)},
+		"empty multi":    {T: geom.NewMultiPoint(geom.XY)},
+		"empty grouping": {T: geom.NewGeometryCollection()},
+	}
+
+	for name, gg := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(gg)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+			if string(got) != "null" {
+				t.Fatalf("expected null, got %s", got)
+			}
+
+			value, err := gg.Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+			if value != nil {
+				t.Fatalf("expected a NULL column value, got %v", value)
+			}
+		})
+	}
+}
+
+func TestGoGeom_NullRoundTrip(t *testing.T) {
+	var feature struct {
+		Geometry GoGeom `json:"geometry"`
+	}
+	feature.Geometry.T = geom.NewPointFlat(geom.XY, []float64{1, 2})
+
+	if err := json.Unmarshal([]byte(`{"geometry":null}`), &feature); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if feature.Geometry.T != nil {
+		t.Fatalf("expected null to clear the geometry, got %v", feature.Geometry.T)
+	}
+
+	out, err := json.Marshal(feature)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(out) != `{"geometry":null}` {
+		t.Fatalf("unexpected encoding: %s", out)
+	}
+}
+
+func TestGoGeom_Scan_NoGeometry(t *testing.T) {
+	for name, value := range map[string]interface{}{
+		"NULL":        nil,
+		"blank":       "",
+		"blank bytes": []byte{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gg := GoGeom{T: geom.NewPointFlat(geom.XY, []float64{1, 2})}
+			if err := gg.Scan(value); err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+			if gg.T != nil {
+				t.Fatalf("expected no geometry, got %v", gg.T)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package common_shared
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	geom "github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/ewkbhex"
+)
+
+// DefaultSRID is the SRID assumed for geometries that carry none. GeoJSON
+// coordinates are always WGS 84 longitude/latitude (EPSG:4326).
+const DefaultSRID = 4326
+
+// EWKBHex returns the geometry as hex-encoded PostGIS Extended WKB, the same
+// text form PostGIS prints for a geometry column, so it can be loaded directly
+// with ST_GeomFromEWKB(decode(..., 'hex')) or a COPY into a geometry column.
+//
+// The output is little-endian (NDR). For the top-level geometry the bytes are:
+//
+//	byte    0     byte order, always 0x01 (little-endian)
+//	bytes   1-4   geometry type: the OGC type code (1 Point, 2 LineString,
+//	              3 Polygon, 4 MultiPoint, 5 MultiLineString, 6 MultiPolygon,
+//	              7 GeometryCollection) ORed with 0x80000000 when the geometry
+//	              has Z ordinates and 0x20000000 because an SRID follows
+//	bytes   5-8   SRID as uint32
+//	bytes   9-    the geometry body: for a Point its 2 or 3 float64 ordinates;
+//	              otherwise a uint32 count followed by the positions (or, for
+//	              rings, ring counts and positions), each ordinate a float64
+//
+// Members of multi-geometries and collections are complete EWKB geometries
+// themselves, with the Z flag but without an SRID. Z ordinates are kept; a
+// geometry without an SRID is written with DefaultSRID.
+func (gg GoGeom) EWKBHex() (string, error) {
+	if gg.T == nil {
+		return "", fmt.Errorf("geometry is empty")
+	}
+	g := gg.T
+	if g.SRID() == 0 {
+		var err error
+		if g, err = withSRID(g, DefaultSRID); err != nil {
+			return "", err
+		}
+	}
+	encoded, err := ewkbhex.Encode(g, binary.LittleEndian)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(encoded), nil
+}
+
+// withSRID returns a copy of g with its SRID set, leaving g untouched.
+func withSRID(g geom.T, srid int) (geom.T, error) {
+	switch tt := g.(type) {
+	case *geom.Point:
+		return tt.Clone().SetSRID(srid), nil
+	case *geom.LineString:
+		return tt.Clone().SetSRID(srid), nil
+	case *geom.Polygon:
+		return tt.Clone().SetSRID(srid), nil
+	case *geom.MultiPoint:
+		return tt.Clone().SetSRID(srid), nil
+	case *geom.MultiLineString:
+		return tt.Clone().SetSRID(srid), nil
+	case *geom.MultiPolygon:
+		return tt.Clone().SetSRID(srid), nil
+	case *geom.GeometryCollection:
+		gc := geom.NewGeometryCollection()
+		if err := gc.Push(tt.Geoms()...); err != nil {
+			return nil, err
+		}
+		return gc.SetSRID(srid), nil
+	}
+	return nil, fmt.Errorf("unsupported geometry type %T", g)
+}
@@ -0,0 +1,136 @@
+package common_shared
+
+import (
+	"encoding/json"
+	"testing"
+
+	geom "github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/ewkbhex"
+)
+
+func TestGoGeom_EWKBHex(t *testing.T) {
+	tests := map[string]struct {
+		geoJSON string
+		srid    int
+		want    string
+	}{
+		// 01 | 01000020 (Point + SRID flag) | E6100000 (4326) | x | y
+		"2D point gets the default SRID": {
+			geoJSON: `{"type":"Point","coordinates":[1,2]}`,
+			want:    "0101000020E6100000000000000000F03F0000000000000040",
+		},
+		// 01 | 010000A0 (Point + Z + SRID flags) | E6100000 | x | y | z
+		"3D point keeps Z": {
+			geoJSON: `{"type":"Point","coordinates":[1,2,3]}`,
+			want:    "01010000A0E6100000000000000000F03F00000000000000400000000000000840",
+		},
+		// 01 | 01000020 | 110F0000 (3857) | x | y
+		"existing SRID is kept": {
+			geoJSON: `{"type":"Point","coordinates":[1,2]}`,
+			srid:    3857,
+			want:    "0101000020110F0000000000000000F03F0000000000000040",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gg GoGeom
+			if err := json.Unmarshal([]byte(tt.geoJSON), &gg); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			if tt.srid != 0 {
+				gg.T.(*geom.Point).SetSRID(tt.srid)
+			}
+
+			got, err := gg.EWKBHex()
+			if err != nil {
+				t.Fatalf("EWKBHex failed: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGoGeom_EWKBHex_RoundTripsZ(t *testing.T) {
+	var gg GoGeom
+	payload := `{"type":"Polygon","coordinates":[[[0,0,10],[1,0,11],[1,1,12],[0,0,10]]]}`
+	if err := json.Unmarshal([]byte(payload), &gg); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	encoded, err := gg.EWKBHex()
+	if err != nil {
+		t.Fatalf("EWKBHex failed: %v", err)
+	}
+	decoded, err := ewkbhex.Decode(encoded)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if decoded.Layout() != geom.XYZ {
+		t.Fatalf("expected XYZ layout, got %v", decoded.Layout())
+	}
+	if decoded.SRID() != DefaultSRID {
+		t.Fatalf("expected SRID %d, got %d", DefaultSRID, decoded.SRID())
+	}
+	if gg.T.SRID() != 0 {
+		t.Fatalf("expected the original geometry to be left unchanged")
+	}
+}
+
+func TestGoGeom_ZCoordinates(t *testing.T) {
+	tests := map[string]struct {
+		geoJSON string
+		wantWKT string
+	}{
+		"2D point": {
+			geoJSON: `{"type":"Point","coordinates":[1.5,2.5]}`,
+			wantWKT: "POINT(1.500000 2.500000)",
+		},
+		"3D point": {
+			geoJSON: `{"type":"Point","coordinates":[1.5,2.5,30]}`,
+			wantWKT: "POINT(1.500000 2.500000 30.000000)",
+		},
+		"3D linestring": {
+			geoJSON: `{"type":"LineString","coordinates":[[0,0,1],[1,1,2]]}`,
+			wantWKT: "LINESTRING(0.000000 0.000000 1.000000, 1.000000 1.000000 2.000000)",
+		},
+		"3D multipoint": {
+			geoJSON: `{"type":"MultiPoint","coordinates":[[0,0,1],[1,1,2]]}`,
+			wantWKT: "MULTIPOINT((0.000000 0.000000 1.000000), (1.000000 1.000000 2.000000))",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gg GoGeom
+			if err := json.Unmarshal([]byte(tt.geoJSON), &gg); err != nil {
+				t.Fatalf("unmarshal failed: %v", err)
+			}
+			if got := wktFromGeom(gg.T); got != tt.wantWKT {
+				t.Fatalf("expected WKT %q, got %q", tt.wantWKT, got)
+			}
+
+			out, err := json.Marshal(gg)
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+			var want, got interface{}
+			json.Unmarshal([]byte(tt.geoJSON), &want) //nolint:errcheck
+			json.Unmarshal(out, &got)                 //nolint:errcheck
+			wantJSON, _ := json.Marshal(want)
+			gotJSON, _ := json.Marshal(got)
+			if string(wantJSON) != string(gotJSON) {
+				t.Fatalf("expected GeoJSON %s, got %s", wantJSON, gotJSON)
+			}
+		})
+	}
+}
+
+func TestGoGeom_MixedDimensionsRejected(t *testing.T) {
+	var gg GoGeom
+	if err := json.Unmarshal([]byte(`{"type":"LineString","coordinates":[[0,0,1],[1,1]]}`), &gg); err == nil {
+		t.Fatalf("expected a 3D linestring with a 2D position to be rejected")
+	}
+}
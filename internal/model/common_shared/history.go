@@ -30,7 +30,7 @@ func (ht *HistoryTime) UnmarshalJSON(b []byte) error {
 			return nil
 		}
 		// try parse as RFC3339 instant
-		if t, err := time.Parse(time.RFC3339, s); err == nil {
+		if t, err := parseTime(s); err == nil {
 			ht.Instant = &t
 			ht.Range = nil
 			return nil
@@ -53,11 +53,11 @@ func (ht *HistoryTime) UnmarshalJSON(b []byte) error {
 	return fmt.Errorf("unsupported history time JSON")
 }
 
-// MarshalJSON emits either an RFC3339 instant string or the TimeRange JSON
+// MarshalJSON emits either an RFC3339 instant string in UTC or the TimeRange JSON
 // representation.
 func (ht HistoryTime) MarshalJSON() ([]byte, error) {
 	if ht.Instant != nil {
-		return json.Marshal(ht.Instant.Format(time.RFC3339))
+		return json.Marshal(formatTime(*ht.Instant))
 	}
 	if ht.Range != nil {
 		return json.Marshal(ht.Range)
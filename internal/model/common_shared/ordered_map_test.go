@@ -0,0 +1,47 @@
+package common_shared
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrderedMap_MarshalKeepsInsertionOrder(t *testing.T) {
+	m := NewOrderedMap()
+	m.Set("uid", "urn:x")
+	m.Set("name", "X")
+	m.Set("description", "d")
+	m.Set("featureType", "t")
+	m.Set("name", "Y") // overwriting keeps the original position
+
+	got, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	want := `{"uid":"urn:x","name":"Y","description":"d","featureType":"t"}`
+	if string(got) != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestOrderedMap_UnmarshalPreservesDocumentOrder(t *testing.T) {
+	input := `{"type":"Feature","id":"1","properties":{"z":1,"a":2},"links":[]}`
+
+	m := NewOrderedMap()
+	if err := json.Unmarshal([]byte(input), m); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	m.Set("extra", true)
+
+	got, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	want := `{"type":"Feature","id":"1","properties":{"z":1,"a":2},"links":[],"extra":true}`
+	if string(got) != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	if err := json.Unmarshal([]byte(`[1,2]`), NewOrderedMap()); err == nil {
+		t.Fatalf("expected an error for a non-object")
+	}
+}
@@ -0,0 +1,115 @@
+package common_shared
+
+import (
+	"fmt"
+
+	geom "github.com/twpayne/go-geom"
+)
+
+// MisorientedRings returns the path of every polygon ring in the geometry that
+// does not follow the right-hand rule of RFC 7946: exterior rings must be
+// counterclockwise and holes clockwise. Paths are relative to path, e.g.
+// "geometry.coordinates[0]" for the exterior ring of a Polygon or
+// "geometry.coordinates[1][2]" for the second hole of a MultiPolygon's second
+// polygon. Rings without area have no orientation and are never reported.
+func (gg *GoGeom) MisorientedRings(path string) []string {
+	if gg == nil || gg.IsEmpty() {
+		return nil
+	}
+	_, misoriented := orientRings(gg.T, path, false)
+	return misoriented
+}
+
+// OrientRings rewinds every ring reported by MisorientedRings so that the
+// geometry follows the right-hand rule, keeping its layout and SRID.
+func (gg *GoGeom) OrientRings() {
+	if gg == nil || gg.IsEmpty() {
+		return
+	}
+	gg.T, _ = orientRings(gg.T, "", true)
+}
+
+// orientRings finds the misoriented rings of t and, when fix is set, returns
+// a copy of t with them reversed; otherwise t is returned unchanged.
+func orientRings(t geom.T, path string, fix bool) (geom.T, []string) {
+	switch g := t.(type) {
+	case *geom.Polygon:
+		coords, misoriented := orientFlatRings(g.FlatCoords(), g.Stride(), [][]int{g.Ends()}, path+".coordinates", false)
+		if fix && len(misoriented) > 0 {
+			return geom.NewPolygonFlat(g.Layout(), coords, g.Ends()).SetSRID(g.SRID()), misoriented
+		}
+		return t, misoriented
+	case *geom.MultiPolygon:
+		coords, misoriented := orientFlatRings(g.FlatCoords(), g.Stride(), g.Endss(), path+".coordinates", true)
+		if fix && len(misoriented) > 0 {
+			return geom.NewMultiPolygonFlat(g.Layout(), coords, g.Endss()).SetSRID(g.SRID()), misoriented
+		}
+		return t, misoriented
+	case *geom.GeometryCollection:
+		var misoriented []string
+		geoms := make([]geom.T, 0, g.NumGeoms())
+		for i, child := range g.Geoms() {
+			oriented, childMisoriented := orientRings(child, fmt.Sprintf("%s.geometries[%d]", path, i), fix)
+			geoms = append(geoms, oriented)
+			misoriented = append(misoriented, childMisoriented...)
+		}
+		if fix && len(misoriented) > 0 {
+			collection := geom.NewGeometryCollection()
+			if err := collection.Push(geoms...); err != nil {
+				return t, misoriented
+			}
+			return collection.SetSRID(g.SRID()), misoriented
+		}
+		return t, misoriented
+	}
+	return t, nil
+}
+
+// orientFlatRings checks the rings of one or more polygons stored as flat
+// coordinates, where endss holds the ring ends of each polygon. It returns a
+// copy of the coordinates with every misoriented ring reversed, and the paths
+// of those rings; multi adds the polygon index to each path.
+func orientFlatRings(flatCoords []float64, stride int, endss [][]int, path string, multi bool) ([]float64, []string) {
+	coords := append([]float64(nil), flatCoords...)
+	var misoriented []string
+
+	start := 0
+	for p, ends := range endss {
+		for i, end := range ends {
+			ring := coords[start:end]
+			start = end
+
+			area := signedRingArea(ring, stride)
+			if area == 0 || (area > 0) == (i == 0) {
+				continue
+			}
+			if multi {
+				misoriented = append(misoriented, fmt.Sprintf("%s[%d][%d]", path, p, i))
+			} else {
+				misoriented = append(misoriented, fmt.Sprintf("%s[%d]", path, i))
+			}
+			reverseRing(ring, stride)
+		}
+	}
+	return coords, misoriented
+}
+
+// signedRingArea is twice the planar area enclosed by ring (shoelace
+// formula): positive when the ring is counterclockwise, negative when it is
+// clockwise.
+func signedRingArea(ring []float64, stride int) float64 {
+	area := 0.0
+	for i := 0; i+stride < len(ring); i += stride {
+		area += ring[i]*ring[i+stride+1] - ring[i+stride]*ring[i+1]
+	}
+	return area
+}
+
+// reverseRing reverses the order of the points of ring in place.
+func reverseRing(ring []float64, stride int) {
+	for i, j := 0, len(ring)-stride; i < j; i, j = i+stride, j-stride {
+		for k := 0; k < stride; k++ {
+			ring[i+k], ring[j+k] = ring[j+k], ring[i+k]
+		}
+	}
+}
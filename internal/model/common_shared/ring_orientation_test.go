@@ -0,0 +1,88 @@
+package common_shared
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	geom "github.com/twpayne/go-geom"
+)
+
+func unmarshalGoGeom(t *testing.T, geoJSON string) *GoGeom {
+	t.Helper()
+	var gg GoGeom
+	require.NoError(t, json.Unmarshal([]byte(geoJSON), &gg))
+	return &gg
+}
+
+func TestGoGeom_MisorientedRings(t *testing.T) {
+	tests := map[string]struct {
+		geometry string
+		want     []string
+	}{
+		"counterclockwise polygon": {
+			geometry: `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]]]}`,
+		},
+		"clockwise polygon": {
+			geometry: `{"type":"Polygon","coordinates":[[[0,0],[0,4],[4,4],[4,0],[0,0]]]}`,
+			want:     []string{"geometry.coordinates[0]"},
+		},
+		"clockwise hole": {
+			geometry: `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[1,2],[2,2],[2,1],[1,1]]]}`,
+		},
+		"counterclockwise hole": {
+			geometry: `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]],[[1,1],[2,1],[2,2],[1,2],[1,1]]]}`,
+			want:     []string{"geometry.coordinates[1]"},
+		},
+		"multipolygon": {
+			geometry: `{"type":"MultiPolygon","coordinates":[` +
+				`[[[0,0],[4,0],[4,4],[0,4],[0,0]]],` +
+				`[[[10,10],[10,14],[14,14],[14,10],[10,10]],[[11,11],[12,11],[12,12],[11,12],[11,11]]]]}`,
+			want: []string{"geometry.coordinates[1][0]", "geometry.coordinates[1][1]"},
+		},
+		"geometry collection": {
+			geometry: `{"type":"GeometryCollection","geometries":[` +
+				`{"type":"Point","coordinates":[1,1]},` +
+				`{"type":"Polygon","coordinates":[[[0,0],[0,4],[4,4],[4,0],[0,0]]]}]}`,
+			want: []string{"geometry.geometries[1].coordinates[0]"},
+		},
+		"point": {
+			geometry: `{"type":"Point","coordinates":[1,1]}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gg := unmarshalGoGeom(t, tt.geometry)
+			assert.Equal(t, tt.want, gg.MisorientedRings("geometry"))
+		})
+	}
+}
+
+func TestGoGeom_OrientRings(t *testing.T) {
+	gg := unmarshalGoGeom(t, `{"type":"Polygon","coordinates":[`+
+		`[[0,0,5],[0,4,5],[4,4,5],[4,0,5],[0,0,5]],`+
+		`[[1,1,5],[2,1,5],[2,2,5],[1,2,5],[1,1,5]]]}`)
+	gg.T.(*geom.Polygon).SetSRID(4326)
+	require.Len(t, gg.MisorientedRings("geometry"), 2)
+
+	gg.OrientRings()
+	assert.Empty(t, gg.MisorientedRings("geometry"))
+
+	polygon, ok := gg.T.(*geom.Polygon)
+	require.True(t, ok)
+	assert.Equal(t, geom.XYZ, polygon.Layout())
+	assert.Equal(t, 4326, polygon.SRID())
+	assert.Equal(t, [][]geom.Coord{
+		{{0, 0, 5}, {4, 0, 5}, {4, 4, 5}, {0, 4, 5}, {0, 0, 5}},
+		{{1, 1, 5}, {1, 2, 5}, {2, 2, 5}, {2, 1, 5}, {1, 1, 5}},
+	}, polygon.Coords())
+}
+
+func TestGoGeom_OrientRings_LeavesCorrectGeometryAlone(t *testing.T) {
+	gg := unmarshalGoGeom(t, `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,4],[0,0]]]}`)
+	before := gg.T
+
+	gg.OrientRings()
+	assert.Same(t, before, gg.T)
+}
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 )
 
+// Term is a SensorML identifier/classifier entry. Only label and value are
+// required by the schema; definition and codeSpace must be valid URIs when present.
 type Term struct {
-	Definition string `json:"definition"`
+	Definition string `json:"definition,omitempty"`
 	Label      string `json:"label"`
-	CodeSpace  string `json:"codeSpace"`
+	CodeSpace  string `json:"codeSpace,omitempty"`
 	Value      string `json:"value"`
 }
 
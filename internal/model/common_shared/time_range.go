@@ -16,31 +16,42 @@ type TimeRange struct {
 	End   *time.Time `json:"end,omitempty"`
 }
 
-// MarshalJSON serializes TimeRange as a JSON array [start, end].
-// Each element is an RFC3339 string or null when missing.
+// openTimeBound is the time period member standing for an unbounded side.
+const openTimeBound = ".."
+
+// parseTime parses an RFC3339 timestamp and returns it in UTC, so the offset a
+// client wrote a time with does not outlive ingest.
+func parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
+
+// formatTime formats t as an RFC3339 timestamp in UTC, with a trailing "Z"
+// whatever location t was read in.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// MarshalJSON serializes TimeRange as the JSON array [start, end] of a time
+// period. Each element is an RFC3339 string in UTC, or ".." when that side is
+// unbounded, so the array always has exactly two elements.
 func (tr TimeRange) MarshalJSON() ([]byte, error) {
-	var s interface{}
-	var e interface{}
+	s, e := openTimeBound, openTimeBound
 	if tr.Start != nil {
-		s = tr.Start.Format(time.RFC3339)
-	} else {
-		s = nil
+		s = formatTime(*tr.Start)
 	}
 	if tr.End != nil {
-		e = tr.End.Format(time.RFC3339)
-	} else {
-		e = nil
-	}
-
-	if e == nil {
-		return json.Marshal([]interface{}{s})
+		e = formatTime(*tr.End)
 	}
 
-	return json.Marshal([]interface{}{s, e})
+	return json.Marshal([]string{s, e})
 }
 
 // UnmarshalJSON supports multiple input shapes for backwards compatibility:
-// - JSON array: [start, end] where elements are RFC3339 strings or null
+// - JSON array: [start, end] where elements are RFC3339 strings, ".." or null
 // - JSON object: {"start":"...","end":"..."}
 // - JSON string: "start/end" (existing ToTimeRange string format)
 func (tr *TimeRange) UnmarshalJSON(b []byte) error {
@@ -55,14 +66,14 @@ func (tr *TimeRange) UnmarshalJSON(b []byte) error {
 	if err := json.Unmarshal(b, &arr); err == nil {
 		if len(arr) > 0 && arr[0] != nil {
 			if s, ok := arr[0].(string); ok {
-				if t, err := time.Parse(time.RFC3339, s); err == nil {
+				if t, err := parseTime(s); err == nil {
 					tr.Start = &t
 				}
 			}
 		}
 		if len(arr) > 1 && arr[1] != nil {
 			if s, ok := arr[1].(string); ok {
-				if t, err := time.Parse(time.RFC3339, s); err == nil {
+				if t, err := parseTime(s); err == nil {
 					tr.End = &t
 				}
 			}
@@ -77,12 +88,12 @@ func (tr *TimeRange) UnmarshalJSON(b []byte) error {
 	}
 	if err := json.Unmarshal(b, &obj); err == nil {
 		if obj.Start != nil && *obj.Start != "" {
-			if t, err := time.Parse(time.RFC3339, *obj.Start); err == nil {
+			if t, err := parseTime(*obj.Start); err == nil {
 				tr.Start = &t
 			}
 		}
 		if obj.End != nil && *obj.End != "" {
-			if t, err := time.Parse(time.RFC3339, *obj.End); err == nil {
+			if t, err := parseTime(*obj.End); err == nil {
 				tr.End = &t
 			}
 		}
@@ -113,12 +124,12 @@ func ToTimeRange(timeValue string) TimeRange {
 		var startTime, endTime *time.Time
 
 		if parts[0] != "" && parts[0] != ".." {
-			t, _ := time.Parse(time.RFC3339, parts[0])
+			t, _ := parseTime(parts[0])
 			startTime = &t
 		}
 
 		if parts[1] != "" && parts[1] != ".." {
-			t, _ := time.Parse(time.RFC3339, parts[1])
+			t, _ := parseTime(parts[1])
 			endTime = &t
 		}
 
@@ -140,14 +151,14 @@ func ToTimeRangeFromSlice(parts []string) TimeRange {
 	var startTime, endTime *time.Time
 
 	if parts[0] != "" && parts[0] != ".." {
-		if t, err := time.Parse(time.RFC3339, parts[0]); err == nil {
+		if t, err := parseTime(parts[0]); err == nil {
 			startTime = &t
 		}
 	}
 
 	if len(parts) > 1 {
 		if parts[1] != "" && parts[1] != ".." {
-			if t, err := time.Parse(time.RFC3339, parts[1]); err == nil {
+			if t, err := parseTime(parts[1]); err == nil {
 				endTime = &t
 			}
 		}
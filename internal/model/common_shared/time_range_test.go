@@ -0,0 +1,86 @@
+package common_shared
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeRange_MarshalJSON(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	tests := map[string]struct {
+		tr   TimeRange
+		want string
+	}{
+		"closed":     {tr: TimeRange{Start: &start, End: &end}, want: `["2024-01-01T00:00:00Z","2024-12-31T23:59:59Z"]`},
+		"open end":   {tr: TimeRange{Start: &start}, want: `["2024-01-01T00:00:00Z",".."]`},
+		"open start": {tr: TimeRange{End: &end}, want: `["..","2024-12-31T23:59:59Z"]`},
+		"unbounded":  {tr: TimeRange{}, want: `["..",".."]`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := json.Marshal(tt.tr)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(encoded))
+
+			var decoded TimeRange
+			require.NoError(t, json.Unmarshal(encoded, &decoded))
+			assert.Equal(t, tt.tr.Start, decoded.Start)
+			assert.Equal(t, tt.tr.End, decoded.End)
+		})
+	}
+}
+
+func TestTimeRange_MarshalJSON_Pointer(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	doc := struct {
+		ValidTime *TimeRange `json:"validTime,omitempty"`
+	}{ValidTime: &TimeRange{Start: &start}}
+
+	encoded, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"validTime":["2024-01-01T00:00:00Z",".."]}`, string(encoded))
+}
+
+func TestTimeRange_UnmarshalJSON_LegacyShapes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]string{
+		"single element": `["2024-01-01T00:00:00Z"]`,
+		"null end":       `["2024-01-01T00:00:00Z",null]`,
+		"object":         `{"start":"2024-01-01T00:00:00Z"}`,
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tr TimeRange
+			require.NoError(t, json.Unmarshal([]byte(input), &tr))
+			require.NotNil(t, tr.Start)
+			assert.True(t, start.Equal(*tr.Start))
+			assert.Nil(t, tr.End)
+		})
+	}
+}
+
+func TestTimeRange_NormalizesToUTC(t *testing.T) {
+	var tr TimeRange
+	require.NoError(t, json.Unmarshal([]byte(`["2025-11-01T10:00:00+02:00","2025-11-30T18:30:00-05:00"]`), &tr))
+	require.NotNil(t, tr.Start)
+	require.NotNil(t, tr.End)
+	assert.Equal(t, time.UTC, tr.Start.Location())
+	assert.Equal(t, time.UTC, tr.End.Location())
+
+	encoded, err := json.Marshal(tr)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["2025-11-01T08:00:00Z","2025-11-30T23:30:00Z"]`, string(encoded))
+
+	// Times read back in another zone are still written in UTC
+	local := time.Date(2025, 11, 1, 10, 0, 0, 0, time.FixedZone("+02:00", 2*60*60))
+	encoded, err = json.Marshal(HistoryTime{Instant: &local})
+	require.NoError(t, err)
+	assert.JSONEq(t, `"2025-11-01T08:00:00Z"`, string(encoded))
+}
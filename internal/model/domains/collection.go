@@ -13,6 +13,7 @@ type Collection struct {
 	Extent      *common_shared.Extent `json:"extent,omitempty" gorm:"type:json"`
 	ItemType    string                `json:"itemType,omitempty" gorm:"default:feature"`
 	CRS         []string              `json:"crs,omitempty" gorm:"type:json"`
+	TenantID    *string               `json:"-" gorm:"type:varchar(255);index"`
 }
 
 type CollectionGeoJSONFeature struct {
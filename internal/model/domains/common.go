@@ -12,13 +12,20 @@ type Base struct {
 	ID        string    `gorm:"primaryKey;type:varchar(255)" json:"id"`
 	CreatedAt time.Time `json:"-"`
 	UpdatedAt time.Time `json:"-"`
+	// TenantID scopes the resource to one tenant when multi-tenancy is
+	// enabled; it is nil in single-tenant deployments.
+	TenantID *string `gorm:"type:varchar(255);index" json:"-"`
 
 	// DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type CommonSSN struct {
 	// Core properties (from SOSA/SSN)
-	UniqueIdentifier UniqueID `gorm:"type:varchar(255);uniqueIndex" json:"uid"`
+	//
+	// UniqueIdentifier is unique per tenant: the idx_<table>_tenant_uid index
+	// pairs it with the tenant, which is empty rather than NULL for it so
+	// untenanted rows still collide with each other.
+	UniqueIdentifier UniqueID `gorm:"type:varchar(255);uniqueIndex:,composite:tenant_uid,priority:1;uniqueIndex:,composite:tenant_uid,expression:COALESCE(tenant_id\\, ''),priority:2" json:"uid"`
 	Name             string   `gorm:"type:varchar(255);not null" json:"name"`
 	Description      string   `gorm:"type:text" json:"description,omitempty"`
 }
@@ -1,6 +1,7 @@
 package domains
 
 import (
+	"sort"
 	"time"
 
 	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
@@ -36,32 +37,41 @@ func (Feature) TableName() string {
 
 // FeatureGeoJSONFeature converts Feature to GeoJSON Feature format
 type FeatureGeoJSONFeature struct {
-	Type       string                 `json:"type"`
-	ID         string                 `json:"id"`
-	Geometry   *common_shared.GoGeom  `json:"geometry"`
-	Properties map[string]interface{} `json:"properties"`
-	Links      common_shared.Links    `json:"links,omitempty"`
+	Type       string                    `json:"type"`
+	ID         string                    `json:"id"`
+	Geometry   *common_shared.GoGeom     `json:"geometry"`
+	Properties *common_shared.OrderedMap `json:"properties"`
+	Links      common_shared.Links       `json:"links,omitempty"`
 }
 
-// ToGeoJSON converts Feature domain to GeoJSON Feature
+// ToGeoJSON converts Feature domain to GeoJSON Feature.
+// Known properties come first in a fixed order, followed by the additional
+// properties sorted by name, so the output is stable for the same feature.
 func (f Feature) ToGeoJSON() FeatureGeoJSONFeature {
-	// Start with extra properties so known fields always win
-	props := make(map[string]interface{}, len(f.Properties)+6)
-	for k, v := range f.Properties {
-		props[k] = v
-	}
-
-	props["uid"] = string(f.UniqueIdentifier)
-	props["name"] = f.Name
-	props["collectionId"] = f.CollectionID
+	props := common_shared.NewOrderedMap()
+	props.Set("uid", string(f.UniqueIdentifier))
+	props.Set("name", f.Name)
 	if f.Description != "" {
-		props["description"] = f.Description
+		props.Set("description", f.Description)
 	}
+	props.Set("collectionId", f.CollectionID)
 	if f.DateTime != nil {
-		props["dateTime"] = f.DateTime
+		props.Set("dateTime", f.DateTime)
 	}
 	if f.ValidTime != nil {
-		props["validTime"] = f.ValidTime
+		props.Set("validTime", f.ValidTime)
+	}
+
+	// Known fields always win over additional properties of the same name
+	extra := make([]string, 0, len(f.Properties))
+	for k := range f.Properties {
+		if !props.Has(k) {
+			extra = append(extra, k)
+		}
+	}
+	sort.Strings(extra)
+	for _, k := range extra {
+		props.Set(k, f.Properties[k])
 	}
 
 	return FeatureGeoJSONFeature{
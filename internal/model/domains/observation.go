@@ -26,3 +26,15 @@ type Observation struct {
 func (Observation) TableName() string {
 	return "observations"
 }
+
+// ApplyTimeDefaults fills in the times an observation may omit: a missing
+// resultTime is now, and a missing phenomenonTime is the resultTime.
+func (o *Observation) ApplyTimeDefaults() {
+	if o.ResultTime.IsZero() {
+		o.ResultTime = time.Now().UTC()
+	}
+	if o.PhenomenonTime == nil {
+		t := o.ResultTime
+		o.PhenomenonTime = &t
+	}
+}
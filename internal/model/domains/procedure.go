@@ -58,7 +58,7 @@ type Procedure struct {
 	Properties common_shared.Properties `gorm:"type:jsonb" json:"properties,omitempty"`
 
 	ValidTime *common_shared.TimeRange `gorm:"embedded;embeddedPrefix:valid_time_" json:"validTime,omitempty"`
-	Systems   []System                 `gorm:"many2many:system_procedures;"`
+	Systems   []System                 `gorm:"many2many:system_procedures;" json:"-"`
 }
 
 // TableName specifies the table name
@@ -84,6 +84,17 @@ const (
 	ProcedureTypeSystem   = "http://www.w3.org/ns/sosa/System"   // sosa:System - Any other system datasheet
 )
 
+// SensorML process classes a procedure is encoded as, stored in ProcessType
+const (
+	ProcessTypeSimple            = "SimpleProcess"
+	ProcessTypeAggregate         = "AggregateProcess"
+	ProcessTypePhysicalSystem    = "PhysicalSystem"
+	ProcessTypePhysicalComponent = "PhysicalComponent"
+)
+
+// ProcessTypes are the SensorML process classes procedures can be filtered on
+var ProcessTypes = []string{ProcessTypeSimple, ProcessTypeAggregate, ProcessTypePhysicalSystem, ProcessTypePhysicalComponent}
+
 // ProcedureGeoJSONFeature converts Procedure to GeoJSON Feature format
 type ProcedureGeoJSONFeature struct {
 	Type       string                     `json:"type"`
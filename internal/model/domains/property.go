@@ -58,26 +58,3 @@ type PropertySensorMLFeature struct {
 	Qualifiers   common_shared.ComponentWrappers `json:"qualifiers,omitempty"`
 	Links        common_shared.Links             `json:"links,omitempty"`
 }
-
-// PropertyGeoJSONFeature represents a Property serialized as GeoJSON Feature
-type PropertyGeoJSONFeature struct {
-	Type       string                    `json:"type"`
-	ID         string                    `json:"id"`
-	Geometry   interface{}               `json:"geometry"` // null for properties (no spatial component)
-	Properties PropertyGeoJSONProperties `json:"properties"`
-	Links      common_shared.Links       `json:"links,omitempty"`
-}
-
-// PropertyGeoJSONProperties represents the properties object in GeoJSON for a Property
-type PropertyGeoJSONProperties struct {
-	UID               UniqueID                        `json:"uid"`
-	Name              string                          `json:"name"`
-	Description       string                          `json:"description,omitempty"`
-	Definition        string                          `json:"definition,omitempty"`
-	PropertyType      string                          `json:"propertyType,omitempty"`
-	BaseProperty      *string                         `json:"baseProperty,omitempty"`
-	ObjectType        *string                         `json:"objectType,omitempty"`
-	Statistic         *string                         `json:"statistic,omitempty"`
-	Qualifiers        common_shared.ComponentWrappers `json:"qualifiers,omitempty"`
-	UnitOfMeasurement *string                         `json:"uom,omitempty"`
-}
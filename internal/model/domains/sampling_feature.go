@@ -22,10 +22,13 @@ type SamplingFeature struct {
 	ParentSystemID  *string `gorm:"type:varchar(255);index;" json:"parentSystemId,omitempty"`
 	ParentSystemUID *string `gorm:"type:varchar(255)" json:"parentSystemUid,omitempty"`
 
-	SampledFeatureID   *string             `gorm:"type:varchar(255);index" json:"featureId"`
+	SampledFeatureID   *string             `gorm:"type:varchar(255);index" json:"featureId,omitempty"`
 	SampledFeatureUID  *string             `gorm:"type:varchar(255)" json:"featureUid,omitempty"`
 	SampledFeatureLink *common_shared.Link `gorm:"type:jsonb" json:"sampledFeature@link,omitempty"`
 
+	// SampleOfIDs are the sampling features on this server named by SampleOf,
+	// resolved by the API before a write; the repository stores one relation
+	// per id
 	SampleOfIDs  *[]string            `gorm:"-" json:"sampleOfIds,omitempty"`
 	SampleOfUIDs *[]string            `gorm:"-" json:"sampleOfUids,omitempty"`
 	SampleOf     *common_shared.Links `gorm:"type:jsonb" json:"sampleOf,omitempty"`
@@ -36,6 +39,11 @@ type SamplingFeature struct {
 	// Additional properties
 	Properties common_shared.Properties `gorm:"type:jsonb" json:"properties,omitempty"`
 
+	// Geodesic area (m²) and length (m) of the geometry; only populated when
+	// requested with computed=area,length and applicable to the geometry type
+	AreaM2  *float64 `gorm:"column:area_m2;->;-:migration" json:"-"`
+	LengthM *float64 `gorm:"column:length_m;->;-:migration" json:"-"`
+
 	// Optional back-reference to parent system. Don't specify foreignKey here since it's already
 	// defined on the System side (System.SamplingFeatures with foreignKey:ParentSystemID).
 	// Specifying it on both sides causes GORM to create duplicate/conflicting FK constraints.
@@ -47,11 +55,31 @@ func (SamplingFeature) TableName() string {
 	return "sampling_features"
 }
 
+// SamplingFeatureSampleOf records that a sampling feature is a sample of
+// another local sampling feature (one row per sampleOf link)
+type SamplingFeatureSampleOf struct {
+	SamplingFeatureID string           `gorm:"type:varchar(255);primaryKey" json:"samplingFeatureId"`
+	SampleOfID        string           `gorm:"type:varchar(255);primaryKey;index" json:"sampleOfId"`
+	SamplingFeature   *SamplingFeature `gorm:"foreignKey:SamplingFeatureID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name
+func (SamplingFeatureSampleOf) TableName() string {
+	return "sampling_feature_sample_of"
+}
+
 // SamplingFeatureType constants (SOSA/SSN)
 const (
 	SamplingFeatureTypeSample = "http://www.w3.org/ns/sosa/Sample"
 )
 
+// SamplingFeatureType constants (OGC O&M spatial sampling features)
+const (
+	SamplingFeatureTypeSamplingPoint   = "http://www.opengis.net/def/samplingFeatureType/OGC-OM/2.0/SF_SamplingPoint"
+	SamplingFeatureTypeSamplingCurve   = "http://www.opengis.net/def/samplingFeatureType/OGC-OM/2.0/SF_SamplingCurve"
+	SamplingFeatureTypeSamplingSurface = "http://www.opengis.net/def/samplingFeatureType/OGC-OM/2.0/SF_SamplingSurface"
+)
+
 // SamplingFeatureGeoJSONFeature converts SamplingFeature to GeoJSON Feature format
 type SamplingFeatureGeoJSONFeature struct {
 	Type       string                           `json:"type"`
@@ -69,6 +97,11 @@ type SamplingFeatureGeoJSONProperties struct {
 	FeatureType        string                   `json:"featureType"`
 	ValidTime          *common_shared.TimeRange `json:"validTime,omitempty"`
 	SampledFeatureLink *common_shared.Link      `json:"sampledFeature@link,omitempty"`
+	// SystemLink is accepted on create as an alternative to a parentSystem link
+	SystemLink *common_shared.Link `json:"system@link,omitempty"`
+	// AreaM2 and LengthM are the geodesic area and length when listing with computed=area,length
+	AreaM2  *float64 `json:"area_m2,omitempty"`
+	LengthM *float64 `json:"length_m,omitempty"`
 }
 
 // SamplingFeatureSensorMLFeature represents a SamplingFeature serialized in SensorML JSON format
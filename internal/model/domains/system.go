@@ -36,6 +36,10 @@ type System struct {
 	SecurityConstraints common_shared.SecurityConstraints `gorm:"type:jsonb" json:"securityConstraints,omitempty"`
 	LegalConstraints    common_shared.LegalConstraints    `gorm:"type:jsonb" json:"legalConstraints,omitempty"`
 
+	// SensorML characteristics/capabilities (e.g. physical properties, accuracy, range)
+	Characteristics common_shared.CharacteristicGroups `gorm:"type:jsonb" json:"characteristics,omitempty"`
+	Capabilities    common_shared.CapabilityGroups     `gorm:"type:jsonb" json:"capabilities,omitempty"`
+
 	// Documentation/contacts/history at top-level (also present in SMLProperties)
 	Contacts      common_shared.ContactWrappers `gorm:"type:jsonb" json:"contacts,omitempty"`
 	Documentation common_shared.Documents       `gorm:"type:jsonb" json:"documentation,omitempty"`
@@ -50,6 +54,10 @@ type System struct {
 	Parameters         common_shared.IOList `gorm:"type:jsonb" json:"parameters,omitempty"`
 	Modes              json.RawMessage      `gorm:"type:jsonb" json:"modes,omitempty"`
 
+	// PhysicalSystem fields: nested components and the connections between them
+	Components  json.RawMessage `gorm:"type:jsonb" json:"components,omitempty"`
+	Connections json.RawMessage `gorm:"type:jsonb" json:"connections,omitempty"`
+
 	// Spatial frame / position
 	AttachedTo           *common_shared.Link           `gorm:"type:jsonb" json:"attachedTo,omitempty"`
 	LocalReferenceFrames []common_shared.SpatialFrame  `gorm:"type:jsonb" json:"localReferenceFrames,omitempty"`
@@ -59,15 +67,18 @@ type System struct {
 	// Links to related resources
 	Links common_shared.Links `gorm:"type:jsonb" json:"links,omitempty"`
 
+	// Distance in meters from the near point; only populated for sortby=distance queries
+	Distance *float64 `gorm:"->;-:migration" json:"-"`
+
 	SystemKind Procedure `gorm:"foreignKey:SystemKindID;" json:"-"`
 
 	// Associations
-	Procedures  []Procedure  `gorm:"many2many:system_procedures;"`
-	Deployments []Deployment `gorm:"many2many:system_deployments;"`
+	Procedures  []Procedure  `gorm:"many2many:system_procedures;" json:"-"`
+	Deployments []Deployment `gorm:"many2many:system_deployments;" json:"-"`
 	//SamplingFeatures []SamplingFeature `gorm:"foreignKey:ParentSystemID;"`
 
-	Datastreams    []Datastream    `gorm:"many2many:system_datastreams;"`
-	Controlstreams []ControlStream `gorm:"many2many:system_controlstreams;"`
+	Datastreams    []Datastream    `gorm:"many2many:system_datastreams;" json:"-"`
+	Controlstreams []ControlStream `gorm:"many2many:system_controlstreams;" json:"-"`
 }
 
 // TableName specifies the table name
@@ -131,36 +142,42 @@ type SystemGeoJSONProperties struct {
 	LocalReferenceFrames []common_shared.SpatialFrame  `json:"localReferenceFrames,omitempty"`
 	LocalTimeFrames      []common_shared.TemporalFrame `json:"localTimeFrames,omitempty"`
 	Position             json.RawMessage               `json:"position,omitempty"`
+	// Distance (meters) from the near point when listing with sortby=distance
+	Distance *float64 `json:"distance,omitempty"`
 }
 
 // SystemSensorMLFeature represents a System serialized in SensorML JSON format
 type SystemSensorMLFeature struct {
-	ID                   string                            `json:"id"`
-	Type                 string                            `json:"type"`
-	Label                string                            `json:"label"`
-	Description          string                            `json:"description,omitempty"`
-	UniqueID             string                            `json:"uniqueId"`
-	ValidTime            *common_shared.TimeRange          `json:"validTime,omitempty"`
-	Lang                 *string                           `json:"lang,omitempty"`
-	Keywords             []string                          `json:"keywords,omitempty"`
-	Identifiers          common_shared.Terms               `json:"identifiers,omitempty"`
-	Classifiers          common_shared.Terms               `json:"classifiers,omitempty"`
-	SecurityConstraints  common_shared.SecurityConstraints `json:"securityConstraints,omitempty"`
-	LegalConstraints     common_shared.LegalConstraints    `json:"legalConstraints,omitempty"`
-	Contacts             []common_shared.ContactWrapper    `json:"contacts,omitempty"`
-	Documentation        common_shared.Documents           `json:"documentation,omitempty"`
-	History              common_shared.History             `json:"history,omitempty"`
-	Definition           string                            `json:"definition,omitempty"`
-	TypeOf               *common_shared.Link               `json:"typeOf,omitempty"`
-	Configuration        json.RawMessage                   `json:"configuration,omitempty"`
-	FeaturesOfInterest   common_shared.Links               `json:"featuresOfInterest,omitempty"`
-	Inputs               common_shared.IOList              `json:"inputs,omitempty"`
-	Outputs              common_shared.IOList              `json:"outputs,omitempty"`
-	Parameters           common_shared.IOList              `json:"parameters,omitempty"`
-	Modes                json.RawMessage                   `json:"modes,omitempty"`
-	Position             json.RawMessage                   `json:"position,omitempty"`
-	AttachedTo           *common_shared.Link               `json:"attachedTo,omitempty"`
-	LocalReferenceFrames []common_shared.SpatialFrame      `json:"localReferenceFrames,omitempty"`
-	LocalTimeFrames      []common_shared.TemporalFrame     `json:"localTimeFrames,omitempty"`
-	Links                common_shared.Links               `json:"links,omitempty"`
+	ID                   string                              `json:"id"`
+	Type                 string                              `json:"type"`
+	Label                string                              `json:"label"`
+	Description          string                              `json:"description,omitempty"`
+	UniqueID             string                              `json:"uniqueId"`
+	ValidTime            *common_shared.TimeRange            `json:"validTime,omitempty"`
+	Lang                 *string                             `json:"lang,omitempty"`
+	Keywords             []string                            `json:"keywords,omitempty"`
+	Identifiers          common_shared.Terms                 `json:"identifiers,omitempty"`
+	Classifiers          common_shared.Terms                 `json:"classifiers,omitempty"`
+	SecurityConstraints  common_shared.SecurityConstraints   `json:"securityConstraints,omitempty"`
+	LegalConstraints     common_shared.LegalConstraints      `json:"legalConstraints,omitempty"`
+	Characteristics      []common_shared.CharacteristicGroup `json:"characteristics,omitempty"`
+	Capabilities         []common_shared.CapabilityGroup     `json:"capabilities,omitempty"`
+	Contacts             []common_shared.ContactWrapper      `json:"contacts,omitempty"`
+	Documentation        common_shared.Documents             `json:"documentation,omitempty"`
+	History              common_shared.History               `json:"history,omitempty"`
+	Definition           string                              `json:"definition,omitempty"`
+	TypeOf               *common_shared.Link                 `json:"typeOf,omitempty"`
+	Configuration        json.RawMessage                     `json:"configuration,omitempty"`
+	FeaturesOfInterest   common_shared.Links                 `json:"featuresOfInterest,omitempty"`
+	Inputs               common_shared.IOList                `json:"inputs,omitempty"`
+	Outputs              common_shared.IOList                `json:"outputs,omitempty"`
+	Parameters           common_shared.IOList                `json:"parameters,omitempty"`
+	Modes                json.RawMessage                     `json:"modes,omitempty"`
+	Components           json.RawMessage                     `json:"components,omitempty"`
+	Connections          json.RawMessage                     `json:"connections,omitempty"`
+	Position             json.RawMessage                     `json:"position,omitempty"`
+	AttachedTo           *common_shared.Link                 `json:"attachedTo,omitempty"`
+	LocalReferenceFrames []common_shared.SpatialFrame        `json:"localReferenceFrames,omitempty"`
+	LocalTimeFrames      []common_shared.TemporalFrame       `json:"localTimeFrames,omitempty"`
+	Links                common_shared.Links                 `json:"links,omitempty"`
 }
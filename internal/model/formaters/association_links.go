@@ -2,6 +2,7 @@ package formaters
 
 import (
 	"net/url"
+	"path"
 	"sort"
 	"strings"
 
@@ -21,6 +22,7 @@ var (
 		common_shared.OGCRel("parentSystem"),
 		common_shared.OGCRel("subsystems"),
 		common_shared.OGCRel("samplingFeatures"),
+		common_shared.OGCRel("featuresOfInterest"),
 		common_shared.OGCRel("deployments"),
 		common_shared.OGCRel("procedures"),
 		common_shared.OGCRel("datastreams"),
@@ -30,6 +32,7 @@ var (
 		common_shared.OGCRel("parentSystem"),
 		common_shared.OGCRel("subsystems"),
 		common_shared.OGCRel("samplingFeatures"),
+		common_shared.OGCRel("featuresOfInterest"),
 		common_shared.OGCRel("deployments"),
 		common_shared.OGCRel("procedures"),
 		common_shared.OGCRel("datastreams"),
@@ -38,6 +41,8 @@ var (
 	deploymentAssociationRels = []string{
 		common_shared.OGCRel("parentDeployment"),
 		common_shared.OGCRel("subdeployments"),
+		common_shared.OGCRel("deployedSystems"),
+		common_shared.OGCRel("platform"),
 		common_shared.OGCRel("featuresOfInterest"),
 		common_shared.OGCRel("samplingFeatures"),
 		common_shared.OGCRel("datastreams"),
@@ -165,6 +170,20 @@ func AppendDeploymentAssociationLinks(deployment *domains.Deployment) common_sha
 		})
 	}
 
+	if deployment.SystemIds != nil && len(*deployment.SystemIds) > 0 {
+		derived = append(derived, common_shared.Link{
+			Rel:  common_shared.OGCRel("deployedSystems"),
+			Href: "/systems?id=" + url.QueryEscape(strings.Join(*deployment.SystemIds, ",")),
+		})
+	}
+
+	if deployment.PlatformID != nil && strings.TrimSpace(*deployment.PlatformID) != "" {
+		derived = append(derived, common_shared.Link{
+			Rel:  common_shared.OGCRel("platform"),
+			Href: "/systems/" + strings.TrimSpace(*deployment.PlatformID),
+		})
+	}
+
 	return mergeAssociationLinks(common_shared.StripAssociationLinks(deployment.Links), deploymentAssociationRels, derived)
 }
 
@@ -368,6 +387,29 @@ func hasAssociationLink(links common_shared.Links, rel string) bool {
 	return false
 }
 
+// LocalResourceID returns the id of the resource of collection that href
+// names on this server, such as "samplingFeatures/{id}", "/samplingFeatures/{id}"
+// or an absolute URL under the configured base URL. Absolute URLs elsewhere
+// are external references and yield false.
+func LocalResourceID(href, collection string) (string, bool) {
+	href = strings.TrimSpace(href)
+	parsed, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	if parsed.IsAbs() || parsed.Host != "" {
+		if associationLinksBaseURL == "" || !strings.HasPrefix(href, associationLinksBaseURL+"/") {
+			return "", false
+		}
+	}
+
+	dir, id := path.Split(strings.TrimRight(parsed.Path, "/"))
+	if id == "" || path.Base(dir) != collection {
+		return "", false
+	}
+	return id, true
+}
+
 func ToFunctionalAssociationHref(href string) string {
 	href = strings.TrimSpace(href)
 	if href == "" {
@@ -51,7 +51,7 @@ func TestAppendGeoJSONSystemAssociationLinks(t *testing.T) {
 	assertHasRel(t, links, common_shared.OGCRel("datastreams"))
 	assertHasRel(t, links, common_shared.OGCRel("controlstreams"))
 	assertHasHref(t, links, common_shared.OGCRel("procedures"), "http://example.test/procedures?id=proc-1%2Cproc-2")
-	assertMissingRel(t, links, common_shared.OGCRel("featuresOfInterest"))
+	assertHasHref(t, links, common_shared.OGCRel("featuresOfInterest"), "http://example.test/features?system=sys-1")
 }
 
 func TestAppendGeoJSONSystemAssociationLinks_DedupesDerivedAndExisting(t *testing.T) {
@@ -131,6 +131,27 @@ func TestAppendDeploymentAssociationLinks(t *testing.T) {
 	assertMissingRel(t, links, common_shared.OGCRel("deployedSystems"))
 }
 
+func TestAppendDeploymentAssociationLinks_SystemsAndPlatform(t *testing.T) {
+	useTestAssociationBaseURL(t)
+
+	platformID := "platform-1"
+	systemIDs := common_shared.StringArray{"s1", "s2"}
+	deployment := &domains.Deployment{
+		Base:       domains.Base{ID: "dep-1"},
+		SystemIds:  &systemIDs,
+		PlatformID: &platformID,
+	}
+
+	links := AppendDeploymentAssociationLinks(deployment)
+
+	assertHasHref(t, links, common_shared.OGCRel("deployedSystems"), "http://example.test/systems?id=s1%2Cs2")
+	assertHasHref(t, links, common_shared.OGCRel("platform"), "http://example.test/systems/platform-1")
+
+	links = AppendDeploymentAssociationLinks(&domains.Deployment{Base: domains.Base{ID: "dep-2"}})
+	assertMissingRel(t, links, common_shared.OGCRel("deployedSystems"))
+	assertMissingRel(t, links, common_shared.OGCRel("platform"))
+}
+
 func TestAppendProcedureAssociationLinks(t *testing.T) {
 	useTestAssociationBaseURL(t)
 
@@ -252,6 +273,35 @@ func TestBuildProceduresEndpointHref(t *testing.T) {
 	}
 }
 
+func TestLocalResourceID(t *testing.T) {
+	useTestAssociationBaseURL(t)
+
+	tests := []struct {
+		href   string
+		wantID string
+		local  bool
+	}{
+		{"samplingFeatures/sf-1", "sf-1", true},
+		{"/samplingFeatures/sf-1", "sf-1", true},
+		{"/systems/sys-1/samplingFeatures/sf-1", "sf-1", true},
+		{testAssociationBaseURL + "/samplingFeatures/sf-1?f=geojson", "sf-1", true},
+		{"https://other.example/samplingFeatures/sf-1", "", false},
+		{testAssociationBaseURL + ".evil/samplingFeatures/sf-1", "", false},
+		{"/systems/sys-1", "", false},
+		{"/samplingFeatures", "", false},
+		{"urn:x:sf-1", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.href, func(t *testing.T) {
+			id, local := LocalResourceID(tt.href, "samplingFeatures")
+			if id != tt.wantID || local != tt.local {
+				t.Fatalf("LocalResourceID(%q) = %q, %v, want %q, %v", tt.href, id, local, tt.wantID, tt.local)
+			}
+		})
+	}
+}
+
 func assertHasRel(t *testing.T, links common_shared.Links, rel string) {
 	t.Helper()
 	for _, link := range links {
@@ -0,0 +1,105 @@
+package formaters
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+)
+
+// Media types a resource can be represented in, and the value of the f query
+// parameter that selects each of them.
+var formatParams = map[string]string{
+	"application/geo+json": "geojson",
+	"application/sml+json": "sml",
+	"application/json":     "json",
+	"text/csv":             "csv",
+	"application/x-ndjson": "ndjson",
+}
+
+// FeatureFormats are the representations of systems, deployments, procedures
+// and sampling features, which have both a GeoJSON and a SensorML-JSON
+// formatter. Resources offered in fewer formats, such as properties, pass
+// their own media types instead, so no alternate link points at a format the
+// server cannot produce.
+var FeatureFormats = []string{"application/geo+json", "application/sml+json"}
+
+// ContentTypeForFormat returns the media type selected by an f query parameter
+// value such as "geojson" or "sml".
+func ContentTypeForFormat(f string) (string, bool) {
+	f = strings.ToLower(strings.TrimSpace(f))
+	for contentType, param := range formatParams {
+		if param == f {
+			return contentType, true
+		}
+	}
+	return "", false
+}
+
+// AppendFormatLinks adds self, canonical and alternate links for the resource at
+// /{collection}/{id} to links. self points at the representation in
+// contentType, canonical at the format-neutral resource URL, and there is one
+// alternate per other media type in supported. Links previously generated this
+// way (for instance sent back by a client on replace) are dropped so they are
+// never duplicated; other alternate links are kept.
+func AppendFormatLinks(links common_shared.Links, collection, id, contentType string, supported ...string) common_shared.Links {
+	return AppendFormatLinksContext(context.Background(), links, collection, id, contentType, supported...)
+}
+
+// AppendFormatLinksContext is AppendFormatLinks for a resource that may have
+// been requested through a nested route. When ctx carries a self path (see
+// WithSelfPath), self and alternate links point at that path while canonical
+// still points at /{collection}/{id}.
+func AppendFormatLinksContext(ctx context.Context, links common_shared.Links, collection, id, contentType string, supported ...string) common_shared.Links {
+	if strings.TrimSpace(id) == "" {
+		return links
+	}
+
+	canonical := ToFunctionalAssociationHref("/" + collection + "/" + url.PathEscape(id))
+	self := canonical
+	if path, ok := ctx.Value(selfPathKey{}).(string); ok && path != "" {
+		self = ToFunctionalAssociationHref(path)
+	}
+
+	out := make(common_shared.Links, 0, len(links)+len(supported)+1)
+	for _, link := range links {
+		switch common_shared.CanonicalRel(link.Rel) {
+		case "self", "canonical":
+			continue
+		case "alternate":
+			if href, _, _ := strings.Cut(link.Href, "?"); href == canonical || href == self {
+				continue
+			}
+		}
+		out = append(out, link)
+	}
+
+	out = append(out,
+		common_shared.Link{Href: formatHref(self, contentType), Rel: "self", Type: contentType},
+		common_shared.Link{Href: canonical, Rel: "canonical"},
+	)
+	for _, alternate := range supported {
+		if alternate == contentType {
+			continue
+		}
+		out = append(out, common_shared.Link{Href: formatHref(self, alternate), Rel: "alternate", Type: alternate})
+	}
+	return out
+}
+
+type selfPathKey struct{}
+
+// WithSelfPath returns a context telling formatters that the resource being
+// serialized was requested at path (e.g. /systems/{id}/samplingFeatures/{sfId})
+// rather than at its canonical URL.
+func WithSelfPath(ctx context.Context, path string) context.Context {
+	return context.WithValue(ctx, selfPathKey{}, path)
+}
+
+func formatHref(href, contentType string) string {
+	if f, ok := formatParams[contentType]; ok {
+		return href + "?f=" + f
+	}
+	return href
+}
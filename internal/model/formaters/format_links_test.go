@@ -0,0 +1,88 @@
+package formaters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+)
+
+func TestAppendFormatLinks(t *testing.T) {
+	useTestAssociationBaseURL(t)
+
+	existing := common_shared.Links{
+		{Href: "/docs/spec", Rel: "alternate"},
+		// Previously generated links echoed back by a client
+		{Href: "http://example.test/systems/sys-1?f=geojson", Rel: "self", Type: "application/geo+json"},
+		{Href: "http://example.test/systems/sys-1", Rel: "canonical"},
+		{Href: "http://example.test/systems/sys-1?f=sml", Rel: "alternate", Type: "application/sml+json"},
+	}
+
+	links := AppendFormatLinks(existing, "systems", "sys-1", "application/geo+json", FeatureFormats...)
+
+	want := common_shared.Links{
+		{Href: "/docs/spec", Rel: "alternate"},
+		{Href: "http://example.test/systems/sys-1?f=geojson", Rel: "self", Type: "application/geo+json"},
+		{Href: "http://example.test/systems/sys-1", Rel: "canonical"},
+		{Href: "http://example.test/systems/sys-1?f=sml", Rel: "alternate", Type: "application/sml+json"},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("expected %d links, got %+v", len(want), links)
+	}
+	for i := range want {
+		if links[i].Href != want[i].Href || links[i].Rel != want[i].Rel || links[i].Type != want[i].Type {
+			t.Fatalf("link %d: expected %+v, got %+v", i, want[i], links[i])
+		}
+	}
+}
+
+func TestAppendFormatLinksContext_NestedRoute(t *testing.T) {
+	useTestAssociationBaseURL(t)
+
+	ctx := WithSelfPath(context.Background(), "/systems/sys-1/samplingFeatures/sf-1")
+	existing := common_shared.Links{
+		// Canonical-route links echoed back by a client
+		{Href: "http://example.test/samplingFeatures/sf-1?f=geojson", Rel: "self", Type: "application/geo+json"},
+		{Href: "http://example.test/samplingFeatures/sf-1?f=sml", Rel: "alternate", Type: "application/sml+json"},
+	}
+
+	links := AppendFormatLinksContext(ctx, existing, "samplingFeatures", "sf-1", "application/geo+json", FeatureFormats...)
+
+	want := common_shared.Links{
+		{Href: "http://example.test/systems/sys-1/samplingFeatures/sf-1?f=geojson", Rel: "self", Type: "application/geo+json"},
+		{Href: "http://example.test/samplingFeatures/sf-1", Rel: "canonical"},
+		{Href: "http://example.test/systems/sys-1/samplingFeatures/sf-1?f=sml", Rel: "alternate", Type: "application/sml+json"},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("expected %d links, got %+v", len(want), links)
+	}
+	for i := range want {
+		if links[i].Href != want[i].Href || links[i].Rel != want[i].Rel || links[i].Type != want[i].Type {
+			t.Fatalf("link %d: expected %+v, got %+v", i, want[i], links[i])
+		}
+	}
+}
+
+func TestAppendFormatLinks_OnlySupportedFormats(t *testing.T) {
+	useTestAssociationBaseURL(t)
+
+	links := AppendFormatLinks(nil, "features", "f-1", "application/geo+json", "application/geo+json")
+	for _, link := range links {
+		if link.Rel == "alternate" {
+			t.Fatalf("expected no alternate links for a single-format resource, got %+v", link)
+		}
+	}
+
+	if links := AppendFormatLinks(nil, "systems", "", "application/geo+json", FeatureFormats...); links != nil {
+		t.Fatalf("expected no links without an id, got %+v", links)
+	}
+}
+
+func TestContentTypeForFormat(t *testing.T) {
+	if ct, ok := ContentTypeForFormat("SML"); !ok || ct != "application/sml+json" {
+		t.Fatalf("expected sml to select application/sml+json, got %q", ct)
+	}
+	if _, ok := ContentTypeForFormat("html"); ok {
+		t.Fatalf("expected html to be unsupported")
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"io"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
 	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
 )
@@ -128,14 +131,169 @@ func RegisterFormatterTypedDefault[Output any, Domain any](m *MultiFormatFormatt
 	return m.RegisterDefault(adapter)
 }
 
-// GetFormatter returns the formatter for the given content type
+// GetFormatter returns the formatter for the given content type. contentType may
+// be a Content-Type value or an Accept header: media type parameters are ignored
+// and, among several media ranges, the representation with the highest q-value
+// wins, the default one included. The default formatter is used when no range
+// gives any representation a q-value above zero.
 func (m *MultiFormatFormatterCollection[Domain]) GetFormatter(contentType string) AnyFormatter[Domain] {
 	if formatter, exists := m.formatters[contentType]; exists {
 		return formatter
 	}
+	if key := m.negotiate(contentType); key != "" {
+		return m.formatters[key]
+	}
 	return m.formatters[m.defaultKey]
 }
 
+// negotiate returns the key of the formatter preferred by header, or "" when
+// no representation is acceptable. Each representation takes the q-value of
+// the most specific media range matching it, so "*/*" and "type/*" ranges
+// count for every type they cover. Ties go to the type named most
+// specifically, then to the range listed first and, within one range, to the
+// default representation.
+func (m *MultiFormatFormatterCollection[Domain]) negotiate(header string) string {
+	type preference struct {
+		q           float64
+		specificity int
+		position    int
+	}
+	candidates := m.negotiable()
+	prefs := make(map[string]preference, len(candidates))
+	for position, mediaRange := range strings.Split(header, ",") {
+		mediaType, q := parseMediaRange(mediaRange)
+		for _, contentType := range candidates {
+			specificity := rangeSpecificity(mediaType, contentType)
+			if specificity < 0 {
+				continue
+			}
+			if pref, seen := prefs[contentType]; seen && pref.specificity >= specificity {
+				continue
+			}
+			prefs[contentType] = preference{q: q, specificity: specificity, position: position}
+		}
+	}
+
+	best, bestPref := "", preference{}
+	for _, contentType := range candidates {
+		pref, ok := prefs[contentType]
+		if !ok || pref.q <= 0 {
+			continue
+		}
+		if best == "" || pref.q > bestPref.q || (pref.q == bestPref.q && (pref.specificity > bestPref.specificity ||
+			(pref.specificity == bestPref.specificity && pref.position < bestPref.position))) {
+			best, bestPref = contentType, pref
+		}
+	}
+	if _, exists := m.formatters[best]; !exists && best != "" {
+		// Only the default formatter produces it
+		return m.defaultKey
+	}
+	return best
+}
+
+// negotiable lists the content types a client can choose between: the
+// default one first, then the registered ones in sorted order.
+func (m *MultiFormatFormatterCollection[Domain]) negotiable() []string {
+	candidates := []string{}
+	if formatter, exists := m.formatters[m.defaultKey]; exists {
+		candidates = append(candidates, formatter.ContentType())
+	}
+	for _, contentType := range m.ContentTypes() {
+		if len(candidates) == 0 || contentType != candidates[0] {
+			candidates = append(candidates, contentType)
+		}
+	}
+	return candidates
+}
+
+// rangeSpecificity reports how specifically mediaRange names contentType: 2
+// for the type itself, 1 for a "type/*" range, 0 for "*/*" and -1 when the
+// range does not cover it.
+func rangeSpecificity(mediaRange, contentType string) int {
+	switch {
+	case mediaRange == contentType:
+		return 2
+	case strings.HasSuffix(mediaRange, "/*") && mediaRange != "*/*" && strings.HasPrefix(contentType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	case mediaRange == "*/*":
+		return 0
+	}
+	return -1
+}
+
+// Acceptable reports whether acceptHeader admits a representation the
+// collection can produce: a registered media type, a wildcard range covering
+// one, or application/json, which every registered format builds on. An empty
+// header accepts anything; ranges with q=0 accept nothing.
+func (m *MultiFormatFormatterCollection[Domain]) Acceptable(acceptHeader string) bool {
+	if strings.TrimSpace(acceptHeader) == "" {
+		return true
+	}
+
+	contentTypes := m.ContentTypes()
+	for _, mediaRange := range strings.Split(acceptHeader, ",") {
+		mediaType, q := parseMediaRange(mediaRange)
+		if q <= 0 {
+			continue
+		}
+		if mediaType == "*/*" || mediaType == "application/json" {
+			return true
+		}
+		for _, contentType := range contentTypes {
+			if mediaType == contentType || (strings.HasSuffix(mediaType, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(mediaType, "*"))) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Prefers reports whether acceptHeader names mediaType itself with a q-value
+// above zero that no other media range beats; on a tie the range listed first
+// wins. Handlers use it to offer a representation no formatter produces, such
+// as a CSV export.
+func Prefers(acceptHeader, mediaType string) bool {
+	bestQ, preferred := 0.0, false
+	for _, mediaRange := range strings.Split(acceptHeader, ",") {
+		rangeType, q := parseMediaRange(mediaRange)
+		if q > bestQ {
+			bestQ, preferred = q, rangeType == mediaType
+		}
+	}
+	return preferred
+}
+
+// ContentTypes returns the registered media types in sorted order.
+func (m *MultiFormatFormatterCollection[Domain]) ContentTypes() []string {
+	contentTypes := make([]string, 0, len(m.formatters))
+	for contentType := range m.formatters {
+		if contentType != m.defaultKey {
+			contentTypes = append(contentTypes, contentType)
+		}
+	}
+	sort.Strings(contentTypes)
+	return contentTypes
+}
+
+// parseMediaRange splits one Accept media range into its lower-cased media
+// type and q-value, which defaults to 1.
+func parseMediaRange(mediaRange string) (string, float64) {
+	params := strings.Split(mediaRange, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+	q := 1.0
+	for _, param := range params[1:] {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if strings.EqualFold(strings.TrimSpace(name), "q") {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+	return mediaType, q
+}
+
 // GetResponseContentType returns the content type that will be produced for the given accept header
 func (m *MultiFormatFormatterCollection[Domain]) GetResponseContentType(acceptHeader string) string {
 	if formatter := m.GetFormatter(acceptHeader); formatter != nil {
@@ -152,6 +310,12 @@ func (m *MultiFormatFormatterCollection[Domain]) Serialize(contentType string, i
 	return formatter.SerializeAny(context.Background(), item)
 }
 
+// SerializeWithContext serializes a single item using the appropriate formatter with context
+func (m *MultiFormatFormatterCollection[Domain]) SerializeWithContext(ctx context.Context, contentType string, item Domain) (any, error) {
+	formatter := m.GetFormatter(contentType)
+	return formatter.SerializeAny(ctx, item)
+}
+
 // SerializeAll serializes multiple items using the appropriate formatter
 func (m *MultiFormatFormatterCollection[Domain]) SerializeAll(contentType string, items []Domain) ([]any, error) {
 	formatter := m.GetFormatter(contentType)
@@ -182,12 +346,5 @@ func (m *MultiFormatFormatterCollection[Domain]) BuildCollection(
 		features = []any{}
 	}
 
-	totalInt := int(total)
-	return AnyFeatureCollection{
-		Type:           "FeatureCollection",
-		Features:       features,
-		NumberMatched:  &totalInt,
-		NumberReturned: len(items),
-		Links:          queryParams.BuildPagintationLinks(basePath, requestParams, &totalInt, len(items)),
-	}
+	return newFeatureCollection(features, total, len(items), basePath, requestParams, queryParams)
 }
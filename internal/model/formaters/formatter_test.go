@@ -0,0 +1,157 @@
+package formaters
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+)
+
+type stubFormatter struct {
+	contentType string
+}
+
+func (s stubFormatter) SerializeAny(ctx context.Context, item string) (any, error) { return item, nil }
+
+func (s stubFormatter) SerializeAllAny(ctx context.Context, items []string) ([]any, error) {
+	return nil, nil
+}
+
+func (s stubFormatter) Deserialize(ctx context.Context, reader io.Reader) (string, error) {
+	return "", nil
+}
+
+func (s stubFormatter) ContentType() string { return s.contentType }
+
+func TestMultiFormatFormatterCollection_GetFormatter(t *testing.T) {
+	collection := NewMultiFormatFormatterCollection[string]("application/geo+json")
+	collection.Register("application/geo+json", stubFormatter{"application/geo+json"})
+	collection.Register("application/sml+json", stubFormatter{"application/sml+json"})
+	collection.RegisterDefault(stubFormatter{"application/geo+json"})
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{"empty header uses default", "", "application/geo+json"},
+		{"exact match", "application/sml+json", "application/sml+json"},
+		{"parameters are ignored", "application/sml+json; charset=utf-8", "application/sml+json"},
+		{"case insensitive", "Application/SML+JSON", "application/sml+json"},
+		{"first listed wins on equal q", "application/sml+json, application/geo+json", "application/sml+json"},
+		{"highest q wins", "application/sml+json;q=0.2, application/geo+json;q=0.9", "application/geo+json"},
+		{"unregistered types are skipped", "text/html, application/sml+json;q=0.1", "application/sml+json"},
+		{"q=0 is not acceptable", "application/sml+json;q=0", "application/geo+json"},
+		{"wildcard uses default", "*/*", "application/geo+json"},
+		{"unknown type uses default", "application/xml", "application/geo+json"},
+		{"default competes on q", "application/sml+json;q=0.1, application/geo+json", "application/geo+json"},
+		{"wildcards give the default their q", "application/sml+json;q=0.1, */*", "application/geo+json"},
+		{"specific ranges override wildcards", "application/sml+json, application/*;q=0.5", "application/sml+json"},
+		{"excluded types stay excluded", "application/geo+json;q=0, */*;q=0.5", "application/sml+json"},
+		{"named types win ties with wildcards", "*/*, application/sml+json", "application/sml+json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collection.GetResponseContentType(tt.accept); got != tt.want {
+				t.Fatalf("GetResponseContentType(%q) = %q, want %q", tt.accept, got, tt.want)
+			}
+		})
+	}
+
+	// The default representation is negotiable even when its type is only
+	// registered as the default
+	defaultOnly := NewMultiFormatFormatterCollection[string]("application/geo+json")
+	defaultOnly.Register("application/sml+json", stubFormatter{"application/sml+json"})
+	defaultOnly.RegisterDefault(stubFormatter{"application/geo+json"})
+	accept := "application/sml+json;q=0.1, application/geo+json"
+	if got := defaultOnly.GetResponseContentType(accept); got != "application/geo+json" {
+		t.Fatalf("GetResponseContentType(%q) = %q with an unregistered default, want application/geo+json", accept, got)
+	}
+}
+
+func TestMultiFormatFormatterCollection_Acceptable(t *testing.T) {
+	collection := NewMultiFormatFormatterCollection[string]("application/sml+json")
+	collection.Register("application/sml+json", stubFormatter{"application/sml+json"})
+	collection.RegisterDefault(stubFormatter{"application/sml+json"})
+
+	tests := []struct {
+		name   string
+		accept string
+		want   bool
+	}{
+		{"empty header", "", true},
+		{"registered type", "application/sml+json", true},
+		{"registered type with parameters", "application/sml+json; charset=utf-8", true},
+		{"any type", "*/*", true},
+		{"subtype wildcard", "application/*", true},
+		{"plain json", "application/json", true},
+		{"unregistered type", "application/geo+json", false},
+		{"other wildcard", "text/*", false},
+		{"q=0 refuses the type", "application/sml+json;q=0", false},
+		{"one acceptable range is enough", "application/geo+json, application/sml+json;q=0.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collection.Acceptable(tt.accept); got != tt.want {
+				t.Fatalf("Acceptable(%q) = %v, want %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+
+	if got := collection.ContentTypes(); len(got) != 1 || got[0] != "application/sml+json" {
+		t.Fatalf("ContentTypes() = %v, want [application/sml+json]", got)
+	}
+}
+
+func TestPrefers(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   bool
+	}{
+		{"only range", "text/csv", true},
+		{"with parameters", "text/csv; charset=utf-8", true},
+		{"empty header", "", false},
+		{"wildcard", "*/*", false},
+		{"other type", "application/geo+json", false},
+		{"listed first", "text/csv, application/json", true},
+		{"listed after an equal q", "application/json, text/csv", false},
+		{"higher q", "application/json;q=0.5, text/csv", true},
+		{"lower q", "text/csv;q=0.5, application/json", false},
+		{"q=0", "text/csv;q=0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Prefers(tt.accept, "text/csv"); got != tt.want {
+				t.Fatalf("Prefers(%q) = %v, want %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiFormatFormatterCollection_BuildCollection_TimeStamp(t *testing.T) {
+	collection := NewMultiFormatFormatterCollection[string]("application/geo+json")
+	collection.RegisterDefault(stubFormatter{"application/geo+json"})
+
+	before := time.Now().UTC().Truncate(time.Second)
+	built := collection.BuildCollection("application/geo+json", []string{"a"}, "http://example.test/systems", 1, url.Values{}, queryparams.QueryParams{Limit: 10})
+	after := time.Now().UTC()
+
+	stamp, err := time.Parse(time.RFC3339, built.TimeStamp)
+	if err != nil {
+		t.Fatalf("timeStamp %q is not RFC 3339: %v", built.TimeStamp, err)
+	}
+	if !strings.HasSuffix(built.TimeStamp, "Z") {
+		t.Fatalf("timeStamp %q is not UTC", built.TimeStamp)
+	}
+	if stamp.Before(before) || stamp.After(after) {
+		t.Fatalf("timeStamp %v outside [%v, %v]", stamp, before, after)
+	}
+}
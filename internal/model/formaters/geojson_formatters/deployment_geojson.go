@@ -81,7 +81,7 @@ func (f *DeploymentGeoJSONFormatter) SerializeAll(ctx context.Context, deploymen
 				Platform:        platformLink,
 				DeployedSystems: systemLinks,
 			},
-			Links: formaters.AppendDeploymentAssociationLinks(deployment),
+			Links: formaters.AppendFormatLinks(formaters.AppendDeploymentAssociationLinks(deployment), "deployments", deployment.ID, GeoJSONContentType, formaters.FeatureFormats...),
 		}
 		features = append(features, feature)
 	}
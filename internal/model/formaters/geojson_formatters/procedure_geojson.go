@@ -54,7 +54,7 @@ func (f *ProcedureGeoJSONFormatter) SerializeAll(ctx context.Context, procedures
 				FeatureType: procedure.ProcedureType,
 				ValidTime:   procedure.ValidTime,
 			},
-			Links: formaters.AppendProcedureAssociationLinks(procedure),
+			Links: formaters.AppendFormatLinks(formaters.AppendProcedureAssociationLinks(procedure), "procedures", procedure.ID, GeoJSONContentType, formaters.FeatureFormats...),
 		}
 		features = append(features, feature)
 	}
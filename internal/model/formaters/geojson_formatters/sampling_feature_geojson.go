@@ -41,9 +41,32 @@ func (f *SamplingFeatureGeoJSONFormatter) SerializeAll(ctx context.Context, samp
 		return []domains.SamplingFeatureGeoJSONFeature{}, nil
 	}
 
-	var features []domains.SamplingFeatureGeoJSONFeature
+	// Load every referenced parent system in one query for the parentSystem links
+	parentIDs := make([]string, 0, len(samplingFeatures))
 	for _, sf := range samplingFeatures {
-		feature := domains.SamplingFeatureGeoJSONFeature{
+		if sf.ParentSystemID != nil {
+			parentIDs = append(parentIDs, *sf.ParentSystemID)
+		}
+	}
+	var loadSystems formaters.ResourceLoader[*domains.System]
+	if f.repos != nil && f.repos.System != nil {
+		loadSystems = f.repos.System.GetByIDs
+	}
+	parents := formaters.NewResourceCache(loadSystems)
+	_ = parents.Prefetch(ctx, parentIDs)
+
+	// Each item writes only its own slot, so large pages can be enriched in parallel
+	features := make([]domains.SamplingFeatureGeoJSONFeature, len(samplingFeatures))
+	formaters.EnrichAll(len(samplingFeatures), func(i int) {
+		sf := samplingFeatures[i]
+		links := formaters.AppendSamplingFeatureGeoJSONAssociationLinks(sf)
+		if sf.ParentSystemID != nil {
+			if parent, ok := parents.Get(ctx, *sf.ParentSystemID); ok {
+				enrichParentSystemLink(links, parent)
+			}
+		}
+
+		features[i] = domains.SamplingFeatureGeoJSONFeature{
 			Type:     "Feature",
 			ID:       sf.ID,
 			Geometry: sf.Geometry,
@@ -54,16 +77,30 @@ func (f *SamplingFeatureGeoJSONFormatter) SerializeAll(ctx context.Context, samp
 				FeatureType:        sf.FeatureType,
 				ValidTime:          sf.ValidTime,
 				SampledFeatureLink: sf.SampledFeatureLink,
+				AreaM2:             sf.AreaM2,
+				LengthM:            sf.LengthM,
 			},
-			Links: formaters.AppendSamplingFeatureGeoJSONAssociationLinks(sf),
+			Links: formaters.AppendFormatLinksContext(ctx, links, "samplingFeatures", sf.ID, GeoJSONContentType, formaters.FeatureFormats...),
 		}
-
-		features = append(features, feature)
-	}
+	})
 
 	return features, nil
 }
 
+// enrichParentSystemLink fills in the media type, title and uid of the
+// parentSystem link from the parent system record.
+func enrichParentSystemLink(links common_shared.Links, parent *domains.System) {
+	for i := range links {
+		if !common_shared.RelEquals(links[i].Rel, common_shared.OGCRel("parentSystem")) {
+			continue
+		}
+		uid := string(parent.UniqueIdentifier)
+		links[i].Type = GeoJSONContentType
+		links[i].Title = parent.Name
+		links[i].UID = &uid
+	}
+}
+
 // --- Deserialization ---
 
 func (f *SamplingFeatureGeoJSONFormatter) Deserialize(ctx context.Context, reader io.Reader) (*domains.SamplingFeature, error) {
@@ -108,5 +145,13 @@ func (f *SamplingFeatureGeoJSONFormatter) Deserialize(ctx context.Context, reade
 
 	formaters.ApplySamplingFeatureGeoJSONAssociationLinks(sf, associationLinks)
 
+	// A parentSystem link takes precedence; fall back to system@link when absent
+	if sf.ParentSystemID == nil && geoJSON.Properties.SystemLink != nil && geoJSON.Properties.SystemLink.Href != "" {
+		sf.ParentSystemID = geoJSON.Properties.SystemLink.GetId("systems")
+		if geoJSON.Properties.SystemLink.UID != nil {
+			sf.ParentSystemUID = geoJSON.Properties.SystemLink.UID
+		}
+	}
+
 	return sf, nil
 }
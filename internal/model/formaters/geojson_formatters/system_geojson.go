@@ -122,8 +122,9 @@ func (f *SystemGeoJSONFormatter) SerializeAll(ctx context.Context, systems []*do
 				LocalReferenceFrames: system.LocalReferenceFrames,
 				LocalTimeFrames:      system.LocalTimeFrames,
 				Position:             system.Position,
+				Distance:             system.Distance,
 			},
-			Links: formaters.AppendGeoJSONSystemAssociationLinks(system),
+			Links: formaters.AppendFormatLinks(formaters.AppendGeoJSONSystemAssociationLinks(system), "systems", system.ID, GeoJSONContentType, formaters.FeatureFormats...),
 		}
 		features = append(features, feature)
 	}
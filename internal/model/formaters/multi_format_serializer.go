@@ -3,6 +3,7 @@ package formaters
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
 	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
@@ -137,11 +138,16 @@ func (m *MultiFormatSerializerCollection[Input]) SerializeAllWithContext(ctx con
 // AnyFeatureCollection represents a feature collection where the features can be any type
 // This is used with MultiFormatSerializerCollection where different formats produce different types
 type AnyFeatureCollection struct {
-	Type           string              `json:"type"`
-	Features       []any               `json:"features"`
+	Type     string `json:"type"`
+	Features []any  `json:"features"`
+	// TimeStamp is when the response was generated, in RFC 3339 (UTC)
+	TimeStamp      string              `json:"timeStamp"`
 	NumberMatched  *int                `json:"numberMatched,omitempty"`
 	NumberReturned int                 `json:"numberReturned"`
 	Links          common_shared.Links `json:"links"`
+
+	// Query echoes the effective query parameters when requested with ?echo=true
+	Query any `json:"query,omitempty"`
 }
 
 // BuildCollection builds a feature collection using the multi-format serializer
@@ -158,12 +164,25 @@ func (m *MultiFormatSerializerCollection[Input]) BuildCollection(
 		features = []any{}
 	}
 
-	totalInt := int(total)
+	return newFeatureCollection(features, total, len(items), basePath, requestParams, queryParams)
+}
+
+// CollectionTimeStamp returns the timeStamp of a collection response generated
+// now: the current time in RFC 3339, in UTC.
+func CollectionTimeStamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// newFeatureCollection wraps one page of serialized features in the collection
+// envelope shared by every feature collection response, stamped with the
+// time it was generated.
+func newFeatureCollection(features []any, total int, returned int, basePath string, requestParams url.Values, queryParams queryparams.QueryParams) AnyFeatureCollection {
 	return AnyFeatureCollection{
 		Type:           "FeatureCollection",
 		Features:       features,
-		NumberMatched:  &totalInt,
-		NumberReturned: len(items),
-		Links:          queryParams.BuildPagintationLinks(basePath, requestParams, &totalInt, len(items)),
+		TimeStamp:      CollectionTimeStamp(),
+		NumberMatched:  &total,
+		NumberReturned: returned,
+		Links:          queryParams.BuildPagintationLinks(basePath, requestParams, &total, returned),
 	}
 }
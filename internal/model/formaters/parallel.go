@@ -0,0 +1,43 @@
+package formaters
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelEnrichThreshold is the collection size from which EnrichAll spreads
+// the work over several goroutines. Smaller pages are not worth the overhead.
+const ParallelEnrichThreshold = 256
+
+// maxEnrichWorkers bounds the goroutines EnrichAll starts for one collection.
+const maxEnrichWorkers = 8
+
+// EnrichAll calls enrich once for every index in [0, n). Collections of at
+// least ParallelEnrichThreshold items are handled by a bounded pool of
+// goroutines, so enrich must be safe to call concurrently for distinct
+// indexes, e.g. by writing only to its own slot of a pre-sized slice and
+// reading shared lookups through a ResourceCache.
+func EnrichAll(n int, enrich func(i int)) {
+	workers := min(runtime.GOMAXPROCS(0), maxEnrichWorkers)
+	if n < ParallelEnrichThreshold || workers < 2 {
+		for i := 0; i < n; i++ {
+			enrich(i)
+		}
+		return
+	}
+
+	// Hand each worker a contiguous chunk of indexes.
+	chunk := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunk {
+		end := min(start+chunk, n)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				enrich(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
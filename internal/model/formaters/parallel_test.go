@@ -0,0 +1,52 @@
+package formaters
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEnrichAll_VisitsEveryIndexOnce(t *testing.T) {
+	for _, n := range []int{0, 1, ParallelEnrichThreshold - 1, ParallelEnrichThreshold, 1000, 1001} {
+		visits := make([]int32, n)
+		EnrichAll(n, func(i int) {
+			atomic.AddInt32(&visits[i], 1)
+		})
+		for i, v := range visits {
+			if v != 1 {
+				t.Fatalf("n=%d: expected index %d to be visited once, got %d", n, i, v)
+			}
+		}
+	}
+}
+
+func TestEnrichAll_SharedCache(t *testing.T) {
+	var loads int32
+	cache := NewResourceCache(func(ctx context.Context, ids []string) (map[string]string, error) {
+		atomic.AddInt32(&loads, 1)
+		out := map[string]string{}
+		for _, id := range ids {
+			out[id] = "name-" + id
+		}
+		return out, nil
+	})
+	parents := []string{"p1", "p2", "p3"}
+	if err := cache.Prefetch(context.Background(), parents); err != nil {
+		t.Fatalf("prefetch failed: %v", err)
+	}
+
+	names := make([]string, 1000)
+	EnrichAll(len(names), func(i int) {
+		name, _ := cache.Get(context.Background(), parents[i%len(parents)])
+		names[i] = name
+	})
+
+	for i, name := range names {
+		if want := "name-" + parents[i%len(parents)]; name != want {
+			t.Fatalf("item %d: expected %q, got %q", i, want, name)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected the prefetch to be the only load, got %d", loads)
+	}
+}
@@ -0,0 +1,123 @@
+package formaters
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// ResourceLoader batch-loads resources by ID. IDs without a matching resource
+// are simply absent from the returned map.
+type ResourceLoader[T any] func(ctx context.Context, ids []string) (map[string]T, error)
+
+// ResourceCache memoizes resources looked up while serializing a collection, so
+// enriching links on many items that reference the same resource costs one
+// query rather than one per item. Misses are remembered too. A cache is meant to
+// live for a single SerializeAll call, and is safe for concurrent use so items
+// can be enriched in parallel.
+type ResourceCache[T any] struct {
+	load ResourceLoader[T]
+
+	mu      sync.Mutex
+	entries map[string]T
+	loaded  map[string]struct{}
+	// pending holds a channel per ID whose load is in flight; it is closed
+	// once that load finishes, successfully or not.
+	pending map[string]chan struct{}
+}
+
+// NewResourceCache creates a cache backed by load. A nil load yields a cache
+// that never finds anything.
+func NewResourceCache[T any](load ResourceLoader[T]) *ResourceCache[T] {
+	return &ResourceCache[T]{
+		load:    load,
+		entries: make(map[string]T),
+		loaded:  make(map[string]struct{}),
+		pending: make(map[string]chan struct{}),
+	}
+}
+
+// Prefetch loads every ID not already looked up with a single loader call.
+// Blank IDs are ignored. IDs another goroutine is already loading are not
+// loaded twice; Prefetch waits for that load instead.
+func (c *ResourceCache[T]) Prefetch(ctx context.Context, ids []string) error {
+	done := make(chan struct{})
+	missing := make([]string, 0, len(ids))
+	var waits []chan struct{}
+
+	c.mu.Lock()
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := c.loaded[id]; ok {
+			continue
+		}
+		if wait, ok := c.pending[id]; ok {
+			if wait != done {
+				waits = append(waits, wait)
+			}
+			continue
+		}
+		c.pending[id] = done
+		missing = append(missing, id)
+	}
+	c.mu.Unlock()
+
+	var err error
+	if len(missing) > 0 {
+		err = c.fetch(ctx, missing)
+		close(done)
+	}
+
+	for _, wait := range waits {
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// fetch loads missing and records the outcome. On error the IDs are left
+// unloaded so a later Prefetch or Get can retry.
+func (c *ResourceCache[T]) fetch(ctx context.Context, missing []string) error {
+	var found map[string]T
+	var err error
+	if c.load != nil {
+		found, err = c.load(ctx, missing)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range missing {
+		delete(c.pending, id)
+		if err == nil {
+			c.loaded[id] = struct{}{}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	for id, resource := range found {
+		c.entries[id] = resource
+	}
+	return nil
+}
+
+// Get returns the resource with the given ID, loading it if it has not been
+// looked up yet.
+func (c *ResourceCache[T]) Get(ctx context.Context, id string) (T, bool) {
+	id = strings.TrimSpace(id)
+	if err := c.Prefetch(ctx, []string{id}); err != nil {
+		var zero T
+		return zero, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resource, ok := c.entries[id]
+	return resource, ok
+}
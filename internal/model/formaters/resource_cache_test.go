@@ -0,0 +1,113 @@
+package formaters
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestResourceCache_LoadsEachIDOnce(t *testing.T) {
+	var calls [][]string
+	cache := NewResourceCache(func(ctx context.Context, ids []string) (map[string]string, error) {
+		calls = append(calls, ids)
+		out := map[string]string{}
+		for _, id := range ids {
+			if id != "missing" {
+				out[id] = "name-" + id
+			}
+		}
+		return out, nil
+	})
+
+	ctx := context.Background()
+	if err := cache.Prefetch(ctx, []string{"a", "a", "b", "", "missing", "a"}); err != nil {
+		t.Fatalf("prefetch failed: %v", err)
+	}
+	if len(calls) != 1 || len(calls[0]) != 3 {
+		t.Fatalf("expected one batched load of 3 ids, got %v", calls)
+	}
+
+	for i := 0; i < 4; i++ {
+		if got, ok := cache.Get(ctx, "a"); !ok || got != "name-a" {
+			t.Fatalf("expected cached value for a, got %q %v", got, ok)
+		}
+		if _, ok := cache.Get(ctx, "missing"); ok {
+			t.Fatalf("expected missing id to stay missing")
+		}
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected cached lookups not to reload, got %d loads", len(calls))
+	}
+
+	if got, ok := cache.Get(ctx, "c"); !ok || got != "name-c" {
+		t.Fatalf("expected c to be loaded on demand, got %q %v", got, ok)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected a second load for c, got %d loads", len(calls))
+	}
+}
+
+func TestResourceCache_RetriesAfterError(t *testing.T) {
+	fail := true
+	cache := NewResourceCache(func(ctx context.Context, ids []string) (map[string]int, error) {
+		if fail {
+			return nil, errors.New("boom")
+		}
+		return map[string]int{"a": 1}, nil
+	})
+
+	if _, ok := cache.Get(context.Background(), "a"); ok {
+		t.Fatalf("expected lookup to fail while loader errors")
+	}
+	fail = false
+	if got, ok := cache.Get(context.Background(), "a"); !ok || got != 1 {
+		t.Fatalf("expected lookup to succeed after loader recovers, got %d %v", got, ok)
+	}
+}
+
+func TestResourceCache_NilLoader(t *testing.T) {
+	cache := NewResourceCache[string](nil)
+	if _, ok := cache.Get(context.Background(), "a"); ok {
+		t.Fatalf("expected nil loader to find nothing")
+	}
+}
+
+func TestResourceCache_ConcurrentGet(t *testing.T) {
+	var mu sync.Mutex
+	loads := map[string]int{}
+	cache := NewResourceCache(func(ctx context.Context, ids []string) (map[string]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		out := map[string]string{}
+		for _, id := range ids {
+			loads[id]++
+			out[id] = "name-" + id
+		}
+		return out, nil
+	})
+
+	ids := []string{"a", "b", "c", "d"}
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for g := 0; g < 32; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				id := ids[(g+i)%len(ids)]
+				if got, ok := cache.Get(ctx, id); !ok || got != "name-"+id {
+					t.Errorf("expected cached value for %s, got %q %v", id, got, ok)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		if loads[id] != 1 {
+			t.Fatalf("expected %s to be loaded exactly once, got %d", id, loads[id])
+		}
+	}
+}
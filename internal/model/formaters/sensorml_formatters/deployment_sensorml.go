@@ -53,7 +53,7 @@ func (f *DeploymentSensorMLFormatter) SerializeAll(ctx context.Context, deployme
 			ValidTime:       deployment.ValidTime,
 			Platform:        deployment.Platform,
 			DeployedSystems: deployment.DeployedSystems,
-			Links:           formaters.AppendDeploymentAssociationLinks(deployment),
+			Links:           formaters.AppendFormatLinks(formaters.AppendDeploymentAssociationLinks(deployment), "deployments", deployment.ID, SensorMLContentType, formaters.FeatureFormats...),
 
 			Lang:                deployment.Lang,
 			Keywords:            deployment.Keywords,
@@ -157,7 +157,7 @@ func (f *ProcedureSensorMLFormatter) SerializeAll(ctx context.Context, procedure
 			LocalReferenceFrames: procedure.LocalReferenceFrames,
 			LocalTimeFrames:      procedure.LocalTimeFrames,
 			ValidTime:            procedure.ValidTime,
-			Links:                formaters.AppendProcedureAssociationLinks(procedure),
+			Links:                formaters.AppendFormatLinks(formaters.AppendProcedureAssociationLinks(procedure), "procedures", procedure.ID, SensorMLContentType, formaters.FeatureFormats...),
 		}
 
 		switch procedure.ProcessType {
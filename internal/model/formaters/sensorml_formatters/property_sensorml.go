@@ -52,7 +52,7 @@ func (f *PropertySensorMLFormatter) SerializeAll(ctx context.Context, properties
 			ObjectType:   property.ObjectType,
 			Statistic:    property.Statistic,
 			Qualifiers:   property.Qualifiers,
-			Links:        property.Links,
+			Links:        formaters.AppendFormatLinks(property.Links, "properties", property.ID, SensorMLContentType, SensorMLContentType),
 		}
 		features = append(features, feature)
 	}
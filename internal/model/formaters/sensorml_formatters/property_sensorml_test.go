@@ -0,0 +1,22 @@
+package sensorml_formatters
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+)
+
+func TestPropertySensorMLSerialize_FormatLinks(t *testing.T) {
+	formatter := NewPropertySensorMLFormatter(nil)
+	property := &domains.Property{Base: domains.Base{ID: "prop-1"}}
+
+	feature, err := formatter.Serialize(context.Background(), property)
+	if err != nil {
+		t.Fatalf("serialize failed: %v", err)
+	}
+
+	assertHasRel(t, feature.Links, "self")
+	// Properties have no GeoJSON representation to point at
+	assertMissingRel(t, feature.Links, "alternate")
+}
@@ -53,7 +53,7 @@ func (f *SamplingFeatureSensorMLFormatter) SerializeAll(ctx context.Context, sam
 			ValidTime:          sf.ValidTime,
 			SampledFeatureLink: sf.SampledFeatureLink,
 			SampleOf:           sf.SampleOf,
-			Links:              sf.Links,
+			Links:              formaters.AppendFormatLinksContext(ctx, sf.Links, "samplingFeatures", sf.ID, SensorMLContentType, formaters.FeatureFormats...),
 		}
 		features = append(features, feature)
 	}
@@ -15,11 +15,16 @@ import (
 const SensorMLContentType = "application/sml+json"
 
 // getSMLType returns the SensorML process type for a system.
-// Defaults to "PhysicalSystem" if not explicitly set.
+// When not explicitly set, sensors, actuators and samplers are atomic
+// "PhysicalComponent"s and everything else defaults to "PhysicalSystem".
 func getSMLType(system *domains.System) string {
 	if system.SMLType != nil && *system.SMLType != "" {
 		return *system.SMLType
 	}
+	switch system.SystemType {
+	case domains.SystemTypeSensor, domains.SystemTypeActuator, domains.SystemTypeSampler:
+		return "PhysicalComponent"
+	}
 	return "PhysicalSystem"
 }
 
@@ -87,9 +92,11 @@ func (f *SystemSensorMLFormatter) SerializeAll(ctx context.Context, systems []*d
 			classifiers = append(classifiers, assetClassifier)
 		}
 
+		smlType := getSMLType(system)
+
 		feature := domains.SystemSensorMLFeature{
 			ID:                   system.ID,
-			Type:                 getSMLType(system),
+			Type:                 smlType,
 			Label:                system.Name,
 			Description:          system.Description,
 			UniqueID:             string(system.UniqueIdentifier),
@@ -101,6 +108,8 @@ func (f *SystemSensorMLFormatter) SerializeAll(ctx context.Context, systems []*d
 			Classifiers:          classifiers,
 			SecurityConstraints:  system.SecurityConstraints,
 			LegalConstraints:     system.LegalConstraints,
+			Characteristics:      system.Characteristics,
+			Capabilities:         system.Capabilities,
 			Contacts:             system.Contacts,
 			Documentation:        system.Documentation,
 			History:              system.History,
@@ -115,7 +124,12 @@ func (f *SystemSensorMLFormatter) SerializeAll(ctx context.Context, systems []*d
 			AttachedTo:           attachedTo,
 			LocalReferenceFrames: system.LocalReferenceFrames,
 			LocalTimeFrames:      system.LocalTimeFrames,
-			Links:                formaters.AppendSensorMLSystemAssociationLinks(system),
+			Links:                formaters.AppendFormatLinks(formaters.AppendSensorMLSystemAssociationLinks(system), "systems", system.ID, SensorMLContentType, formaters.FeatureFormats...),
+		}
+		// Only a PhysicalSystem is made of components; atomic types have none
+		if smlType == "PhysicalSystem" {
+			feature.Components = system.Components
+			feature.Connections = system.Connections
 		}
 		features = append(features, feature)
 	}
@@ -213,6 +227,8 @@ func (f *SystemSensorMLFormatter) Deserialize(ctx context.Context, reader io.Rea
 	system.Identifiers = sml.Identifiers
 	system.SecurityConstraints = sml.SecurityConstraints
 	system.LegalConstraints = sml.LegalConstraints
+	system.Characteristics = sml.Characteristics
+	system.Capabilities = sml.Capabilities
 	system.Lang = sml.Lang
 	system.Keywords = sml.Keywords
 
@@ -224,6 +240,8 @@ func (f *SystemSensorMLFormatter) Deserialize(ctx context.Context, reader io.Rea
 	system.Outputs = sml.Outputs
 	system.Parameters = sml.Parameters
 	system.Modes = sml.Modes
+	system.Components = sml.Components
+	system.Connections = sml.Connections
 	system.LocalReferenceFrames = sml.LocalReferenceFrames
 	system.LocalTimeFrames = sml.LocalTimeFrames
 	system.Position = sml.Position
@@ -2,6 +2,7 @@ package sensorml_formatters
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -68,3 +69,130 @@ func TestSystemSensorMLDeserialize_AssociationHandling(t *testing.T) {
 		t.Fatalf("expected only non-association links to remain, got %+v", system.Links)
 	}
 }
+
+func TestGetSMLType(t *testing.T) {
+	explicit := "PhysicalSystem"
+	tests := []struct {
+		name   string
+		system *domains.System
+		want   string
+	}{
+		{"sensor", &domains.System{SystemType: domains.SystemTypeSensor}, "PhysicalComponent"},
+		{"actuator", &domains.System{SystemType: domains.SystemTypeActuator}, "PhysicalComponent"},
+		{"sampler", &domains.System{SystemType: domains.SystemTypeSampler}, "PhysicalComponent"},
+		{"platform", &domains.System{SystemType: domains.SystemTypePlatform}, "PhysicalSystem"},
+		{"system", &domains.System{SystemType: domains.SystemTypeSystem}, "PhysicalSystem"},
+		{"explicit type wins", &domains.System{SystemType: domains.SystemTypeSensor, SMLType: &explicit}, "PhysicalSystem"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getSMLType(tt.system); got != tt.want {
+				t.Fatalf("getSMLType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSystemSensorML_CapabilitiesRoundTrip(t *testing.T) {
+	formatter := NewSystemSensorMLFormatter(nil)
+	payload := `{
+		"type": "PhysicalComponent",
+		"label": "Thermometer",
+		"uniqueId": "urn:system:thermo",
+		"characteristics": [{
+			"definition": "http://sensorml.com/ont/swe/property/PhysicalProperties",
+			"characteristics": [
+				{"type": "Quantity", "name": "weight", "definition": "http://qudt.org/vocab/quantitykind/Mass", "label": "Weight", "uom": {"code": "kg"}, "value": 0.4}
+			]
+		}],
+		"capabilities": [{
+			"label": "Measurement Capabilities",
+			"definition": "http://sensorml.com/ont/swe/property/MeasurementProperties",
+			"capabilities": [
+				{"type": "Quantity", "name": "accuracy", "definition": "http://sensorml.com/ont/swe/property/Accuracy", "label": "Accuracy", "uom": {"code": "Cel"}, "value": 0.1},
+				{"type": "QuantityRange", "name": "range", "definition": "http://sensorml.com/ont/swe/property/MeasurementRange", "label": "Range", "uom": {"code": "Cel"}, "value": [-40, 60]}
+			]
+		}]
+	}`
+
+	system, err := formatter.Deserialize(context.Background(), strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("deserialize failed: %v", err)
+	}
+	if len(system.Capabilities) != 1 || len(system.Capabilities[0].Capabilities) != 2 {
+		t.Fatalf("expected one capability group with two capabilities, got %+v", system.Capabilities)
+	}
+	if len(system.Characteristics) != 1 || len(system.Characteristics[0].Characteristics) != 1 {
+		t.Fatalf("expected one characteristic group with one characteristic, got %+v", system.Characteristics)
+	}
+
+	feature, err := formatter.Serialize(context.Background(), system)
+	if err != nil {
+		t.Fatalf("serialize failed: %v", err)
+	}
+	out, err := json.Marshal(feature)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var got, want struct {
+		Characteristics json.RawMessage `json:"characteristics"`
+		Capabilities    json.RawMessage `json:"capabilities"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal output failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(payload), &want); err != nil {
+		t.Fatalf("unmarshal payload failed: %v", err)
+	}
+	assertJSONEqual(t, want.Characteristics, got.Characteristics)
+	assertJSONEqual(t, want.Capabilities, got.Capabilities)
+}
+
+func TestSystemSensorML_ComponentsRoundTrip(t *testing.T) {
+	formatter := NewSystemSensorMLFormatter(nil)
+	payload := `{
+		"type": "PhysicalSystem",
+		"label": "Weather Station",
+		"uniqueId": "urn:system:station",
+		"components": [
+			{"name": "thermometer", "href": "https://example.org/sensors/thermo", "title": "Thermometer"},
+			{"name": "anemometer", "type": "PhysicalComponent", "label": "Anemometer", "uniqueId": "urn:system:station:anemo"}
+		],
+		"connections": [
+			{"source": "components/anemometer/outputs/speed", "destination": "outputs/windSpeed"}
+		]
+	}`
+
+	system, err := formatter.Deserialize(context.Background(), strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("deserialize failed: %v", err)
+	}
+
+	feature, err := formatter.Serialize(context.Background(), system)
+	if err != nil {
+		t.Fatalf("serialize failed: %v", err)
+	}
+
+	var want struct {
+		Components  json.RawMessage `json:"components"`
+		Connections json.RawMessage `json:"connections"`
+	}
+	if err := json.Unmarshal([]byte(payload), &want); err != nil {
+		t.Fatalf("unmarshal payload failed: %v", err)
+	}
+	assertJSONEqual(t, want.Components, feature.Components)
+	assertJSONEqual(t, want.Connections, feature.Connections)
+
+	// Atomic components cannot contain other components
+	component := "PhysicalComponent"
+	system.SMLType = &component
+	feature, err = formatter.Serialize(context.Background(), system)
+	if err != nil {
+		t.Fatalf("serialize failed: %v", err)
+	}
+	if feature.Components != nil || feature.Connections != nil {
+		t.Fatalf("expected no components on a PhysicalComponent, got %s", feature.Components)
+	}
+}
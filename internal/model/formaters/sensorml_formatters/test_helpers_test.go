@@ -1,6 +1,7 @@
 package sensorml_formatters
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
@@ -45,3 +46,19 @@ func assertHasHref(t *testing.T, links common_shared.Links, rel, href string) {
 	}
 	t.Fatalf("expected rel %q href %q in %+v", rel, href, links)
 }
+
+func assertJSONEqual(t *testing.T, want, got json.RawMessage) {
+	t.Helper()
+	var w, g interface{}
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatalf("invalid expected JSON: %v", err)
+	}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid actual JSON: %v", err)
+	}
+	wb, _ := json.Marshal(w)
+	gb, _ := json.Marshal(g)
+	if string(wb) != string(gb) {
+		t.Fatalf("JSON mismatch\nwant: %s\ngot:  %s", wb, gb)
+	}
+}
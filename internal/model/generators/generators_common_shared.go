@@ -124,7 +124,7 @@ func FakeComponentWrapper() common_shared.ComponentWrapper {
 	}
 
 	// set UOM/Constraint/NilValues for richer payloads
-	uom, _ := json.Marshal(map[string]string{"uom": "m"})
+	uom, _ := json.Marshal(map[string]string{"code": "m"})
 	constr, _ := json.Marshal(map[string]interface{}{"type": "AllowedValues", "values": []int{1, 2, 3}})
 	nilv, _ := json.Marshal([]string{})
 	cw.UOM = uom
@@ -328,7 +328,7 @@ func FakeComponentWrapperQuantity() common_shared.ComponentWrapper {
 	quantityVal := rand.Float64() * 100
 	valJSON, _ := json.Marshal(quantityVal)
 
-	uoms := []string{"m", "cm", "mm", "km", "kg", "g", "s", "Hz", "Pa", "K", "°C", "m/s", "m^2"}
+	uoms := []string{"m", "cm", "mm", "km", "kg", "g", "s", "Hz", "Pa", "K", "Cel", "m/s", "m2"}
 	uom := uoms[rand.Intn(len(uoms))]
 	uomJSON, _ := json.Marshal(map[string]string{"code": uom})
 
@@ -21,9 +21,13 @@ type CommandsQueryParams struct {
 }
 
 // BuildFromRequest parses command query parameters from request.
-func (CommandsQueryParams) BuildFromRequest(r *http.Request) *CommandsQueryParams {
+func (CommandsQueryParams) BuildFromRequest(r *http.Request) (*CommandsQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &CommandsQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if cs := r.URL.Query().Get("controlStream"); cs != "" {
@@ -52,5 +56,5 @@ func (CommandsQueryParams) BuildFromRequest(r *http.Request) *CommandsQueryParam
 		params.ExecutionTime = &tr
 	}
 
-	return params
+	return params, nil
 }
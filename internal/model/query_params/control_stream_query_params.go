@@ -20,9 +20,13 @@ type ControlStreamsQueryParams struct {
 }
 
 // BuildFromRequest parses control stream query parameters from request.
-func (ControlStreamsQueryParams) BuildFromRequest(r *http.Request) *ControlStreamsQueryParams {
+func (ControlStreamsQueryParams) BuildFromRequest(r *http.Request) (*ControlStreamsQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &ControlStreamsQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if system := r.URL.Query().Get("system"); system != "" {
@@ -47,5 +51,5 @@ func (ControlStreamsQueryParams) BuildFromRequest(r *http.Request) *ControlStrea
 		params.ExecutionTime = &tr
 	}
 
-	return params
+	return params, nil
 }
@@ -0,0 +1,334 @@
+package queryparams
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/twpayne/go-geom/encoding/wkt"
+)
+
+// FilterLangCQL2Text is the only filter-lang accepted with the filter parameter.
+const FilterLangCQL2Text = "cql2-text"
+
+// SpatialFilterProperty is the property S_INTERSECTS tests against.
+const SpatialFilterProperty = "geometry"
+
+// CQL2Expr is a node of a parsed CQL2-Text filter expression: a CQL2Logical,
+// a CQL2Comparison or a CQL2Intersects.
+//
+// Only a subset of CQL2-Text is supported:
+//   - comparisons property = | <> | < | <= | > | >= 'text', where property is
+//     one of the queryables of the resource and the literal is a single-quoted
+//     string, in which a doubled quote stands for one quote
+//   - S_INTERSECTS(geometry, <WKT geometry>) with a WGS84 WKT literal such as
+//     POINT(lon lat) or POLYGON((...))
+//   - AND and OR, with AND binding tighter, and parentheses for grouping
+//
+// Keywords are case-insensitive. Anything else (NOT, LIKE, IN, BETWEEN,
+// IS NULL, numeric or temporal literals, functions, arithmetic) is rejected.
+type CQL2Expr interface {
+	cql2Expr()
+}
+
+// CQL2Logical combines two or more expressions with AND or OR.
+type CQL2Logical struct {
+	Op   string // "AND" or "OR"
+	Args []CQL2Expr
+}
+
+// CQL2Comparison compares a property with a string literal.
+type CQL2Comparison struct {
+	Property string
+	Op       string // one of =, <>, <, <=, >, >=
+	Value    string
+}
+
+// CQL2Intersects matches resources whose geometry intersects a WKT geometry.
+type CQL2Intersects struct {
+	Property string
+	WKT      string
+}
+
+func (CQL2Logical) cql2Expr()    {}
+func (CQL2Comparison) cql2Expr() {}
+func (CQL2Intersects) cql2Expr() {}
+
+// ParseCQL2Text parses a CQL2-Text filter expression. Comparisons may only use
+// the given properties; S_INTERSECTS only applies to SpatialFilterProperty.
+func ParseCQL2Text(text string, properties ...string) (CQL2Expr, error) {
+	tokens, err := tokenizeCQL2(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("filter: empty expression")
+	}
+
+	p := &cql2Parser{text: text, tokens: tokens, properties: properties}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.unexpected()
+	}
+	return expr, nil
+}
+
+type cql2TokenKind int
+
+const (
+	cql2Word cql2TokenKind = iota
+	cql2String
+	cql2Operator
+	cql2LParen
+	cql2RParen
+	cql2Comma
+)
+
+type cql2Token struct {
+	kind       cql2TokenKind
+	value      string
+	start, end int
+}
+
+// tokenizeCQL2 splits text into words (identifiers, keywords and numbers),
+// quoted strings, comparison operators, parentheses and commas.
+func tokenizeCQL2(text string) ([]cql2Token, error) {
+	var tokens []cql2Token
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, cql2Token{cql2LParen, "(", i, i + 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, cql2Token{cql2RParen, ")", i, i + 1})
+			i++
+		case c == ',':
+			tokens = append(tokens, cql2Token{cql2Comma, ",", i, i + 1})
+			i++
+		case c == '\'':
+			var value strings.Builder
+			j := i + 1
+			for {
+				if j >= len(text) {
+					return nil, fmt.Errorf("filter: unterminated string starting at position %d", i+1)
+				}
+				if text[j] == '\'' {
+					if j+1 < len(text) && text[j+1] == '\'' {
+						value.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				value.WriteByte(text[j])
+				j++
+			}
+			tokens = append(tokens, cql2Token{cql2String, value.String(), i, j + 1})
+			i = j + 1
+		case c == '=' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(text) && (c == '<' && (text[i+1] == '=' || text[i+1] == '>') || c == '>' && text[i+1] == '=') {
+				op += string(text[i+1])
+			}
+			tokens = append(tokens, cql2Token{cql2Operator, op, i, i + len(op)})
+			i += len(op)
+		default:
+			j := i
+			for j < len(text) && !strings.ContainsRune(" \t\n\r(),'=<>", rune(text[j])) {
+				j++
+			}
+			tokens = append(tokens, cql2Token{cql2Word, text[i:j], i, j})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type cql2Parser struct {
+	text       string
+	tokens     []cql2Token
+	pos        int
+	properties []string
+}
+
+func (p *cql2Parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *cql2Parser) peek() cql2Token {
+	return p.tokens[p.pos]
+}
+
+func (p *cql2Parser) peekKeyword(keyword string) bool {
+	return !p.done() && p.peek().kind == cql2Word && strings.EqualFold(p.peek().value, keyword)
+}
+
+func (p *cql2Parser) expect(kind cql2TokenKind, what string) (cql2Token, error) {
+	if p.done() {
+		return cql2Token{}, fmt.Errorf("filter: expected %s at end of expression", what)
+	}
+	token := p.peek()
+	if token.kind != kind {
+		return cql2Token{}, fmt.Errorf("filter: expected %s at position %d, got %q", what, token.start+1, token.value)
+	}
+	p.pos++
+	return token, nil
+}
+
+func (p *cql2Parser) unexpected() error {
+	if p.done() {
+		return fmt.Errorf("filter: unexpected end of expression")
+	}
+	token := p.peek()
+	return fmt.Errorf("filter: unexpected %q at position %d", token.value, token.start+1)
+}
+
+func (p *cql2Parser) parseOr() (CQL2Expr, error) {
+	return p.parseLogical("OR", p.parseAnd)
+}
+
+func (p *cql2Parser) parseAnd() (CQL2Expr, error) {
+	return p.parseLogical("AND", p.parsePrimary)
+}
+
+// parseLogical parses operands joined by the keyword op into one CQL2Logical,
+// or returns the single operand unchanged.
+func (p *cql2Parser) parseLogical(op string, operand func() (CQL2Expr, error)) (CQL2Expr, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	args := []CQL2Expr{first}
+	for p.peekKeyword(op) {
+		p.pos++
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, next)
+	}
+	if len(args) == 1 {
+		return first, nil
+	}
+	return CQL2Logical{Op: op, Args: args}, nil
+}
+
+func (p *cql2Parser) parsePrimary() (CQL2Expr, error) {
+	if p.done() {
+		return nil, p.unexpected()
+	}
+
+	token := p.peek()
+	switch {
+	case token.kind == cql2LParen:
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(cql2RParen, `")"`); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case p.peekKeyword("S_INTERSECTS"):
+		p.pos++
+		return p.parseIntersects()
+	case token.kind == cql2Word:
+		return p.parseComparison()
+	}
+	return nil, p.unexpected()
+}
+
+func (p *cql2Parser) parseComparison() (CQL2Expr, error) {
+	property := p.tokens[p.pos]
+	p.pos++
+	if !p.isProperty(property.value) {
+		return nil, fmt.Errorf("filter: unsupported property %q, expected one of: %s", property.value, strings.Join(p.properties, ", "))
+	}
+
+	op, err := p.expect(cql2Operator, "a comparison operator (=, <>, <, <=, >, >=)")
+	if err != nil {
+		return nil, err
+	}
+	value, err := p.expect(cql2String, "a quoted string literal")
+	if err != nil {
+		return nil, err
+	}
+	return CQL2Comparison{Property: property.value, Op: op.value, Value: value.value}, nil
+}
+
+func (p *cql2Parser) parseIntersects() (CQL2Expr, error) {
+	if _, err := p.expect(cql2LParen, `"(" after S_INTERSECTS`); err != nil {
+		return nil, err
+	}
+	property, err := p.expect(cql2Word, "a property name")
+	if err != nil {
+		return nil, err
+	}
+	if property.value != SpatialFilterProperty {
+		return nil, fmt.Errorf("filter: S_INTERSECTS only applies to %q, got %q", SpatialFilterProperty, property.value)
+	}
+	if _, err := p.expect(cql2Comma, `","`); err != nil {
+		return nil, err
+	}
+
+	geometry, err := p.parseGeometryLiteral()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(cql2RParen, `")"`); err != nil {
+		return nil, err
+	}
+	return CQL2Intersects{Property: property.value, WKT: geometry}, nil
+}
+
+// parseGeometryLiteral consumes a WKT geometry, a type keyword and optional
+// dimension followed by a parenthesized coordinate list, and returns its text once it parses as WKT.
+func (p *cql2Parser) parseGeometryLiteral() (string, error) {
+	keyword, err := p.expect(cql2Word, "a WKT geometry")
+	if err != nil {
+		return "", err
+	}
+	if p.peekKeyword("Z") || p.peekKeyword("M") || p.peekKeyword("ZM") {
+		p.pos++
+	}
+	if _, err := p.expect(cql2LParen, `"(" after `+keyword.value); err != nil {
+		return "", err
+	}
+
+	depth := 1
+	for depth > 0 {
+		if p.done() {
+			return "", fmt.Errorf("filter: unterminated %s geometry", keyword.value)
+		}
+		switch p.peek().kind {
+		case cql2LParen:
+			depth++
+		case cql2RParen:
+			depth--
+		case cql2String, cql2Operator:
+			return "", p.unexpected()
+		}
+		p.pos++
+	}
+
+	literal := p.text[keyword.start:p.tokens[p.pos-1].end]
+	if _, err := wkt.Unmarshal(literal); err != nil {
+		return "", fmt.Errorf("filter: invalid WKT geometry %q: %w", literal, err)
+	}
+	return literal, nil
+}
+
+func (p *cql2Parser) isProperty(name string) bool {
+	for _, property := range p.properties {
+		if name == property {
+			return true
+		}
+	}
+	return false
+}
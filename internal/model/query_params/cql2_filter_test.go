@@ -0,0 +1,117 @@
+package queryparams
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseCQL2Text(t *testing.T) {
+	tests := map[string]struct {
+		filter string
+		want   CQL2Expr
+	}{
+		"comparison": {
+			filter: "name = 'Weather Station'",
+			want:   CQL2Comparison{Property: "name", Op: "=", Value: "Weather Station"},
+		},
+		"operators without spaces": {
+			filter: "uid<>'urn:x:1'",
+			want:   CQL2Comparison{Property: "uid", Op: "<>", Value: "urn:x:1"},
+		},
+		"escaped quote": {
+			filter: "name >= 'O''Hare'",
+			want:   CQL2Comparison{Property: "name", Op: ">=", Value: "O'Hare"},
+		},
+		"and binds tighter than or": {
+			filter: "name = 'a' or name = 'b' AND uid = 'c'",
+			want: CQL2Logical{Op: "OR", Args: []CQL2Expr{
+				CQL2Comparison{Property: "name", Op: "=", Value: "a"},
+				CQL2Logical{Op: "AND", Args: []CQL2Expr{
+					CQL2Comparison{Property: "name", Op: "=", Value: "b"},
+					CQL2Comparison{Property: "uid", Op: "=", Value: "c"},
+				}},
+			}},
+		},
+		"parentheses": {
+			filter: "(name = 'a' OR name = 'b') AND systemType = 'http://www.w3.org/ns/sosa/Sensor'",
+			want: CQL2Logical{Op: "AND", Args: []CQL2Expr{
+				CQL2Logical{Op: "OR", Args: []CQL2Expr{
+					CQL2Comparison{Property: "name", Op: "=", Value: "a"},
+					CQL2Comparison{Property: "name", Op: "=", Value: "b"},
+				}},
+				CQL2Comparison{Property: "systemType", Op: "=", Value: "http://www.w3.org/ns/sosa/Sensor"},
+			}},
+		},
+		"s_intersects": {
+			filter: "s_intersects(geometry, POLYGON((-120 30, -110 30, -110 40, -120 40, -120 30))) AND name < 'm'",
+			want: CQL2Logical{Op: "AND", Args: []CQL2Expr{
+				CQL2Intersects{Property: "geometry", WKT: "POLYGON((-120 30, -110 30, -110 40, -120 40, -120 30))"},
+				CQL2Comparison{Property: "name", Op: "<", Value: "m"},
+			}},
+		},
+		"s_intersects with z": {
+			filter: "S_INTERSECTS(geometry, POINT Z (1 2 3))",
+			want:   CQL2Intersects{Property: "geometry", WKT: "POINT Z (1 2 3)"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseCQL2Text(tc.filter, SystemFilterProperties...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseCQL2Text_Unsupported(t *testing.T) {
+	for _, filter := range []string{
+		"",
+		"description = 'x'",
+		"name = 5",
+		"name LIKE 'a%'",
+		"NOT name = 'a'",
+		"name IN ('a', 'b')",
+		"name = 'a' AND",
+		"(name = 'a'",
+		"name = 'unterminated",
+		"name = 'a' name = 'b'",
+		"S_INTERSECTS(location, POINT(1 2))",
+		"S_INTERSECTS(geometry, POINT(1))",
+		"S_INTERSECTS(geometry, BBOX(1, 2, 3, 4))",
+		"S_INTERSECTS(geometry, POINT(1 2)",
+	} {
+		t.Run(filter, func(t *testing.T) {
+			if expr, err := ParseCQL2Text(filter, SystemFilterProperties...); err == nil {
+				t.Fatalf("expected error, got %#v", expr)
+			}
+		})
+	}
+}
+
+func TestSystemQueryParams_Filter(t *testing.T) {
+	query := url.Values{"filter": {"uid = 'urn:x:1'"}, "filter-lang": {"cql2-text"}}
+	r := httptest.NewRequest("GET", "/systems?"+query.Encode(), nil)
+	params, err := SystemQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Filter != "uid = 'urn:x:1'" {
+		t.Fatalf("Filter = %q", params.Filter)
+	}
+	if want := (CQL2Comparison{Property: "uid", Op: "=", Value: "urn:x:1"}); params.FilterExpr != want {
+		t.Fatalf("FilterExpr = %#v, want %#v", params.FilterExpr, want)
+	}
+
+	query.Set("filter-lang", "cql2-json")
+	r = httptest.NewRequest("GET", "/systems?"+query.Encode(), nil)
+	if _, err := (SystemQueryParams{}).BuildFromRequest(r); err == nil {
+		t.Fatal("expected error for unsupported filter-lang")
+	}
+}
@@ -20,9 +20,13 @@ type DatastreamsQueryParams struct {
 }
 
 // BuildFromRequest parses datastream query parameters from request.
-func (DatastreamsQueryParams) BuildFromRequest(r *http.Request) *DatastreamsQueryParams {
+func (DatastreamsQueryParams) BuildFromRequest(r *http.Request) (*DatastreamsQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &DatastreamsQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if system := r.URL.Query().Get("system"); system != "" {
@@ -47,5 +51,5 @@ func (DatastreamsQueryParams) BuildFromRequest(r *http.Request) *DatastreamsQuer
 		params.ResultTime = &tr
 	}
 
-	return params
+	return params, nil
 }
@@ -10,19 +10,24 @@ import (
 type DeploymentsQueryParams struct {
 	QueryParams
 
-	DateTime           *common_shared.TimeRange
-	ObservedProperty   []string
-	ControlledProperty []string
-	Parent             []string
-	System             []string
-	Foi                []string
-	Recursive          bool
+	DateTime           *common_shared.TimeRange   `json:"dateTime,omitempty"`
+	Bbox               *common_shared.BoundingBox `json:"bbox,omitempty"`
+	ObservedProperty   []string                   `json:"observedProperty,omitempty"`
+	ControlledProperty []string                   `json:"controlledProperty,omitempty"`
+	Parent             []string                   `json:"parent,omitempty"`
+	System             []string                   `json:"system,omitempty"`
+	Foi                []string                   `json:"foi,omitempty"`
+	Recursive          bool                       `json:"recursive,omitempty"`
 }
 
 // BuildFromRequest parses common query parameters
-func (DeploymentsQueryParams) BuildFromRequest(r *http.Request) *DeploymentsQueryParams {
+func (DeploymentsQueryParams) BuildFromRequest(r *http.Request) (*DeploymentsQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &DeploymentsQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if observedProperty := r.URL.Query().Get("observedProperty"); observedProperty != "" {
@@ -49,5 +54,23 @@ func (DeploymentsQueryParams) BuildFromRequest(r *http.Request) *DeploymentsQuer
 		params.Recursive = true
 	}
 
-	return params
+	if dateVals := dateTimeValues(r.URL.Query()); len(dateVals) > 0 {
+		var tr common_shared.TimeRange
+		if len(dateVals) == 1 {
+			tr = common_shared.ToTimeRange(dateVals[0])
+		} else {
+			tr = common_shared.ToTimeRangeFromSlice(dateVals)
+		}
+		params.DateTime = &tr
+	}
+
+	if bbox := r.URL.Query().Get("bbox"); bbox != "" {
+		parsed, err := common_shared.ParseBoundingBox(bbox)
+		if err != nil {
+			return nil, err
+		}
+		params.Bbox = parsed
+	}
+
+	return params, nil
 }
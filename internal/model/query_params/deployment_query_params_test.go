@@ -0,0 +1,80 @@
+package queryparams
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+)
+
+func TestDeploymentsQueryParams_DateTimeAndBbox(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		query    string
+		wantTime *common_shared.TimeRange
+		wantBbox *common_shared.BoundingBox
+		wantErr  bool
+	}{
+		"absent": {
+			query: "",
+		},
+		"interval": {
+			query:    "dateTime=2024-01-01T00:00:00Z/2024-06-30T00:00:00Z",
+			wantTime: &common_shared.TimeRange{Start: &start, End: &end},
+		},
+		"datetime spelling": {
+			query:    "datetime=2024-01-01T00:00:00Z/2024-06-30T00:00:00Z",
+			wantTime: &common_shared.TimeRange{Start: &start, End: &end},
+		},
+		"dateTime preferred": {
+			query:    "datetime=2020-01-01T00:00:00Z/2020-02-01T00:00:00Z&dateTime=2024-01-01T00:00:00Z/2024-06-30T00:00:00Z",
+			wantTime: &common_shared.TimeRange{Start: &start, End: &end},
+		},
+		"bbox": {
+			query:    "bbox=-10,20,30,40",
+			wantBbox: &common_shared.BoundingBox{MinX: -10, MinY: 20, MaxX: 30, MaxY: 40},
+		},
+		"malformed bbox": {
+			query:   "bbox=1,2,3",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/deployments?"+tc.query, nil)
+			params, err := DeploymentsQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got params %+v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.wantTime == nil {
+				if params.DateTime != nil {
+					t.Fatalf("DateTime = %+v, want nil", params.DateTime)
+				}
+			} else {
+				if params.DateTime == nil || params.DateTime.Start == nil || params.DateTime.End == nil ||
+					!params.DateTime.Start.Equal(*tc.wantTime.Start) || !params.DateTime.End.Equal(*tc.wantTime.End) {
+					t.Fatalf("DateTime = %+v, want %+v", params.DateTime, tc.wantTime)
+				}
+			}
+
+			if tc.wantBbox == nil {
+				if params.Bbox != nil {
+					t.Fatalf("Bbox = %+v, want nil", params.Bbox)
+				}
+			} else if params.Bbox == nil || *params.Bbox != *tc.wantBbox {
+				t.Fatalf("Bbox = %+v, want %+v", params.Bbox, tc.wantBbox)
+			}
+		})
+	}
+}
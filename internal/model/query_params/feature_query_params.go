@@ -17,6 +17,10 @@ type FeatureQueryParams struct {
 
 	// Collection ID (for features within a collection)
 	CollectionID string `json:"collectionId,omitempty"`
+
+	// SortBy is SortByName or SortByCreated; SortDesc reverses the order
+	SortBy   string `json:"sortby,omitempty"`
+	SortDesc bool   `json:"sortDesc,omitempty"`
 }
 
 // TimeFilter represents a temporal filter (instant or interval)
@@ -26,12 +30,12 @@ type TimeFilter struct {
 }
 
 // BuildFromRequest parses query parameters from HTTP request
-func (FeatureQueryParams) BuildFromRequest(r *http.Request) *FeatureQueryParams {
-	params := &FeatureQueryParams{}
-	baseParams := QueryParams{}.BuildFromRequest(r)
-	if baseParams != nil {
-		params.QueryParams = *baseParams
+func (FeatureQueryParams) BuildFromRequest(r *http.Request) (*FeatureQueryParams, error) {
+	baseParams, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
 	}
+	params := &FeatureQueryParams{QueryParams: *baseParams}
 
 	// Parse bbox parameter
 	if bboxStr := r.URL.Query().Get("bbox"); bboxStr != "" {
@@ -52,7 +56,16 @@ func (FeatureQueryParams) BuildFromRequest(r *http.Request) *FeatureQueryParams
 		params.DateTime = parseDateTime(dtStr)
 	}
 
-	return params
+	if sortBy := r.URL.Query().Get("sortby"); sortBy != "" {
+		key, desc, err := ParseSortBy(sortBy, SortByName, SortByCreated)
+		if err != nil {
+			return nil, err
+		}
+		params.SortBy = key
+		params.SortDesc = desc
+	}
+
+	return params, nil
 }
 
 // parseDateTime parses OGC API datetime parameter
@@ -0,0 +1,42 @@
+package queryparams
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestFeatureQueryParams_SortBy(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		wantKey  string
+		wantDesc bool
+		wantErr  bool
+	}{
+		"absent":          {query: ""},
+		"name":            {query: "sortby=name", wantKey: SortByName},
+		"explicit ascend": {query: "sortby=" + url.QueryEscape("+created"), wantKey: SortByCreated},
+		"descending":      {query: "sortby=-name", wantKey: SortByName, wantDesc: true},
+		"unknown key":     {query: "sortby=distance", wantErr: true},
+		"bare sign":       {query: "sortby=-", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/collections/c/items?"+tc.query, nil)
+			params, err := FeatureQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got sortby %q", params.SortBy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.SortBy != tc.wantKey || params.SortDesc != tc.wantDesc {
+				t.Fatalf("sortby = (%q, desc %v), want (%q, desc %v)", params.SortBy, params.SortDesc, tc.wantKey, tc.wantDesc)
+			}
+		})
+	}
+}
@@ -21,9 +21,13 @@ type ObservationsQueryParams struct {
 }
 
 // BuildFromRequest parses observation query parameters from request.
-func (ObservationsQueryParams) BuildFromRequest(r *http.Request) *ObservationsQueryParams {
+func (ObservationsQueryParams) BuildFromRequest(r *http.Request) (*ObservationsQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &ObservationsQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if foi := r.URL.Query().Get("foi"); foi != "" {
@@ -52,5 +56,5 @@ func (ObservationsQueryParams) BuildFromRequest(r *http.Request) *ObservationsQu
 		params.ResultTime = &tr
 	}
 
-	return params
+	return params, nil
 }
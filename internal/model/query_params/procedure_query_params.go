@@ -1,25 +1,35 @@
 package queryparams
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
 )
 
 type ProceduresQueryParams struct {
 	QueryParams
 
-	DateTime *common_shared.TimeRange
+	DateTime *common_shared.TimeRange `json:"dateTime,omitempty"`
 
-	ObservedProperty   []string
-	ControlledProperty []string
+	ObservedProperty   []string `json:"observedProperty,omitempty"`
+	ControlledProperty []string `json:"controlledProperty,omitempty"`
+
+	// ProcessType restricts results to procedures of these SensorML classes
+	// (domains.ProcessTypes), OR-combined
+	ProcessType []string `json:"type,omitempty"`
 }
 
 // parseQueryParams parses common query parameters
-func (ProceduresQueryParams) BuildFromRequest(r *http.Request) *ProceduresQueryParams {
+func (ProceduresQueryParams) BuildFromRequest(r *http.Request) (*ProceduresQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &ProceduresQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if controlledProperties := r.URL.Query().Get("controlledProperty"); controlledProperties != "" {
@@ -42,5 +52,39 @@ func (ProceduresQueryParams) BuildFromRequest(r *http.Request) *ProceduresQueryP
 		params.DateTime = &tr
 	}
 
-	return params
+	if types := r.URL.Query()["type"]; len(types) > 0 {
+		processTypes, err := ParseProcessTypes(types)
+		if err != nil {
+			return nil, err
+		}
+		params.ProcessType = processTypes
+	}
+
+	return params, nil
+}
+
+// ParseProcessTypes resolves repeated and comma-separated type values to
+// SensorML process classes, matching the class names case-insensitively.
+func ParseProcessTypes(values []string) ([]string, error) {
+	var types []string
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			processType, ok := resolveProcessType(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown procedure type %q: must be one of %s", name, strings.Join(domains.ProcessTypes, ", "))
+			}
+			types = append(types, processType)
+		}
+	}
+	return types, nil
+}
+
+func resolveProcessType(name string) (string, bool) {
+	for _, processType := range domains.ProcessTypes {
+		if strings.EqualFold(name, processType) {
+			return processType, true
+		}
+	}
+	return "", false
 }
@@ -0,0 +1,50 @@
+package queryparams
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+)
+
+func TestProceduresQueryParams_ProcessType(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		"absent":           {query: ""},
+		"single":           {query: "type=PhysicalSystem", want: []string{domains.ProcessTypePhysicalSystem}},
+		"case-insensitive": {query: "type=physicalcomponent", want: []string{domains.ProcessTypePhysicalComponent}},
+		"comma-separated": {
+			query: "type=SimpleProcess,AggregateProcess",
+			want:  []string{domains.ProcessTypeSimple, domains.ProcessTypeAggregate},
+		},
+		"repeated": {
+			query: "type=SimpleProcess&type=PhysicalSystem",
+			want:  []string{domains.ProcessTypeSimple, domains.ProcessTypePhysicalSystem},
+		},
+		"unknown class": {query: "type=Sensor", wantErr: true},
+		"empty value":   {query: "type=SimpleProcess,", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/procedures?"+tc.query, nil)
+			params, err := ProceduresQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", params.ProcessType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params.ProcessType, tc.want) {
+				t.Fatalf("ProcessType = %v, want %v", params.ProcessType, tc.want)
+			}
+		})
+	}
+}
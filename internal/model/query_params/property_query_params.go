@@ -8,14 +8,18 @@ import (
 type PropertiesQueryParams struct {
 	QueryParams
 
-	BaseProperty []string
-	ObjectType   []string
+	BaseProperty []string `json:"baseProperty,omitempty"`
+	ObjectType   []string `json:"objectType,omitempty"`
 }
 
 // parseQueryParams parses common query parameters
-func (PropertiesQueryParams) BuildFromRequest(r *http.Request) *PropertiesQueryParams {
+func (PropertiesQueryParams) BuildFromRequest(r *http.Request) (*PropertiesQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &PropertiesQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if baseProps := r.URL.Query().Get("baseProperty"); baseProps != "" {
@@ -26,5 +30,5 @@ func (PropertiesQueryParams) BuildFromRequest(r *http.Request) *PropertiesQueryP
 		params.ObjectType = strings.Split(objTypes, ",")
 	}
 
-	return params
+	return params, nil
 }
@@ -1,8 +1,10 @@
 package queryparams
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -27,41 +29,137 @@ func cloneURLValues(params url.Values) url.Values {
 	return cloned
 }
 
+// splitListValues merges repeated and comma-separated values of a list
+// parameter, dropping blanks and duplicates while keeping first-seen order.
+func splitListValues(values []string) []string {
+	var out []string
+	seen := make(map[string]struct{})
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if _, ok := seen[item]; ok {
+				continue
+			}
+			seen[item] = struct{}{}
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// PropertyFilterPrefix introduces an exact-match property filter parameter,
+// as in properties.name=Weather%20Station.
+const PropertyFilterPrefix = "properties."
+
+// ParsePropertyFilters collects the properties.<name>=<value> parameters of
+// query into exact-match predicates keyed by property name. Repeated values
+// for one name are kept as alternatives; values are not split on commas. A
+// name not in allowed is an error, so only whitelisted properties ever reach
+// the database.
+func ParsePropertyFilters(query url.Values, allowed ...string) (map[string][]string, error) {
+	var filters map[string][]string
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, PropertyFilterPrefix)
+		if !ok {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("unsupported property filter %q, expected one of: %s", key, PropertyFilterPrefix+strings.Join(allowed, ", "+PropertyFilterPrefix))
+		}
+		if filters == nil {
+			filters = map[string][]string{}
+		}
+		filters[name] = append(filters[name], values...)
+	}
+	return filters, nil
+}
+
+// QueryParams holds the parameters shared by every list endpoint. The json tags
+// (here and on the resource-specific params embedding it) name the query
+// parameter each field was parsed from, so the effective values can be echoed
+// back to the client with ?echo=true.
 type QueryParams struct {
-	IDs []string
-	Q   []string // Full-text search
+	IDs []string `json:"id,omitempty"`
+	Q   []string `json:"q,omitempty"` // Full-text search
+
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"` // Not part of standard, but useful for pagination (till i do curorsors)
 
-	Limit  int
-	Offset int // Not part of standard, but useful for pagination (till i do curorsors)
+	// SkipGeometry (skipGeometry=true) returns features with a null geometry
+	SkipGeometry bool `json:"skipGeometry,omitempty"`
+
+	// Echo (echo=true) asks for the effective parameters in the response
+	Echo bool `json:"-"`
 }
 
-func (QueryParams) BuildFromRequest(r *http.Request) *QueryParams {
+// BuildFromRequest parses the shared list parameters. A limit or offset that
+// is not a non-negative integer is an *InvalidParameterError.
+func (QueryParams) BuildFromRequest(r *http.Request) (*QueryParams, error) {
 	params := &QueryParams{
 		Limit:  10,
 		Offset: 0,
 	}
 
 	if limit := r.URL.Query().Get("limit"); limit != "" {
-		if val, err := strconv.Atoi(limit); err == nil {
-			params.Limit = val
+		val, err := parseNonNegativeInt("limit", limit)
+		if err != nil {
+			return nil, err
 		}
+		params.Limit = val
 	}
 
 	if offset := r.URL.Query().Get("offset"); offset != "" {
-		if val, err := strconv.Atoi(offset); err == nil {
-			params.Offset = val
+		val, err := parseNonNegativeInt("offset", offset)
+		if err != nil {
+			return nil, err
 		}
+		params.Offset = val
 	}
 
-	if ids := r.URL.Query().Get("id"); ids != "" {
-		params.IDs = strings.Split(ids, ",")
+	// Accept both ?id=a&id=b and ?id=a,b (and mixtures of the two)
+	if ids := splitListValues(r.URL.Query()["id"]); len(ids) > 0 {
+		params.IDs = ids
+	}
+
+	if skip := r.URL.Query().Get("skipGeometry"); skip != "" {
+		if val, err := strconv.ParseBool(skip); err == nil {
+			params.SkipGeometry = val
+		}
+	}
+
+	if echo := r.URL.Query().Get("echo"); echo != "" {
+		if val, err := strconv.ParseBool(echo); err == nil {
+			params.Echo = val
+		}
 	}
 
 	if queries := r.URL.Query().Get("q"); queries != "" {
 		params.Q = strings.Split(queries, ",")
 	}
 
-	return params
+	return params, nil
+}
+
+// InvalidParameterError reports a query parameter whose value cannot be used.
+type InvalidParameterError struct {
+	Name   string
+	Value  string
+	Reason string
+}
+
+func (e *InvalidParameterError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Name, e.Value, e.Reason)
+}
+
+func parseNonNegativeInt(name, value string) (int, error) {
+	val, err := strconv.Atoi(value)
+	if err != nil || val < 0 {
+		return 0, &InvalidParameterError{Name: name, Value: value, Reason: "must be a non-negative integer"}
+	}
+	return val, nil
 }
 
 func (qp *QueryParams) BuildPagintationLinks(baseURL string, params url.Values, total *int, returned int) common_shared.Links {
@@ -89,11 +187,18 @@ func (qp *QueryParams) BuildPagintationLinks(baseURL string, params url.Values,
 	}
 
 	if currentOffset > 0 {
+		prevOffset := currentOffset - qp.Limit
+		// Paging past the end yields an empty page; point prev at the last
+		// page that has results rather than at another empty one.
+		if currentOffset >= *total {
+			prevOffset = *total - qp.Limit
+		}
+
 		prevLink := cloneURLValues(params)
-		if currentOffset-qp.Limit <= 0 {
+		if prevOffset <= 0 {
 			prevLink.Del("offset")
 		} else {
-			prevLink.Set("offset", strconv.Itoa(currentOffset-qp.Limit))
+			prevLink.Set("offset", strconv.Itoa(prevOffset))
 		}
 
 		links = append(links, common_shared.Link{
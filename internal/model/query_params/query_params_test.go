@@ -1,7 +1,10 @@
 package queryparams
 
 import (
+	"errors"
+	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -60,3 +63,179 @@ func TestBuildPagintationLinks_WithQueryBuildsNextAndPrev(t *testing.T) {
 		t.Fatalf("expected original params offset to remain unchanged, got %q", params.Get("offset"))
 	}
 }
+
+func TestBuildPagintationLinks_OffsetBeyondTotal(t *testing.T) {
+	tests := map[string]struct {
+		total    int
+		offset   string
+		wantPrev string
+	}{
+		"past the last page":   {total: 35, offset: "100", wantPrev: "http://localhost:8080/systems?limit=10&offset=25"},
+		"exactly at the total": {total: 35, offset: "35", wantPrev: "http://localhost:8080/systems?limit=10&offset=25"},
+		"fewer than a page":    {total: 4, offset: "50", wantPrev: "http://localhost:8080/systems?limit=10"},
+		"empty collection":     {total: 0, offset: "10", wantPrev: "http://localhost:8080/systems?limit=10"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			qp := &QueryParams{Limit: 10}
+			params := url.Values{}
+			params.Set("limit", "10")
+			params.Set("offset", tt.offset)
+			total := tt.total
+
+			links := qp.BuildPagintationLinks("http://localhost:8080/systems", params, &total, 0)
+
+			var prev string
+			for _, link := range links {
+				if link.Rel == "next" {
+					t.Fatalf("expected no next link past the end, got %q", link.Href)
+				}
+				if link.Rel == "prev" {
+					prev = link.Href
+				}
+			}
+			if prev != tt.wantPrev {
+				t.Fatalf("expected prev %q, got %q", tt.wantPrev, prev)
+			}
+		})
+	}
+}
+
+func TestQueryParams_IDs(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  []string
+	}{
+		"absent":            {query: "", want: nil},
+		"single":            {query: "id=a", want: []string{"a"}},
+		"comma separated":   {query: "id=a,b", want: []string{"a", "b"}},
+		"repeated":          {query: "id=a&id=b", want: []string{"a", "b"}},
+		"repeated and csv":  {query: "id=a,b&id=c", want: []string{"a", "b", "c"}},
+		"duplicates merged": {query: "id=a,b&id=b&id=a", want: []string{"a", "b"}},
+		"blanks ignored":    {query: "id=a,,%20b%20&id=", want: []string{"a", "b"}},
+		"uids with colons":  {query: "id=urn:x:1,urn:x:2", want: []string{"urn:x:1", "urn:x:2"}},
+		"only blank values": {query: "id=,&id=", want: nil},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/systems?"+tt.query, nil)
+			params, err := QueryParams{}.BuildFromRequest(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params.IDs, tt.want) {
+				t.Fatalf("expected IDs %v, got %v", tt.want, params.IDs)
+			}
+		})
+	}
+}
+
+func TestQueryParams_SkipGeometry(t *testing.T) {
+	tests := map[string]bool{
+		"":                   false,
+		"skipGeometry=true":  true,
+		"skipGeometry=TRUE":  true,
+		"skipGeometry=false": false,
+		"skipGeometry=maybe": false,
+	}
+
+	for query, want := range tests {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/systems?"+query, nil)
+			params, err := QueryParams{}.BuildFromRequest(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := params.SkipGeometry; got != want {
+				t.Fatalf("expected SkipGeometry=%v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestQueryParams_Echo(t *testing.T) {
+	tests := map[string]bool{
+		"":           false,
+		"echo=true":  true,
+		"echo=1":     true,
+		"echo=false": false,
+		"echo=maybe": false,
+	}
+
+	for query, want := range tests {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/systems?"+query, nil)
+			params, err := QueryParams{}.BuildFromRequest(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := params.Echo; got != want {
+				t.Fatalf("expected Echo=%v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestQueryParams_Pagination(t *testing.T) {
+	tests := map[string]struct {
+		query      string
+		wantLimit  int
+		wantOffset int
+		wantErr    string
+	}{
+		"defaults":           {query: "", wantLimit: 10},
+		"explicit":           {query: "limit=25&offset=50", wantLimit: 25, wantOffset: 50},
+		"zero":               {query: "limit=0&offset=0", wantLimit: 0},
+		"non-integer limit":  {query: "limit=abc", wantErr: "limit"},
+		"fractional limit":   {query: "limit=2.5", wantErr: "limit"},
+		"negative limit":     {query: "limit=-5", wantErr: "limit"},
+		"non-integer offset": {query: "offset=ten", wantErr: "offset"},
+		"negative offset":    {query: "offset=-1", wantErr: "offset"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/systems?"+tt.query, nil)
+			params, err := QueryParams{}.BuildFromRequest(req)
+			if tt.wantErr != "" {
+				var invalid *InvalidParameterError
+				if !errors.As(err, &invalid) || invalid.Name != tt.wantErr {
+					t.Fatalf("expected invalid %s error, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.Limit != tt.wantLimit || params.Offset != tt.wantOffset {
+				t.Fatalf("expected limit=%d offset=%d, got limit=%d offset=%d", tt.wantLimit, tt.wantOffset, params.Limit, params.Offset)
+			}
+		})
+	}
+}
+
+func TestDateTimeValues(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  []string
+	}{
+		"dateTime":           {query: "dateTime=2024-01-01T00:00:00Z", want: []string{"2024-01-01T00:00:00Z"}},
+		"datetime":           {query: "datetime=2024-01-01T00:00:00Z/..", want: []string{"2024-01-01T00:00:00Z/.."}},
+		"repeated":           {query: "datetime=..&datetime=2024-01-01T00:00:00Z", want: []string{"..", "2024-01-01T00:00:00Z"}},
+		"dateTime preferred": {query: "datetime=a&dateTime=b", want: []string{"b"}},
+		"absent":             {query: "limit=10"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := dateTimeValues(q); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dateTimeValues(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
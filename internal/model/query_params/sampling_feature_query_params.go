@@ -1,28 +1,101 @@
 package queryparams
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/twpayne/go-geom/encoding/wkt"
 	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
 )
 
 type SamplingFeatureQueryParams struct {
 	QueryParams
 
-	Geom string // WKT geometry
+	Geom string `json:"geom,omitempty"` // WKT geometry
 
-	DateTime *common_shared.TimeRange
-	Bbox     *common_shared.BoundingBox
+	DateTime *common_shared.TimeRange   `json:"dateTime,omitempty"`
+	Bbox     *common_shared.BoundingBox `json:"bbox,omitempty"`
 
-	ObservedProperty   []string
-	ControlledProperty []string
-	FOI                []string
+	ObservedProperty   []string `json:"observedProperty,omitempty"`
+	ControlledProperty []string `json:"controlledProperty,omitempty"`
+	FOI                []string `json:"foi,omitempty"`
+
+	// System restricts results to sampling features whose parent system is one of these ids
+	System []string `json:"system,omitempty"`
+
+	// FeatureType restricts results to sampling features whose featureType is one of these URIs
+	FeatureType []string `json:"featureType,omitempty"`
+
+	// SortBy is SortByName or SortByCreated; SortDesc reverses the order
+	SortBy   string `json:"sortby,omitempty"`
+	SortDesc bool   `json:"sortDesc,omitempty"`
+
+	// Computed lists the geometry measures (ComputedArea, ComputedLength) to
+	// add to each returned feature
+	Computed []string `json:"computed,omitempty"`
+}
+
+// Geometry measures accepted by computed on sampling feature listings
+const (
+	// ComputedArea adds area_m2, the geodesic area of polygonal geometries
+	ComputedArea = "area"
+	// ComputedLength adds length_m, the geodesic length of linear geometries
+	ComputedLength = "length"
+)
+
+// Computes reports whether measure was requested with computed.
+func (p *SamplingFeatureQueryParams) Computes(measure string) bool {
+	return slices.Contains(p.Computed, measure)
+}
+
+// resourceIDPattern matches the characters a resource id may contain in a URL path
+var resourceIDPattern = regexp.MustCompile(`^[A-Za-z0-9._~-]+$`)
+
+// parseResourceIDs splits repeated and comma-separated id values, rejecting
+// empty or malformed entries.
+func parseResourceIDs(name string, values []string) ([]string, error) {
+	var ids []string
+	for _, value := range values {
+		for _, id := range strings.Split(value, ",") {
+			id = strings.TrimSpace(id)
+			if !resourceIDPattern.MatchString(id) {
+				return nil, fmt.Errorf("invalid %s id %q", name, id)
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// parseURIs splits repeated and comma-separated values, requiring each entry
+// to be an absolute URI such as
+// http://www.opengis.net/def/samplingFeatureType/OGC-OM/2.0/SF_SamplingPoint.
+func parseURIs(name string, values []string) ([]string, error) {
+	var uris []string
+	for _, value := range values {
+		for _, uri := range strings.Split(value, ",") {
+			uri = strings.TrimSpace(uri)
+			parsed, err := url.Parse(uri)
+			if err != nil || !parsed.IsAbs() || (parsed.Host == "" && parsed.Opaque == "" && parsed.Path == "") {
+				return nil, fmt.Errorf("invalid %s URI %q", name, uri)
+			}
+			uris = append(uris, uri)
+		}
+	}
+	return uris, nil
 }
 
 func (SamplingFeatureQueryParams) BuildFromRequest(r *http.Request) (*SamplingFeatureQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &SamplingFeatureQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if controlledProperty := r.URL.Query().Get("controlledProperty"); controlledProperty != "" {
@@ -33,15 +106,59 @@ func (SamplingFeatureQueryParams) BuildFromRequest(r *http.Request) (*SamplingFe
 		params.ObservedProperty = strings.Split(observedProperty, ",")
 	}
 
-	// dateTime may be provided either as a single value or as repeated params
-	if dateVals := r.URL.Query()["dateTime"]; len(dateVals) > 0 {
-		var tr common_shared.TimeRange
-		if len(dateVals) == 1 {
-			tr = common_shared.ToTimeRange(dateVals[0])
-		} else {
-			tr = common_shared.ToTimeRangeFromSlice(dateVals)
+	// dateTime is parsed strictly, because a value that silently filtered
+	// nothing would widen a bulk delete to every sampling feature.
+	params.DateTime, err = parseTimeFilter("dateTime", dateTimeValues(r.URL.Query()))
+	if err != nil {
+		return nil, err
+	}
+
+	if systems := r.URL.Query()["system"]; len(systems) > 0 {
+		ids, err := parseResourceIDs("system", systems)
+		if err != nil {
+			return nil, err
+		}
+		params.System = ids
+	}
+
+	if featureTypes := r.URL.Query()["featureType"]; len(featureTypes) > 0 {
+		uris, err := parseURIs("featureType", featureTypes)
+		if err != nil {
+			return nil, err
+		}
+		params.FeatureType = uris
+	}
+
+	if geom := r.URL.Query().Get("geom"); geom != "" {
+		// Reject malformed WKT here rather than letting PostGIS fail the query
+		if _, err := wkt.Unmarshal(geom); err != nil {
+			return nil, fmt.Errorf("invalid geom WKT %q: %w", geom, err)
+		}
+		params.Geom = geom
+	}
+
+	if bbox := r.URL.Query().Get("bbox"); bbox != "" {
+		parsed, err := common_shared.ParseBoundingBox(bbox)
+		if err != nil {
+			return nil, err
+		}
+		params.Bbox = parsed
+	}
+
+	for _, measure := range splitListValues(r.URL.Query()["computed"]) {
+		if measure != ComputedArea && measure != ComputedLength {
+			return nil, fmt.Errorf("unsupported computed %q, expected one of: %s, %s", measure, ComputedArea, ComputedLength)
+		}
+		params.Computed = append(params.Computed, measure)
+	}
+
+	if sortBy := r.URL.Query().Get("sortby"); sortBy != "" {
+		key, desc, err := ParseSortBy(sortBy, SortByName, SortByCreated)
+		if err != nil {
+			return nil, err
 		}
-		params.DateTime = &tr
+		params.SortBy = key
+		params.SortDesc = desc
 	}
 
 	return params, nil
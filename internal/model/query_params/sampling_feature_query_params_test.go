@@ -0,0 +1,313 @@
+package queryparams
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSamplingFeatureQueryParams_System(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		"absent": {
+			query: "",
+		},
+		"single": {
+			query: "system=abc-123",
+			want:  []string{"abc-123"},
+		},
+		"repeated and comma separated": {
+			query: "system=a1,b2&system=c3",
+			want:  []string{"a1", "b2", "c3"},
+		},
+		"empty value": {
+			query:   "system=",
+			wantErr: true,
+		},
+		"empty list entry": {
+			query:   "system=a1,,b2",
+			wantErr: true,
+		},
+		"malformed": {
+			query:   "system=a%20b",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/samplingFeatures?"+tc.query, nil)
+			params, err := SamplingFeatureQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got params %+v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(params.System) != len(tc.want) {
+				t.Fatalf("System = %v, want %v", params.System, tc.want)
+			}
+			for i := range tc.want {
+				if params.System[i] != tc.want[i] {
+					t.Fatalf("System = %v, want %v", params.System, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSamplingFeatureQueryParams_FeatureType(t *testing.T) {
+	const (
+		point = "http://www.opengis.net/def/samplingFeatureType/OGC-OM/2.0/SF_SamplingPoint"
+		curve = "http://www.opengis.net/def/samplingFeatureType/OGC-OM/2.0/SF_SamplingCurve"
+	)
+
+	tests := map[string]struct {
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		"absent": {
+			query: "",
+		},
+		"single": {
+			query: "featureType=" + url.QueryEscape(point),
+			want:  []string{point},
+		},
+		"repeated": {
+			query: "featureType=" + url.QueryEscape(point) + "&featureType=" + url.QueryEscape(curve),
+			want:  []string{point, curve},
+		},
+		"comma separated": {
+			query: "featureType=" + url.QueryEscape(point+","+curve),
+			want:  []string{point, curve},
+		},
+		"urn": {
+			query: "featureType=urn:ogc:def:sf:point",
+			want:  []string{"urn:ogc:def:sf:point"},
+		},
+		"bare name": {
+			query:   "featureType=SF_SamplingPoint",
+			wantErr: true,
+		},
+		"empty": {
+			query:   "featureType=",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/samplingFeatures?"+tc.query, nil)
+			params, err := SamplingFeatureQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got params %+v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params.FeatureType, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, params.FeatureType)
+			}
+		})
+	}
+}
+
+func TestSamplingFeatureQueryParams_SortBy(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		wantKey  string
+		wantDesc bool
+		wantErr  bool
+	}{
+		"absent":      {query: ""},
+		"created":     {query: "sortby=created", wantKey: SortByCreated},
+		"descending":  {query: "sortby=-name", wantKey: SortByName, wantDesc: true},
+		"unknown key": {query: "sortby=featureType", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/samplingFeatures?"+tc.query, nil)
+			params, err := SamplingFeatureQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got sortby %q", params.SortBy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.SortBy != tc.wantKey || params.SortDesc != tc.wantDesc {
+				t.Fatalf("sortby = (%q, desc %v), want (%q, desc %v)", params.SortBy, params.SortDesc, tc.wantKey, tc.wantDesc)
+			}
+		})
+	}
+}
+
+func TestSamplingFeatureQueryParams_Computed(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		"absent":   {query: ""},
+		"single":   {query: "computed=area", want: []string{ComputedArea}},
+		"both":     {query: "computed=area,length", want: []string{ComputedArea, ComputedLength}},
+		"repeated": {query: "computed=length&computed=area&computed=length", want: []string{ComputedLength, ComputedArea}},
+		"unknown":  {query: "computed=volume", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/samplingFeatures?"+tc.query, nil)
+			params, err := SamplingFeatureQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got params %+v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params.Computed, tc.want) {
+				t.Fatalf("Computed = %v, want %v", params.Computed, tc.want)
+			}
+		})
+	}
+}
+
+func TestSamplingFeatureQueryParams_Geom(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		"absent":  {query: ""},
+		"polygon": {query: "geom=" + url.QueryEscape("POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))"), want: "POLYGON((0 0, 4 0, 4 4, 0 4, 0 0))"},
+		"invalid": {query: "geom=" + url.QueryEscape("POLYGON((0 0, 4 0"), wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/samplingFeatures?"+tc.query, nil)
+			params, err := SamplingFeatureQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got params %+v", params)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.Geom != tc.want {
+				t.Fatalf("Geom = %q, want %q", params.Geom, tc.want)
+			}
+		})
+	}
+}
+
+func TestSamplingFeatureQueryParams_DateTime(t *testing.T) {
+	tests := map[string]struct {
+		query     string
+		wantStart string
+		wantEnd   string
+	}{
+		"dateTime":           {query: "dateTime=2024-01-01T00:00:00Z/2024-02-01T00:00:00Z", wantStart: "2024-01-01T00:00:00Z", wantEnd: "2024-02-01T00:00:00Z"},
+		"datetime":           {query: "datetime=2024-01-01T00:00:00Z/2024-02-01T00:00:00Z", wantStart: "2024-01-01T00:00:00Z", wantEnd: "2024-02-01T00:00:00Z"},
+		"dateTime preferred": {query: "datetime=2020-01-01T00:00:00Z/..&dateTime=2024-01-01T00:00:00Z/..", wantStart: "2024-01-01T00:00:00Z"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/samplingFeatures?"+tc.query, nil)
+			params, err := SamplingFeatureQueryParams{}.BuildFromRequest(r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.DateTime == nil {
+				t.Fatal("DateTime is nil")
+			}
+			if got := formatOptionalTime(params.DateTime.Start); got != tc.wantStart {
+				t.Fatalf("DateTime.Start = %q, want %q", got, tc.wantStart)
+			}
+			if got := formatOptionalTime(params.DateTime.End); got != tc.wantEnd {
+				t.Fatalf("DateTime.End = %q, want %q", got, tc.wantEnd)
+			}
+		})
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func TestSamplingFeatureQueryParams_DateTimeStrict(t *testing.T) {
+	tests := map[string]struct {
+		query     string
+		wantErr   bool
+		wantNil   bool
+		wantStart string
+		wantEnd   string
+	}{
+		"instant":          {query: "dateTime=2024-01-01T00:00:00Z", wantStart: "2024-01-01T00:00:00Z", wantEnd: "2024-01-01T00:00:00Z"},
+		"half open":        {query: "dateTime=../2024-02-01T00:00:00Z", wantEnd: "2024-02-01T00:00:00Z"},
+		"repeated":         {query: "dateTime=2024-01-01T00:00:00Z&dateTime=..", wantStart: "2024-01-01T00:00:00Z"},
+		"fully open":       {query: "dateTime=../..", wantNil: true},
+		"open":             {query: "datetime=..", wantNil: true},
+		"garbage":          {query: "dateTime=garbage", wantErr: true},
+		"garbage bound":    {query: "datetime=garbage/..", wantErr: true},
+		"too many bounds":  {query: "dateTime=2024-01-01T00:00:00Z/../..", wantErr: true},
+		"garbage repeated": {query: "dateTime=2024-01-01T00:00:00Z&dateTime=tomorrow", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/samplingFeatures?"+tc.query, nil)
+			params, err := SamplingFeatureQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				var invalid *InvalidParameterError
+				if !errors.As(err, &invalid) {
+					t.Fatalf("err = %v, want an *InvalidParameterError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantNil {
+				if params.DateTime != nil {
+					t.Fatalf("DateTime = %+v, want nil", params.DateTime)
+				}
+				return
+			}
+			if params.DateTime == nil {
+				t.Fatal("DateTime is nil")
+			}
+			if got := formatOptionalTime(params.DateTime.Start); got != tc.wantStart {
+				t.Fatalf("DateTime.Start = %q, want %q", got, tc.wantStart)
+			}
+			if got := formatOptionalTime(params.DateTime.End); got != tc.wantEnd {
+				t.Fatalf("DateTime.End = %q, want %q", got, tc.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package queryparams
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sort keys accepted by sortby on feature and sampling feature listings
+const (
+	SortByName    = "name"
+	SortByCreated = "created"
+)
+
+// ParseSortBy parses a sortby value of the form [+|-]key, where key must be one
+// of allowed. It returns the key and whether a "-" prefix asked for descending
+// order.
+func ParseSortBy(value string, allowed ...string) (string, bool, error) {
+	value = strings.TrimSpace(value)
+	desc := false
+	switch {
+	case strings.HasPrefix(value, "-"):
+		desc = true
+		value = value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	for _, key := range allowed {
+		if value == key {
+			return key, desc, nil
+		}
+	}
+	return "", false, fmt.Errorf("unsupported sortby %q, expected one of: %s", value, strings.Join(allowed, ", "))
+}
@@ -17,9 +17,13 @@ type SystemEventsQueryParams struct {
 	System    []string
 }
 
-func (SystemEventsQueryParams) BuildFromRequest(r *http.Request) *SystemEventsQueryParams {
+func (SystemEventsQueryParams) BuildFromRequest(r *http.Request) (*SystemEventsQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &SystemEventsQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if vals := r.URL.Query()["datetime"]; len(vals) > 0 {
@@ -42,5 +46,5 @@ func (SystemEventsQueryParams) BuildFromRequest(r *http.Request) *SystemEventsQu
 		params.System = strings.Split(system, ",")
 	}
 
-	return params
+	return params, nil
 }
@@ -11,13 +11,17 @@ import (
 type SystemHistoryQueryParams struct {
 	QueryParams
 
-	ValidTime *common_shared.TimeRange
-	Keyword   []string
+	ValidTime *common_shared.TimeRange `json:"validTime,omitempty"`
+	Keyword   []string                 `json:"keyword,omitempty"`
 }
 
-func (SystemHistoryQueryParams) BuildFromRequest(r *http.Request) *SystemHistoryQueryParams {
+func (SystemHistoryQueryParams) BuildFromRequest(r *http.Request) (*SystemHistoryQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &SystemHistoryQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	if vals := r.URL.Query()["validTime"]; len(vals) > 0 {
@@ -29,5 +33,5 @@ func (SystemHistoryQueryParams) BuildFromRequest(r *http.Request) *SystemHistory
 		params.Keyword = strings.Split(keyword, ",")
 	}
 
-	return params
+	return params, nil
 }
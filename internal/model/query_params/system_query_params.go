@@ -1,39 +1,176 @@
 package queryparams
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/twpayne/go-geom/encoding/wkt"
 	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
 )
 
 type SystemQueryParams struct {
 	QueryParams
 
-	Bbox               *common_shared.BoundingBox
-	Datetime           *common_shared.TimeRange
-	Geom               string // WKT geometry
-	Parent             []string
-	Procedure          []string
-	FOI                []string
-	ObservedProperty   []string
-	ControlledProperty []string
-	Recursive          bool
+	Bbox               *common_shared.BoundingBox `json:"bbox,omitempty"`
+	Datetime           *common_shared.TimeRange   `json:"dateTime,omitempty"`
+	DatetimeOp         string                     `json:"datetimeOp,omitempty"`        // how Datetime is compared with validTime; see DatetimeOp* constants
+	ValidTimeContains  *time.Time                 `json:"validTimeContains,omitempty"` // instant validTime must contain
+	Geom               string                     `json:"geom,omitempty"`              // WKT geometry
+	Parent             []string                   `json:"parent,omitempty"`
+	RootOnly           bool                       `json:"rootOnly,omitempty"` // parent=null or rootOnly=true: systems without a parent
+	Procedure          []string                   `json:"procedure,omitempty"`
+	FOI                []string                   `json:"foi,omitempty"`
+	ObservedProperty   []string                   `json:"observedProperty,omitempty"`
+	ControlledProperty []string                   `json:"controlledProperty,omitempty"`
+	SystemType         []string                   `json:"systemType,omitempty"` // system type URIs, OR-combined
+	AssetType          []string                   `json:"assetType,omitempty"`  // asset types, OR-combined
+	SystemKind         []string                   `json:"systemKind,omitempty"` // system kind procedure ids or uids, OR-combined
+	Recursive          bool                       `json:"recursive,omitempty"`
+
+	// Properties holds properties.<name>=<value> exact-match filters, keyed by
+	// one of SystemPropertyFilterNames. Values for one name are OR-combined.
+	Properties map[string][]string `json:"properties,omitempty"`
+
+	// Filter is the CQL2-Text filter expression as given; FilterExpr is its
+	// parsed form. See CQL2Expr for the supported subset.
+	Filter     string   `json:"filter,omitempty"`
+	FilterExpr CQL2Expr `json:"-"`
+
+	SortBy string     `json:"sortby,omitempty"`
+	Near   *NearPoint `json:"near,omitempty"`   // reference point for sortby=distance and radius
+	Radius *float64   `json:"radius,omitempty"` // meters from Near; only systems within it match
+}
+
+// NearPoint is a WGS84 lon/lat reference point parsed from near=POINT(lon lat)
+type NearPoint struct {
+	Lon float64 `json:"lon"`
+	Lat float64 `json:"lat"`
+}
+
+// SortByDistance orders results by distance from the near point
+const SortByDistance = "distance"
+
+// Datetime operators select how the requested datetime interval is compared
+// with a resource's validTime.
+const (
+	// DatetimeOpIntersects matches resources valid at some point within the interval
+	DatetimeOpIntersects = "intersects"
+	// DatetimeOpContains matches resources valid for the entire interval
+	DatetimeOpContains = "contains"
+	// DatetimeOpDuring matches resources whose validTime lies within the interval
+	DatetimeOpDuring = "during"
+)
+
+// SystemFilterProperties are the properties a systems filter expression may
+// compare: name, systemType (the full system type URI) and uid.
+var SystemFilterProperties = []string{"name", "systemType", "uid"}
+
+// SystemPropertyFilterNames are the top-level string properties systems can
+// be filtered on with properties.<name>=<value>.
+var SystemPropertyFilterNames = []string{"uid", "name", "description", "featureType", "assetType", "lang"}
+
+// knownSystemTypes are the system types accepted by the systemType filter
+var knownSystemTypes = []string{
+	domains.SystemTypeSensor,
+	domains.SystemTypeActuator,
+	domains.SystemTypeSampler,
+	domains.SystemTypePlatform,
+	domains.SystemTypeSystem,
 }
 
-func (SystemQueryParams) BuildFromRequest(r *http.Request) *SystemQueryParams {
+// ParseSystemTypes resolves repeated and comma-separated systemType values to
+// system type URIs. Each value may be the full URI, a sosa:/ssn: CURIE or the
+// bare type name (case-insensitive), e.g. "sensor".
+func ParseSystemTypes(values []string) ([]string, error) {
+	var types []string
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			systemType, ok := resolveSystemType(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown systemType %q", name)
+			}
+			types = append(types, systemType)
+		}
+	}
+	return types, nil
+}
+
+func resolveSystemType(name string) (string, bool) {
+	short := name
+	if i := strings.LastIndex(short, ":"); i >= 0 && !strings.Contains(short, "/") {
+		short = short[i+1:]
+	}
+	for _, systemType := range knownSystemTypes {
+		if name == systemType || strings.EqualFold(short, systemType[strings.LastIndex(systemType, "/")+1:]) {
+			return systemType, true
+		}
+	}
+	return "", false
+}
+
+var nearPointPattern = regexp.MustCompile(`(?i)^\s*POINT\s*\(\s*(\S+)\s+(\S+)\s*\)\s*$`)
+
+// ParseNearPoint parses a WKT POINT(lon lat) into a NearPoint
+func ParseNearPoint(value string) (*NearPoint, error) {
+	m := nearPointPattern.FindStringSubmatch(value)
+	if m == nil {
+		return nil, fmt.Errorf("near must be a WKT point like POINT(lon lat), got %q", value)
+	}
+
+	lon, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid near longitude %q", m[1])
+	}
+	lat, err := strconv.ParseFloat(m[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid near latitude %q", m[2])
+	}
+
+	if lon < -180 || lon > 180 || lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("near point %q is outside WGS84 bounds", value)
+	}
+
+	return &NearPoint{Lon: lon, Lat: lat}, nil
+}
+
+func (SystemQueryParams) BuildFromRequest(r *http.Request) (*SystemQueryParams, error) {
+	base, err := QueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	params := &SystemQueryParams{
-		QueryParams: *QueryParams{}.BuildFromRequest(r),
+		QueryParams: *base,
 	}
 
 	params.Recursive = r.URL.Query().Get("recursive") == "true"
 
 	if parent := r.URL.Query().Get("parent"); parent != "" {
-		params.Parent = strings.Split(parent, ",")
+		// "null" stands for "no parent", so parent=null lists only root systems
+		for _, id := range strings.Split(parent, ",") {
+			if strings.EqualFold(strings.TrimSpace(id), "null") {
+				params.RootOnly = true
+				continue
+			}
+			params.Parent = append(params.Parent, id)
+		}
+	}
+	if rootOnly := r.URL.Query().Get("rootOnly"); rootOnly != "" {
+		val, err := strconv.ParseBool(rootOnly)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rootOnly %q: must be true or false", rootOnly)
+		}
+		params.RootOnly = params.RootOnly || val
 	}
 
-	// dateTime may be supplied as a single string or as repeated parameters
-	if dateVals := r.URL.Query()["dateTime"]; len(dateVals) > 0 {
+	if dateVals := dateTimeValues(r.URL.Query()); len(dateVals) > 0 {
 		var tr common_shared.TimeRange
 		if len(dateVals) == 1 {
 			tr = common_shared.ToTimeRange(dateVals[0])
@@ -43,6 +180,27 @@ func (SystemQueryParams) BuildFromRequest(r *http.Request) *SystemQueryParams {
 		params.Datetime = &tr
 	}
 
+	params.DatetimeOp = DatetimeOpIntersects
+	if op := r.URL.Query().Get("datetimeOp"); op != "" {
+		switch op {
+		case DatetimeOpIntersects, DatetimeOpContains, DatetimeOpDuring:
+			params.DatetimeOp = op
+		default:
+			return nil, fmt.Errorf("datetimeOp must be one of: intersects, contains, during")
+		}
+	}
+
+	// validTimeContains is the single-instant shorthand for a degenerate
+	// datetime interval
+	if instant := r.URL.Query().Get("validTimeContains"); instant != "" {
+		t, err := time.Parse(time.RFC3339, instant)
+		if err != nil {
+			return nil, &InvalidParameterError{Name: "validTimeContains", Value: instant, Reason: "must be an RFC 3339 instant"}
+		}
+		t = t.UTC()
+		params.ValidTimeContains = &t
+	}
+
 	if procedure := r.URL.Query().Get("procedure"); procedure != "" {
 		params.Procedure = strings.Split(procedure, ",")
 	}
@@ -59,9 +217,85 @@ func (SystemQueryParams) BuildFromRequest(r *http.Request) *SystemQueryParams {
 		params.ControlledProperty = strings.Split(controlledProperty, ",")
 	}
 
+	if systemTypes := r.URL.Query()["systemType"]; len(systemTypes) > 0 {
+		types, err := ParseSystemTypes(systemTypes)
+		if err != nil {
+			return nil, err
+		}
+		params.SystemType = types
+	}
+
+	// Accept both ?assetType=a&assetType=b and ?assetType=a,b
+	if assetTypes := splitListValues(r.URL.Query()["assetType"]); len(assetTypes) > 0 {
+		params.AssetType = assetTypes
+	}
+
+	if systemKinds := splitListValues(r.URL.Query()["systemKind"]); len(systemKinds) > 0 {
+		params.SystemKind = systemKinds
+	}
+
+	properties, err := ParsePropertyFilters(r.URL.Query(), SystemPropertyFilterNames...)
+	if err != nil {
+		return nil, err
+	}
+	params.Properties = properties
+
 	if geom := r.URL.Query().Get("geom"); geom != "" {
+		// Reject malformed WKT here rather than letting PostGIS fail the query
+		if _, err := wkt.Unmarshal(geom); err != nil {
+			return nil, fmt.Errorf("invalid geom WKT %q: %w", geom, err)
+		}
 		params.Geom = geom
 	}
 
-	return params
+	if bbox := r.URL.Query().Get("bbox"); bbox != "" {
+		parsed, err := common_shared.ParseBoundingBox(bbox)
+		if err != nil {
+			return nil, err
+		}
+		params.Bbox = parsed
+	}
+
+	if filter := r.URL.Query().Get("filter"); filter != "" {
+		if lang := r.URL.Query().Get("filter-lang"); lang != "" && lang != FilterLangCQL2Text {
+			return nil, fmt.Errorf("unsupported filter-lang %q: only %s is supported", lang, FilterLangCQL2Text)
+		}
+		expr, err := ParseCQL2Text(filter, SystemFilterProperties...)
+		if err != nil {
+			return nil, err
+		}
+		params.Filter = filter
+		params.FilterExpr = expr
+	}
+
+	if near := r.URL.Query().Get("near"); near != "" {
+		point, err := ParseNearPoint(near)
+		if err != nil {
+			return nil, err
+		}
+		params.Near = point
+	}
+
+	if radius := r.URL.Query().Get("radius"); radius != "" {
+		meters, err := strconv.ParseFloat(radius, 64)
+		if err != nil || math.IsNaN(meters) || math.IsInf(meters, 0) || meters < 0 {
+			return nil, fmt.Errorf("radius must be a non-negative distance in meters, got %q", radius)
+		}
+		if params.Near == nil {
+			return nil, errors.New("near is required with radius")
+		}
+		params.Radius = &meters
+	}
+
+	if sortBy := r.URL.Query().Get("sortby"); sortBy != "" {
+		if sortBy != SortByDistance {
+			return nil, fmt.Errorf("unsupported sortby %q", sortBy)
+		}
+		if params.Near == nil {
+			return nil, errors.New("near is required when sortby=distance")
+		}
+		params.SortBy = sortBy
+	}
+
+	return params, nil
 }
@@ -0,0 +1,356 @@
+package queryparams
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+)
+
+func TestSystemQueryParams_SortByDistance(t *testing.T) {
+	tests := map[string]struct {
+		query     string
+		wantErr   bool
+		wantNear  *NearPoint
+		wantOrder string
+	}{
+		"distance with near": {
+			query:     "sortby=distance&near=POINT(-117.5%2033.25)",
+			wantNear:  &NearPoint{Lon: -117.5, Lat: 33.25},
+			wantOrder: SortByDistance,
+		},
+		"near without sortby": {
+			query:    "near=point(10%2020)",
+			wantNear: &NearPoint{Lon: 10, Lat: 20},
+		},
+		"distance without near": {
+			query:   "sortby=distance",
+			wantErr: true,
+		},
+		"malformed near": {
+			query:   "sortby=distance&near=10,20",
+			wantErr: true,
+		},
+		"near out of range": {
+			query:   "sortby=distance&near=POINT(200%2010)",
+			wantErr: true,
+		},
+		"unsupported sortby": {
+			query:   "sortby=name",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/systems?"+tc.query, nil)
+			params, err := SystemQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tc.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.SortBy != tc.wantOrder {
+				t.Fatalf("expected sortby %q, got %q", tc.wantOrder, params.SortBy)
+			}
+			if params.Near == nil || *params.Near != *tc.wantNear {
+				t.Fatalf("expected near %+v, got %+v", tc.wantNear, params.Near)
+			}
+		})
+	}
+}
+
+func TestSystemQueryParams_Radius(t *testing.T) {
+	r := httptest.NewRequest("GET", "/systems?near=POINT(0%2089.9)&radius=1500.5", nil)
+	params, err := SystemQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Radius == nil || *params.Radius != 1500.5 {
+		t.Fatalf("expected radius 1500.5, got %v", params.Radius)
+	}
+
+	for _, query := range []string{
+		"radius=1000",
+		"near=POINT(0%200)&radius=-1",
+		"near=POINT(0%200)&radius=1km",
+		"near=POINT(0%200)&radius=NaN",
+		"near=POINT(0%200)&radius=Inf",
+	} {
+		r := httptest.NewRequest("GET", "/systems?"+query, nil)
+		if _, err := (SystemQueryParams{}).BuildFromRequest(r); err == nil {
+			t.Fatalf("expected error for %q", query)
+		}
+	}
+}
+
+func TestSystemQueryParams_DatetimeOp(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		"default":    {query: "", want: DatetimeOpIntersects},
+		"intersects": {query: "datetimeOp=intersects", want: DatetimeOpIntersects},
+		"contains":   {query: "datetimeOp=contains", want: DatetimeOpContains},
+		"during":     {query: "datetimeOp=during", want: DatetimeOpDuring},
+		"unknown":    {query: "datetimeOp=overlaps", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/systems?"+tc.query, nil)
+			params, err := SystemQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", params.DatetimeOp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.DatetimeOp != tc.want {
+				t.Fatalf("DatetimeOp = %q, want %q", params.DatetimeOp, tc.want)
+			}
+		})
+	}
+}
+
+func TestSystemQueryParams_DatetimeSpellings(t *testing.T) {
+	for _, query := range []string{"dateTime=2025-01-01T00:00:00Z/..", "datetime=2025-01-01T00:00:00Z/.."} {
+		r := httptest.NewRequest("GET", "/systems?"+query, nil)
+		params, err := SystemQueryParams{}.BuildFromRequest(r)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", query, err)
+		}
+		if params.Datetime == nil || params.Datetime.Start == nil || params.Datetime.End != nil {
+			t.Fatalf("%s: Datetime = %+v, want an interval open at the end", query, params.Datetime)
+		}
+	}
+}
+
+func TestSystemQueryParams_ValidTimeContains(t *testing.T) {
+	r := httptest.NewRequest("GET", "/systems?validTimeContains="+url.QueryEscape("2025-11-03T14:00:00+02:00"), nil)
+	params, err := SystemQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	if params.ValidTimeContains == nil || !params.ValidTimeContains.Equal(want) || params.ValidTimeContains.Location() != time.UTC {
+		t.Fatalf("ValidTimeContains = %v, want %v", params.ValidTimeContains, want)
+	}
+
+	for _, value := range []string{"2025-11-03", "2025-11-01T00:00:00Z/2025-11-30T00:00:00Z", "now"} {
+		r := httptest.NewRequest("GET", "/systems?validTimeContains="+url.QueryEscape(value), nil)
+		_, err := SystemQueryParams{}.BuildFromRequest(r)
+		var invalid *InvalidParameterError
+		if !errors.As(err, &invalid) || invalid.Name != "validTimeContains" {
+			t.Fatalf("%s: expected an invalid validTimeContains error, got %v", value, err)
+		}
+	}
+}
+
+func TestSystemQueryParams_SystemType(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		"absent":      {query: "", want: nil},
+		"full uri":    {query: "systemType=http://www.w3.org/ns/sosa/Sensor", want: []string{domains.SystemTypeSensor}},
+		"bare name":   {query: "systemType=sensor", want: []string{domains.SystemTypeSensor}},
+		"curie":       {query: "systemType=ssn:System", want: []string{domains.SystemTypeSystem}},
+		"repeated":    {query: "systemType=sensor&systemType=Platform", want: []string{domains.SystemTypeSensor, domains.SystemTypePlatform}},
+		"csv":         {query: "systemType=actuator,sampler", want: []string{domains.SystemTypeActuator, domains.SystemTypeSampler}},
+		"unknown":     {query: "systemType=robot", wantErr: true},
+		"unknown uri": {query: "systemType=http://example.org/Sensor", wantErr: true},
+		"empty entry": {query: "systemType=sensor,", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/systems?"+tc.query, nil)
+			params, err := SystemQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", params.SystemType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params.SystemType, tc.want) {
+				t.Fatalf("SystemType = %v, want %v", params.SystemType, tc.want)
+			}
+		})
+	}
+}
+
+func TestSystemQueryParams_AssetTypeAndSystemKind(t *testing.T) {
+	tests := map[string]struct {
+		query          string
+		wantAssetType  []string
+		wantSystemKind []string
+	}{
+		"absent":   {query: ""},
+		"single":   {query: "assetType=Equipment&systemKind=proc-1", wantAssetType: []string{"Equipment"}, wantSystemKind: []string{"proc-1"}},
+		"repeated": {query: "assetType=Equipment&assetType=Platform", wantAssetType: []string{"Equipment", "Platform"}},
+		"csv":      {query: "systemKind=proc-1,urn:test:proc:2", wantSystemKind: []string{"proc-1", "urn:test:proc:2"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/systems?"+tc.query, nil)
+			params, err := SystemQueryParams{}.BuildFromRequest(r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params.AssetType, tc.wantAssetType) {
+				t.Fatalf("AssetType = %v, want %v", params.AssetType, tc.wantAssetType)
+			}
+			if !reflect.DeepEqual(params.SystemKind, tc.wantSystemKind) {
+				t.Fatalf("SystemKind = %v, want %v", params.SystemKind, tc.wantSystemKind)
+			}
+		})
+	}
+}
+
+func TestSystemQueryParams_PropertyFilters(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		want    map[string][]string
+		wantErr bool
+	}{
+		"absent":            {query: "", want: nil},
+		"single":            {query: "properties.name=Weather%20Station", want: map[string][]string{"name": {"Weather Station"}}},
+		"repeated":          {query: "properties.assetType=Equipment&properties.assetType=Platform", want: map[string][]string{"assetType": {"Equipment", "Platform"}}},
+		"commas kept":       {query: "properties.description=a,b", want: map[string][]string{"description": {"a,b"}}},
+		"several names":     {query: "properties.uid=urn:x&properties.lang=en", want: map[string][]string{"uid": {"urn:x"}, "lang": {"en"}}},
+		"not whitelisted":   {query: "properties.smlType=PhysicalSystem", wantErr: true},
+		"sql in name":       {query: "properties.name%3D1%20OR%201=1", wantErr: true},
+		"unprefixed ignore": {query: "name=x", want: nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/systems?"+tc.query, nil)
+			params, err := SystemQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", params.Properties)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params.Properties, tc.want) {
+				t.Fatalf("Properties = %v, want %v", params.Properties, tc.want)
+			}
+		})
+	}
+}
+
+func TestSystemQueryParams_Geom(t *testing.T) {
+	tests := map[string]struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		"absent":       {query: ""},
+		"point":        {query: "geom=POINT(-117.5%2033.25)", want: "POINT(-117.5 33.25)"},
+		"polygon":      {query: "geom=POLYGON((0%200,1%200,1%201,0%200))", want: "POLYGON((0 0,1 0,1 1,0 0))"},
+		"unclosed":     {query: "geom=POINT(broken", wantErr: true},
+		"unknown type": {query: "geom=CIRCLE(0%200)", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/systems?"+tc.query, nil)
+			params, err := SystemQueryParams{}.BuildFromRequest(r)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", params.Geom)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if params.Geom != tc.want {
+				t.Fatalf("Geom = %q, want %q", params.Geom, tc.want)
+			}
+		})
+	}
+}
+
+func TestSystemQueryParams_EchoJSON(t *testing.T) {
+	r := httptest.NewRequest("GET", "/systems?echo=true&limit=5&id=a,b&bbox=-10,-5,10,5&systemType=sensor&near=POINT(1%202)&sortby=distance&recursive=true", nil)
+	params, err := SystemQueryParams{}.BuildFromRequest(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !params.Echo {
+		t.Fatal("expected Echo to be set")
+	}
+
+	got, err := json.Marshal(params)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"id":["a","b"],"limit":5,"offset":0,"bbox":[-10,-5,10,5],"datetimeOp":"intersects","systemType":["` + domains.SystemTypeSensor + `"],"recursive":true,"sortby":"distance","near":{"lon":1,"lat":2}}`
+	if string(got) != want {
+		t.Fatalf("echoed params\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestSystemQueryParams_Parent(t *testing.T) {
+	tests := map[string]struct {
+		query        string
+		wantErr      bool
+		wantParent   []string
+		wantRootOnly bool
+	}{
+		"no parent filter":      {query: ""},
+		"parent ids":            {query: "parent=a,b", wantParent: []string{"a", "b"}},
+		"parent null":           {query: "parent=null", wantRootOnly: true},
+		"parent null uppercase": {query: "parent=NULL", wantRootOnly: true},
+		"parent null and id":    {query: "parent=null,a", wantParent: []string{"a"}, wantRootOnly: true},
+		"rootOnly":              {query: "rootOnly=true", wantRootOnly: true},
+		"rootOnly false":        {query: "rootOnly=false"},
+		"invalid rootOnly":      {query: "rootOnly=maybe", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/systems?"+tt.query, nil)
+			params, err := SystemQueryParams{}.BuildFromRequest(req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(params.Parent, tt.wantParent) {
+				t.Fatalf("expected parent %v, got %v", tt.wantParent, params.Parent)
+			}
+			if params.RootOnly != tt.wantRootOnly {
+				t.Fatalf("expected rootOnly %v, got %v", tt.wantRootOnly, params.RootOnly)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package queryparams
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+)
+
+// dateTimeValues returns the values of the dateTime parameter, or of its OGC
+// API spelling datetime when dateTime is absent. Either may be given once or
+// repeated.
+func dateTimeValues(q url.Values) []string {
+	if values := q["dateTime"]; len(values) > 0 {
+		return values
+	}
+	return q["datetime"]
+}
+
+// parseTimeFilter parses the values of the temporal filter parameter name: a
+// single "start/end" interval, a single instant, "now", or repeated start and
+// end values. Each bound must be an RFC 3339 instant, or ".." or empty for an
+// open bound. It returns nil when both bounds are open, since such a filter
+// matches everything, so callers never mistake it for a restriction.
+func parseTimeFilter(name string, values []string) (*common_shared.TimeRange, error) {
+	var bounds []string
+	switch {
+	case len(values) == 0:
+		return nil, nil
+	case len(values) > 1:
+		bounds = values
+	case values[0] == "now" || values[0] == "latest":
+		tr := common_shared.ToTimeRange(values[0])
+		return &tr, nil
+	default:
+		bounds = strings.Split(values[0], "/")
+	}
+	if len(bounds) > 2 {
+		return nil, &InvalidParameterError{Name: name, Value: strings.Join(values, ","), Reason: "must be an instant or a start/end interval"}
+	}
+
+	parsed := make([]*time.Time, len(bounds))
+	for i, bound := range bounds {
+		if bound == "" || bound == ".." {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound)
+		if err != nil {
+			return nil, &InvalidParameterError{Name: name, Value: bound, Reason: "must be an RFC 3339 instant or .."}
+		}
+		t = t.UTC()
+		parsed[i] = &t
+	}
+
+	tr := common_shared.TimeRange{Start: parsed[0], End: parsed[0]}
+	if len(parsed) == 2 {
+		tr.End = parsed[1]
+	}
+	if tr.Start == nil && tr.End == nil {
+		return nil, nil
+	}
+	return &tr, nil
+}
@@ -24,19 +24,37 @@ func TestDatastreamRepository_DeleteCascade_RemovesObservations(t *testing.T) {
 	}
 	require.NoError(t, datastreamRepo.Create(datastream))
 
-	observation := &domains.Observation{
-		DatastreamID: datastream.ID,
+	other := &domains.Datastream{
+		CommonSSN: domains.CommonSSN{
+			UniqueIdentifier: domains.UniqueID("urn:test:ds:cascade:2"),
+			Name:             "Surviving Datastream",
+		},
+	}
+	require.NoError(t, datastreamRepo.Create(other))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, observationRepo.Create(&domains.Observation{
+			DatastreamID: datastream.ID,
+			ResultTime:   time.Now().UTC().Add(time.Duration(i) * time.Second),
+		}))
+	}
+	survivor := &domains.Observation{
+		DatastreamID: other.ID,
 		ResultTime:   time.Now().UTC(),
 	}
-	require.NoError(t, observationRepo.Create(observation))
+	require.NoError(t, observationRepo.Create(survivor))
 
-	require.NoError(t, datastreamRepo.Delete(datastream.ID, true))
+	require.NoError(t, datastreamRepo.Delete(datastream.ID))
 
 	_, err := datastreamRepo.GetByID(datastream.ID)
 	require.Error(t, err)
 
-	_, err = observationRepo.GetByID(observation.ID)
-	require.Error(t, err)
+	var orphaned int64
+	require.NoError(t, db.Model(&domains.Observation{}).Where("datastream_id = ?", datastream.ID).Count(&orphaned).Error)
+	require.Zero(t, orphaned, "no observation may outlive its datastream")
+
+	_, err = observationRepo.GetByID(survivor.ID)
+	require.NoError(t, err, "observations of other datastreams are kept")
 }
 
 func TestControlStreamRepository_DeleteCascade_RemovesCommands(t *testing.T) {
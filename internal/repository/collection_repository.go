@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
 	"gorm.io/gorm"
 )
@@ -31,7 +32,13 @@ func (r *CollectionRepository) GetCollectionByID(ctx context.Context, id string)
 	return &collection, nil
 }
 
+// CreateCollection inserts collection. Collection ids are global, so under a
+// tenant (see WithTenant) the id is always generated here: a client-chosen id
+// that clashed with another tenant's collection would reveal that it exists.
 func (r *CollectionRepository) CreateCollection(ctx context.Context, collection *domains.Collection) error {
+	if _, ok := TenantFromContext(ctx); ok {
+		collection.ID = uuid.New().String()
+	}
 	return r.DB.WithContext(ctx).Create(collection).Error
 }
 
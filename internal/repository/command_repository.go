@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -19,6 +20,12 @@ func NewCommandRepository(db *gorm.DB) *CommandRepository {
 	return &CommandRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *CommandRepository) WithContext(ctx context.Context) *CommandRepository {
+	return &CommandRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create persists a new command. IssueTime is set to now if omitted.
 func (r *CommandRepository) Create(cmd *domains.Command) error {
 	if cmd.IssueTime == nil {
@@ -28,7 +35,9 @@ func (r *CommandRepository) Create(cmd *domains.Command) error {
 	if cmd.CurrentStatus == "" {
 		cmd.CurrentStatus = domains.CommandStatusPending
 	}
-	return r.db.Create(cmd).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Create(cmd).Error
+	})
 }
 
 // GetByID retrieves a command by ID.
@@ -74,12 +83,16 @@ func (r *CommandRepository) ListByControlStream(controlStreamID string, params *
 
 // Update updates a command.
 func (r *CommandRepository) Update(cmd *domains.Command) error {
-	return r.db.Save(cmd).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Save(cmd).Error
+	})
 }
 
 // Delete deletes a command.
 func (r *CommandRepository) Delete(id string) error {
-	return r.db.Delete(&domains.Command{}, "id = ?", id).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Delete(&domains.Command{}, "id = ?", id).Error
+	})
 }
 
 func (r *CommandRepository) applyFilters(query *gorm.DB, params *queryparams.CommandsQueryParams, controlStreamFixed bool) *gorm.DB {
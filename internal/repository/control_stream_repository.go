@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"strings"
 
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
@@ -18,10 +19,24 @@ func NewControlStreamRepository(db *gorm.DB) *ControlStreamRepository {
 	return &ControlStreamRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *ControlStreamRepository) WithContext(ctx context.Context) *ControlStreamRepository {
+	return &ControlStreamRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create creates a new control stream.
 func (r *ControlStreamRepository) Create(cs *domains.ControlStream) error {
 	normalizeControlStreamRefs(cs)
-	return r.db.Create(cs).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Create(cs).Error
+	})
+}
+
+// UniqueIDInUse reports whether another control stream already has the unique
+// identifier uid. A blank uid is never in use.
+func (r *ControlStreamRepository) UniqueIDInUse(uid string) (bool, error) {
+	return uniqueIDInUse(r.db, &domains.ControlStream{}, uid)
 }
 
 // GetByID retrieves a control stream by ID.
@@ -57,20 +72,44 @@ func (r *ControlStreamRepository) List(params *queryparams.ControlStreamsQueryPa
 	return controlStreams, total, err
 }
 
+// ListBySystem retrieves the control streams of a single system. It returns
+// ErrNotFound when the system does not exist, and enriches each
+// control stream's system@link with the parent system's name and uid.
+func (r *ControlStreamRepository) ListBySystem(params *queryparams.ControlStreamsQueryParams, systemID string) ([]*domains.ControlStream, int64, error) {
+	var system domains.System
+	if err := r.db.Select("id", "name", "unique_identifier").Where("id = ?", systemID).First(&system).Error; err != nil {
+		return nil, 0, err
+	}
+
+	controlStreams, total, err := r.List(params, &systemID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, cs := range controlStreams {
+		cs.SystemLink = enrichSystemLink(cs.SystemLink, &system)
+	}
+	return controlStreams, total, nil
+}
+
 // Update updates a control stream.
 func (r *ControlStreamRepository) Update(cs *domains.ControlStream) error {
 	normalizeControlStreamRefs(cs)
-	return r.db.Save(cs).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Save(cs).Error
+	})
 }
 
 // Delete deletes a control stream.
 // If cascade is true, all commands associated with the control stream are deleted first.
 func (r *ControlStreamRepository) Delete(id string, cascade bool) error {
 	if !cascade {
-		return r.db.Delete(&domains.ControlStream{}, "id = ?", id).Error
+		return retryWrites(r.db, func() error {
+			return r.db.Delete(&domains.ControlStream{}, "id = ?", id).Error
+		})
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return retryTransaction(r.db, func(tx *gorm.DB) error {
 		if err := tx.Where("control_stream_id = ?", id).Delete(&domains.Command{}).Error; err != nil {
 			return err
 		}
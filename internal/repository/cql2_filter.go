@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"gorm.io/gorm"
+)
+
+// systemFilterColumns maps the properties of a systems filter expression to
+// their columns.
+var systemFilterColumns = map[string]string{
+	"name":                            "systems.name",
+	"systemType":                      "systems.system_type",
+	"uid":                             "systems.unique_identifier",
+	queryparams.SpatialFilterProperty: "systems.geometry",
+}
+
+// systemPropertyColumns maps the properties.<name> filters on systems to
+// their columns.
+var systemPropertyColumns = map[string]string{
+	"uid":         "systems.unique_identifier",
+	"name":        "systems.name",
+	"description": "systems.description",
+	"featureType": "systems.system_type",
+	"assetType":   "systems.asset_type",
+	"lang":        "systems.lang",
+}
+
+// wherePropertyFilters restricts query to rows whose columns exactly equal
+// one of the values given for each filtered property. Properties are ANDed;
+// names without a column (which the parser never lets through) match nothing.
+func wherePropertyFilters(query *gorm.DB, filters map[string][]string, columns map[string]string) *gorm.DB {
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		column, ok := columns[name]
+		if !ok {
+			query = query.Where("FALSE")
+			continue
+		}
+		query = query.Where(column+" IN ?", filters[name])
+	}
+	return query
+}
+
+// whereCQL2 restricts query to rows matching a parsed filter expression.
+// columns maps each property the parser accepted to its SQL column; literals
+// are always bound as parameters.
+func whereCQL2(query *gorm.DB, expr queryparams.CQL2Expr, columns map[string]string) *gorm.DB {
+	if expr == nil {
+		return query
+	}
+	sql, args := cql2SQL(expr, columns)
+	return query.Where(sql, args...)
+}
+
+// cql2SQL translates expr into a SQL condition and its arguments.
+func cql2SQL(expr queryparams.CQL2Expr, columns map[string]string) (string, []interface{}) {
+	switch e := expr.(type) {
+	case queryparams.CQL2Logical:
+		clauses := make([]string, 0, len(e.Args))
+		var args []interface{}
+		for _, arg := range e.Args {
+			clause, clauseArgs := cql2SQL(arg, columns)
+			clauses = append(clauses, clause)
+			args = append(args, clauseArgs...)
+		}
+		return "(" + strings.Join(clauses, " "+e.Op+" ") + ")", args
+	case queryparams.CQL2Comparison:
+		return columns[e.Property] + " " + e.Op + " ?", []interface{}{e.Value}
+	case queryparams.CQL2Intersects:
+		return "ST_Intersects(" + columns[e.Property] + ", ST_GeomFromText(?, 4326))", []interface{}{e.WKT}
+	}
+	// The parser only produces the node types above
+	return "FALSE", nil
+}
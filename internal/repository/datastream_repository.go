@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"encoding/json"
 	"strings"
 
 	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
@@ -19,11 +21,25 @@ func NewDatastreamRepository(db *gorm.DB) *DatastreamRepository {
 	return &DatastreamRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *DatastreamRepository) WithContext(ctx context.Context) *DatastreamRepository {
+	return &DatastreamRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create creates a new datastream.
 func (r *DatastreamRepository) Create(datastream *domains.Datastream) error {
 	normalizeDatastreamRefs(datastream)
 	r.populateSystemAssociations(datastream)
-	return r.db.Create(datastream).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Create(datastream).Error
+	})
+}
+
+// UniqueIDInUse reports whether another datastream already has the unique
+// identifier uid. A blank uid is never in use.
+func (r *DatastreamRepository) UniqueIDInUse(uid string) (bool, error) {
+	return uniqueIDInUse(r.db, &domains.Datastream{}, uid)
 }
 
 // GetByID retrieves a datastream by ID.
@@ -59,6 +75,61 @@ func (r *DatastreamRepository) List(params *queryparams.DatastreamsQueryParams,
 	return datastreams, total, err
 }
 
+// ListBySystem retrieves the datastreams of a single system. It returns
+// ErrNotFound when the system does not exist, and enriches each
+// datastream's system@link with the parent system's name and uid.
+func (r *DatastreamRepository) ListBySystem(params *queryparams.DatastreamsQueryParams, systemID string) ([]*domains.Datastream, int64, error) {
+	var system domains.System
+	if err := r.db.Select("id", "name", "unique_identifier").Where("id = ?", systemID).First(&system).Error; err != nil {
+		return nil, 0, err
+	}
+
+	datastreams, total, err := r.List(params, &systemID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, ds := range datastreams {
+		ds.SystemLink = enrichSystemLink(ds.SystemLink, &system)
+	}
+	return datastreams, total, nil
+}
+
+// ListByProperty retrieves the datastreams observing a property: those with
+// an observedProperties member whose definition is the property's uid. It
+// returns ErrNotFound when the property does not exist.
+func (r *DatastreamRepository) ListByProperty(params *queryparams.DatastreamsQueryParams, propertyID string) ([]*domains.Datastream, int64, error) {
+	var property domains.Property
+	if err := r.db.Select("id", "unique_identifier").Where("id = ?", propertyID).First(&property).Error; err != nil {
+		return nil, 0, err
+	}
+
+	observed, err := json.Marshal(domains.DatastreamObservedProperties{{Definition: string(property.UniqueIdentifier)}})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var datastreams []*domains.Datastream
+	var total int64
+
+	query := r.db.Model(&domains.Datastream{}).Where("observed_properties @> ?::jsonb", string(observed))
+	query = r.applyFilters(query, params, nil)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		query = query.Offset(params.Offset)
+	}
+
+	err = query.Find(&datastreams).Error
+	return datastreams, total, err
+}
+
 // Update updates a datastream.
 // The system-derived fields (procedure, deployment, featureOfInterest, samplingFeature)
 // are locked: they are always restored from the existing record and cannot be changed by the client.
@@ -76,7 +147,9 @@ func (r *DatastreamRepository) Update(datastream *domains.Datastream) error {
 		datastream.SamplingFeatureID = existing.SamplingFeatureID
 	}
 	normalizeDatastreamRefs(datastream)
-	return r.db.Save(datastream).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Save(datastream).Error
+	})
 }
 
 // populateSystemAssociations overwrites the system-derived fields on a datastream
@@ -119,14 +192,10 @@ func (r *DatastreamRepository) populateSystemAssociations(datastream *domains.Da
 	}
 }
 
-// Delete deletes a datastream.
-// If cascade is true, all observations associated with the datastream are deleted first.
-func (r *DatastreamRepository) Delete(id string, cascade bool) error {
-	if !cascade {
-		return r.db.Delete(&domains.Datastream{}, "id = ?", id).Error
-	}
-
-	return r.db.Transaction(func(tx *gorm.DB) error {
+// Delete deletes a datastream together with all of its observations, in one
+// transaction, so no observation is left without a datastream.
+func (r *DatastreamRepository) Delete(id string) error {
+	return retryTransaction(r.db, func(tx *gorm.DB) error {
 		if err := tx.Where("datastream_id = ?", id).Delete(&domains.Observation{}).Error; err != nil {
 			return err
 		}
@@ -229,3 +298,20 @@ func normalizeDatastreamRefs(datastream *domains.Datastream) {
 		datastream.SamplingFeatureID = datastream.SamplingFeatureLink.GetId("samplingFeatures")
 	}
 }
+
+// enrichSystemLink fills in the title and uid of a system@link from the system record,
+// creating the link if the stream did not store one.
+func enrichSystemLink(link *common_shared.Link, system *domains.System) *common_shared.Link {
+	enriched := common_shared.Link{Href: "systems/" + system.ID}
+	if link != nil {
+		enriched = *link
+	}
+	if enriched.Title == "" {
+		enriched.Title = system.Name
+	}
+	if enriched.UID == nil && system.UniqueIdentifier != "" {
+		uid := string(system.UniqueIdentifier)
+		enriched.UID = &uid
+	}
+	return &enriched
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -20,9 +21,23 @@ func NewDeploymentRepository(db *gorm.DB) *DeploymentRepository {
 	return &DeploymentRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *DeploymentRepository) WithContext(ctx context.Context) *DeploymentRepository {
+	return &DeploymentRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create creates a new deployment
 func (r *DeploymentRepository) Create(deployment *domains.Deployment) error {
-	return r.db.Create(deployment).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Create(deployment).Error
+	})
+}
+
+// UniqueIDInUse reports whether another deployment already has the unique
+// identifier uid. A blank uid is never in use.
+func (r *DeploymentRepository) UniqueIDInUse(uid string) (bool, error) {
+	return uniqueIDInUse(r.db, &domains.Deployment{}, uid)
 }
 
 // GetByID retrieves a deployment by ID
@@ -71,12 +86,16 @@ func (r *DeploymentRepository) List(params *queryparams.DeploymentsQueryParams,
 
 // Update updates a deployment
 func (r *DeploymentRepository) Update(deployment *domains.Deployment) error {
-	return r.db.Save(deployment).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Save(deployment).Error
+	})
 }
 
 // Delete deletes a deployment
 func (r *DeploymentRepository) Delete(id string) error {
-	return r.db.Delete(&domains.Deployment{}, "id = ?", id).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Delete(&domains.Deployment{}, "id = ?", id).Error
+	})
 }
 
 // Delete all deployments - for testing purposes
@@ -112,6 +131,8 @@ func (r *DeploymentRepository) applyFilters(query *gorm.DB, params *queryparams.
 		}
 	}
 
+	query = whereIntersectsBbox(query, "deployments.geometry", params.Bbox)
+
 	if len(params.ControlledProperty) > 0 {
 		query = query.Joins("JOIN procedure_controlled_properties ON procedures.id = procedure_controlled_properties.procedure_id").
 			Where("procedure_controlled_properties.property_id IN ?", params.ControlledProperty)
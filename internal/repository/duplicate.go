@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// pgUniqueViolation is the PostgreSQL SQLSTATE for a unique constraint violation.
+const pgUniqueViolation = "23505"
+
+// DuplicateUIDError is returned when a resource is stored with a unique
+// identifier that another resource of the same type already uses.
+type DuplicateUIDError struct {
+	UID string
+}
+
+func (e *DuplicateUIDError) Error() string {
+	return fmt.Sprintf("uniqueId %q is already in use", e.UID)
+}
+
+// Is makes a DuplicateUIDError match ErrConflict.
+func (e *DuplicateUIDError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation
+// raised by PostgreSQL.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+// uniqueIDInUse reports whether a row of model's table already has the unique
+// identifier uid. A blank uid is never in use.
+func uniqueIDInUse(db *gorm.DB, model any, uid string) (bool, error) {
+	if uid == "" {
+		return false, nil
+	}
+	var count int64
+	if err := db.Model(model).Where("unique_identifier = ?", uid).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
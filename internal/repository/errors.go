@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Kinds of repository failure, independent of the database driver. Errors
+// returned by repositories match one of these with errors.Is whenever the
+// cause is known, and still match the underlying driver error as well.
+var (
+	// ErrNotFound means the requested record does not exist.
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict means a write clashes with an existing record, such as a
+	// second resource with the same unique identifier.
+	ErrConflict = errors.New("conflicts with an existing record")
+	// ErrValidation means the input cannot be stored as given.
+	ErrValidation = errors.New("invalid input")
+	// ErrConstraint means a write would break a database constraint other
+	// than uniqueness, such as a reference to a record that does not exist.
+	ErrConstraint = errors.New("violates a database constraint")
+)
+
+// PostgreSQL SQLSTATEs for integrity constraint violations other than
+// pgUniqueViolation, and the class of data exceptions (invalid input syntax,
+// out of range values and the like).
+const (
+	pgNotNullViolation    = "23502"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgExclusionViolation  = "23P01"
+	pgDataExceptionClass  = "22"
+)
+
+// kindError tags err with one of the error kinds above. It reads exactly
+// like err, and errors.Is matches both the kind and anything err matches.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// errorKind returns the kind err already matches, or nil.
+func errorKind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrConflict, ErrValidation, ErrConstraint} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// classifyError tags a raw database error with the kind of failure it
+// represents. Errors that already have a kind, or whose cause is unknown,
+// are returned unchanged.
+func classifyError(err error) error {
+	if err == nil || errorKind(err) != nil {
+		return err
+	}
+
+	var kind error
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		kind = ErrNotFound
+	case errors.As(err, &pgErr):
+		switch {
+		case pgErr.Code == pgUniqueViolation:
+			kind = ErrConflict
+		case pgErr.Code == pgNotNullViolation, pgErr.Code == pgForeignKeyViolation,
+			pgErr.Code == pgCheckViolation, pgErr.Code == pgExclusionViolation:
+			kind = ErrConstraint
+		case len(pgErr.Code) == 5 && pgErr.Code[:2] == pgDataExceptionClass:
+			kind = ErrValidation
+		}
+	}
+	if kind == nil {
+		return err
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// RegisterErrorClassification tags the error of every create, query, update,
+// delete and raw statement run through db with its kind (ErrNotFound,
+// ErrConflict, ErrValidation or ErrConstraint), so callers can tell why a
+// repository call failed without knowing about gorm or PostgreSQL.
+func RegisterErrorClassification(db *gorm.DB) error {
+	classify := func(tx *gorm.DB) {
+		if tx.Error != nil {
+			tx.Error = classifyError(tx.Error)
+		}
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().After("*").Register("errors:classify", classify),
+		cb.Query().After("*").Register("errors:classify", classify),
+		cb.Update().After("*").Register("errors:classify", classify),
+		cb.Delete().After("*").Register("errors:classify", classify),
+		cb.Raw().After("*").Register("errors:classify", classify),
+	)
+}
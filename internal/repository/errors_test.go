@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"record not found", gorm.ErrRecordNotFound, ErrNotFound},
+		{"wrapped record not found", fmt.Errorf("lookup: %w", gorm.ErrRecordNotFound), ErrNotFound},
+		{"unique violation", &pgconn.PgError{Code: pgUniqueViolation}, ErrConflict},
+		{"foreign key violation", &pgconn.PgError{Code: pgForeignKeyViolation}, ErrConstraint},
+		{"not null violation", &pgconn.PgError{Code: pgNotNullViolation}, ErrConstraint},
+		{"check violation", &pgconn.PgError{Code: pgCheckViolation}, ErrConstraint},
+		{"invalid text representation", &pgconn.PgError{Code: "22P02"}, ErrValidation},
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, nil},
+		{"unknown error", errors.New("connection reset"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classified := classifyError(tt.err)
+			assert.ErrorIs(t, classified, tt.err, "the original error must still match")
+			assert.Equal(t, tt.err.Error(), classified.Error())
+			assert.Equal(t, tt.kind, errorKind(classified))
+		})
+	}
+}
+
+func TestClassifyError_KeepsExistingKind(t *testing.T) {
+	err := &DuplicateUIDError{UID: "urn:x:1"}
+	assert.Same(t, err, classifyError(err))
+	assert.ErrorIs(t, err, ErrConflict)
+
+	assert.ErrorIs(t, ErrUnfilteredDelete, ErrValidation)
+	assert.NoError(t, classifyError(nil))
+}
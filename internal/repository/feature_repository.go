@@ -2,9 +2,9 @@ package repository
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
 	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
 	"gorm.io/gorm"
@@ -20,9 +20,17 @@ func NewFeatureRepository(db *gorm.DB) *FeatureRepository {
 	return &FeatureRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *FeatureRepository) WithContext(ctx context.Context) *FeatureRepository {
+	return &FeatureRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create creates a new feature
 func (r *FeatureRepository) Create(feature *domains.Feature) error {
-	return r.db.Create(feature).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Create(feature).Error
+	})
 }
 
 // GetByID retrieves a feature by ID
@@ -57,6 +65,8 @@ func (r *FeatureRepository) List(params *queryparams.FeatureQueryParams) ([]*dom
 		return nil, 0, err
 	}
 
+	query = orderBySort(query, "features", params.SortBy, params.SortDesc)
+
 	if params.Limit > 0 {
 		query = query.Limit(params.Limit)
 	}
@@ -64,7 +74,7 @@ func (r *FeatureRepository) List(params *queryparams.FeatureQueryParams) ([]*dom
 		query = query.Offset(params.Offset)
 	}
 
-	err := query.Find(&features).Error
+	err := omitGeometry(query, params.SkipGeometry).Find(&features).Error
 	return features, total, err
 }
 
@@ -80,6 +90,8 @@ func (r *FeatureRepository) ListByCollection(collectionID string, params *queryp
 		return nil, 0, err
 	}
 
+	query = orderBySort(query, "features", params.SortBy, params.SortDesc)
+
 	if params.Limit > 0 {
 		query = query.Limit(params.Limit)
 	}
@@ -87,18 +99,22 @@ func (r *FeatureRepository) ListByCollection(collectionID string, params *queryp
 		query = query.Offset(params.Offset)
 	}
 
-	err := query.Find(&features).Error
+	err := omitGeometry(query, params.SkipGeometry).Find(&features).Error
 	return features, total, err
 }
 
 // Update updates a feature
 func (r *FeatureRepository) Update(feature *domains.Feature) error {
-	return r.db.Save(feature).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Save(feature).Error
+	})
 }
 
 // Delete deletes a feature
 func (r *FeatureRepository) Delete(id string) error {
-	return r.db.Delete(&domains.Feature{}, "id = ?", id).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Delete(&domains.Feature{}, "id = ?", id).Error
+	})
 }
 
 func (r *FeatureRepository) applyFilters(query *gorm.DB, params *queryparams.FeatureQueryParams) *gorm.DB {
@@ -120,33 +136,29 @@ func (r *FeatureRepository) applyFilters(query *gorm.DB, params *queryparams.Fea
 	}
 
 	// Bounding box filter (OGC bbox parameter)
-	if len(params.BBox) >= 4 {
-		// PostGIS ST_Intersects on geometry JSONB
-		// Format: [minLon, minLat, maxLon, maxLat] or [minLon, minLat, minZ, maxLon, maxLat, maxZ]
-		minLon, minLat, maxLon, maxLat := params.BBox[0], params.BBox[1], params.BBox[2], params.BBox[3]
-		bboxWKT := fmt.Sprintf("POLYGON((%f %f, %f %f, %f %f, %f %f, %f %f))",
-			minLon, minLat,
-			maxLon, minLat,
-			maxLon, maxLat,
-			minLon, maxLat,
-			minLon, minLat,
-		)
-		// Assuming geometry is stored as JSONB with GeoJSON structure
-		// For production, consider using PostGIS geometry column instead
-		query = query.Where("ST_Intersects(ST_GeomFromGeoJSON(geometry::text), ST_GeomFromText(?, 4326))", bboxWKT)
+	// Format: [minLon, minLat, maxLon, maxLat] or [minLon, minLat, minZ, maxLon, maxLat, maxZ]
+	if len(params.BBox) == 4 {
+		bbox := common_shared.BoundingBox{MinX: params.BBox[0], MinY: params.BBox[1], MaxX: params.BBox[2], MaxY: params.BBox[3]}
+		query = whereIntersectsBbox(query, "features.geometry", &bbox)
+	} else if len(params.BBox) == 6 {
+		bbox := common_shared.BoundingBox{MinX: params.BBox[0], MinY: params.BBox[1], MaxX: params.BBox[3], MaxY: params.BBox[4]}
+		query = whereIntersectsBbox(query, "features.geometry", &bbox)
 	}
 
-	// DateTime filter (OGC datetime parameter)
+	// DateTime filter (OGC datetime parameter). A feature matches when its
+	// dateTime instant falls within the interval or its validTime overlaps it.
 	if params.DateTime != nil {
 		if params.DateTime.Start != nil && params.DateTime.End != nil {
 			// Interval: both start and end
-			query = query.Where("date_time >= ? AND date_time <= ?", params.DateTime.Start, params.DateTime.End)
+			query = query.Where("(date_time >= ? AND date_time <= ?) OR (valid_time_start <= ? AND (valid_time_end IS NULL OR valid_time_end >= ?))",
+				params.DateTime.Start, params.DateTime.End, params.DateTime.End, params.DateTime.Start)
 		} else if params.DateTime.Start != nil {
 			// Open-ended: start only
-			query = query.Where("date_time >= ?", params.DateTime.Start)
+			query = query.Where("date_time >= ? OR (valid_time_start IS NOT NULL AND (valid_time_end IS NULL OR valid_time_end >= ?))",
+				params.DateTime.Start, params.DateTime.Start)
 		} else if params.DateTime.End != nil {
 			// Open-ended: end only
-			query = query.Where("date_time <= ?", params.DateTime.End)
+			query = query.Where("date_time <= ? OR valid_time_start <= ?", params.DateTime.End, params.DateTime.End)
 		}
 	}
 
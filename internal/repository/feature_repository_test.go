@@ -2,8 +2,10 @@ package repository
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
 	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
 	"github.com/yourusername/connected-systems-go/internal/repository/testutil"
@@ -258,6 +260,33 @@ func TestFeatureRepository_List(t *testing.T) {
 				require.Equal(t, "Feature Beta", features[0].Name)
 			},
 		},
+		{
+			name: "sortby name descending",
+			params: &queryparams.FeatureQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				SortBy:      queryparams.SortByName,
+				SortDesc:    true,
+			},
+			wantCount: 3,
+			wantTotal: 3,
+			checkFunc: func(t *testing.T, features []*domains.Feature) {
+				require.Equal(t, []string{"Feature Gamma", "Feature Beta", "Feature Alpha"},
+					[]string{features[0].Name, features[1].Name, features[2].Name})
+			},
+		},
+		{
+			name: "sortby created",
+			params: &queryparams.FeatureQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				SortBy:      queryparams.SortByCreated,
+			},
+			wantCount: 3,
+			wantTotal: 3,
+			checkFunc: func(t *testing.T, features []*domains.Feature) {
+				require.Equal(t, []string{feat1.ID, feat2.ID, feat3.ID},
+					[]string{features[0].ID, features[1].ID, features[2].ID})
+			},
+		},
 		{
 			name: "empty result set",
 			params: &queryparams.FeatureQueryParams{
@@ -287,6 +316,135 @@ func TestFeatureRepository_List(t *testing.T) {
 	}
 }
 
+func TestFeatureRepository_List_DateTime(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewFeatureRepository(db)
+
+	at := func(value string) *time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		require.NoError(t, err)
+		return &parsed
+	}
+
+	instant := &domains.Feature{
+		CommonSSN:    domains.CommonSSN{UniqueIdentifier: "urn:test:dt:instant", Name: "Instant"},
+		CollectionID: "collection1",
+		DateTime:     at("2024-03-15T12:00:00Z"),
+	}
+	require.NoError(t, repo.Create(instant))
+
+	valid := &domains.Feature{
+		CommonSSN:    domains.CommonSSN{UniqueIdentifier: "urn:test:dt:valid", Name: "Valid"},
+		CollectionID: "collection1",
+		ValidTime:    &common_shared.TimeRange{Start: at("2024-01-01T00:00:00Z"), End: at("2024-02-01T00:00:00Z")},
+	}
+	require.NoError(t, repo.Create(valid))
+
+	open := &domains.Feature{
+		CommonSSN:    domains.CommonSSN{UniqueIdentifier: "urn:test:dt:open", Name: "Open"},
+		CollectionID: "collection1",
+		ValidTime:    &common_shared.TimeRange{Start: at("2024-06-01T00:00:00Z")},
+	}
+	require.NoError(t, repo.Create(open))
+
+	untimed := &domains.Feature{
+		CommonSSN:    domains.CommonSSN{UniqueIdentifier: "urn:test:dt:untimed", Name: "Untimed"},
+		CollectionID: "collection1",
+	}
+	require.NoError(t, repo.Create(untimed))
+
+	tests := map[string]struct {
+		filter *queryparams.TimeFilter
+		want   []string
+	}{
+		"interval covers instant and validTime": {
+			filter: &queryparams.TimeFilter{Start: at("2024-01-15T00:00:00Z"), End: at("2024-03-31T00:00:00Z")},
+			want:   []string{instant.ID, valid.ID},
+		},
+		"interval overlaps open validTime": {
+			filter: &queryparams.TimeFilter{Start: at("2025-01-01T00:00:00Z"), End: at("2025-02-01T00:00:00Z")},
+			want:   []string{open.ID},
+		},
+		"open start": {
+			filter: &queryparams.TimeFilter{Start: at("2024-03-01T00:00:00Z")},
+			want:   []string{instant.ID, open.ID},
+		},
+		"open end": {
+			filter: &queryparams.TimeFilter{End: at("2024-01-10T00:00:00Z")},
+			want:   []string{valid.ID},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			features, total, err := repo.List(&queryparams.FeatureQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				DateTime:    tt.filter,
+			})
+			require.NoError(t, err)
+			require.Equal(t, int64(len(tt.want)), total)
+			ids := make([]string, 0, len(features))
+			for _, f := range features {
+				ids = append(ids, f.ID)
+			}
+			require.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
+
+func TestFeatureRepository_List_Bbox(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewFeatureRepository(db)
+
+	inside := &domains.Feature{
+		CommonSSN:    domains.CommonSSN{UniqueIdentifier: "urn:test:bbox:inside", Name: "Inside"},
+		Geometry:     testutil.MakePoint(-122.4194, 37.7749),
+		CollectionID: "collection1",
+	}
+	require.NoError(t, repo.Create(inside))
+
+	outside := &domains.Feature{
+		CommonSSN:    domains.CommonSSN{UniqueIdentifier: "urn:test:bbox:outside", Name: "Outside"},
+		Geometry:     testutil.MakePoint(2.3522, 48.8566),
+		CollectionID: "collection1",
+	}
+	require.NoError(t, repo.Create(outside))
+
+	// A feature without geometry cannot intersect any box, however large
+	require.NoError(t, repo.Create(&domains.Feature{
+		CommonSSN:    domains.CommonSSN{UniqueIdentifier: "urn:test:bbox:none", Name: "No Geometry"},
+		CollectionID: "collection1",
+	}))
+
+	tests := map[string]struct {
+		bbox []float64
+		want []string
+	}{
+		"2D box":      {bbox: []float64{-123, 37, -122, 38}, want: []string{inside.ID}},
+		"3D box":      {bbox: []float64{-123, 37, 0, -122, 38, 100}, want: []string{inside.ID}},
+		"whole world": {bbox: []float64{-180, -90, 180, 90}, want: []string{inside.ID, outside.ID}},
+		"empty area":  {bbox: []float64{0, 0, 1, 1}, want: []string{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			features, total, err := repo.List(&queryparams.FeatureQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				BBox:        tt.bbox,
+			})
+			require.NoError(t, err)
+			require.Equal(t, int64(len(tt.want)), total)
+			ids := make([]string, 0, len(features))
+			for _, f := range features {
+				ids = append(ids, f.ID)
+			}
+			require.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
+
 func TestFeatureRepository_ListByCollection(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -1,8 +1,8 @@
 package repository
 
 import (
+	"context"
 	"strings"
-	"time"
 
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
 	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
@@ -18,19 +18,31 @@ func NewObservationRepository(db *gorm.DB) *ObservationRepository {
 	return &ObservationRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *ObservationRepository) WithContext(ctx context.Context) *ObservationRepository {
+	return &ObservationRepository{db: r.db.WithContext(ctx)}
+}
+
 func (r *ObservationRepository) Create(observation *domains.Observation) error {
-	if observation.PhenomenonTime == nil {
-		t := observation.ResultTime
-		observation.PhenomenonTime = &t
-	}
-	if observation.ResultTime.IsZero() {
-		now := time.Now().UTC()
-		observation.ResultTime = now
-		if observation.PhenomenonTime == nil {
-			observation.PhenomenonTime = &now
-		}
+	observation.ApplyTimeDefaults()
+	return retryWrites(r.db, func() error {
+		return r.db.Create(observation).Error
+	})
+}
+
+// CreateBatch inserts all observations in a single transaction; either every
+// observation is stored or none are.
+func (r *ObservationRepository) CreateBatch(observations []*domains.Observation) error {
+	if len(observations) == 0 {
+		return nil
+	}
+	for _, observation := range observations {
+		observation.ApplyTimeDefaults()
 	}
-	return r.db.Create(observation).Error
+	return retryTransaction(r.db, func(tx *gorm.DB) error {
+		return tx.Create(observations).Error
+	})
 }
 
 func (r *ObservationRepository) GetByID(id string) (*domains.Observation, error) {
@@ -44,6 +56,47 @@ func (r *ObservationRepository) GetByID(id string) (*domains.Observation, error)
 
 func (r *ObservationRepository) List(params *queryparams.ObservationsQueryParams, datastreamID *string) ([]*domains.Observation, int64, error) {
 	var observations []*domains.Observation
+
+	query, total, err := r.pageQuery(params, datastreamID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = query.Find(&observations).Error
+	return observations, total, err
+}
+
+// Each calls fn for every observation List would return for params and
+// datastreamID, in the same order, scanning rows from the database cursor one
+// at a time so the result is never held in memory. Iteration stops at the
+// first error from fn. The returned total counts all matches, ignoring paging.
+func (r *ObservationRepository) Each(params *queryparams.ObservationsQueryParams, datastreamID *string, fn func(*domains.Observation) error) (int64, error) {
+	query, total, err := r.pageQuery(params, datastreamID)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return total, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var observation domains.Observation
+		if err := scanRowsUTC(r.db, rows, &observation); err != nil {
+			return total, err
+		}
+		if err := fn(&observation); err != nil {
+			return total, err
+		}
+	}
+	return total, rows.Err()
+}
+
+// pageQuery builds the filtered, paged and ordered observation query shared
+// by List and Each, along with the total number of matches before paging.
+func (r *ObservationRepository) pageQuery(params *queryparams.ObservationsQueryParams, datastreamID *string) (*gorm.DB, int64, error) {
 	var total int64
 
 	query := r.db.Model(&domains.Observation{})
@@ -63,8 +116,7 @@ func (r *ObservationRepository) List(params *queryparams.ObservationsQueryParams
 		query = query.Offset(params.Offset)
 	}
 
-	err := query.Order("result_time desc").Find(&observations).Error
-	return observations, total, err
+	return query.Order("result_time desc"), total, nil
 }
 
 func (r *ObservationRepository) ListByDatastream(datastreamID string, params *queryparams.ObservationsQueryParams) ([]*domains.Observation, int64, error) {
@@ -72,15 +124,16 @@ func (r *ObservationRepository) ListByDatastream(datastreamID string, params *qu
 }
 
 func (r *ObservationRepository) Update(observation *domains.Observation) error {
-	if observation.PhenomenonTime == nil {
-		t := observation.ResultTime
-		observation.PhenomenonTime = &t
-	}
-	return r.db.Save(observation).Error
+	observation.ApplyTimeDefaults()
+	return retryWrites(r.db, func() error {
+		return r.db.Save(observation).Error
+	})
 }
 
 func (r *ObservationRepository) Delete(id string) error {
-	return r.db.Delete(&domains.Observation{}, "id = ?", id).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Delete(&domains.Observation{}, "id = ?", id).Error
+	})
 }
 
 func (r *ObservationRepository) applyFilters(query *gorm.DB, params *queryparams.ObservationsQueryParams, datastreamFixed bool) *gorm.DB {
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// StorageSRID is the spatial reference system geometries are stored in: WGS 84
+// longitude/latitude.
+const StorageSRID = 4326
+
+// geometryFieldName is the field through which a model's geometry is
+// reprojected; systems, deployments, sampling features and features carry it.
+const geometryFieldName = "Geometry"
+
+type outputSRIDContextKey struct{}
+
+// outputSRID is the value WithOutputSRID stores. read is set once a query run
+// under the context returns geometries, so callers can tell whether a
+// response actually carries any in srid.
+type outputSRID struct {
+	srid int
+	read atomic.Bool
+}
+
+// WithOutputSRID returns a copy of ctx under which geometries are read in the
+// spatial reference system srid rather than StorageSRID, once
+// RegisterOutputReprojection is in place.
+func WithOutputSRID(ctx context.Context, srid int) context.Context {
+	return context.WithValue(ctx, outputSRIDContextKey{}, &outputSRID{srid: srid})
+}
+
+// OutputSRIDFromContext returns the spatial reference system stored in ctx by
+// WithOutputSRID, when it differs from StorageSRID.
+func OutputSRIDFromContext(ctx context.Context) (int, bool) {
+	out := outputSRIDFrom(ctx)
+	if out == nil || out.srid == 0 || out.srid == StorageSRID {
+		return 0, false
+	}
+	return out.srid, true
+}
+
+// GeometriesRead reports whether a query run under ctx, which must carry an
+// output SRID, has read geometries since WithOutputSRID was called.
+func GeometriesRead(ctx context.Context) bool {
+	out := outputSRIDFrom(ctx)
+	return out != nil && out.read.Load()
+}
+
+// markGeometriesRead records on ctx that geometries were read under it.
+func markGeometriesRead(ctx context.Context) {
+	if out := outputSRIDFrom(ctx); out != nil {
+		out.read.Store(true)
+	}
+}
+
+func outputSRIDFrom(ctx context.Context) *outputSRID {
+	if ctx == nil {
+		return nil
+	}
+	out, _ := ctx.Value(outputSRIDContextKey{}).(*outputSRID)
+	return out
+}
+
+// RegisterOutputReprojection makes queries run under a context carrying an
+// output SRID (see WithOutputSRID) read the geometry column through PostGIS
+// ST_Transform, for models with a Geometry field. Queries that select the
+// whole row, including "table.*" selects with extra computed columns, are
+// rewritten, whether they are loaded at once or streamed through Rows();
+// queries selecting specific columns are left alone, as are writes, so stored
+// geometries always stay in StorageSRID.
+func RegisterOutputReprojection(db *gorm.DB) error {
+	reproject := func(tx *gorm.DB) {
+		if tx.Error != nil || tx.Statement.Schema == nil || tx.Statement.SQL.Len() > 0 || outputSRIDFrom(tx.Statement.Context) == nil {
+			return
+		}
+		field := tx.Statement.Schema.LookUpField(geometryFieldName)
+		if field == nil || field.DBName == "" {
+			return
+		}
+		var columns string
+		srid, transform := OutputSRIDFromContext(tx.Statement.Context)
+		if transform {
+			columns = reprojectedColumns(tx.Statement, field, srid)
+		}
+		wildcard := tx.Statement.Table + ".*"
+		if c, ok := tx.Statement.Clauses["SELECT"]; ok && c.Expression != nil {
+			// Select with arguments, or Count's count(*)
+			if sel, ok := c.Expression.(clause.Expr); ok && strings.Contains(sel.SQL, wildcard) {
+				markGeometriesRead(tx.Statement.Context)
+				if transform {
+					sel.SQL = strings.Replace(sel.SQL, wildcard, columns, 1)
+					c.Expression = sel
+					tx.Statement.Clauses["SELECT"] = c
+				}
+			}
+			return
+		}
+		if len(tx.Statement.Selects) > 0 {
+			for i, sel := range tx.Statement.Selects {
+				if !strings.Contains(sel, wildcard) {
+					continue
+				}
+				markGeometriesRead(tx.Statement.Context)
+				if transform {
+					tx.Statement.Selects[i] = strings.Replace(sel, wildcard, columns, 1)
+				}
+			}
+			return
+		}
+		markGeometriesRead(tx.Statement.Context)
+		if transform {
+			tx.Statement.AddClause(clause.Select{Expression: clause.Expr{SQL: columns}})
+		}
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Query().Before("gorm:query").Register("crs:reproject", reproject),
+		// Rows() runs the row callbacks rather than the query ones
+		cb.Row().Before("gorm:row").Register("crs:reproject", reproject),
+	)
+}
+
+// reprojectedColumns lists every column of the statement's table, reading the
+// geometry column in srid. Geometries are written without an SRID, so the
+// storage SRID is set before transforming.
+func reprojectedColumns(stmt *gorm.Statement, geometry *schema.Field, srid int) string {
+	columns := make([]string, 0, len(stmt.Schema.DBNames))
+	for _, name := range stmt.Schema.DBNames {
+		column := stmt.Quote(clause.Column{Table: stmt.Table, Name: name})
+		if name == geometry.DBName {
+			column = fmt.Sprintf("ST_Transform(ST_SetSRID(%s, %d), %d) AS %s", column, StorageSRID, srid, stmt.Quote(name))
+		}
+		columns = append(columns, column)
+	}
+	return strings.Join(columns, ", ")
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+)
+
+func TestRegisterOutputReprojection_SQL(t *testing.T) {
+	db := openDryRunDB(t)
+	require.NoError(t, RegisterOutputReprojection(db))
+	mercator := WithOutputSRID(context.Background(), 3857)
+	const transformed = `ST_Transform(ST_SetSRID("systems"."geometry", 4326), 3857) AS "geometry"`
+
+	t.Run("whole rows read the geometry reprojected", func(t *testing.T) {
+		var systems []domains.System
+		sql := db.WithContext(mercator).Where("name = ?", "x").Find(&systems).Statement.SQL.String()
+		assert.Contains(t, sql, transformed)
+		assert.Contains(t, sql, `"systems"."name"`)
+		assert.NotContains(t, sql, "SELECT *")
+	})
+
+	t.Run("wildcard selects with computed columns are rewritten", func(t *testing.T) {
+		var systems []domains.System
+		sql := db.WithContext(mercator).Model(&domains.System{}).Select("systems.*, ? AS distance", 1).Find(&systems).Statement.SQL.String()
+		assert.Contains(t, sql, transformed)
+		assert.Contains(t, sql, "AS distance")
+		assert.NotContains(t, sql, "systems.*")
+
+		var features []domains.SamplingFeature
+		sql = db.WithContext(mercator).Select("sampling_features.*, 1 AS area_m2").Find(&features).Statement.SQL.String()
+		assert.Contains(t, sql, `ST_Transform(ST_SetSRID("sampling_features"."geometry", 4326), 3857) AS "geometry"`)
+		assert.Contains(t, sql, "AS area_m2")
+	})
+
+	t.Run("streamed rows read the geometry reprojected", func(t *testing.T) {
+		tx := db.WithContext(mercator).Model(&domains.System{}).Where("name = ?", "x").Set("rows", true)
+		sql := db.Callback().Row().Execute(tx).Statement.SQL.String()
+		assert.Contains(t, sql, transformed)
+	})
+
+	t.Run("counts and column selects are left alone", func(t *testing.T) {
+		var total int64
+		sql := db.WithContext(mercator).Model(&domains.System{}).Count(&total).Statement.SQL.String()
+		assert.NotContains(t, sql, "ST_Transform")
+
+		var system domains.System
+		sql = db.WithContext(mercator).Select("id", "name").First(&system).Statement.SQL.String()
+		assert.NotContains(t, sql, "ST_Transform")
+	})
+
+	t.Run("storage srid and models without geometry are not reprojected", func(t *testing.T) {
+		var systems []domains.System
+		sql := db.WithContext(WithOutputSRID(context.Background(), StorageSRID)).Find(&systems).Statement.SQL.String()
+		assert.NotContains(t, sql, "ST_Transform")
+
+		var procedures []domains.Procedure
+		sql = db.WithContext(mercator).Find(&procedures).Statement.SQL.String()
+		assert.NotContains(t, sql, "ST_Transform")
+	})
+}
+
+func TestGeometriesRead(t *testing.T) {
+	db := openDryRunDB(t)
+	require.NoError(t, RegisterOutputReprojection(db))
+
+	t.Run("reading geometries is recorded", func(t *testing.T) {
+		for _, srid := range []int{StorageSRID, 3857} {
+			ctx := WithOutputSRID(context.Background(), srid)
+			var count int64
+			db.WithContext(ctx).Model(&domains.System{}).Count(&count)
+			assert.False(t, GeometriesRead(ctx), "counting reads no geometry")
+
+			var systems []domains.System
+			db.WithContext(ctx).Find(&systems)
+			assert.True(t, GeometriesRead(ctx))
+		}
+	})
+
+	t.Run("models without geometries are not", func(t *testing.T) {
+		ctx := WithOutputSRID(context.Background(), 3857)
+		var datastreams []domains.Datastream
+		db.WithContext(ctx).Find(&datastreams)
+		assert.False(t, GeometriesRead(ctx))
+		assert.False(t, GeometriesRead(context.Background()))
+	})
+}
@@ -19,9 +19,23 @@ func NewProcedureRepository(db *gorm.DB) *ProcedureRepository {
 	return &ProcedureRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *ProcedureRepository) WithContext(ctx context.Context) *ProcedureRepository {
+	return &ProcedureRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create creates a new procedure
 func (r *ProcedureRepository) Create(procedure *domains.Procedure) error {
-	return r.db.Create(procedure).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Create(procedure).Error
+	})
+}
+
+// UniqueIDInUse reports whether another procedure already has the unique
+// identifier uid. A blank uid is never in use.
+func (r *ProcedureRepository) UniqueIDInUse(uid string) (bool, error) {
+	return uniqueIDInUse(r.db, &domains.Procedure{}, uid)
 }
 
 // GetByID retrieves a procedure by ID
@@ -34,6 +48,16 @@ func (r *ProcedureRepository) GetByID(id string) (*domains.Procedure, error) {
 	return &procedure, nil
 }
 
+// GetByUID retrieves a procedure by unique identifier
+func (r *ProcedureRepository) GetByUID(uid string) (*domains.Procedure, error) {
+	var procedure domains.Procedure
+	err := r.db.Where("unique_identifier = ?", uid).First(&procedure).Error
+	if err != nil {
+		return nil, err
+	}
+	return &procedure, nil
+}
+
 // List retrieves procedures with filtering
 func (r *ProcedureRepository) List(params *queryparams.ProceduresQueryParams) ([]*domains.Procedure, int64, error) {
 	var procedures []*domains.Procedure
@@ -85,12 +109,16 @@ func (r *ProcedureRepository) ListBySystem(systemID string, params *queryparams.
 
 // Update updates a procedure
 func (r *ProcedureRepository) Update(procedure *domains.Procedure) error {
-	return r.db.Save(procedure).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Save(procedure).Error
+	})
 }
 
 // Delete deletes a procedure
 func (r *ProcedureRepository) Delete(id string) error {
-	return r.db.Delete(&domains.Procedure{}, "id = ?", id).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Delete(&domains.Procedure{}, "id = ?", id).Error
+	})
 }
 
 func (r *ProcedureRepository) applyFilters(query *gorm.DB, params *queryparams.ProceduresQueryParams) *gorm.DB {
@@ -122,6 +150,10 @@ func (r *ProcedureRepository) applyFilters(query *gorm.DB, params *queryparams.P
 		}
 	}
 
+	if len(params.ProcessType) > 0 {
+		query = query.Where("procedures.process_type IN ?", params.ProcessType)
+	}
+
 	if len(params.ControlledProperty) > 0 {
 		query = query.Joins("JOIN procedure_controlled_properties ON procedures.id = procedure_controlled_properties.procedure_id").
 			Where("procedure_controlled_properties.property_id IN ?", params.ControlledProperty)
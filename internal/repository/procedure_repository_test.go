@@ -117,18 +117,21 @@ func TestProcedureRepository_List(t *testing.T) {
 	proc1 := &domains.Procedure{
 		CommonSSN:     domains.CommonSSN{UniqueIdentifier: "urn:test:proc1", Name: "Temperature Method"},
 		ProcedureType: domains.ProcedureTypeActuating,
+		ProcessType:   domains.ProcessTypeSimple,
 	}
 	require.NoError(t, repo.Create(proc1))
 
 	proc2 := &domains.Procedure{
 		CommonSSN:     domains.CommonSSN{UniqueIdentifier: "urn:test:proc2", Name: "Humidity Method"},
 		ProcedureType: domains.ProcedureTypeObserving,
+		ProcessType:   domains.ProcessTypeAggregate,
 	}
 	require.NoError(t, repo.Create(proc2))
 
 	proc3 := &domains.Procedure{
 		CommonSSN:     domains.CommonSSN{UniqueIdentifier: "urn:test:proc3", Name: "Sensor Datasheet", Description: "Complete specifications"},
 		ProcedureType: "Datasheet",
+		ProcessType:   domains.ProcessTypePhysicalSystem,
 	}
 	require.NoError(t, repo.Create(proc3))
 
@@ -208,6 +211,40 @@ func TestProcedureRepository_List(t *testing.T) {
 				require.Equal(t, "Humidity Method", procedures[0].Name)
 			},
 		},
+		{
+			name: "filter by process type",
+			params: &queryparams.ProceduresQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				ProcessType: []string{domains.ProcessTypePhysicalSystem},
+			},
+			wantCount: 1,
+			wantTotal: 1,
+			checkFunc: func(t *testing.T, procedures []*domains.Procedure) {
+				require.Equal(t, "Sensor Datasheet", procedures[0].Name)
+			},
+		},
+		{
+			name: "filter by several process types is OR-combined",
+			params: &queryparams.ProceduresQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				ProcessType: []string{domains.ProcessTypeSimple, domains.ProcessTypeAggregate},
+			},
+			wantCount: 2,
+			wantTotal: 2,
+			checkFunc: func(t *testing.T, procedures []*domains.Procedure) {
+				names := []string{procedures[0].Name, procedures[1].Name}
+				require.ElementsMatch(t, []string{"Temperature Method", "Humidity Method"}, names)
+			},
+		},
+		{
+			name: "filter by process type without matches",
+			params: &queryparams.ProceduresQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				ProcessType: []string{domains.ProcessTypePhysicalComponent},
+			},
+			wantCount: 0,
+			wantTotal: 0,
+		},
 		{
 			name: "query test - search by name",
 			params: &queryparams.ProceduresQueryParams{
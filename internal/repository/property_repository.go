@@ -20,9 +20,42 @@ func NewPropertyRepository(db *gorm.DB) *PropertyRepository {
 	return &PropertyRepository{db: db}
 }
 
-// Create creates a new property
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *PropertyRepository) WithContext(ctx context.Context) *PropertyRepository {
+	return &PropertyRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create creates a new property. It returns a *DuplicateUIDError when the
+// property's unique identifier is already taken.
 func (r *PropertyRepository) Create(property *domains.Property) error {
-	return r.db.Create(property).Error
+	uid := string(property.UniqueIdentifier)
+	inUse, err := r.UniqueIDInUse(uid)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return &DuplicateUIDError{UID: uid}
+	}
+
+	// The check above can race a concurrent insert; the unique index still
+	// catches that case.
+	err = retryWrites(r.db, func() error {
+		return r.db.Create(property).Error
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return &DuplicateUIDError{UID: uid}
+		}
+		return err
+	}
+	return nil
+}
+
+// UniqueIDInUse reports whether another property already has the unique
+// identifier uid. A blank uid is never in use.
+func (r *PropertyRepository) UniqueIDInUse(uid string) (bool, error) {
+	return uniqueIDInUse(r.db, &domains.Property{}, uid)
 }
 
 // GetByID retrieves a property by ID
@@ -35,6 +68,16 @@ func (r *PropertyRepository) GetByID(id string) (*domains.Property, error) {
 	return &property, nil
 }
 
+// GetByUID retrieves a property by unique identifier
+func (r *PropertyRepository) GetByUID(uid string) (*domains.Property, error) {
+	var property domains.Property
+	err := r.db.Where("unique_identifier = ?", uid).First(&property).Error
+	if err != nil {
+		return nil, err
+	}
+	return &property, nil
+}
+
 // List retrieves properties with filtering
 func (r *PropertyRepository) List(params *queryparams.PropertiesQueryParams) ([]*domains.Property, int64, error) {
 	var properties []*domains.Property
@@ -58,14 +101,26 @@ func (r *PropertyRepository) List(params *queryparams.PropertiesQueryParams) ([]
 	return properties, total, err
 }
 
-// Update updates a property
+// Update updates a property. It returns a *DuplicateUIDError when the new
+// unique identifier belongs to another property.
 func (r *PropertyRepository) Update(property *domains.Property) error {
-	return r.db.Save(property).Error
+	err := retryWrites(r.db, func() error {
+		return r.db.Save(property).Error
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return &DuplicateUIDError{UID: string(property.UniqueIdentifier)}
+		}
+		return err
+	}
+	return nil
 }
 
 // Delete deletes a property
 func (r *PropertyRepository) Delete(id string) error {
-	return r.db.Delete(&domains.Property{}, "id = ?", id).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Delete(&domains.Property{}, "id = ?", id).Error
+	})
 }
 
 func (r *PropertyRepository) applyFilters(query *gorm.DB, params *queryparams.PropertiesQueryParams) *gorm.DB {
@@ -118,6 +118,26 @@ func TestPropertyRepository_Create(t *testing.T) {
 	}
 }
 
+func TestPropertyRepository_Create_DuplicateUID(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewPropertyRepository(db)
+
+	first := &domains.Property{CommonSSN: domains.CommonSSN{UniqueIdentifier: "urn:test:property:dup", Name: "First"}}
+	require.NoError(t, repo.Create(first))
+
+	second := &domains.Property{CommonSSN: domains.CommonSSN{UniqueIdentifier: "urn:test:property:dup", Name: "Second"}}
+	err := repo.Create(second)
+	var duplicate *DuplicateUIDError
+	require.ErrorAs(t, err, &duplicate)
+	require.Equal(t, "urn:test:property:dup", duplicate.UID)
+
+	other := &domains.Property{CommonSSN: domains.CommonSSN{UniqueIdentifier: "urn:test:property:other", Name: "Other"}}
+	require.NoError(t, repo.Create(other))
+	other.UniqueIdentifier = "urn:test:property:dup"
+	require.ErrorAs(t, repo.Update(other), &duplicate)
+}
+
 func TestPropertyRepository_GetByID(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	queryTimeoutCancelKey = "query_timeout:cancel"
+	queryTimeoutParentKey = "query_timeout:parent"
+)
+
+// RegisterQueryTimeout bounds every create, query, update, delete and raw
+// statement run through db by timeout, on top of whatever context the
+// statement already carries (such as the request context passed with
+// WithContext). A timeout of zero or less disables the limit.
+//
+// The Statement, and so its context, is shared by chained calls such as a
+// Count followed by a Find, so each statement restores the context it started
+// with once it finishes rather than leaving its cancelled one behind.
+func RegisterQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	start := func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryTimeoutParentKey, parent)
+		tx.InstanceSet(queryTimeoutCancelKey, cancel)
+	}
+	finish := func(tx *gorm.DB) {
+		if cancel, ok := tx.InstanceGet(queryTimeoutCancelKey); ok {
+			cancel.(context.CancelFunc)()
+		}
+		if parent, ok := tx.InstanceGet(queryTimeoutParentKey); ok {
+			tx.Statement.Context = parent.(context.Context)
+		}
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("query_timeout:start", start),
+		cb.Create().After("*").Register("query_timeout:finish", finish),
+		cb.Query().Before("*").Register("query_timeout:start", start),
+		cb.Query().After("*").Register("query_timeout:finish", finish),
+		cb.Update().Before("*").Register("query_timeout:start", start),
+		cb.Update().After("*").Register("query_timeout:finish", finish),
+		cb.Delete().Before("*").Register("query_timeout:start", start),
+		cb.Delete().After("*").Register("query_timeout:finish", finish),
+		cb.Raw().Before("*").Register("query_timeout:start", start),
+		cb.Raw().After("*").Register("query_timeout:finish", finish),
+	)
+}
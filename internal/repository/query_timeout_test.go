@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"gorm.io/gorm"
+)
+
+func TestRepository_WithContext_CanceledContext(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSystemRepository(db)
+	system := &domains.System{
+		CommonSSN: domains.CommonSSN{
+			UniqueIdentifier: "urn:test:system:canceled",
+			Name:             "Canceled Context System",
+		},
+		SystemType: domains.SystemTypeSensor,
+	}
+	require.NoError(t, repo.Create(system))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := repo.WithContext(ctx).GetByID(system.ID)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("query with a canceled context did not return")
+	}
+
+	// The original repository is unaffected
+	found, err := repo.GetByID(system.ID)
+	require.NoError(t, err)
+	require.Equal(t, system.ID, found.ID)
+}
+
+func TestRegisterQueryTimeout(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, RegisterQueryTimeout(db, 100*time.Millisecond))
+
+	start := time.Now()
+	err := db.Exec("SELECT pg_sleep(5)").Error
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 4*time.Second, "query should be canceled by the timeout")
+
+	// Queries that finish in time are unaffected
+	var one int
+	require.NoError(t, db.Raw("SELECT 1").Scan(&one).Error)
+	require.Equal(t, 1, one)
+}
+
+// Count and Find on one query share its Statement; the Find must not inherit
+// the context the Count's timeout cancelled.
+func TestRegisterQueryTimeout_ChainedQueries(t *testing.T) {
+	db := openDryRunDB(t)
+	require.NoError(t, RegisterQueryTimeout(db, time.Minute))
+
+	var ctxErrs []error
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:ctx", func(tx *gorm.DB) {
+		ctxErrs = append(ctxErrs, tx.Statement.Context.Err())
+	}))
+
+	query := db.Model(&domains.System{}).Where("name = ?", "chained")
+	var count int64
+	require.NoError(t, query.Count(&count).Error)
+	var systems []domains.System
+	require.NoError(t, query.Find(&systems).Error)
+
+	require.Len(t, ctxErrs, 2)
+	require.NoError(t, ctxErrs[0])
+	require.NoError(t, ctxErrs[1], "the Find ran with the Count's cancelled context")
+}
+
+func TestRegisterQueryTimeout_CountThenFind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, RegisterQueryTimeout(db, 30*time.Second))
+	repo := NewSystemRepository(db)
+	require.NoError(t, repo.Create(&domains.System{
+		CommonSSN:  domains.CommonSSN{UniqueIdentifier: "urn:test:system:count-find", Name: "Count Then Find"},
+		SystemType: domains.SystemTypeSensor,
+	}))
+
+	query := db.Model(&domains.System{})
+	var count int64
+	require.NoError(t, query.Count(&count).Error)
+	var systems []domains.System
+	require.NoError(t, query.Find(&systems).Error)
+	require.Len(t, systems, int(count))
+}
@@ -54,6 +54,7 @@ func AutoMigrate(db *gorm.DB) error {
 		&domains.Deployment{},
 		&domains.Procedure{},
 		&domains.SamplingFeature{},
+		&domains.SamplingFeatureSampleOf{},
 		&domains.Property{},
 		&domains.Feature{},
 		&domains.Datastream{},
@@ -68,6 +69,10 @@ func AutoMigrate(db *gorm.DB) error {
 		return err
 	}
 
+	if err := dropGlobalUIDConstraints(db); err != nil {
+		return err
+	}
+
 	// Ensure generic closure support for deployments (creates triggers/functions)
 	if err := EnsureClosureSupport(db, "deployments", "id", "parent_deployment_id", "deployment_closures"); err != nil {
 		return err
@@ -78,6 +83,43 @@ func AutoMigrate(db *gorm.DB) error {
 		return err
 	}
 
+	// Trigram indexes back the q filter's ILIKE '%term%' matching
+	if err := EnsureTrigramSearchIndexes(db); err != nil {
+		return err
+	}
+
+	return recordSchemaVersion(db)
+}
+
+// uidTables are the tables of the resources carrying a unique identifier
+// (domains.CommonSSN).
+var uidTables = []string{
+	"systems",
+	"deployments",
+	"procedures",
+	"sampling_features",
+	"properties",
+	"features",
+	"datastreams",
+	"control_streams",
+}
+
+// dropGlobalUIDConstraints drops the unique constraint and index that made
+// unique identifiers unique across all tenants before schema version 7. The
+// per-tenant idx_<table>_tenant_uid index created by AutoMigrate replaces
+// them.
+func dropGlobalUIDConstraints(db *gorm.DB) error {
+	for _, table := range uidTables {
+		statements := []string{
+			fmt.Sprintf(`ALTER TABLE IF EXISTS "%s" DROP CONSTRAINT IF EXISTS "%s_unique_identifier_key"`, table, table),
+			fmt.Sprintf(`DROP INDEX IF EXISTS "idx_%s_unique_identifier"`, table),
+		}
+		for _, statement := range statements {
+			if err := db.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
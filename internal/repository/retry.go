@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// PostgreSQL SQLSTATEs for a transaction aborted because of concurrent
+// activity. Running the same transaction again is expected to succeed.
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// Writes failing with a retryable error are attempted up to maxWriteAttempts
+// times. The wait before the second attempt is writeRetryBaseDelay plus
+// jitter, and it doubles for each further attempt.
+var (
+	maxWriteAttempts    = 4
+	writeRetryBaseDelay = 20 * time.Millisecond
+)
+
+// isRetryableWriteError reports whether err is a serialization failure or a
+// deadlock raised by PostgreSQL.
+func isRetryableWriteError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected)
+}
+
+// retryWrites runs write, running it again with exponential backoff while it
+// fails with a retryable error, up to maxWriteAttempts in total. write must
+// be safe to repeat: a single statement, or a whole transaction. The wait is
+// cut short, returning the last error, when db's context is done.
+func retryWrites(db *gorm.DB, write func() error) error {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	delay := writeRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if err == nil || attempt >= maxWriteAttempts || !isRetryableWriteError(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay + rand.N(delay))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// retryTransaction runs fn in a transaction on db, starting the transaction
+// over when PostgreSQL aborts it with a serialization failure or a deadlock,
+// so fn may run several times. When db is already inside a transaction the
+// error is returned as is: the enclosing transaction is aborted as well and
+// only its owner can retry it.
+func retryTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if _, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter); inTransaction {
+		return db.Transaction(fn)
+	}
+	return retryWrites(db, func() error {
+		return db.Transaction(fn)
+	})
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// failingDriver wraps the pgx driver and fails the next statements starting
+// with a given prefix with a serialization failure, as PostgreSQL does when a
+// concurrent transaction wins a conflict.
+type failingDriver struct {
+	driver.Driver
+
+	mu        sync.Mutex
+	prefix    string
+	remaining int
+	failures  int
+}
+
+// failNext makes the next n statements starting with prefix fail.
+func (d *failingDriver) failNext(prefix string, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.prefix, d.remaining, d.failures = prefix, n, 0
+}
+
+func (d *failingDriver) failureCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.failures
+}
+
+func (d *failingDriver) injectedError(query string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.remaining == 0 || !strings.HasPrefix(strings.TrimSpace(query), d.prefix) {
+		return nil
+	}
+	d.remaining--
+	d.failures++
+	return &pgconn.PgError{Severity: "ERROR", Code: pgSerializationFailure, Message: "could not serialize access due to concurrent update"}
+}
+
+func (d *failingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &failingConn{Conn: conn, driver: d}, nil
+}
+
+// failingConn forwards to the pgx connection, failing statements chosen by
+// its driver.
+type failingConn struct {
+	driver.Conn
+	driver *failingDriver
+}
+
+func (c *failingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.driver.injectedError(query); err != nil {
+		return nil, err
+	}
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func (c *failingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.driver.injectedError(query); err != nil {
+		return nil, err
+	}
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c *failingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *failingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+}
+
+func (c *failingConn) CheckNamedValue(value *driver.NamedValue) error {
+	return c.Conn.(driver.NamedValueChecker).CheckNamedValue(value)
+}
+
+func (c *failingConn) ResetSession(ctx context.Context) error {
+	return c.Conn.(driver.SessionResetter).ResetSession(ctx)
+}
+
+type failingConnector struct {
+	dsn    string
+	driver *failingDriver
+}
+
+func (c *failingConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *failingConnector) Driver() driver.Driver { return c.driver }
+
+// openFailingDB opens a second connection to the database behind db through a
+// failingDriver.
+func openFailingDB(t *testing.T, db *gorm.DB) (*gorm.DB, *failingDriver) {
+	t.Helper()
+	dialector, ok := db.Dialector.(*postgres.Dialector)
+	require.True(t, ok)
+
+	failing := &failingDriver{Driver: stdlib.GetDefaultDriver()}
+	sqlDB := sql.OpenDB(&failingConnector{dsn: dialector.Config.DSN, driver: failing})
+	t.Cleanup(func() { sqlDB.Close() })
+
+	wrapped, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err)
+	return wrapped, failing
+}
+
+func TestRepository_RetriesSerializationFailures(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	baseDelay := writeRetryBaseDelay
+	writeRetryBaseDelay = time.Millisecond
+	t.Cleanup(func() { writeRetryBaseDelay = baseDelay })
+
+	wrapped, failing := openFailingDB(t, db)
+	repo := NewSystemRepository(wrapped)
+
+	newSystem := func(uid string, parentID *string) *domains.System {
+		return &domains.System{
+			CommonSSN:      domains.CommonSSN{UniqueIdentifier: domains.UniqueID(uid), Name: uid},
+			SystemType:     domains.SystemTypeSensor,
+			ParentSystemID: parentID,
+		}
+	}
+	count := func(t *testing.T) int64 {
+		t.Helper()
+		var n int64
+		require.NoError(t, db.Model(&domains.System{}).Count(&n).Error)
+		return n
+	}
+
+	t.Run("create succeeds after transient failures", func(t *testing.T) {
+		failing.failNext(`INSERT INTO "systems"`, maxWriteAttempts-1)
+		system := newSystem("urn:test:retry:create", nil)
+
+		require.NoError(t, repo.Create(system))
+		require.Equal(t, maxWriteAttempts-1, failing.failureCount())
+
+		stored, err := NewSystemRepository(db).GetByID(system.ID)
+		require.NoError(t, err)
+		require.Equal(t, "urn:test:retry:create", string(stored.UniqueIdentifier))
+	})
+
+	t.Run("cascade delete reruns the whole transaction", func(t *testing.T) {
+		parent := newSystem("urn:test:retry:parent", nil)
+		require.NoError(t, NewSystemRepository(db).Create(parent))
+		child := newSystem("urn:test:retry:child", &parent.ID)
+		require.NoError(t, NewSystemRepository(db).Create(child))
+
+		// The child's row is deleted before the parent's in the same
+		// transaction; each injected failure aborts the transaction and the
+		// whole cascade is rerun.
+		failing.failNext(`DELETE FROM "systems" WHERE id = $1`, 2)
+		require.NoError(t, repo.Delete(parent.ID, true))
+		require.Equal(t, 2, failing.failureCount())
+
+		_, err := NewSystemRepository(db).GetByID(parent.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = NewSystemRepository(db).GetByID(child.ID)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("gives up after maxWriteAttempts", func(t *testing.T) {
+		before := count(t)
+		failing.failNext(`INSERT INTO "systems"`, maxWriteAttempts)
+
+		err := repo.Create(newSystem("urn:test:retry:exhausted", nil))
+		require.Error(t, err)
+		require.True(t, isRetryableWriteError(err), "expected the serialization failure, got %v", err)
+		require.Equal(t, maxWriteAttempts, failing.failureCount())
+		require.Equal(t, before, count(t))
+	})
+}
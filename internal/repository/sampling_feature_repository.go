@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
@@ -8,6 +11,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrUnfilteredDelete is returned by a bulk delete called without any filter.
+// It matches ErrValidation.
+var ErrUnfilteredDelete error = &kindError{kind: ErrValidation, err: errors.New("bulk delete requires at least one filter")}
+
+// UnknownSampleOfError is returned when the sampleOf links of a sampling
+// feature name local sampling features that do not exist.
+type UnknownSampleOfError struct {
+	IDs []string
+}
+
+func (e *UnknownSampleOfError) Error() string {
+	return fmt.Sprintf("sampleOf references unknown sampling features: %s", strings.Join(e.IDs, ", "))
+}
+
+// Is makes an UnknownSampleOfError match ErrValidation.
+func (e *UnknownSampleOfError) Is(target error) bool {
+	return target == ErrValidation
+}
+
 // SamplingFeatureRepository handles SamplingFeature data access
 type SamplingFeatureRepository struct {
 	db *gorm.DB
@@ -18,9 +40,30 @@ func NewSamplingFeatureRepository(db *gorm.DB) *SamplingFeatureRepository {
 	return &SamplingFeatureRepository{db: db}
 }
 
-// Create creates a new sampling feature
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *SamplingFeatureRepository) WithContext(ctx context.Context) *SamplingFeatureRepository {
+	return &SamplingFeatureRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create creates a new sampling feature together with its sampleOf relations.
+// The feature row and the relation rows are written in one transaction, so a
+// failure part-way leaves neither behind. The relations are those in
+// sf.SampleOfIDs, which callers resolve from the sampleOf links. It returns an
+// *UnknownSampleOfError when one of them does not exist.
 func (r *SamplingFeatureRepository) Create(sf *domains.SamplingFeature) error {
-	return r.db.Create(sf).Error
+	return retryTransaction(r.db, func(tx *gorm.DB) error {
+		if err := tx.Create(sf).Error; err != nil {
+			return err
+		}
+		return createSampleOfRelations(tx, sf)
+	})
+}
+
+// UniqueIDInUse reports whether another sampling feature already has the unique
+// identifier uid. A blank uid is never in use.
+func (r *SamplingFeatureRepository) UniqueIDInUse(uid string) (bool, error) {
+	return uniqueIDInUse(r.db, &domains.SamplingFeature{}, uid)
 }
 
 // GetByID retrieves a sampling feature by ID
@@ -33,23 +76,38 @@ func (r *SamplingFeatureRepository) GetByID(id string) (*domains.SamplingFeature
 	return &sf, nil
 }
 
+// GetBySystemAndID retrieves a sampling feature by ID, only if its parent
+// system is systemID
+func (r *SamplingFeatureRepository) GetBySystemAndID(systemID, id string) (*domains.SamplingFeature, error) {
+	var sf domains.SamplingFeature
+	err := r.db.Where("parent_system_id = ? AND id = ?", systemID, id).First(&sf).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sf, nil
+}
+
 // List retrieves sampling features with filtering
 func (r *SamplingFeatureRepository) List(params *queryparams.SamplingFeatureQueryParams) ([]*domains.SamplingFeature, int64, error) {
 	return r.ListSystem(params, nil)
 }
 
-// List retrieves sampling features with filtering
-func (r *SamplingFeatureRepository) ListSystem(params *queryparams.SamplingFeatureQueryParams, systemID *string) ([]*domains.SamplingFeature, int64, error) {
+// ListSystem retrieves sampling features with filtering, restricted to those
+// whose parent system is one of systemIDs when systemIDs is non-nil
+func (r *SamplingFeatureRepository) ListSystem(params *queryparams.SamplingFeatureQueryParams, systemIDs []string) ([]*domains.SamplingFeature, int64, error) {
 	var features []*domains.SamplingFeature
 	var total int64
 
 	query := r.db.Model(&domains.SamplingFeature{})
-	query = r.applyFilters(query, params, systemID)
+	query = r.applyFilters(query, params, systemIDs)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
+	// Stable ordering keeps offset pagination consistent across pages
+	query = orderBySort(query, "sampling_features", params.SortBy, params.SortDesc)
+
 	if params.Limit > 0 {
 		query = query.Limit(params.Limit)
 	}
@@ -57,25 +115,98 @@ func (r *SamplingFeatureRepository) ListSystem(params *queryparams.SamplingFeatu
 		query = query.Offset(params.Offset)
 	}
 
-	err := query.Find(&features).Error
-	return features, total, err
+	// Only the requested measures are computed; each stays NULL for geometry
+	// types it does not apply to
+	computed := []string{}
+	if params.Computes(queryparams.ComputedArea) {
+		computed = append(computed, geodesicArea("sampling_features.geometry")+" AS area_m2")
+	}
+	if params.Computes(queryparams.ComputedLength) {
+		computed = append(computed, geodesicLength("sampling_features.geometry")+" AS length_m")
+	}
+	if len(computed) > 0 {
+		query = query.Select("sampling_features.*, " + strings.Join(computed, ", "))
+	}
+
+	if err := omitGeometry(query, params.SkipGeometry).Find(&features).Error; err != nil {
+		return nil, 0, err
+	}
+	if params.SkipGeometry {
+		// Computed measures select sampling_features.* explicitly, which Omit cannot narrow
+		for _, sf := range features {
+			sf.Geometry = nil
+		}
+	}
+	return features, total, nil
 }
 
-// Update updates a sampling feature
+// Update updates a sampling feature and replaces its sampleOf relations. Like
+// Create, it returns an *UnknownSampleOfError for a dangling id in
+// sf.SampleOfIDs.
 func (r *SamplingFeatureRepository) Update(sf *domains.SamplingFeature) error {
-	return r.db.Save(sf).Error
+	return retryTransaction(r.db, func(tx *gorm.DB) error {
+		if err := tx.Save(sf).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("sampling_feature_id = ?", sf.ID).Delete(&domains.SamplingFeatureSampleOf{}).Error; err != nil {
+			return err
+		}
+		return createSampleOfRelations(tx, sf)
+	})
 }
 
 // Delete deletes a sampling feature
 func (r *SamplingFeatureRepository) Delete(id string) error {
-	return r.db.Delete(&domains.SamplingFeature{}, "id = ?", id).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Delete(&domains.SamplingFeature{}, "id = ?", id).Error
+	})
 }
 
-func (r *SamplingFeatureRepository) applyFilters(query *gorm.DB, params *queryparams.SamplingFeatureQueryParams, systemID *string) *gorm.DB {
+// DeleteMatching deletes every sampling feature matching the filters in params
+// in one transaction and returns how many were deleted. Paging is ignored. It
+// returns ErrUnfilteredDelete when params carry no filter, so it can never
+// empty the table.
+func (r *SamplingFeatureRepository) DeleteMatching(params *queryparams.SamplingFeatureQueryParams) (int64, error) {
+	if !hasSamplingFeatureFilter(params) {
+		return 0, ErrUnfilteredDelete
+	}
+
+	var deleted int64
+	err := retryTransaction(r.db, func(tx *gorm.DB) error {
+		matching := r.applyFilters(tx.Model(&domains.SamplingFeature{}).Select("sampling_features.id"), params, nil)
+		result := tx.Where("id IN (?)", matching).Delete(&domains.SamplingFeature{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// hasSamplingFeatureFilter reports whether params carry at least one filter
+// that applyFilters turns into a predicate. A dateTime with both bounds open
+// adds none, so it does not count.
+func hasSamplingFeatureFilter(params *queryparams.SamplingFeatureQueryParams) bool {
+	return len(params.IDs) > 0 ||
+		len(params.System) > 0 ||
+		len(params.FeatureType) > 0 ||
+		len(params.FOI) > 0 ||
+		len(params.Q) > 0 ||
+		(params.DateTime != nil && (params.DateTime.Start != nil || params.DateTime.End != nil)) ||
+		params.Bbox != nil ||
+		params.Geom != ""
+}
+
+func (r *SamplingFeatureRepository) applyFilters(query *gorm.DB, params *queryparams.SamplingFeatureQueryParams, systemIDs []string) *gorm.DB {
 	if len(params.IDs) > 0 {
 		query = query.Where("id IN ? OR unique_identifier IN ?", params.IDs, params.IDs)
 	}
 
+	if len(params.FeatureType) > 0 {
+		query = query.Where("feature_type IN ?", params.FeatureType)
+	}
+
 	if len(params.Q) > 0 {
 		query = query.Where("name ILIKE ? OR description ILIKE ?", "%"+strings.Join(params.Q, "%")+"%", "%"+strings.Join(params.Q, "%")+"%")
 	}
@@ -91,9 +222,7 @@ func (r *SamplingFeatureRepository) applyFilters(query *gorm.DB, params *querypa
 		}
 	}
 
-	if params.Bbox != nil {
-		query = query.Where("ST_Intersects(geometry, ST_MakeEnvelope(?, ?, ?, ?, 4326))", params.Bbox.MinX, params.Bbox.MinY, params.Bbox.MaxX, params.Bbox.MaxY)
-	}
+	query = whereIntersectsBbox(query, "geometry", params.Bbox)
 
 	if params.Geom != "" {
 		query = query.Where("ST_Intersects(geometry, ST_GeomFromText(?, 4326))", params.Geom)
@@ -104,9 +233,82 @@ func (r *SamplingFeatureRepository) applyFilters(query *gorm.DB, params *querypa
 			Where("sff.foi_id IN ?", params.FOI)
 	}
 
-	if systemID != nil {
-		query = query.Where("parent_system_id = ?", *systemID)
+	if systemIDs != nil {
+		query = query.Where("parent_system_id IN ?", systemIDs)
+	}
+
+	if len(params.System) > 0 {
+		query = query.Where("parent_system_id IN ?", params.System)
 	}
 
 	return query
 }
+
+// createSampleOfRelations inserts one relation row per local sampling feature
+// in the SampleOfIDs of sf. It returns an *UnknownSampleOfError when any of
+// them does not exist.
+func createSampleOfRelations(tx *gorm.DB, sf *domains.SamplingFeature) error {
+	ids := sampleOfIDs(sf)
+	if err := checkSampleOf(tx, ids); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	relations := make([]domains.SamplingFeatureSampleOf, 0, len(ids))
+	for _, id := range ids {
+		relations = append(relations, domains.SamplingFeatureSampleOf{SamplingFeatureID: sf.ID, SampleOfID: id})
+	}
+	return tx.Create(&relations).Error
+}
+
+// CheckSampleOf returns an *UnknownSampleOfError naming the ids that are not
+// existing sampling features, so a payload can be validated without being
+// written.
+func (r *SamplingFeatureRepository) CheckSampleOf(ids []string) error {
+	return checkSampleOf(r.db, ids)
+}
+
+func checkSampleOf(tx *gorm.DB, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var existing []string
+	if err := tx.Model(&domains.SamplingFeature{}).Where("id IN ?", ids).Pluck("id", &existing).Error; err != nil {
+		return err
+	}
+	if len(existing) == len(ids) {
+		return nil
+	}
+	found := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+	unknown := &UnknownSampleOfError{}
+	for _, id := range ids {
+		if !found[id] {
+			unknown.IDs = append(unknown.IDs, id)
+		}
+	}
+	return unknown
+}
+
+// sampleOfIDs returns the distinct SampleOfIDs of sf.
+func sampleOfIDs(sf *domains.SamplingFeature) []string {
+	if sf.SampleOfIDs == nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(*sf.SampleOfIDs))
+	seen := make(map[string]bool, len(*sf.SampleOfIDs))
+	for _, id := range *sf.SampleOfIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
 	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
 	"github.com/yourusername/connected-systems-go/internal/repository/testutil"
+	"gorm.io/gorm"
 )
 
 func TestSamplingFeatureRepository_Create(t *testing.T) {
@@ -128,6 +131,30 @@ func TestSamplingFeatureRepository_GetByID(t *testing.T) {
 	}
 }
 
+func TestSamplingFeatureRepository_GetBySystemAndID(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSamplingFeatureRepository(db)
+
+	systemA := "system-a"
+	sf := &domains.SamplingFeature{
+		CommonSSN:      domains.CommonSSN{UniqueIdentifier: "urn:test:scoped1", Name: "Scoped SamplingFeature"},
+		FeatureType:    "Point",
+		ParentSystemID: &systemA,
+	}
+	require.NoError(t, repo.Create(sf))
+
+	got, err := repo.GetBySystemAndID(systemA, sf.ID)
+	require.NoError(t, err)
+	require.Equal(t, sf.ID, got.ID)
+
+	_, err = repo.GetBySystemAndID("system-b", sf.ID)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = repo.GetBySystemAndID(systemA, "non-existent-id")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
 func TestSamplingFeatureRepository_List(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -136,21 +163,21 @@ func TestSamplingFeatureRepository_List(t *testing.T) {
 	// Setup: create multiple test sampling features
 	sf1 := &domains.SamplingFeature{
 		CommonSSN:   domains.CommonSSN{UniqueIdentifier: "urn:test:sf1", Name: "Point Feature 1"},
-		FeatureType: "Point",
+		FeatureType: domains.SamplingFeatureTypeSamplingPoint,
 		Geometry:    testutil.MakePoint(-122.4194, 37.7749),
 	}
 	require.NoError(t, repo.Create(sf1))
 
 	sf2 := &domains.SamplingFeature{
 		CommonSSN:   domains.CommonSSN{UniqueIdentifier: "urn:test:sf2", Name: "Curve Feature 1"},
-		FeatureType: "Curve",
+		FeatureType: domains.SamplingFeatureTypeSamplingCurve,
 		Geometry:    testutil.MakeLineString([]float64{-122.0, 37.0, -123.0, 38.0}),
 	}
 	require.NoError(t, repo.Create(sf2))
 
 	sf3 := &domains.SamplingFeature{
 		CommonSSN:   domains.CommonSSN{UniqueIdentifier: "urn:test:sf3", Name: "Surface Feature 1", Description: "Test surface"},
-		FeatureType: "Surface",
+		FeatureType: domains.SamplingFeatureTypeSamplingSurface,
 		Geometry:    testutil.MakePolygon([]float64{-122.0, 37.0, -123.0, 37.0, -123.0, 38.0, -122.0, 38.0, -122.0, 37.0}),
 	}
 	require.NoError(t, repo.Create(sf3))
@@ -231,6 +258,59 @@ func TestSamplingFeatureRepository_List(t *testing.T) {
 				require.Equal(t, "Surface Feature 1", features[0].Name)
 			},
 		},
+		{
+			name: "filter by featureType",
+			params: &queryparams.SamplingFeatureQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				FeatureType: []string{domains.SamplingFeatureTypeSamplingPoint},
+			},
+			wantCount: 1,
+			wantTotal: 1,
+			checkFunc: func(t *testing.T, features []*domains.SamplingFeature) {
+				require.Len(t, features, 1)
+				require.Equal(t, sf1.ID, features[0].ID)
+			},
+		},
+		{
+			name: "filter by multiple featureTypes is OR-combined",
+			params: &queryparams.SamplingFeatureQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				FeatureType: []string{domains.SamplingFeatureTypeSamplingCurve, domains.SamplingFeatureTypeSamplingSurface},
+			},
+			wantCount: 2,
+			wantTotal: 2,
+			checkFunc: func(t *testing.T, features []*domains.SamplingFeature) {
+				ids := []string{features[0].ID, features[1].ID}
+				require.ElementsMatch(t, []string{sf2.ID, sf3.ID}, ids)
+			},
+		},
+		{
+			name: "sortby name",
+			params: &queryparams.SamplingFeatureQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				SortBy:      queryparams.SortByName,
+			},
+			wantCount: 3,
+			wantTotal: 3,
+			checkFunc: func(t *testing.T, features []*domains.SamplingFeature) {
+				require.Equal(t, []string{sf2.ID, sf1.ID, sf3.ID},
+					[]string{features[0].ID, features[1].ID, features[2].ID})
+			},
+		},
+		{
+			name: "sortby created descending",
+			params: &queryparams.SamplingFeatureQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				SortBy:      queryparams.SortByCreated,
+				SortDesc:    true,
+			},
+			wantCount: 3,
+			wantTotal: 3,
+			checkFunc: func(t *testing.T, features []*domains.SamplingFeature) {
+				require.Equal(t, []string{sf3.ID, sf2.ID, sf1.ID},
+					[]string{features[0].ID, features[1].ID, features[2].ID})
+			},
+		},
 		{
 			name: "empty result set",
 			params: &queryparams.SamplingFeatureQueryParams{
@@ -374,3 +454,249 @@ func TestSamplingFeatureRepository_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestSamplingFeatureRepository_DeleteMatching(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSamplingFeatureRepository(db)
+
+	systemA := "system-a"
+	systemB := "system-b"
+	create := func(uid string, parent *string, featureType string) *domains.SamplingFeature {
+		sf := &domains.SamplingFeature{
+			CommonSSN:      domains.CommonSSN{UniqueIdentifier: domains.UniqueID(uid), Name: uid},
+			FeatureType:    featureType,
+			ParentSystemID: parent,
+		}
+		require.NoError(t, repo.Create(sf))
+		return sf
+	}
+	a1 := create("urn:test:bulk:a1", &systemA, domains.SamplingFeatureTypeSamplingPoint)
+	a2 := create("urn:test:bulk:a2", &systemA, domains.SamplingFeatureTypeSamplingCurve)
+	b1 := create("urn:test:bulk:b1", &systemB, domains.SamplingFeatureTypeSamplingPoint)
+	b2 := create("urn:test:bulk:b2", &systemB, domains.SamplingFeatureTypeSamplingCurve)
+
+	remaining := func(t *testing.T) []string {
+		var ids []string
+		require.NoError(t, db.Model(&domains.SamplingFeature{}).Order("unique_identifier").Pluck("id", &ids).Error)
+		return ids
+	}
+
+	t.Run("no filter deletes nothing", func(t *testing.T) {
+		deleted, err := repo.DeleteMatching(&queryparams.SamplingFeatureQueryParams{QueryParams: queryparams.QueryParams{Limit: 10}})
+		require.ErrorIs(t, err, ErrUnfilteredDelete)
+		require.Zero(t, deleted)
+		require.Equal(t, []string{a1.ID, a2.ID, b1.ID, b2.ID}, remaining(t))
+	})
+
+	t.Run("open dateTime deletes nothing", func(t *testing.T) {
+		deleted, err := repo.DeleteMatching(&queryparams.SamplingFeatureQueryParams{DateTime: &common_shared.TimeRange{}})
+		require.ErrorIs(t, err, ErrUnfilteredDelete)
+		require.Zero(t, deleted)
+		require.Equal(t, []string{a1.ID, a2.ID, b1.ID, b2.ID}, remaining(t))
+	})
+
+	t.Run("system and feature type", func(t *testing.T) {
+		deleted, err := repo.DeleteMatching(&queryparams.SamplingFeatureQueryParams{
+			System:      []string{systemB},
+			FeatureType: []string{domains.SamplingFeatureTypeSamplingCurve},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+		require.Equal(t, []string{a1.ID, a2.ID, b1.ID}, remaining(t))
+	})
+
+	t.Run("system", func(t *testing.T) {
+		deleted, err := repo.DeleteMatching(&queryparams.SamplingFeatureQueryParams{System: []string{systemA}})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+		require.Equal(t, []string{b1.ID}, remaining(t))
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		deleted, err := repo.DeleteMatching(&queryparams.SamplingFeatureQueryParams{System: []string{"unknown"}})
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+		require.Equal(t, []string{b1.ID}, remaining(t))
+	})
+}
+
+func TestSamplingFeatureRepository_List_Computed(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSamplingFeatureRepository(db)
+
+	create := func(uid string, geometry *common_shared.GoGeom) *domains.SamplingFeature {
+		sf := &domains.SamplingFeature{
+			CommonSSN: domains.CommonSSN{UniqueIdentifier: domains.UniqueID(uid), Name: uid},
+			Geometry:  geometry,
+		}
+		require.NoError(t, repo.Create(sf))
+		return sf
+	}
+	// A 0.01° square and a 0.01° line on the equator: about 1113 m by 1106 m
+	surface := create("urn:test:computed:surface", testutil.MakePolygon([]float64{0, 0, 0.01, 0, 0.01, 0.01, 0, 0.01, 0, 0}))
+	curve := create("urn:test:computed:curve", testutil.MakeLineString([]float64{0, 0, 0.01, 0}))
+	point := create("urn:test:computed:point", testutil.MakePoint(0, 0))
+	unlocated := create("urn:test:computed:none", nil)
+
+	list := func(t *testing.T, params *queryparams.SamplingFeatureQueryParams) map[string]*domains.SamplingFeature {
+		t.Helper()
+		params.Limit = 10
+		features, total, err := repo.List(params)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), total)
+		byID := map[string]*domains.SamplingFeature{}
+		for _, sf := range features {
+			byID[sf.ID] = sf
+		}
+		return byID
+	}
+
+	t.Run("nothing computed by default", func(t *testing.T) {
+		for _, sf := range list(t, &queryparams.SamplingFeatureQueryParams{}) {
+			require.Nil(t, sf.AreaM2)
+			require.Nil(t, sf.LengthM)
+		}
+	})
+
+	t.Run("area and length where applicable", func(t *testing.T) {
+		byID := list(t, &queryparams.SamplingFeatureQueryParams{Computed: []string{queryparams.ComputedArea, queryparams.ComputedLength}})
+
+		require.NotNil(t, byID[surface.ID].AreaM2)
+		require.InEpsilon(t, 1113.19*1105.74, *byID[surface.ID].AreaM2, 0.01)
+		require.Nil(t, byID[surface.ID].LengthM)
+
+		require.NotNil(t, byID[curve.ID].LengthM)
+		require.InEpsilon(t, 1113.19, *byID[curve.ID].LengthM, 0.01)
+		require.Nil(t, byID[curve.ID].AreaM2)
+
+		for _, id := range []string{point.ID, unlocated.ID} {
+			require.Nil(t, byID[id].AreaM2)
+			require.Nil(t, byID[id].LengthM)
+		}
+	})
+
+	t.Run("only requested measures", func(t *testing.T) {
+		byID := list(t, &queryparams.SamplingFeatureQueryParams{Computed: []string{queryparams.ComputedLength}})
+		require.Nil(t, byID[surface.ID].AreaM2)
+		require.NotNil(t, byID[curve.ID].LengthM)
+	})
+
+	t.Run("with skipGeometry", func(t *testing.T) {
+		byID := list(t, &queryparams.SamplingFeatureQueryParams{
+			QueryParams: queryparams.QueryParams{SkipGeometry: true},
+			Computed:    []string{queryparams.ComputedArea},
+		})
+		require.Nil(t, byID[surface.ID].Geometry)
+		require.NotNil(t, byID[surface.ID].AreaM2)
+	})
+}
+
+func TestSamplingFeatureRepository_Create_SampleOfRelations(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSamplingFeatureRepository(db)
+
+	parent := &domains.SamplingFeature{
+		CommonSSN:   domains.CommonSSN{UniqueIdentifier: "urn:test:sampleof:parent", Name: "Parent Sample"},
+		FeatureType: "Point",
+	}
+	require.NoError(t, repo.Create(parent))
+
+	// The API resolves both local links to the parent; the external one is opaque
+	sampleOf := common_shared.Links{
+		{Href: "http://example.test/samplingFeatures/" + parent.ID, Rel: common_shared.OGCRel("sampleOf")},
+		{Href: "/samplingFeatures/" + parent.ID, Rel: common_shared.OGCRel("sampleOf")},
+		{Href: "https://other.example/features/river-1", Rel: common_shared.OGCRel("sampleOf")},
+	}
+	child := &domains.SamplingFeature{
+		CommonSSN:   domains.CommonSSN{UniqueIdentifier: "urn:test:sampleof:child", Name: "Child Sample"},
+		FeatureType: "Point",
+		SampleOf:    &sampleOf,
+		SampleOfIDs: &[]string{parent.ID, parent.ID},
+	}
+	require.NoError(t, repo.Create(child))
+
+	var relations []domains.SamplingFeatureSampleOf
+	require.NoError(t, db.Where("sampling_feature_id = ?", child.ID).Find(&relations).Error)
+	require.Len(t, relations, 1)
+	require.Equal(t, parent.ID, relations[0].SampleOfID)
+
+	// Deleting the feature removes its relation rows
+	require.NoError(t, repo.Delete(child.ID))
+	var remaining int64
+	require.NoError(t, db.Model(&domains.SamplingFeatureSampleOf{}).Where("sampling_feature_id = ?", child.ID).Count(&remaining).Error)
+	require.Zero(t, remaining)
+}
+
+func TestSamplingFeatureRepository_SampleOfUnknownFeature(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSamplingFeatureRepository(db)
+
+	sampleOf := common_shared.Links{
+		{Href: "/samplingFeatures/sf-missing", Rel: common_shared.OGCRel("sampleOf")},
+	}
+	sf := &domains.SamplingFeature{
+		CommonSSN:   domains.CommonSSN{UniqueIdentifier: "urn:test:sampleof:dangling", Name: "Dangling"},
+		FeatureType: "Point",
+		SampleOf:    &sampleOf,
+		SampleOfIDs: &[]string{"sf-missing"},
+	}
+	err := repo.Create(sf)
+	var unknown *UnknownSampleOfError
+	require.ErrorAs(t, err, &unknown)
+	require.Equal(t, []string{"sf-missing"}, unknown.IDs)
+
+	var count int64
+	require.NoError(t, db.Model(&domains.SamplingFeature{}).Where("unique_identifier = ?", "urn:test:sampleof:dangling").Count(&count).Error)
+	require.Zero(t, count, "feature row must not be kept for a dangling sampleOf link")
+
+	require.ErrorAs(t, repo.CheckSampleOf([]string{"sf-missing"}), &unknown)
+	require.NoError(t, repo.CheckSampleOf(nil))
+
+	// External references resolve to no ids and are never checked
+	external := common_shared.Links{
+		{Href: "https://other.example/samplingFeatures/sf-missing", Rel: common_shared.OGCRel("sampleOf")},
+	}
+	sf.SampleOf = &external
+	sf.SampleOfIDs = &[]string{}
+	require.NoError(t, repo.Create(sf))
+}
+
+func TestSamplingFeatureRepository_Create_RollsBackOnLinkFailure(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSamplingFeatureRepository(db)
+
+	target := &domains.SamplingFeature{
+		CommonSSN:   domains.CommonSSN{UniqueIdentifier: "urn:test:sampleof:target", Name: "Target"},
+		FeatureType: "Point",
+	}
+	require.NoError(t, repo.Create(target))
+
+	// Fail every insert into the relation table, after the feature row is written
+	injected := errors.New("injected sampleOf insert failure")
+	require.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:fail_sample_of", func(tx *gorm.DB) {
+		if tx.Statement.Table == (domains.SamplingFeatureSampleOf{}).TableName() {
+			tx.AddError(injected)
+		}
+	}))
+
+	sampleOf := common_shared.Links{
+		{Href: "/samplingFeatures/" + target.ID, Rel: common_shared.OGCRel("sampleOf")},
+	}
+	sf := &domains.SamplingFeature{
+		CommonSSN:   domains.CommonSSN{UniqueIdentifier: "urn:test:sampleof:rollback", Name: "Rolled Back"},
+		FeatureType: "Point",
+		SampleOf:    &sampleOf,
+		SampleOfIDs: &[]string{target.ID},
+	}
+	err := repo.Create(sf)
+	require.ErrorIs(t, err, injected)
+
+	var count int64
+	require.NoError(t, db.Model(&domains.SamplingFeature{}).Where("unique_identifier = ?", "urn:test:sampleof:rollback").Count(&count).Error)
+	require.Zero(t, count, "feature row must be rolled back with its links")
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SchemaVersion is the database schema version this build expects. Bump it
+// whenever a change to the domain models or AutoMigrate requires migrating an
+// existing database.
+const SchemaVersion = 7
+
+// ErrSchemaVersionMismatch is returned by CheckSchemaVersion when the database
+// has not been migrated to SchemaVersion.
+var ErrSchemaVersionMismatch = errors.New("database schema version mismatch")
+
+// SchemaVersionRecord is a row of the schema_versions table, written each time
+// AutoMigrate brings the database to a new version.
+type SchemaVersionRecord struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name
+func (SchemaVersionRecord) TableName() string {
+	return "schema_versions"
+}
+
+// CurrentSchemaVersion returns the highest recorded schema version, or 0 when
+// the database has never been migrated.
+func CurrentSchemaVersion(db *gorm.DB) (int, error) {
+	if !db.Migrator().HasTable(&SchemaVersionRecord{}) {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.Model(&SchemaVersionRecord{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// CheckSchemaVersion verifies the database is at SchemaVersion without
+// modifying it.
+func CheckSchemaVersion(db *gorm.DB) error {
+	current, err := CurrentSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if current != SchemaVersion {
+		return fmt.Errorf("%w: database is at version %d, server expects version %d", ErrSchemaVersionMismatch, current, SchemaVersion)
+	}
+	return nil
+}
+
+// recordSchemaVersion marks the database as migrated to SchemaVersion.
+func recordSchemaVersion(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SchemaVersionRecord{}); err != nil {
+		return err
+	}
+	return db.Where(SchemaVersionRecord{Version: SchemaVersion}).
+		Attrs(SchemaVersionRecord{AppliedAt: time.Now().UTC()}).
+		FirstOrCreate(&SchemaVersionRecord{}).Error
+}
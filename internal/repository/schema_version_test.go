@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaVersion_CheckBeforeAndAfterAutoMigrate(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	current, err := CurrentSchemaVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, 0, current)
+
+	err = CheckSchemaVersion(db)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrSchemaVersionMismatch))
+
+	require.NoError(t, AutoMigrate(db))
+
+	current, err = CurrentSchemaVersion(db)
+	require.NoError(t, err)
+	require.Equal(t, SchemaVersion, current)
+	require.NoError(t, CheckSchemaVersion(db))
+
+	// Running the migration again is idempotent
+	require.NoError(t, AutoMigrate(db))
+	require.NoError(t, CheckSchemaVersion(db))
+}
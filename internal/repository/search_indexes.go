@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// trigramSearchColumns lists, per table, the text columns matched by the q
+// filter with ILIKE '%term%'.
+var trigramSearchColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"systems", []string{"name", "description"}},
+	{"deployments", []string{"name", "description"}},
+	{"procedures", []string{"name", "description"}},
+	{"sampling_features", []string{"name", "description"}},
+	{"properties", []string{"name", "description"}},
+	{"datastreams", []string{"name", "description"}},
+	{"control_streams", []string{"name", "description"}},
+	{"system_events", []string{"label", "description"}},
+}
+
+// TrigramIndexName returns the name of the pg_trgm index on table.column.
+func TrigramIndexName(table, column string) string {
+	return fmt.Sprintf("idx_%s_%s_trgm", table, column)
+}
+
+// EnsureTrigramSearchIndexes enables the pg_trgm extension and creates a GIN
+// trigram index on every column searched by the q filter. PostgreSQL uses these
+// indexes for ILIKE '%term%' (terms of three or more characters), so the
+// queries keep their matching semantics while avoiding sequential scans.
+func EnsureTrigramSearchIndexes(db *gorm.DB) error {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm;").Error; err != nil {
+		return err
+	}
+
+	for _, t := range trigramSearchColumns {
+		for _, column := range t.columns {
+			stmt := fmt.Sprintf(
+				"CREATE INDEX IF NOT EXISTS %s ON %s USING gin (%s gin_trgm_ops);",
+				TrigramIndexName(t.table, column), t.table, column,
+			)
+			if err := db.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"github.com/yourusername/connected-systems-go/internal/repository/testutil"
+	"gorm.io/gorm"
+)
+
+// seedSearchableSystems inserts n filler systems plus two that match "anemometer",
+// one by name and one by description, and refreshes planner statistics.
+func seedSearchableSystems(tb testing.TB, db *gorm.DB, n int) {
+	tb.Helper()
+	require.NoError(tb, EnsureTrigramSearchIndexes(db))
+	require.NoError(tb, db.Exec(`
+INSERT INTO systems (id, unique_identifier, name, description, system_type, created_at, updated_at)
+SELECT 'sys-' || g, 'urn:test:trgm:' || g, 'Sensor ' || md5(g::text), 'Filler system ' || md5((g * 7)::text),
+       'http://www.w3.org/ns/sosa/Sensor', now(), now()
+FROM generate_series(1, ?) AS g`, n).Error)
+	require.NoError(tb, db.Exec(`
+INSERT INTO systems (id, unique_identifier, name, description, system_type, created_at, updated_at) VALUES
+('sys-anemo-name', 'urn:test:trgm:anemo-name', 'Rooftop ANEMOMETER', '', 'http://www.w3.org/ns/sosa/Sensor', now(), now()),
+('sys-anemo-desc', 'urn:test:trgm:anemo-desc', 'Wind Mast', 'Carries an anemometer at 10 m', 'http://www.w3.org/ns/sosa/Sensor', now(), now())`).Error)
+	require.NoError(tb, db.Exec("ANALYZE systems").Error)
+}
+
+// captureQueries records the SQL of every SELECT run through db, with its
+// arguments interpolated.
+func captureQueries(tb testing.TB, db *gorm.DB) *[]string {
+	tb.Helper()
+	var statements []string
+	require.NoError(tb, db.Callback().Query().After("gorm:query").Register("test:capture_sql", func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}))
+	return &statements
+}
+
+func TestSystemRepository_List_QUsesTrigramIndex(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	seedSearchableSystems(t, db, 5000)
+
+	statements := captureQueries(t, db)
+	repo := NewSystemRepository(db)
+	systems, total, err := repo.List(&queryparams.SystemQueryParams{
+		QueryParams: queryparams.QueryParams{Q: []string{"anemometer"}, Limit: 10},
+	})
+	require.NoError(t, err)
+
+	// Matching is unchanged: case-insensitive substring of name or description
+	require.Equal(t, int64(2), total)
+	require.Len(t, systems, 2)
+	ids := []string{systems[0].ID, systems[1].ID}
+	require.ElementsMatch(t, []string{"sys-anemo-name", "sys-anemo-desc"}, ids)
+
+	var listSQL string
+	for _, statement := range *statements {
+		if strings.Contains(statement, "ILIKE") && !strings.Contains(statement, "count(") {
+			listSQL = statement
+		}
+	}
+	require.NotEmpty(t, listSQL, "List query was not captured")
+
+	// Small test tables may still be cheapest to scan in full, or to walk the
+	// primary key until LIMIT rows match. Disabling both leaves bitmap scans, the
+	// only way a GIN index is read, and shows the planner can use the trigram
+	// indexes for this query.
+	var plan []string
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL enable_seqscan = off").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("SET LOCAL enable_indexscan = off").Error; err != nil {
+			return err
+		}
+		return tx.Raw("EXPLAIN " + listSQL).Scan(&plan).Error
+	}))
+
+	joined := strings.Join(plan, "\n")
+	require.Contains(t, joined, TrigramIndexName("systems", "name"), joined)
+	require.Contains(t, joined, TrigramIndexName("systems", "description"), joined)
+}
+
+func BenchmarkSystemRepository_List_Q(b *testing.B) {
+	ctx := context.Background()
+	container := testutil.StartPostGISContainer(ctx, b)
+	defer container.Terminate(ctx)
+
+	db := testutil.OpenTestDB(b, container.DSN, testutil.OpenTestDBOptions{Models: testutil.DefaultSystemModels()})
+	seedSearchableSystems(b, db, 50000)
+
+	repo := NewSystemRepository(db)
+	params := &queryparams.SystemQueryParams{
+		QueryParams: queryparams.QueryParams{Q: []string{"anemometer"}, Limit: 10},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := repo.List(params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
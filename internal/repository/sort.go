@@ -0,0 +1,21 @@
+package repository
+
+import (
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"gorm.io/gorm"
+)
+
+// orderBySort orders query by the sortby key (queryparams.SortByName or
+// SortByCreated) on table, defaulting to creation order. The id is always the
+// last key so offset pagination stays stable when the sort key ties.
+func orderBySort(query *gorm.DB, table, sortBy string, desc bool) *gorm.DB {
+	column := table + ".created_at"
+	if sortBy == queryparams.SortByName {
+		column = table + ".name"
+	}
+	direction := " ASC"
+	if desc {
+		direction = " DESC"
+	}
+	return query.Order(column + direction).Order(table + ".id" + direction)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"gorm.io/gorm"
+)
+
+// whereIntersectsBbox restricts query to rows whose geometry (given as a SQL
+// expression) intersects bbox. Boxes crossing the antimeridian are split into
+// their eastern and western halves and ORed together. Rows without a geometry
+// never match: ST_Intersects of a NULL geometry is NULL, which WHERE rejects.
+func whereIntersectsBbox(query *gorm.DB, geometryExpr string, bbox *common_shared.BoundingBox) *gorm.DB {
+	if bbox == nil {
+		return query
+	}
+
+	var clauses []string
+	var args []interface{}
+	for _, part := range bbox.Split() {
+		clauses = append(clauses, "ST_Intersects("+geometryExpr+", ST_MakeEnvelope(?, ?, ?, ?, 4326))")
+		args = append(args, part.MinX, part.MinY, part.MaxX, part.MaxY)
+	}
+
+	return query.Where("("+strings.Join(clauses, " OR ")+")", args...)
+}
+
+// nearPointGeography is the SQL for a WGS84 lon/lat point, bound as two
+// parameters, cast to geography. Distances between geography values are
+// geodesic meters on the WGS84 spheroid; between geometry values they would be
+// planar degrees, whose length in meters shrinks towards the poles.
+const nearPointGeography = "ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography"
+
+// distanceToNear returns the SQL expression for the distance in meters from
+// the geometry (given as a SQL expression) to near, with its arguments.
+func distanceToNear(geometryExpr string, near *queryparams.NearPoint) (string, []interface{}) {
+	return "ST_Distance(" + geometryExpr + "::geography, " + nearPointGeography + ")", []interface{}{near.Lon, near.Lat}
+}
+
+// geodesicArea returns the SQL expression for the area in square meters of
+// the geometry (given as a SQL expression), or NULL unless it is polygonal.
+func geodesicArea(geometryExpr string) string {
+	return "CASE WHEN ST_Dimension(" + geometryExpr + ") = 2 THEN ST_Area(" + geometryExpr + "::geography) END"
+}
+
+// geodesicLength returns the SQL expression for the length in meters of the
+// geometry (given as a SQL expression), or NULL unless it is linear.
+func geodesicLength(geometryExpr string) string {
+	return "CASE WHEN ST_Dimension(" + geometryExpr + ") = 1 THEN ST_Length(" + geometryExpr + "::geography) END"
+}
+
+// whereWithinRadius restricts query to rows whose geometry (given as a SQL
+// expression) lies within radius meters of near. Like distanceToNear it
+// compares geography values, so the radius is in meters at every latitude.
+func whereWithinRadius(query *gorm.DB, geometryExpr string, near *queryparams.NearPoint, radius *float64) *gorm.DB {
+	if near == nil || radius == nil {
+		return query
+	}
+	return query.Where("ST_DWithin("+geometryExpr+"::geography, "+nearPointGeography+", ?)", near.Lon, near.Lat, *radius)
+}
+
+// omitGeometry leaves the geometry column out of the rows query selects when
+// skip is set (skipGeometry=true), so large geometries are neither read nor
+// decoded.
+func omitGeometry(query *gorm.DB, skip bool) *gorm.DB {
+	if !skip {
+		return query
+	}
+	return query.Omit("geometry")
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -18,9 +19,17 @@ func NewSystemEventRepository(db *gorm.DB) *SystemEventRepository {
 	return &SystemEventRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *SystemEventRepository) WithContext(ctx context.Context) *SystemEventRepository {
+	return &SystemEventRepository{db: r.db.WithContext(ctx)}
+}
+
 func (r *SystemEventRepository) Create(event *domains.SystemEvent) error {
 	normalizeSystemEventTime(event)
-	return r.db.Create(event).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Create(event).Error
+	})
 }
 
 func (r *SystemEventRepository) GetByID(systemID, eventID string) (*domains.SystemEvent, error) {
@@ -32,6 +41,9 @@ func (r *SystemEventRepository) GetByID(systemID, eventID string) (*domains.Syst
 	return &event, nil
 }
 
+// List returns the matching events newest first: by event time, then by when
+// they were recorded, with the id breaking ties so offset pagination is stable.
+// Events take no sortby, so this is always the timeline order.
 func (r *SystemEventRepository) List(params *queryparams.SystemEventsQueryParams, fixedSystemID *string) ([]*domains.SystemEvent, int64, error) {
 	var events []*domains.SystemEvent
 	var total int64
@@ -50,17 +62,21 @@ func (r *SystemEventRepository) List(params *queryparams.SystemEventsQueryParams
 		query = query.Offset(params.Offset)
 	}
 
-	err := query.Order("time_start desc, created_at desc").Find(&events).Error
+	err := query.Order("time_start DESC, created_at DESC, id DESC").Find(&events).Error
 	return events, total, err
 }
 
 func (r *SystemEventRepository) Update(event *domains.SystemEvent) error {
 	normalizeSystemEventTime(event)
-	return r.db.Save(event).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Save(event).Error
+	})
 }
 
 func (r *SystemEventRepository) Delete(systemID, eventID string) error {
-	return r.db.Where("id = ? AND system_id = ?", eventID, systemID).Delete(&domains.SystemEvent{}).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Where("id = ? AND system_id = ?", eventID, systemID).Delete(&domains.SystemEvent{}).Error
+	})
 }
 
 func (r *SystemEventRepository) applyFilters(query *gorm.DB, params *queryparams.SystemEventsQueryParams, fixedSystemID *string) *gorm.DB {
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+)
+
+func TestSystemEventRepository_List_NewestFirst(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSystemEventRepository(db)
+
+	systemID := "system-events-order"
+	at := func(day int) common_shared.HistoryTime {
+		instant := time.Date(2024, time.March, day, 12, 0, 0, 0, time.UTC)
+		return common_shared.HistoryTime{Instant: &instant}
+	}
+	recorded := func(minute int) domains.Base {
+		return domains.Base{CreatedAt: time.Date(2024, time.April, 1, 0, minute, 0, 0, time.UTC)}
+	}
+	// Created out of order, with two events sharing an event time so the
+	// recording time has to break the tie
+	for id, event := range map[string]*domains.SystemEvent{
+		"event-mid":        {Base: recorded(1), SystemID: systemID, Label: "Calibrated", Time: at(10)},
+		"event-old":        {Base: recorded(2), SystemID: systemID, Label: "Deployed", Time: at(1)},
+		"event-new-first":  {Base: recorded(3), SystemID: systemID, Label: "Serviced", Time: at(20)},
+		"event-new-second": {Base: recorded(4), SystemID: systemID, Label: "Inspected", Time: at(20)},
+	} {
+		event.ID = id
+		require.NoError(t, repo.Create(event))
+	}
+
+	events, total, err := repo.List(&queryparams.SystemEventsQueryParams{}, &systemID)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), total)
+
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	require.Equal(t, []string{"event-new-second", "event-new-first", "event-mid", "event-old"}, ids)
+}
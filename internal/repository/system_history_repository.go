@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -19,6 +20,12 @@ func NewSystemHistoryRepository(db *gorm.DB) *SystemHistoryRepository {
 	return &SystemHistoryRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *SystemHistoryRepository) WithContext(ctx context.Context) *SystemHistoryRepository {
+	return &SystemHistoryRepository{db: r.db.WithContext(ctx)}
+}
+
 func (r *SystemHistoryRepository) CreateFromSystem(system *domains.System) (*domains.SystemHistoryRevision, error) {
 	if system == nil {
 		return nil, fmt.Errorf("system is nil")
@@ -1,13 +1,17 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
 	"github.com/yourusername/connected-systems-go/internal/model/domains"
 	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // SystemRepository handles System data access
@@ -20,6 +24,12 @@ func NewSystemRepository(db *gorm.DB) *SystemRepository {
 	return &SystemRepository{db: db}
 }
 
+// WithContext returns a copy of the repository whose queries run under ctx, so
+// they are canceled when ctx is done.
+func (r *SystemRepository) WithContext(ctx context.Context) *SystemRepository {
+	return &SystemRepository{db: r.db.WithContext(ctx)}
+}
+
 // Build all necessary associations for a system
 func (r *SystemRepository) BuildSystemAssociations(systemID string) common_shared.Links {
 
@@ -37,6 +47,10 @@ func (r *SystemRepository) BuildSystemAssociations(systemID string) common_share
 			Rel:  common_shared.OGCRel("samplingFeatures"),
 			Href: "/systems/" + systemID + "/samplingFeatures",
 		})
+
+		if featuresOfInterest, err := r.FeaturesOfInterest(systemID); err == nil {
+			links = append(links, featuresOfInterest...)
+		}
 	}
 
 	if has, err := r.HasDatastreams(systemID); err == nil && has {
@@ -72,7 +86,15 @@ func (r *SystemRepository) BuildSystemAssociations(systemID string) common_share
 
 // Create creates a new system
 func (r *SystemRepository) Create(system *domains.System) error {
-	return r.db.Create(system).Error
+	return retryWrites(r.db, func() error {
+		return r.db.Create(system).Error
+	})
+}
+
+// UniqueIDInUse reports whether another system already has the unique
+// identifier uid. A blank uid is never in use.
+func (r *SystemRepository) UniqueIDInUse(uid string) (bool, error) {
+	return uniqueIDInUse(r.db, &domains.System{}, uid)
 }
 
 // GetByID retrieves a system by ID
@@ -95,6 +117,27 @@ func (r *SystemRepository) GetByUID(uid string) (*domains.System, error) {
 	return &system, nil
 }
 
+// GetByIDs returns the systems with the given IDs keyed by ID
+func (r *SystemRepository) GetByIDs(ctx context.Context, ids []string) (map[string]*domains.System, error) {
+	result := make(map[string]*domains.System)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var systems []*domains.System
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&systems).Error; err != nil {
+		return nil, err
+	}
+
+	for _, s := range systems {
+		if s != nil {
+			result[s.ID] = s
+		}
+	}
+
+	return result, nil
+}
+
 // List retrieves systems with filtering
 func (r *SystemRepository) List(params *queryparams.SystemQueryParams) ([]*domains.System, int64, error) {
 	var systems []*domains.System
@@ -110,7 +153,48 @@ func (r *SystemRepository) List(params *queryparams.SystemQueryParams) ([]*domai
 		return nil, 0, err
 	}
 
-	// Apply pagination
+	if err := r.pageQuery(query, params).Find(&systems).Error; err != nil {
+		return nil, 0, err
+	}
+	if params.SkipGeometry {
+		// The distance ordering selects systems.* explicitly, which Omit cannot narrow
+		for _, system := range systems {
+			system.Geometry = nil
+		}
+	}
+	return systems, total, nil
+}
+
+// Each calls fn for every system List would return for params, in the same
+// order, scanning rows from the database cursor one at a time so the result
+// is never held in memory. Iteration stops at the first error from fn.
+func (r *SystemRepository) Each(params *queryparams.SystemQueryParams, fn func(*domains.System) error) error {
+	query := r.pageQuery(r.applyFilters(r.db.Model(&domains.System{}), params), params)
+
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var system domains.System
+		if err := scanRowsUTC(r.db, rows, &system); err != nil {
+			return err
+		}
+		if params.SkipGeometry {
+			system.Geometry = nil
+		}
+		if err := fn(&system); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// pageQuery applies the paging and ordering of params to a filtered system
+// query, as shared by List and Each.
+func (r *SystemRepository) pageQuery(query *gorm.DB, params *queryparams.SystemQueryParams) *gorm.DB {
 	if params.Limit > 0 {
 		query = query.Limit(params.Limit)
 	}
@@ -118,51 +202,203 @@ func (r *SystemRepository) List(params *queryparams.SystemQueryParams) ([]*domai
 		query = query.Offset(params.Offset)
 	}
 
-	err := query.Debug().Find(&systems).Error
-	return systems, total, err
+	if params.SortBy == queryparams.SortByDistance && params.Near != nil {
+		distance, args := distanceToNear("systems.geometry", params.Near)
+		query = query.
+			Select("systems.*, "+distance+" AS distance", args...).
+			Order("distance ASC NULLS LAST")
+	}
+
+	return omitGeometry(query, params.SkipGeometry)
 }
 
-// GetSubsystems retrieves subsystems of a parent system
-func (r *SystemRepository) GetSubsystems(parentID string, recursive bool) ([]*domains.System, error) {
-	var systems []*domains.System
+// SystemSummary is the tabular view of a system used by the CSV export. Lon and
+// Lat are the centroid of the geometry, nil when the system has no location.
+type SystemSummary struct {
+	ID               string
+	UniqueIdentifier string
+	Name             string
+	SystemType       string
+	Lon              *float64
+	Lat              *float64
+	CreatedAt        time.Time
+}
 
-	query := r.db.Where("parent_system_id = ?", parentID)
+// EachSummary calls fn for every system List would return for params, in the
+// same order, reading rows from the database one at a time so the result is
+// never held in memory. Iteration stops at the first error from fn. The
+// centroid is given in the output SRID of the repository's context, if any.
+func (r *SystemRepository) EachSummary(params *queryparams.SystemQueryParams, fn func(SystemSummary) error) error {
+	centroid := "ST_Centroid(systems.geometry)"
+	if srid, ok := OutputSRIDFromContext(r.db.Statement.Context); ok {
+		centroid = fmt.Sprintf("ST_Transform(ST_SetSRID(%s, %d), %d)", centroid, StorageSRID, srid)
+	}
+	markGeometriesRead(r.db.Statement.Context)
+	query := r.applyFilters(r.db.Model(&domains.System{}), params).
+		Select("systems.id, systems.unique_identifier, systems.name, systems.system_type, " +
+			"ST_X(" + centroid + ") AS lon, ST_Y(" + centroid + ") AS lat, systems.created_at")
 
-	if err := query.Find(&systems).Error; err != nil {
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		query = query.Offset(params.Offset)
+	}
+
+	if params.SortBy == queryparams.SortByDistance && params.Near != nil {
+		distance, args := distanceToNear("systems.geometry", params.Near)
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{SQL: distance + " ASC NULLS LAST", Vars: args}})
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var summary SystemSummary
+		if err := rows.Scan(&summary.ID, &summary.UniqueIdentifier, &summary.Name, &summary.SystemType, &summary.Lon, &summary.Lat, &summary.CreatedAt); err != nil {
+			return err
+		}
+		summary.CreatedAt = summary.CreatedAt.UTC()
+		if err := fn(summary); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetSubsystems retrieves all subsystems of a parent system without paging.
+// Endpoints should use ListSubsystems; this is for internal traversals.
+func (r *SystemRepository) GetSubsystems(parentID string, recursive bool) ([]*domains.System, error) {
+	if recursive {
+		return r.collectSubsystems(parentID, map[string]bool{parentID: true})
+	}
+
+	var systems []*domains.System
+	if err := r.db.Where("parent_system_id = ?", parentID).Find(&systems).Error; err != nil {
 		return nil, err
 	}
+	return systems, nil
+}
 
-	// If recursive, get subsystems of subsystems
+// ListSubsystems retrieves one page of the subsystems of a parent system,
+// ordered by creation time then id, along with the total number of subsystems.
+// With recursive set every descendant is included, not only direct children.
+func (r *SystemRepository) ListSubsystems(parentID string, recursive bool, params *queryparams.SystemQueryParams) ([]*domains.System, int64, error) {
+	var systems []*domains.System
+	var total int64
+
+	query := r.db.Model(&domains.System{})
 	if recursive {
-		var allSystems []*domains.System
-		allSystems = append(allSystems, systems...)
+		descendants, err := r.collectSubsystems(parentID, map[string]bool{parentID: true})
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(descendants) == 0 {
+			return systems, 0, nil
+		}
+		ids := make([]string, 0, len(descendants))
+		for _, descendant := range descendants {
+			ids = append(ids, descendant.ID)
+		}
+		query = query.Where("systems.id IN ?", ids)
+	} else {
+		query = query.Where("systems.parent_system_id = ?", parentID)
+	}
 
-		for _, sys := range systems {
-			children, err := r.GetSubsystems(sys.ID, true)
-			if err != nil {
-				return nil, err
-			}
-			allSystems = append(allSystems, children...)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Stable ordering keeps offset pagination consistent across pages
+	query = query.Order("systems.created_at, systems.id")
+
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		query = query.Offset(params.Offset)
+	}
+
+	err := omitGeometry(query, params.SkipGeometry).Find(&systems).Error
+	return systems, total, err
+}
+
+// collectSubsystems walks the hierarchy below parentID. Systems already in
+// visited are skipped so a cycle in parent_system_id cannot recurse forever.
+func (r *SystemRepository) collectSubsystems(parentID string, visited map[string]bool) ([]*domains.System, error) {
+	var children []*domains.System
+	if err := r.db.Where("parent_system_id = ?", parentID).Find(&children).Error; err != nil {
+		return nil, err
+	}
+
+	var unvisited []*domains.System
+	for _, child := range children {
+		if visited[child.ID] {
+			continue
 		}
-		return allSystems, nil
+		visited[child.ID] = true
+		unvisited = append(unvisited, child)
 	}
 
-	return systems, nil
+	allSystems := append([]*domains.System{}, unvisited...)
+	for _, child := range unvisited {
+		descendants, err := r.collectSubsystems(child.ID, visited)
+		if err != nil {
+			return nil, err
+		}
+		allSystems = append(allSystems, descendants...)
+	}
+	return allSystems, nil
 }
 
 // Update updates a system
+//
+// Only the scalar columns of the system row are replaced. The many2many
+// association tables (procedures, deployments, datastreams, controlstreams) are
+// left untouched unless the caller sets the corresponding slice, in which case
+// that association is replaced with exactly the provided members; a non-nil
+// empty slice clears it.
 func (r *SystemRepository) Update(systemId string, system *domains.System) error {
 	system.ID = systemId
-	return r.db.Save(system).Error
+	return retryTransaction(r.db, func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(system).Error; err != nil {
+			return err
+		}
+
+		associations := []struct {
+			name     string
+			provided bool
+			members  interface{}
+		}{
+			{"Procedures", system.Procedures != nil, system.Procedures},
+			{"Deployments", system.Deployments != nil, system.Deployments},
+			{"Datastreams", system.Datastreams != nil, system.Datastreams},
+			{"Controlstreams", system.Controlstreams != nil, system.Controlstreams},
+		}
+		for _, association := range associations {
+			if !association.provided {
+				continue
+			}
+			if err := tx.Model(system).Association(association.name).Replace(association.members); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // Delete deletes a system
 func (r *SystemRepository) Delete(id string, cascade bool) error {
 	if !cascade {
-		return r.db.Delete(&domains.System{}, "id = ?", id).Error
+		return retryWrites(r.db, func() error {
+			return r.db.Delete(&domains.System{}, "id = ?", id).Error
+		})
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return retryTransaction(r.db, func(tx *gorm.DB) error {
 		return r.deleteCascade(tx, id)
 	})
 }
@@ -302,6 +538,38 @@ func (r *SystemRepository) removeSystemFromDeployments(tx *gorm.DB, systemID str
 	return nil
 }
 
+// FeaturesOfInterest returns one featuresOfInterest link per distinct feature
+// sampled by the system's sampling features, taken from their
+// sampledFeature@link and de-duplicated by href.
+func (r *SystemRepository) FeaturesOfInterest(systemID string) (common_shared.Links, error) {
+	var rows []struct {
+		SampledFeatureLink *common_shared.Link
+	}
+	err := r.db.Model(&domains.SamplingFeature{}).
+		Select("sampled_feature_link").
+		Where("parent_system_id = ? AND sampled_feature_link IS NOT NULL", systemID).
+		Order("created_at, id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	links := common_shared.Links{}
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		sampled := row.SampledFeatureLink
+		if sampled == nil || strings.TrimSpace(sampled.Href) == "" || seen[sampled.Href] {
+			continue
+		}
+		seen[sampled.Href] = true
+
+		link := *sampled
+		link.Rel = common_shared.OGCRel("featuresOfInterest")
+		links = append(links, link)
+	}
+	return links, nil
+}
+
 // Checks if a system has subsystems
 func (r *SystemRepository) HasSubsystems(systemID string) (bool, error) {
 	return r.hasAssociatedRecords(&domains.System{}, "parent_system_id = ?", systemID)
@@ -359,77 +627,114 @@ func (r *SystemRepository) hasAssociatedRecords(model interface{}, query string,
 	return count > 0, nil
 }
 
-// applyFilters applies query filters
+// applyFilters applies query filters. Columns are qualified with the table
+// name and related-resource filters use EXISTS subqueries, so filters compose
+// without ambiguous columns and the total counts each system once.
 func (r *SystemRepository) applyFilters(query *gorm.DB, params *queryparams.SystemQueryParams) *gorm.DB {
-	if !params.Recursive {
-		query = query.Where("parent_system_id IS NULL")
+	// Without a parent filter only top-level systems are listed unless recursive
+	switch {
+	case params.RootOnly && len(params.Parent) > 0:
+		query = query.Where("(systems.parent_system_id IS NULL OR systems.parent_system_id IN ?)", params.Parent)
+	case params.RootOnly:
+		query = query.Where("systems.parent_system_id IS NULL")
+	case len(params.Parent) > 0:
+		query = query.Where("systems.parent_system_id IN ?", params.Parent)
+	case !params.Recursive:
+		query = query.Where("systems.parent_system_id IS NULL")
 	}
 
 	if len(params.IDs) > 0 {
-		query = query.Where("id IN ? OR unique_identifier IN ?", params.IDs, params.IDs)
+		query = query.Where("systems.id IN ? OR systems.unique_identifier IN ?", params.IDs, params.IDs)
 	}
 
 	if len(params.Q) > 0 {
 		var clauses []string
 		var args []interface{}
 		for _, term := range params.Q {
-			clauses = append(clauses, "name ILIKE ?")
+			clauses = append(clauses, "systems.name ILIKE ?")
 			args = append(args, "%"+term+"%")
-			clauses = append(clauses, "description ILIKE ?")
+			clauses = append(clauses, "systems.description ILIKE ?")
 			args = append(args, "%"+term+"%")
 		}
-		query = query.Where(strings.Join(clauses, " OR "), args...)
+		query = query.Where("("+strings.Join(clauses, " OR ")+")", args...)
+	}
+
+	if len(params.SystemType) > 0 {
+		query = query.Where("systems.system_type IN ?", params.SystemType)
+	}
+
+	if len(params.AssetType) > 0 {
+		query = query.Where("systems.asset_type IN ?", params.AssetType)
 	}
 
-	if len(params.Parent) > 0 {
-		query = query.Where("parent_system_id IN ?", params.Parent)
+	if len(params.SystemKind) > 0 {
+		query = query.Where("systems.system_kind_id IN (SELECT procedures.id FROM procedures WHERE procedures.id IN ? OR procedures.unique_identifier IN ?)", params.SystemKind, params.SystemKind)
 	}
 
 	if params.Datetime != nil {
-		// Only add conditions if start/end are not nil
-		if params.Datetime.Start != nil && params.Datetime.End != nil {
-			query = query.Where("valid_time_start <= ? AND (valid_time_end IS NULL OR valid_time_end >= ?)", params.Datetime.End, params.Datetime.Start)
-		} else if params.Datetime.Start != nil {
-			query = query.Where("valid_time_end IS NULL OR valid_time_end >= ?", params.Datetime.Start)
-		} else if params.Datetime.End != nil {
-			query = query.Where("valid_time_start <= ?", params.Datetime.End)
+		switch params.DatetimeOp {
+		case queryparams.DatetimeOpContains, queryparams.DatetimeOpDuring:
+			// Missing bounds on either side are open-ended, so a system without any
+			// validTime contains every interval but lies only within a fully open one
+			operator := "@>"
+			if params.DatetimeOp == queryparams.DatetimeOpDuring {
+				operator = "<@"
+			}
+			query = query.Where("tstzrange(systems.valid_time_start, systems.valid_time_end, '[]') "+operator+" tstzrange(?::timestamptz, ?::timestamptz, '[]')", params.Datetime.Start, params.Datetime.End)
+		default:
+			// A missing validTime bound is open-ended, so systems without any
+			// validTime are valid at all times and match every interval
+			if params.Datetime.End != nil {
+				query = query.Where("(systems.valid_time_start IS NULL OR systems.valid_time_start <= ?)", params.Datetime.End)
+			}
+			if params.Datetime.Start != nil {
+				query = query.Where("(systems.valid_time_end IS NULL OR systems.valid_time_end >= ?)", params.Datetime.Start)
+			}
 		}
 	}
 
-	if params.Bbox != nil {
-		query = query.Where("ST_Intersects(geometry, ST_MakeEnvelope(?, ?, ?, ?, 4326))", params.Bbox.MinX, params.Bbox.MinY, params.Bbox.MaxX, params.Bbox.MaxY)
+	if params.ValidTimeContains != nil {
+		// As with datetime, missing validTime bounds are open-ended
+		query = query.Where("(systems.valid_time_start IS NULL OR systems.valid_time_start <= ?) AND (systems.valid_time_end IS NULL OR systems.valid_time_end >= ?)", *params.ValidTimeContains, *params.ValidTimeContains)
 	}
 
+	query = whereIntersectsBbox(query, "systems.geometry", params.Bbox)
+	query = whereWithinRadius(query, "systems.geometry", params.Near, params.Radius)
+
 	if params.Geom != "" {
-		query = query.Where("ST_Intersects(geometry, ST_GeomFromText(?, 4326))", params.Geom)
+		query = query.Where("ST_Intersects(systems.geometry, ST_GeomFromText(?, 4326))", params.Geom)
 	}
 
+	query = whereCQL2(query, params.FilterExpr, systemFilterColumns)
+	query = wherePropertyFilters(query, params.Properties, systemPropertyColumns)
+
 	if len(params.Procedure) > 0 {
-		query = query.Joins("JOIN system_procedures ON systems.id = system_procedures.system_id").
-			Where("system_procedures.procedure_id IN ?", params.Procedure)
+		query = query.Where("EXISTS (SELECT 1 FROM system_procedures WHERE system_procedures.system_id = systems.id AND system_procedures.procedure_id IN ?)", params.Procedure)
 	}
 
 	if len(params.FOI) > 0 {
-		query = query.Joins("JOIN sampling_features ON systems.id = sampling_features.parent_system_id").
-			Where("sampling_features.id IN ?", params.FOI)
+		query = query.Where("EXISTS (SELECT 1 FROM sampling_features WHERE sampling_features.parent_system_id = systems.id AND sampling_features.id IN ?)", params.FOI)
 	}
 
 	if len(params.ObservedProperty) > 0 {
-		query = query.
-			Joins("JOIN system_datastreams ON systems.id = system_datastreams.system_id").
-			Joins("JOIN datastreams ON system_datastreams.datastream_id = datastreams.id")
+		// Every observed property must be matched by the same datastream
+		clauses := []string{"system_datastreams.system_id = systems.id"}
+		args := make([]interface{}, 0, len(params.ObservedProperty))
 		for _, op := range params.ObservedProperty {
-			query = query.Where("datastreams.observed_properties::text ILIKE ?", "%"+op+"%")
+			clauses = append(clauses, "datastreams.observed_properties::text ILIKE ?")
+			args = append(args, "%"+op+"%")
 		}
+		query = query.Where("EXISTS (SELECT 1 FROM system_datastreams JOIN datastreams ON system_datastreams.datastream_id = datastreams.id WHERE "+strings.Join(clauses, " AND ")+")", args...)
 	}
 
 	if len(params.ControlledProperty) > 0 {
-		query = query.
-			Joins("JOIN system_controlstreams ON systems.id = system_controlstreams.system_id").
-			Joins("JOIN control_streams ON system_controlstreams.control_stream_id = control_streams.id")
+		clauses := []string{"system_controlstreams.system_id = systems.id"}
+		args := make([]interface{}, 0, len(params.ControlledProperty))
 		for _, cp := range params.ControlledProperty {
-			query = query.Where("control_streams.controlled_properties::text ILIKE ?", "%"+cp+"%")
+			clauses = append(clauses, "control_streams.controlled_properties::text ILIKE ?")
+			args = append(args, "%"+cp+"%")
 		}
+		query = query.Where("EXISTS (SELECT 1 FROM system_controlstreams JOIN control_streams ON system_controlstreams.control_stream_id = control_streams.id WHERE "+strings.Join(clauses, " AND ")+")", args...)
 	}
 	return query
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -25,6 +26,10 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 		Models:        testutil.DefaultSystemModels(),
 	})
 
+	if err := RegisterErrorClassification(db); err != nil {
+		t.Fatalf("Failed to register error classification: %v", err)
+	}
+
 	cleanup := func() {
 		sqlDB, _ := db.DB()
 		if sqlDB != nil {
@@ -290,6 +295,38 @@ func TestSystemRepository_List(t *testing.T) {
 				require.Len(t, systems, 5)
 			},
 		},
+		{
+			name: "skipGeometry leaves geometry unloaded",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10, SkipGeometry: true},
+				Recursive:   true,
+			},
+			wantCount: 5,
+			wantTotal: 5,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				for _, system := range systems {
+					require.Nil(t, system.Geometry, "system %s", system.Name)
+					require.NotEmpty(t, system.Name)
+				}
+			},
+		},
+		{
+			name: "skipGeometry with distance ordering",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10, SkipGeometry: true},
+				Recursive:   true,
+				SortBy:      queryparams.SortByDistance,
+				Near:        &queryparams.NearPoint{Lon: -122.4, Lat: 37.7},
+			},
+			wantCount: 5,
+			wantTotal: 5,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				require.Equal(t, sensor1.ID, systems[0].ID)
+				for _, system := range systems {
+					require.Nil(t, system.Geometry, "system %s", system.Name)
+				}
+			},
+		},
 		{
 			name: "list with limit 2",
 			params: &queryparams.SystemQueryParams{
@@ -346,6 +383,43 @@ func TestSystemRepository_List(t *testing.T) {
 				require.Equal(t, "Child Sensor", systems[0].Name)
 			},
 		},
+		{
+			name: "filter by parent system without recursive",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				Parent:      []string{platform1.ID},
+			},
+			wantCount: 1,
+			wantTotal: 1,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				require.Equal(t, childSensor.ID, systems[0].ID)
+			},
+		},
+		{
+			name: "root only ignores recursive",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				RootOnly:    true,
+				Recursive:   true,
+			},
+			wantCount: 4,
+			wantTotal: 4,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				for _, sys := range systems {
+					require.Nil(t, sys.ParentSystemID, "%s has a parent", sys.Name)
+				}
+			},
+		},
+		{
+			name: "root only or children of a parent",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				RootOnly:    true,
+				Parent:      []string{platform1.ID},
+			},
+			wantCount: 5,
+			wantTotal: 5,
+		},
 		{
 			name: "in bbox filter",
 			params: &queryparams.SystemQueryParams{
@@ -377,6 +451,36 @@ func TestSystemRepository_List(t *testing.T) {
 				require.Contains(t, names, "Valve Controller")
 			},
 		},
+		{
+			name: "filter by systemType sensors only",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				SystemType:  []string{domains.SystemTypeSensor},
+				Recursive:   true,
+			},
+			wantCount: 3,
+			wantTotal: 3,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				for _, sys := range systems {
+					require.Equal(t, domains.SystemTypeSensor, sys.SystemType)
+				}
+			},
+		},
+		{
+			name: "filter by several systemTypes is OR-combined",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				SystemType:  []string{domains.SystemTypeSensor, domains.SystemTypeActuator},
+				Recursive:   true,
+			},
+			wantCount: 4,
+			wantTotal: 4,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				for _, sys := range systems {
+					require.NotEqual(t, domains.SystemTypePlatform, sys.SystemType)
+				}
+			},
+		},
 		{
 			name: "Datetime test",
 			params: &queryparams.SystemQueryParams{
@@ -397,6 +501,245 @@ func TestSystemRepository_List(t *testing.T) {
 				require.Contains(t, names, "Weather Station")
 			},
 		},
+		{
+			name: "Datetime intersects partial overlap",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				// platform1 is valid for part of the interval
+				Datetime: &common_shared.TimeRange{
+					Start: testutil.PtrTime(time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC)),
+					End:   testutil.PtrTime(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)),
+				},
+				DatetimeOp: queryparams.DatetimeOpIntersects,
+			},
+			wantCount: 1,
+			wantTotal: 1,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				require.Len(t, systems, 1)
+
+				names := []string{}
+				for _, s := range systems {
+					names = append(names, s.Name)
+				}
+				require.Contains(t, names, "Weather Station")
+			},
+		},
+		{
+			name: "Datetime contains",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				// Only platform1 is valid for the whole interval
+				Datetime: &common_shared.TimeRange{
+					Start: testutil.PtrTime(time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)),
+					End:   testutil.PtrTime(time.Date(2025, 11, 4, 0, 0, 0, 0, time.UTC)),
+				},
+				DatetimeOp: queryparams.DatetimeOpContains,
+			},
+			wantCount: 1,
+			wantTotal: 1,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				require.Len(t, systems, 1)
+
+				names := []string{}
+				for _, s := range systems {
+					names = append(names, s.Name)
+				}
+				require.Contains(t, names, "Weather Station")
+			},
+		},
+		{
+			name: "Datetime contains open-ended validity",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				// actuator1 has no start so it covers any interval before its end
+				Datetime: &common_shared.TimeRange{
+					Start: testutil.PtrTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+					End:   testutil.PtrTime(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+				},
+				DatetimeOp: queryparams.DatetimeOpContains,
+			},
+			wantCount: 1,
+			wantTotal: 1,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				require.Len(t, systems, 1)
+
+				names := []string{}
+				for _, s := range systems {
+					names = append(names, s.Name)
+				}
+				require.Contains(t, names, "Valve Controller")
+			},
+		},
+		{
+			name: "Datetime contains partial overlap",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				// platform1 expires before the interval ends so it does not contain it
+				Datetime: &common_shared.TimeRange{
+					Start: testutil.PtrTime(time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC)),
+					End:   testutil.PtrTime(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)),
+				},
+				DatetimeOp: queryparams.DatetimeOpContains,
+			},
+			wantCount: 0,
+			wantTotal: 0,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				require.Len(t, systems, 0)
+			},
+		},
+		{
+			name: "Datetime during",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				// platform1 validity lies entirely within the interval; open-ended systems do not
+				Datetime: &common_shared.TimeRange{
+					Start: testutil.PtrTime(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)),
+					End:   testutil.PtrTime(time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)),
+				},
+				DatetimeOp: queryparams.DatetimeOpDuring,
+			},
+			wantCount: 1,
+			wantTotal: 1,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				require.Len(t, systems, 1)
+
+				names := []string{}
+				for _, s := range systems {
+					names = append(names, s.Name)
+				}
+				require.Contains(t, names, "Weather Station")
+			},
+		},
+		{
+			name: "Datetime during interval inside validity",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				// platform1 is valid beyond both ends of the interval
+				Datetime: &common_shared.TimeRange{
+					Start: testutil.PtrTime(time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC)),
+					End:   testutil.PtrTime(time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)),
+				},
+				DatetimeOp: queryparams.DatetimeOpDuring,
+			},
+			wantCount: 0,
+			wantTotal: 0,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				require.Len(t, systems, 0)
+			},
+		},
+		{
+			name: "Datetime intersects includes systems without validTime",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				Recursive:   true,
+				// childSensor has no validTime, so it is valid at all times
+				Datetime: &common_shared.TimeRange{
+					Start: testutil.PtrTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+					End:   testutil.PtrTime(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)),
+				},
+				DatetimeOp: queryparams.DatetimeOpIntersects,
+			},
+			wantCount: 2,
+			wantTotal: 2,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				names := []string{}
+				for _, s := range systems {
+					names = append(names, s.Name)
+				}
+				require.ElementsMatch(t, []string{"Child Sensor", "Valve Controller"}, names)
+			},
+		},
+		{
+			name: "Datetime instant includes systems without validTime",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				Recursive:   true,
+				Datetime: &common_shared.TimeRange{
+					Start: testutil.PtrTime(time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)),
+					End:   testutil.PtrTime(time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)),
+				},
+			},
+			wantCount: 2,
+			wantTotal: 2,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				names := []string{}
+				for _, s := range systems {
+					names = append(names, s.Name)
+				}
+				require.ElementsMatch(t, []string{"Child Sensor", "Weather Station"}, names)
+			},
+		},
+		{
+			name: "Datetime contains includes systems without validTime",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				Recursive:   true,
+				// No other system is valid for the whole of 2010-2030
+				Datetime: &common_shared.TimeRange{
+					Start: testutil.PtrTime(time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)),
+					End:   testutil.PtrTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)),
+				},
+				DatetimeOp: queryparams.DatetimeOpContains,
+			},
+			wantCount: 1,
+			wantTotal: 1,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				require.Equal(t, "Child Sensor", systems[0].Name)
+			},
+		},
+		{
+			name: "Datetime during fully open interval includes systems without validTime",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				Recursive:   true,
+				Datetime:    &common_shared.TimeRange{},
+				DatetimeOp:  queryparams.DatetimeOpDuring,
+			},
+			wantCount: 5,
+			wantTotal: 5,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				names := []string{}
+				for _, s := range systems {
+					names = append(names, s.Name)
+				}
+				require.Contains(t, names, "Child Sensor")
+			},
+		},
+		{
+			name: "ValidTimeContains instant",
+			params: &queryparams.SystemQueryParams{
+				QueryParams:       queryparams.QueryParams{Limit: 10},
+				Recursive:         true,
+				ValidTimeContains: testutil.PtrTime(time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)),
+			},
+			wantCount: 2,
+			wantTotal: 2,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				names := []string{}
+				for _, s := range systems {
+					names = append(names, s.Name)
+				}
+				// childSensor has no validTime, so it is valid at all times
+				require.ElementsMatch(t, []string{"Child Sensor", "Weather Station"}, names)
+			},
+		},
+		{
+			name: "ValidTimeContains instant on the validity end",
+			params: &queryparams.SystemQueryParams{
+				QueryParams:       queryparams.QueryParams{Limit: 10},
+				Recursive:         true,
+				ValidTimeContains: testutil.PtrTime(time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)),
+			},
+			wantCount: 2,
+			wantTotal: 2,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				names := []string{}
+				for _, s := range systems {
+					names = append(names, s.Name)
+				}
+				require.ElementsMatch(t, []string{"Child Sensor", "Valve Controller"}, names)
+			},
+		},
 		{
 			name: "Geom test",
 			params: &queryparams.SystemQueryParams{
@@ -414,6 +757,32 @@ func TestSystemRepository_List(t *testing.T) {
 				require.Contains(t, names, "Weather Station")
 			},
 		},
+		{
+			name: "bbox excludes systems without geometry",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				Recursive:   true,
+				// actuator1 and childSensor have no geometry, so even the whole world misses them
+				Bbox: &common_shared.BoundingBox{MinX: -180, MinY: -90, MaxX: 180, MaxY: 90},
+			},
+			wantCount: 3,
+			wantTotal: 3,
+			checkFunc: func(t *testing.T, systems []*domains.System) {
+				for _, sys := range systems {
+					require.NotNil(t, sys.Geometry, "system %s", sys.Name)
+				}
+			},
+		},
+		{
+			name: "geom excludes systems without geometry",
+			params: &queryparams.SystemQueryParams{
+				QueryParams: queryparams.QueryParams{Limit: 10},
+				Recursive:   true,
+				Geom:        "POLYGON((-180 -90,180 -90,180 90,-180 90,-180 -90))",
+			},
+			wantCount: 3,
+			wantTotal: 3,
+		},
 		{
 			name: "in bbox filter multiple results",
 			params: &queryparams.SystemQueryParams{
@@ -455,6 +824,235 @@ func TestSystemRepository_List(t *testing.T) {
 	}
 }
 
+func TestSystemRepository_List_CombinedFilters(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSystemRepository(db)
+	sfRepo := NewSamplingFeatureRepository(db)
+
+	validIn := func(startYear, endYear int) *common_shared.TimeRange {
+		tr := &common_shared.TimeRange{Start: testutil.PtrTime(time.Date(startYear, 1, 1, 0, 0, 0, 0, time.UTC))}
+		if endYear != 0 {
+			tr.End = testutil.PtrTime(time.Date(endYear, 12, 31, 0, 0, 0, 0, time.UTC))
+		}
+		return tr
+	}
+	newSystem := func(uid, name string, location *common_shared.GoGeom, validTime *common_shared.TimeRange) *domains.System {
+		system := &domains.System{
+			CommonSSN:  domains.CommonSSN{UniqueIdentifier: domains.UniqueID(uid), Name: name},
+			SystemType: domains.SystemTypeSensor,
+			Geometry:   location,
+			ValidTime:  validTime,
+		}
+		require.NoError(t, repo.Create(system))
+		return system
+	}
+
+	la := func() *common_shared.GoGeom { return testutil.MakePoint(-118.2437, 34.0522) }
+	buoyA := newSystem("urn:test:combined:a", "Harbor Buoy A", la(), validIn(2025, 2025))
+	buoyB := newSystem("urn:test:combined:b", "Harbor Buoy B", la(), validIn(2025, 0))
+	newSystem("urn:test:combined:old", "Harbor Buoy Old", la(), validIn(2020, 2020))
+	newSystem("urn:test:combined:camera", "Traffic Camera", la(), validIn(2025, 2025))
+	newSystem("urn:test:combined:sf", "Harbor Buoy SF", testutil.MakePoint(-122.4194, 37.7749), validIn(2025, 2025))
+
+	// Two sampling features under the same system must not double count it
+	var foiIDs []string
+	for i, uid := range []string{"urn:test:combined:foi:1", "urn:test:combined:foi:2"} {
+		sf := &domains.SamplingFeature{
+			CommonSSN:      domains.CommonSSN{UniqueIdentifier: domains.UniqueID(uid), Name: fmt.Sprintf("Buoy Station %d", i)},
+			ParentSystemID: &buoyA.ID,
+		}
+		require.NoError(t, sfRepo.Create(sf))
+		foiIDs = append(foiIDs, sf.ID)
+	}
+
+	combined := func(limit int) *queryparams.SystemQueryParams {
+		return &queryparams.SystemQueryParams{
+			QueryParams: queryparams.QueryParams{Limit: limit, Q: []string{"buoy"}},
+			Bbox:        testutil.TestBoundingBoxLA(),
+			Datetime: &common_shared.TimeRange{
+				Start: testutil.PtrTime(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)),
+				End:   testutil.PtrTime(time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)),
+			},
+		}
+	}
+
+	t.Run("bbox, datetime and q all apply", func(t *testing.T) {
+		systems, total, err := repo.List(combined(10))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), total)
+		ids := []string{}
+		for _, s := range systems {
+			ids = append(ids, s.ID)
+		}
+		require.ElementsMatch(t, []string{buoyA.ID, buoyB.ID}, ids)
+	})
+
+	t.Run("total counts every match, not just the page", func(t *testing.T) {
+		systems, total, err := repo.List(combined(1))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), total)
+		require.Len(t, systems, 1)
+	})
+
+	t.Run("related-resource filter composes without duplicates", func(t *testing.T) {
+		params := combined(10)
+		params.FOI = foiIDs
+		systems, total, err := repo.List(params)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), total)
+		require.Len(t, systems, 1)
+		require.Equal(t, buoyA.ID, systems[0].ID)
+	})
+}
+
+func TestSystemRepository_List_AssetTypeAndSystemKind(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSystemRepository(db)
+	procRepo := NewProcedureRepository(db)
+
+	newKind := func(uid string) *domains.Procedure {
+		procedure := &domains.Procedure{
+			CommonSSN:     domains.CommonSSN{UniqueIdentifier: domains.UniqueID(uid), Name: uid},
+			ProcedureType: domains.ProcedureTypeSystem,
+		}
+		require.NoError(t, procRepo.Create(procedure))
+		return procedure
+	}
+	newSystem := func(uid, assetType string, kind *domains.Procedure) *domains.System {
+		system := &domains.System{
+			CommonSSN:  domains.CommonSSN{UniqueIdentifier: domains.UniqueID(uid), Name: uid},
+			SystemType: domains.SystemTypeSensor,
+		}
+		if assetType != "" {
+			system.AssetType = &assetType
+		}
+		if kind != nil {
+			system.SystemKindID = &kind.ID
+		}
+		require.NoError(t, repo.Create(system))
+		return system
+	}
+
+	weatherKind := newKind("urn:test:kind:weather")
+	cameraKind := newKind("urn:test:kind:camera")
+
+	station := newSystem("urn:test:asset:station", domains.AssetTypeEquipment, weatherKind)
+	camera := newSystem("urn:test:asset:camera", domains.AssetTypeEquipment, cameraKind)
+	truck := newSystem("urn:test:asset:truck", domains.AssetTypePlatform, weatherKind)
+	observer := newSystem("urn:test:asset:observer", domains.AssetTypeHuman, nil)
+	newSystem("urn:test:asset:untyped", "", nil)
+
+	list := func(t *testing.T, params *queryparams.SystemQueryParams) []string {
+		t.Helper()
+		params.Limit = 100
+		systems, total, err := repo.List(params)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(systems)), total)
+		ids := []string{}
+		for _, s := range systems {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	t.Run("assetType matches one value", func(t *testing.T) {
+		ids := list(t, &queryparams.SystemQueryParams{AssetType: []string{domains.AssetTypeHuman}})
+		require.ElementsMatch(t, []string{observer.ID}, ids)
+	})
+
+	t.Run("assetType values are OR-combined", func(t *testing.T) {
+		ids := list(t, &queryparams.SystemQueryParams{AssetType: []string{domains.AssetTypeEquipment, domains.AssetTypePlatform}})
+		require.ElementsMatch(t, []string{station.ID, camera.ID, truck.ID}, ids)
+	})
+
+	t.Run("systemKind matches by id or uid", func(t *testing.T) {
+		ids := list(t, &queryparams.SystemQueryParams{SystemKind: []string{weatherKind.ID}})
+		require.ElementsMatch(t, []string{station.ID, truck.ID}, ids)
+
+		ids = list(t, &queryparams.SystemQueryParams{SystemKind: []string{"urn:test:kind:camera"}})
+		require.ElementsMatch(t, []string{camera.ID}, ids)
+	})
+
+	t.Run("systemKind values are OR-combined", func(t *testing.T) {
+		ids := list(t, &queryparams.SystemQueryParams{SystemKind: []string{weatherKind.ID, cameraKind.ID}})
+		require.ElementsMatch(t, []string{station.ID, camera.ID, truck.ID}, ids)
+	})
+
+	t.Run("assetType and systemKind are ANDed", func(t *testing.T) {
+		ids := list(t, &queryparams.SystemQueryParams{
+			AssetType:  []string{domains.AssetTypeEquipment},
+			SystemKind: []string{weatherKind.ID},
+		})
+		require.ElementsMatch(t, []string{station.ID}, ids)
+	})
+
+	t.Run("unknown values match nothing", func(t *testing.T) {
+		require.Empty(t, list(t, &queryparams.SystemQueryParams{AssetType: []string{"Simulation"}}))
+		require.Empty(t, list(t, &queryparams.SystemQueryParams{SystemKind: []string{"urn:test:kind:missing"}}))
+	})
+}
+
+func TestSystemRepository_List_PropertyFilters(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewSystemRepository(db)
+
+	newSystem := func(uid, name, description, assetType string) *domains.System {
+		system := &domains.System{
+			CommonSSN:  domains.CommonSSN{UniqueIdentifier: domains.UniqueID(uid), Name: name, Description: description},
+			SystemType: domains.SystemTypeSensor,
+			AssetType:  &assetType,
+		}
+		require.NoError(t, repo.Create(system))
+		return system
+	}
+
+	station := newSystem("urn:test:props:station", "Weather Station", "Rooftop, north side", domains.AssetTypeEquipment)
+	stationCopy := newSystem("urn:test:props:station-2", "Weather Station", "Rooftop, south side", domains.AssetTypePlatform)
+	newSystem("urn:test:props:station-3", "Weather Station 3", "Rooftop, north side", domains.AssetTypeEquipment)
+
+	list := func(t *testing.T, properties map[string][]string) []string {
+		t.Helper()
+		systems, _, err := repo.List(&queryparams.SystemQueryParams{
+			QueryParams: queryparams.QueryParams{Limit: 100},
+			Properties:  properties,
+		})
+		require.NoError(t, err)
+		ids := []string{}
+		for _, s := range systems {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	t.Run("exact match, not substring", func(t *testing.T) {
+		require.ElementsMatch(t, []string{station.ID, stationCopy.ID}, list(t, map[string][]string{"name": {"Weather Station"}}))
+	})
+
+	t.Run("values for one property are OR-combined", func(t *testing.T) {
+		ids := list(t, map[string][]string{"uid": {"urn:test:props:station", "urn:test:props:station-2"}})
+		require.ElementsMatch(t, []string{station.ID, stationCopy.ID}, ids)
+	})
+
+	t.Run("properties are ANDed", func(t *testing.T) {
+		ids := list(t, map[string][]string{"name": {"Weather Station"}, "description": {"Rooftop, north side"}})
+		require.ElementsMatch(t, []string{station.ID}, ids)
+
+		ids = list(t, map[string][]string{"name": {"Weather Station"}, "assetType": {domains.AssetTypePlatform}})
+		require.ElementsMatch(t, []string{stationCopy.ID}, ids)
+	})
+
+	t.Run("featureType matches the system type", func(t *testing.T) {
+		require.Len(t, list(t, map[string][]string{"featureType": {domains.SystemTypeSensor}}), 3)
+		require.Empty(t, list(t, map[string][]string{"featureType": {domains.SystemTypePlatform}}))
+	})
+}
+
 func TestSystemRepository_DeeplyNestedSystems(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -649,6 +1247,96 @@ func TestSystemRepository_Hierarchy(t *testing.T) {
 	}
 }
 
+func TestSystemRepository_GetSubsystems_RecursiveGuardsCycles(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSystemRepository(db)
+
+	root := &domains.System{
+		CommonSSN:  domains.CommonSSN{UniqueIdentifier: "urn:test:cycle:root", Name: "Cycle Root"},
+		SystemType: domains.SystemTypePlatform,
+	}
+	require.NoError(t, repo.Create(root))
+
+	child := &domains.System{
+		CommonSSN:      domains.CommonSSN{UniqueIdentifier: "urn:test:cycle:child", Name: "Cycle Child"},
+		SystemType:     domains.SystemTypePlatform,
+		ParentSystemID: &root.ID,
+	}
+	require.NoError(t, repo.Create(child))
+
+	grandchild := &domains.System{
+		CommonSSN:      domains.CommonSSN{UniqueIdentifier: "urn:test:cycle:grandchild", Name: "Cycle Grandchild"},
+		SystemType:     domains.SystemTypeSensor,
+		ParentSystemID: &child.ID,
+	}
+	require.NoError(t, repo.Create(grandchild))
+
+	// Close the loop: root now claims the grandchild as its parent
+	require.NoError(t, db.Model(&domains.System{}).Where("id = ?", root.ID).Update("parent_system_id", grandchild.ID).Error)
+
+	subs, err := repo.GetSubsystems(root.ID, true)
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+	}
+	require.ElementsMatch(t, []string{child.ID, grandchild.ID}, ids)
+}
+
+func TestSystemRepository_ListSubsystems_Paginates(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSystemRepository(db)
+
+	parent := &domains.System{
+		CommonSSN:  domains.CommonSSN{UniqueIdentifier: "urn:test:page:parent", Name: "Paged Parent"},
+		SystemType: domains.SystemTypePlatform,
+	}
+	require.NoError(t, repo.Create(parent))
+
+	var childIDs []string
+	for i := 0; i < 5; i++ {
+		child := &domains.System{
+			CommonSSN:      domains.CommonSSN{UniqueIdentifier: domains.UniqueID(fmt.Sprintf("urn:test:page:child:%d", i)), Name: fmt.Sprintf("Paged Child %d", i)},
+			SystemType:     domains.SystemTypeSensor,
+			ParentSystemID: &parent.ID,
+		}
+		require.NoError(t, repo.Create(child))
+		childIDs = append(childIDs, child.ID)
+	}
+
+	grandchild := &domains.System{
+		CommonSSN:      domains.CommonSSN{UniqueIdentifier: "urn:test:page:grandchild", Name: "Paged Grandchild"},
+		SystemType:     domains.SystemTypeSensor,
+		ParentSystemID: &childIDs[0],
+	}
+	require.NoError(t, repo.Create(grandchild))
+
+	var paged []string
+	for offset := 0; offset < 5; offset += 2 {
+		params := &queryparams.SystemQueryParams{QueryParams: queryparams.QueryParams{Limit: 2, Offset: offset}}
+		page, total, err := repo.ListSubsystems(parent.ID, false, params)
+		require.NoError(t, err)
+		require.Equal(t, int64(5), total)
+		require.LessOrEqual(t, len(page), 2)
+		for _, sub := range page {
+			paged = append(paged, sub.ID)
+		}
+	}
+	require.ElementsMatch(t, childIDs, paged, "pages must cover every child exactly once")
+
+	again, _, err := repo.ListSubsystems(parent.ID, false, &queryparams.SystemQueryParams{QueryParams: queryparams.QueryParams{Limit: 2, Offset: 2}})
+	require.NoError(t, err)
+	require.Equal(t, paged[2:4], []string{again[0].ID, again[1].ID}, "ordering must be stable")
+
+	recursive, total, err := repo.ListSubsystems(parent.ID, true, &queryparams.SystemQueryParams{QueryParams: queryparams.QueryParams{Limit: 10}})
+	require.NoError(t, err)
+	require.Equal(t, int64(6), total)
+	require.Len(t, recursive, 6)
+}
+
 func TestSystemRepository_Delete(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -760,6 +1448,58 @@ func TestSystemRepository_Update(t *testing.T) {
 	}
 }
 
+func TestSystemRepository_Update_PreservesAssociations(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewSystemRepository(db)
+	datastreamRepo := NewDatastreamRepository(db)
+
+	system := &domains.System{
+		CommonSSN:  domains.CommonSSN{UniqueIdentifier: "urn:test:sensor:assoc", Name: "Associated Sensor"},
+		SystemType: domains.SystemTypeSensor,
+		Geometry:   testutil.MakePoint(-122.4194, 37.7749),
+	}
+	require.NoError(t, repo.Create(system))
+
+	datastream := &domains.Datastream{
+		CommonSSN: domains.CommonSSN{UniqueIdentifier: "urn:test:ds:assoc", Name: "Associated DS"},
+		SystemID:  &system.ID,
+	}
+	require.NoError(t, datastreamRepo.Create(datastream))
+	require.NoError(t, db.Model(system).Association("Datastreams").Append(datastream))
+
+	countDatastreams := func(t *testing.T) int64 {
+		t.Helper()
+		return db.Model(&domains.System{Base: domains.Base{ID: system.ID}}).Association("Datastreams").Count()
+	}
+	require.Equal(t, int64(1), countDatastreams(t))
+
+	t.Run("geometry-only update keeps associations", func(t *testing.T) {
+		err := repo.Update(system.ID, &domains.System{
+			CommonSSN:  domains.CommonSSN{UniqueIdentifier: "urn:test:sensor:assoc", Name: "Associated Sensor"},
+			SystemType: domains.SystemTypeSensor,
+			Geometry:   testutil.MakePoint(-122.3321, 47.6062),
+		})
+		require.NoError(t, err)
+
+		updated, err := repo.GetByID(system.ID)
+		require.NoError(t, err)
+		require.NotNil(t, updated.Geometry)
+		require.Equal(t, []float64{-122.3321, 47.6062}, updated.Geometry.T.FlatCoords())
+		require.Equal(t, int64(1), countDatastreams(t))
+	})
+
+	t.Run("explicit empty association clears it", func(t *testing.T) {
+		err := repo.Update(system.ID, &domains.System{
+			CommonSSN:   domains.CommonSSN{UniqueIdentifier: "urn:test:sensor:assoc", Name: "Associated Sensor"},
+			SystemType:  domains.SystemTypeSensor,
+			Datastreams: []domains.Datastream{},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(0), countDatastreams(t))
+	})
+}
+
 func TestSystemRepository_HasSubsystems(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// tenantFieldName is the field through which a model opts into tenant
+// scoping; domains.Base and domains.Collection carry it.
+const tenantFieldName = "TenantID"
+
+const tenantGuardedUpsertKey = "tenant:guarded_upsert"
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx carrying tenantID, which scopes every
+// statement run under it once RegisterTenantScoping is in place.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant stored in ctx by WithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// RegisterTenantScoping confines statements run through db to the tenant in
+// their context (see WithTenant), for models with a TenantID field: creates
+// stamp the tenant on new rows, while queries (including streamed Rows()
+// reads), updates and deletes only see the tenant's own rows, so another
+// tenant's resource looks like one that does not exist. Updates never change a row's tenant, and an upsert that
+// conflicts with another tenant's row fails with gorm.ErrRecordNotFound.
+// Statements without a tenant in their context, and raw SQL, are left alone.
+func RegisterTenantScoping(db *gorm.DB) error {
+	stamp := func(tx *gorm.DB) {
+		field, tenantID, ok := tenantScope(tx)
+		if !ok {
+			return
+		}
+		tx.Statement.SetColumn(field.Name, &tenantID, true)
+		// Save falls back to creating the row after its update, whose
+		// statement still omits the tenant column (see scopeUpdate)
+		omits := tx.Statement.Omits[:0:0]
+		for _, omit := range tx.Statement.Omits {
+			if omit != field.DBName {
+				omits = append(omits, omit)
+			}
+		}
+		tx.Statement.Omits = omits
+
+		// Upserts (such as Save of a row its tenant cannot see) must not
+		// update a conflicting row of another tenant
+		if c, ok := tx.Statement.Clauses["ON CONFLICT"]; ok {
+			if onConflict, ok := c.Expression.(clause.OnConflict); ok && !onConflict.DoNothing {
+				onConflict.Where.Exprs = append(onConflict.Where.Exprs, tenantCondition(field, tenantID))
+				tx.Statement.AddClause(onConflict)
+				tx.InstanceSet(tenantGuardedUpsertKey, true)
+			}
+		}
+	}
+	// An upsert that touched nothing hit another tenant's row, which must
+	// look like one that does not exist
+	checkUpsert := func(tx *gorm.DB) {
+		if guarded, ok := tx.InstanceGet(tenantGuardedUpsertKey); ok && guarded.(bool) &&
+			tx.Error == nil && tx.RowsAffected == 0 && !tx.DryRun {
+			tx.AddError(gorm.ErrRecordNotFound)
+		}
+	}
+	scope := func(tx *gorm.DB) {
+		if field, tenantID, ok := tenantScope(tx); ok {
+			tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{tenantCondition(field, tenantID)}})
+		}
+	}
+	scopeUpdate := func(tx *gorm.DB) {
+		if field, _, ok := tenantScope(tx); ok {
+			tx.Statement.Omits = append(tx.Statement.Omits, field.DBName)
+			scope(tx)
+		}
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tenant:stamp", stamp),
+		cb.Create().After("gorm:create").Register("tenant:check_upsert", checkUpsert),
+		cb.Query().Before("gorm:query").Register("tenant:scope", scope),
+		// Rows() runs the row callbacks rather than the query ones
+		cb.Row().Before("gorm:row").Register("tenant:scope", scope),
+		cb.Update().Before("gorm:update").Register("tenant:scope", scopeUpdate),
+		cb.Delete().Before("gorm:delete").Register("tenant:scope", scope),
+	)
+}
+
+// tenantScope returns the tenant field of the statement's model and the
+// tenant to confine it to, or false when the statement is not scoped.
+func tenantScope(tx *gorm.DB) (*schema.Field, string, bool) {
+	if tx.Error != nil || tx.Statement.Schema == nil {
+		return nil, "", false
+	}
+	tenantID, ok := TenantFromContext(tx.Statement.Context)
+	if !ok {
+		return nil, "", false
+	}
+	field := tx.Statement.Schema.LookUpField(tenantFieldName)
+	if field == nil {
+		return nil, "", false
+	}
+	return field, tenantID, true
+}
+
+// tenantCondition matches rows of the statement's table that belong to tenantID.
+func tenantCondition(field *schema.Field, tenantID string) clause.Expression {
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenantID}
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	queryparams "github.com/yourusername/connected-systems-go/internal/model/query_params"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// openDryRunDB returns a database handle that renders statements without a
+// server, for inspecting the SQL they would run.
+func openDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=unused"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, RegisterTenantScoping(db))
+	return db
+}
+
+func TestRegisterTenantScoping_SQL(t *testing.T) {
+	db := openDryRunDB(t)
+	tenantCtx := WithTenant(context.Background(), "team-a")
+
+	t.Run("queries are scoped", func(t *testing.T) {
+		var systems []domains.System
+		stmt := db.WithContext(tenantCtx).Where("name = ?", "x").Find(&systems).Statement
+		assert.Contains(t, stmt.SQL.String(), `"systems"."tenant_id" = `)
+		assert.Contains(t, stmt.Vars, "team-a")
+	})
+
+	t.Run("streamed rows are scoped", func(t *testing.T) {
+		// Rows() runs the row callbacks, which DryRun leaves without a cursor
+		tx := db.WithContext(tenantCtx).Model(&domains.Observation{}).Where("datastream_id = ?", "x").Set("rows", true)
+		stmt := db.Callback().Row().Execute(tx).Statement
+		assert.Contains(t, stmt.SQL.String(), `"observations"."tenant_id" = `)
+		assert.Contains(t, stmt.Vars, "team-a")
+	})
+
+	t.Run("subqueries are scoped", func(t *testing.T) {
+		tx := db.WithContext(tenantCtx)
+		var systems []domains.System
+		stmt := tx.Where("id IN (?)", tx.Model(&domains.Datastream{}).Select("system_id")).Find(&systems).Statement
+		assert.Contains(t, stmt.SQL.String(), `"datastreams"."tenant_id" = `)
+		assert.Contains(t, stmt.SQL.String(), `"systems"."tenant_id" = `)
+	})
+
+	t.Run("deletes are scoped", func(t *testing.T) {
+		stmt := db.WithContext(tenantCtx).Delete(&domains.Procedure{}, "id = ?", "p1").Statement
+		assert.Contains(t, stmt.SQL.String(), `"procedures"."tenant_id" = `)
+	})
+
+	t.Run("updates are scoped and keep the tenant", func(t *testing.T) {
+		stmt := db.WithContext(tenantCtx).Save(&domains.Property{Base: domains.Base{ID: "p1"}}).Statement
+		sql := stmt.SQL.String()
+		assert.Contains(t, sql, `"properties"."tenant_id" = `)
+		assert.NotContains(t, sql, `SET "tenant_id"`)
+		assert.NotContains(t, sql, `,"tenant_id"=`)
+	})
+
+	t.Run("creates are stamped", func(t *testing.T) {
+		features := []domains.SamplingFeature{{}, {}}
+		db.WithContext(tenantCtx).Create(&features)
+		for _, f := range features {
+			require.NotNil(t, f.TenantID)
+			assert.Equal(t, "team-a", *f.TenantID)
+		}
+	})
+
+	t.Run("upserts only update the tenant's own rows", func(t *testing.T) {
+		stmt := db.WithContext(tenantCtx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&domains.System{}).Statement
+		assert.Regexp(t, `ON CONFLICT .* DO UPDATE SET .* WHERE "systems"."tenant_id" = `, stmt.SQL.String())
+	})
+
+	t.Run("without a tenant nothing changes", func(t *testing.T) {
+		var systems []domains.System
+		stmt := db.WithContext(context.Background()).Find(&systems).Statement
+		assert.NotContains(t, stmt.SQL.String(), "tenant_id")
+	})
+
+	t.Run("models without a tenant are not scoped", func(t *testing.T) {
+		var rows []domains.DeploymentClosure
+		stmt := db.WithContext(tenantCtx).Find(&rows).Statement
+		assert.NotContains(t, stmt.SQL.String(), "tenant_id")
+	})
+}
+
+func TestTenantScoping_IsolatesTenants(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	require.NoError(t, RegisterTenantScoping(db))
+
+	repo := NewSystemRepository(db)
+	teamA := repo.WithContext(WithTenant(context.Background(), "team-a"))
+	teamB := repo.WithContext(WithTenant(context.Background(), "team-b"))
+
+	newSystem := func(uid, name string) *domains.System {
+		return &domains.System{
+			CommonSSN:  domains.CommonSSN{UniqueIdentifier: domains.UniqueID(uid), Name: name},
+			SystemType: domains.SystemTypeSensor,
+		}
+	}
+	systemA := newSystem("urn:test:tenant:a", "Team A Sensor")
+	require.NoError(t, teamA.Create(systemA))
+	systemB := newSystem("urn:test:tenant:b", "Team B Sensor")
+	require.NoError(t, teamB.Create(systemB))
+
+	require.NotNil(t, systemA.TenantID)
+	require.Equal(t, "team-a", *systemA.TenantID)
+
+	params := &queryparams.SystemQueryParams{QueryParams: queryparams.QueryParams{Limit: 10}}
+	listed, total, err := teamA.List(params)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Len(t, listed, 1)
+	require.Equal(t, systemA.ID, listed[0].ID)
+
+	_, err = teamA.GetByID(systemB.ID)
+	require.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+
+	systemA.Name = "Renamed by Team A"
+	require.NoError(t, teamA.Update(systemA.ID, systemA))
+	found, err := teamA.GetByID(systemA.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Renamed by Team A", found.Name)
+	require.NotNil(t, found.TenantID)
+	require.Equal(t, "team-a", *found.TenantID)
+
+	hijack := newSystem("urn:test:tenant:hijack", "Renamed by Team A")
+	err = teamA.Update(systemB.ID, hijack)
+	require.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+	found, err = teamB.GetByID(systemB.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Team B Sensor", found.Name)
+
+	// Replacing a system that does not exist yet creates it in the tenant
+	created := newSystem("urn:test:tenant:a2", "Team A Upserted")
+	require.NoError(t, teamA.Update("team-a-upserted", created))
+	found, err = teamA.GetByID("team-a-upserted")
+	require.NoError(t, err)
+	require.NotNil(t, found.TenantID)
+	require.Equal(t, "team-a", *found.TenantID)
+
+	_ = teamA.Delete(systemB.ID, false)
+	_, err = teamB.GetByID(systemB.ID)
+	require.NoError(t, err, "team A must not be able to delete team B's system")
+
+	// Without a tenant every row is visible, as in single-tenant deployments
+	_, total, err = repo.List(params)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), total)
+}
+
+func TestCollectionRepository_CreateUnderTenantGeneratesID(t *testing.T) {
+	repo := NewCollectionRepository(openDryRunDB(t))
+
+	collection := &domains.Collection{ID: "chosen-by-client"}
+	require.NoError(t, repo.CreateCollection(WithTenant(context.Background(), "team-a"), collection))
+	assert.NotEqual(t, "chosen-by-client", collection.ID)
+	assert.NotEmpty(t, collection.ID)
+
+	collection = &domains.Collection{ID: "chosen-by-client"}
+	require.NoError(t, repo.CreateCollection(context.Background(), collection))
+	assert.Equal(t, "chosen-by-client", collection.ID, "without tenancy the client's id is kept")
+}
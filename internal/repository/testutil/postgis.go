@@ -2,6 +2,7 @@ package testutil
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
 	"time"
@@ -24,7 +25,7 @@ type PostGISContainer struct {
 }
 
 // StartPostGISContainer starts a PostGIS container for tests and returns a container wrapper
-func StartPostGISContainer(ctx context.Context, t *testing.T) *PostGISContainer {
+func StartPostGISContainer(ctx context.Context, t testing.TB) *PostGISContainer {
 	t.Helper()
 
 	req := tc.ContainerRequest{
@@ -35,7 +36,13 @@ func StartPostGISContainer(ctx context.Context, t *testing.T) *PostGISContainer
 			"POSTGRES_PASSWORD": "secret",
 			"POSTGRES_DB":       "testdb",
 		},
-		WaitingFor: wait.ForListeningPort("5432/tcp").WithStartupTimeout(2 * time.Minute),
+		// The image's entrypoint runs a temporary server to initialise the
+		// database before starting the real one, so the port alone can open
+		// too early: wait for the second "ready" line as well
+		WaitingFor: wait.ForAll(
+			wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+			wait.ForListeningPort("5432/tcp"),
+		).WithDeadline(2 * time.Minute),
 	}
 
 	container, err := tc.GenericContainer(ctx, tc.GenericContainerRequest{
@@ -72,13 +79,29 @@ type OpenTestDBOptions struct {
 	EnableLogging bool
 	// Models to auto-migrate (in order)
 	Models []interface{}
+	// ReadyTimeout bounds how long to wait for the database to accept
+	// connections; zero means DefaultReadyTimeout
+	ReadyTimeout time.Duration
 }
 
+// DefaultReadyTimeout is how long OpenTestDB waits for the database to accept
+// connections unless OpenTestDBOptions.ReadyTimeout says otherwise.
+const DefaultReadyTimeout = 30 * time.Second
+
+// Backoff between readiness pings, doubling from the initial delay up to the
+// maximum.
+var (
+	readyInitialBackoff = 100 * time.Millisecond
+	readyMaxBackoff     = 2 * time.Second
+)
+
 // OpenTestDB opens a GORM database connection with PostGIS extension and auto-migration
-func OpenTestDB(t *testing.T, dsn string, opts OpenTestDBOptions) *gorm.DB {
+func OpenTestDB(t testing.TB, dsn string, opts OpenTestDBOptions) *gorm.DB {
 	t.Helper()
 
-	config := &gorm.Config{}
+	// Opening pings once; a container that is still starting would fail the
+	// test there, so readiness is awaited below instead
+	config := &gorm.Config{DisableAutomaticPing: true}
 	if opts.EnableLogging {
 		config.Logger = logger.Default.LogMode(logger.Info)
 	}
@@ -86,14 +109,16 @@ func OpenTestDB(t *testing.T, dsn string, opts OpenTestDBOptions) *gorm.DB {
 	db, err := gorm.Open(postgres.Open(dsn), config)
 	require.NoError(t, err)
 
-	// Ensure PostGIS is available
 	sqlDB, err := db.DB()
 	require.NoError(t, err)
 
-	// Wait a short moment for postgres readiness
-	time.Sleep(250 * time.Millisecond)
+	timeout := opts.ReadyTimeout
+	if timeout <= 0 {
+		timeout = DefaultReadyTimeout
+	}
+	require.NoError(t, WaitForDB(context.Background(), sqlDB, timeout))
 
-	// Create PostGIS extension if missing
+	// Ensure PostGIS is available
 	err = db.Exec("CREATE EXTENSION IF NOT EXISTS postgis;").Error
 	require.NoError(t, err)
 
@@ -105,12 +130,30 @@ func OpenTestDB(t *testing.T, dsn string, opts OpenTestDBOptions) *gorm.DB {
 		}
 	}
 
-	// Ping to ensure connectivity
-	require.NoError(t, sqlDB.Ping())
-
 	return db
 }
 
+// WaitForDB pings db until it answers, backing off exponentially between
+// attempts, and gives up with the last ping error once timeout has passed.
+func WaitForDB(ctx context.Context, db *sql.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backoff := readyInitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database not ready after %d attempts in %s: %w", attempt, timeout, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, readyMaxBackoff)
+	}
+}
+
 // DefaultSystemModels returns the standard migration order for System-related tests
 func DefaultSystemModels() []interface{} {
 	return []interface{}{
@@ -118,6 +161,7 @@ func DefaultSystemModels() []interface{} {
 		&domains.Deployment{},
 		&domains.Procedure{},
 		&domains.SamplingFeature{},
+		&domains.SamplingFeatureSampleOf{},
 		&domains.Property{},
 		&domains.Feature{},
 		&domains.Datastream{},
@@ -138,6 +182,7 @@ func AllModels() []interface{} {
 		&domains.Deployment{},
 		&domains.Procedure{},
 		&domains.SamplingFeature{},
+		&domains.SamplingFeatureSampleOf{},
 		&domains.Property{},
 		&domains.Feature{},
 		&domains.Datastream{},
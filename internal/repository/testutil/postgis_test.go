@@ -0,0 +1,64 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotReady = errors.New("the database system is starting up")
+
+// startingConnector refuses connections until it has been asked ready times.
+type startingConnector struct {
+	ready    int32
+	attempts atomic.Int32
+}
+
+func (c *startingConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.attempts.Add(1) < c.ready {
+		return nil, errNotReady
+	}
+	return readyConn{}, nil
+}
+
+func (c *startingConnector) Driver() driver.Driver { return nil }
+
+type readyConn struct{}
+
+func (readyConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (readyConn) Close() error                        { return nil }
+func (readyConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func withFastBackoff(t *testing.T) {
+	t.Helper()
+	initial, maxBackoff := readyInitialBackoff, readyMaxBackoff
+	readyInitialBackoff, readyMaxBackoff = time.Millisecond, 4*time.Millisecond
+	t.Cleanup(func() { readyInitialBackoff, readyMaxBackoff = initial, maxBackoff })
+}
+
+func TestWaitForDB_RetriesUntilReady(t *testing.T) {
+	withFastBackoff(t)
+	connector := &startingConnector{ready: 4}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	require.NoError(t, WaitForDB(context.Background(), db, time.Second))
+	assert.Equal(t, int32(4), connector.attempts.Load())
+}
+
+func TestWaitForDB_TimesOut(t *testing.T) {
+	withFastBackoff(t)
+	db := sql.OpenDB(&startingConnector{ready: 1 << 30})
+	defer db.Close()
+
+	err := WaitForDB(context.Background(), db, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database not ready")
+}
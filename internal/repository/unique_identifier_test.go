@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"gorm.io/gorm/schema"
+)
+
+// uidScopedModels are the resource types whose unique identifier must be
+// unique among resources of the same type only.
+var uidScopedModels = []interface{}{
+	&domains.System{},
+	&domains.Deployment{},
+	&domains.Procedure{},
+	&domains.SamplingFeature{},
+	&domains.Property{},
+	&domains.Feature{},
+	&domains.Datastream{},
+	&domains.ControlStream{},
+}
+
+func TestUniqueIdentifierIndexes_ArePerTable(t *testing.T) {
+	cache := &sync.Map{}
+	names := map[string]string{}
+	for _, model := range uidScopedModels {
+		s, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		require.NoError(t, err)
+		assert.Contains(t, uidTables, s.Table, "AutoMigrate does not drop the global uid constraint of %s", s.Table)
+
+		var index *schema.Index
+		for _, idx := range s.ParseIndexes() {
+			if len(idx.Fields) > 0 && idx.Fields[0].DBName == "unique_identifier" {
+				index = &idx
+			}
+		}
+		require.NotNil(t, index, "%s has no unique_identifier index", s.Table)
+		assert.Equal(t, "UNIQUE", index.Class, s.Table)
+		assert.Equal(t, "idx_"+s.Table+"_tenant_uid", index.Name, s.Table)
+		// Unique per tenant, with untenanted rows sharing the empty tenant
+		require.Len(t, index.Fields, 2, s.Table)
+		assert.Equal(t, "COALESCE(tenant_id, '')", index.Fields[1].Expression, s.Table)
+		assert.False(t, index.Fields[0].Unique, "%s.unique_identifier must not be unique on its own", s.Table)
+
+		// A shared index name would make AutoMigrate skip the second table's
+		// index, or fail it outright
+		if other, ok := names[index.Name]; ok {
+			t.Errorf("%s and %s share the index %s", other, s.Table, index.Name)
+		}
+		names[index.Name] = s.Table
+	}
+}
+
+func TestUniqueIdentifier_SharedAcrossResourceTypes(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	const uid = "urn:test:shared:uid"
+	common := func(name string) domains.CommonSSN {
+		return domains.CommonSSN{UniqueIdentifier: uid, Name: name}
+	}
+
+	system := &domains.System{CommonSSN: common("Shared System")}
+	require.NoError(t, NewSystemRepository(db).Create(system))
+	require.NoError(t, NewProcedureRepository(db).Create(&domains.Procedure{CommonSSN: common("Shared Procedure")}))
+	require.NoError(t, NewDeploymentRepository(db).Create(&domains.Deployment{CommonSSN: common("Shared Deployment")}))
+	require.NoError(t, NewSamplingFeatureRepository(db).Create(&domains.SamplingFeature{CommonSSN: common("Shared Sampling Feature")}))
+	require.NoError(t, NewPropertyRepository(db).Create(&domains.Property{CommonSSN: common("Shared Property")}))
+
+	// Within one resource type the identifier is still unique
+	err := NewSystemRepository(db).Create(&domains.System{CommonSSN: common("Duplicate System")})
+	require.ErrorIs(t, err, ErrConflict)
+	err = NewProcedureRepository(db).Create(&domains.Procedure{CommonSSN: common("Duplicate Procedure")})
+	require.ErrorIs(t, err, ErrConflict)
+
+	found, err := NewSystemRepository(db).GetByUID(uid)
+	require.NoError(t, err)
+	require.Equal(t, system.ID, found.ID)
+}
+
+func TestUniqueIdentifier_UniquePerTenant(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	require.NoError(t, RegisterTenantScoping(db))
+
+	const uid = "urn:test:tenant:shared-uid"
+	newSystem := func(name string) *domains.System {
+		return &domains.System{
+			CommonSSN:  domains.CommonSSN{UniqueIdentifier: uid, Name: name},
+			SystemType: domains.SystemTypeSensor,
+		}
+	}
+	repo := NewSystemRepository(db)
+	teamA := repo.WithContext(WithTenant(context.Background(), "team-a"))
+	teamB := repo.WithContext(WithTenant(context.Background(), "team-b"))
+
+	require.NoError(t, teamA.Create(newSystem("Team A")))
+	require.NoError(t, teamB.Create(newSystem("Team B")), "another tenant's uid must not conflict")
+	require.ErrorIs(t, teamA.Create(newSystem("Team A Again")), ErrConflict)
+
+	// Without tenancy the uid is still unique
+	require.NoError(t, repo.Create(newSystem("Untenanted")))
+	require.ErrorIs(t, repo.Create(newSystem("Untenanted Again")), ErrConflict)
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	timePtrType = reflect.TypeOf(&time.Time{})
+)
+
+// RegisterUTCTimestamps keeps every time value of a model in UTC: times are
+// converted before rows are created or updated, including the created_at and
+// updated_at stamps, and again after rows are read, since the driver returns
+// timestamptz columns in the process's local zone. Serialized times then
+// carry a "Z" whatever offset a client wrote them with or the zone of the
+// database session.
+func RegisterUTCTimestamps(db *gorm.DB) error {
+	db.Config.NowFunc = func() time.Time { return time.Now().UTC() }
+
+	normalize := func(tx *gorm.DB) {
+		if tx.Error != nil || tx.Statement.Schema == nil {
+			return
+		}
+		rv := tx.Statement.ReflectValue
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				if err := normalizeRowTimes(tx.Statement, reflect.Indirect(rv.Index(i))); err != nil {
+					tx.AddError(err)
+					return
+				}
+			}
+		case reflect.Struct:
+			if err := normalizeRowTimes(tx.Statement, rv); err != nil {
+				tx.AddError(err)
+			}
+		}
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("utc:normalize", normalize),
+		cb.Update().Before("gorm:update").Register("utc:normalize", normalize),
+		cb.Query().After("gorm:query").Register("utc:normalize", normalize),
+	)
+}
+
+// scanRowsUTC is db.ScanRows followed by the UTC conversion
+// RegisterUTCTimestamps applies to queries, which rows streamed through
+// Rows() never reach.
+func scanRowsUTC(db *gorm.DB, rows *sql.Rows, dest interface{}) error {
+	if err := db.ScanRows(rows, dest); err != nil {
+		return err
+	}
+	stmt := &gorm.Statement{DB: db, Context: db.Statement.Context}
+	if err := stmt.Parse(dest); err != nil {
+		return err
+	}
+	return normalizeRowTimes(stmt, reflect.Indirect(reflect.ValueOf(dest)))
+}
+
+// normalizeRowTimes converts the time fields of row, a value of the
+// statement's model, to UTC. Rows of another type, such as the destination
+// of a Scan, are left alone.
+func normalizeRowTimes(stmt *gorm.Statement, row reflect.Value) error {
+	if row.Kind() != reflect.Struct || row.Type() != stmt.Schema.ModelType {
+		return nil
+	}
+	for _, field := range stmt.Schema.Fields {
+		if field.FieldType != timeType && field.FieldType != timePtrType {
+			continue
+		}
+		value, zero := field.ValueOf(stmt.Context, row)
+		if zero {
+			continue
+		}
+		var err error
+		switch t := value.(type) {
+		case time.Time:
+			err = field.Set(stmt.Context, row, t.UTC())
+		case *time.Time:
+			utc := t.UTC()
+			err = field.Set(stmt.Context, row, &utc)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/connected-systems-go/internal/model/common_shared"
+	"github.com/yourusername/connected-systems-go/internal/model/domains"
+	"github.com/yourusername/connected-systems-go/internal/repository/testutil"
+	"gorm.io/gorm/schema"
+)
+
+func TestRegisterUTCTimestamps_Create(t *testing.T) {
+	db := openDryRunDB(t)
+	require.NoError(t, RegisterUTCTimestamps(db))
+
+	plusTwo := time.FixedZone("+02:00", 2*60*60)
+	start := time.Date(2025, time.November, 1, 10, 0, 0, 0, plusTwo)
+	system := &domains.System{
+		CommonSSN: domains.CommonSSN{UniqueIdentifier: "urn:test:system:utc", Name: "UTC"},
+		ValidTime: &common_shared.TimeRange{Start: &start},
+	}
+	stmt := db.Create(system).Statement
+
+	require.NotNil(t, system.ValidTime.Start)
+	assert.Equal(t, time.UTC, system.ValidTime.Start.Location())
+	assert.True(t, start.Equal(*system.ValidTime.Start))
+	assert.Equal(t, time.UTC, system.CreatedAt.Location())
+	assert.Contains(t, stmt.Vars, system.ValidTime.Start, "the UTC time is what gets written")
+}
+
+func TestRegisterUTCTimestamps_Update(t *testing.T) {
+	db := openDryRunDB(t)
+	require.NoError(t, RegisterUTCTimestamps(db))
+
+	issued := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.FixedZone("-05:00", -5*60*60))
+	command := &domains.Command{IssueTime: &issued}
+	command.ID = "command-utc"
+	db.Save(command)
+
+	require.NotNil(t, command.IssueTime)
+	assert.Equal(t, time.UTC, command.IssueTime.Location())
+	assert.True(t, issued.Equal(*command.IssueTime))
+	assert.Equal(t, time.UTC, command.UpdatedAt.Location())
+}
+
+// zonedRowConn answers every query with one observation row whose times are
+// in +02:00, as the driver returns them for a session outside UTC.
+type zonedRowConn struct{ at time.Time }
+
+func (c zonedRowConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c zonedRowConn) Driver() driver.Driver                        { return nil }
+func (zonedRowConn) Prepare(string) (driver.Stmt, error)            { return nil, errors.New("not supported") }
+func (zonedRowConn) Close() error                                   { return nil }
+func (zonedRowConn) Begin() (driver.Tx, error)                      { return nil, errors.New("not supported") }
+
+func (c zonedRowConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &zonedRows{at: c.at}, nil
+}
+
+type zonedRows struct {
+	at   time.Time
+	done bool
+}
+
+func (*zonedRows) Columns() []string { return []string{"id", "result_time", "phenomenon_time"} }
+func (*zonedRows) Close() error      { return nil }
+
+func (r *zonedRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0], dest[1], dest[2] = "obs-1", r.at, r.at
+	return nil
+}
+
+func TestScanRowsUTC(t *testing.T) {
+	db := openDryRunDB(t)
+	at := time.Date(2025, time.November, 1, 10, 0, 0, 0, time.FixedZone("+02:00", 2*60*60))
+	conn := sql.OpenDB(zonedRowConn{at: at})
+	defer conn.Close()
+
+	rows, err := conn.Query("SELECT")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	require.True(t, rows.Next())
+	var observation domains.Observation
+	require.NoError(t, scanRowsUTC(db, rows, &observation))
+
+	assert.Equal(t, "obs-1", observation.ID)
+	assert.Equal(t, time.UTC, observation.ResultTime.Location())
+	assert.True(t, at.Equal(observation.ResultTime))
+	require.NotNil(t, observation.PhenomenonTime)
+	assert.Equal(t, time.UTC, observation.PhenomenonTime.Location())
+}
+
+// Times are stored with their zone, so a session in another zone reads the
+// same instant.
+func TestTimeColumnsAreTimestamptz(t *testing.T) {
+	db := openDryRunDB(t)
+	for _, model := range testutil.AllModels() {
+		s, err := schema.Parse(model, &sync.Map{}, db.NamingStrategy)
+		require.NoError(t, err)
+		for _, field := range s.Fields {
+			if field.DBName == "" || (field.FieldType != timeType && field.FieldType != timePtrType) {
+				continue
+			}
+			dataType := db.Dialector.DataTypeOf(field)
+			assert.True(t, strings.HasPrefix(dataType, "timestamptz"), "%s.%s is %s", s.Table, field.DBName, dataType)
+		}
+	}
+}